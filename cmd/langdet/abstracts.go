@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// fewRecords is the number of abstract records below which a dump that was read completely is
+// reported as suspicious
+const fewRecords = 100
+
+// recordElements are the elements of the records of the supported dump formats: <doc> of the
+// abstract dumps, and <page> of the exports of newer dump tools with an abstract per page
+var recordElements = map[string]bool{"doc": true, "page": true}
+
+// processAbstracts parses wikipedia abstracts from r and calls handle with every abstract, until
+// handle returns false. It returns the number of records with an abstract. Records and abstracts
+// are recognized by their local names in any case and namespace, abstracts may be nested in other
+// elements of their record, and HTML entities like &nbsp; are decoded.
+func processAbstracts(r io.Reader, handle func(abstract string) bool) (int, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	records := 0
+	record, inAbstract, hasAbstract := "", false, false
+	var abstract strings.Builder
+	for {
+		t, err := decoder.Token()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case record == "" && recordElements[name]:
+				record, hasAbstract = name, false
+				abstract.Reset()
+			case record != "" && name == "abstract" && !hasAbstract:
+				inAbstract = true
+			}
+		case xml.CharData:
+			if inAbstract {
+				abstract.Write(t)
+			}
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case inAbstract && name == "abstract":
+				inAbstract, hasAbstract = false, true
+			case name == record:
+				record = ""
+				if !hasAbstract {
+					continue
+				}
+				records++
+				if !handle(abstract.String()) {
+					return records, nil
+				}
+			}
+		}
+	}
+}
+
+// dumpWarning returns a warning if a dump has suspiciously few abstract records, which points to
+// a wrong URL or an unsupported format, unless the reading stopped early at the limit, or ""
+func dumpWarning(name string, records int, stopped bool) string {
+	switch {
+	case records == 0:
+		return fmt.Sprintf("WARNING: %s has no <doc> or <page> records with an <abstract>, is it an abstract dump?", name)
+	case records < fewRecords && !stopped:
+		return fmt.Sprintf("WARNING: %s has only %d records with an <abstract>, is it a complete abstract dump?", name, records)
+	}
+	return ""
+}
+
+// isBlankAbstract tells whether an abstract has no text to train with: it is empty, has no
+// letters, or is a fragment of a template, like "| image = Paris.jpg", which the abstracts of
+// pages starting with an infobox often are
+func isBlankAbstract(abstract string) bool {
+	abstract = strings.TrimSpace(abstract)
+	if strings.HasPrefix(abstract, "|") || strings.HasPrefix(abstract, "{{") || strings.HasPrefix(abstract, "}}") {
+		return true
+	}
+	return strings.IndexFunc(abstract, unicode.IsLetter) < 0
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// parseAbstracts returns the abstracts of a dump and its number of records
+func parseAbstracts(dump string) ([]string, int) {
+	abstracts := []string{}
+	records, err := processAbstracts(strings.NewReader(dump), func(abstract string) bool {
+		abstracts = append(abstracts, abstract)
+		return true
+	})
+	So(err, ShouldBeNil)
+	return abstracts, records
+}
+
+func TestProcessAbstracts(t *testing.T) {
+	Convey("Subject: Parsing abstract dumps", t, func() {
+		Convey("Abstracts of the classic format should be found", func() {
+			abstracts, records := parseAbstracts(`<feed><doc><title>Wikipedia: Paris</title>
+				<abstract>Paris is the capital of France.</abstract>
+				<links><sublink linktype="nav"><anchor>History</anchor><link>https://en.wikipedia.org/wiki/Paris#History</link></sublink></links></doc>
+				<doc><title>Wikipedia: Lyon</title><abstract>Lyon is a city.</abstract></doc></feed>`)
+			So(abstracts, ShouldResemble, []string{"Paris is the capital of France.", "Lyon is a city."})
+			So(records, ShouldEqual, 2)
+		})
+		Convey("Namespaced records with nested abstracts should be found", func() {
+			abstracts, _ := parseAbstracts(`<ab:feed xmlns:ab="http://example.org/abstracts">
+				<ab:page><ab:title>Paris</ab:title><ab:meta><ab:Abstract>Paris&nbsp;is a city.</ab:Abstract></ab:meta></ab:page>
+				</ab:feed>`)
+			So(abstracts, ShouldResemble, []string{"Paris is a city."})
+		})
+		Convey("Records without abstracts should not be counted", func() {
+			abstracts, records := parseAbstracts(`<mediawiki><page><title>Paris</title><text>...</text></page></mediawiki>`)
+			So(abstracts, ShouldBeEmpty)
+			So(records, ShouldEqual, 0)
+		})
+		Convey("Parsing should stop when the handler returns false", func() {
+			records, err := processAbstracts(strings.NewReader(`<feed><doc><abstract>a</abstract></doc><doc><abstract>b</abstract></doc></feed>`),
+				func(string) bool { return false })
+			So(err, ShouldBeNil)
+			So(records, ShouldEqual, 1)
+		})
+		Convey("Dumps with no or few records should be reported", func() {
+			So(dumpWarning("dump.xml", 0, false), ShouldContainSubstring, "no <doc> or <page> records")
+			So(dumpWarning("dump.xml", 5, false), ShouldContainSubstring, "only 5 records")
+			So(dumpWarning("dump.xml", 5, true), ShouldEqual, "")
+			So(dumpWarning("dump.xml", fewRecords, false), ShouldEqual, "")
+		})
+	})
+
+	Convey("Subject: Blank abstracts", t, func() {
+		Convey("Empty abstracts, abstracts without letters and template fragments should be blank", func() {
+			for _, abstract := range []string{"", "  \n", "|", "| image = Paris.jpg | caption = 1900", "{{Infobox city", "}} 42 –"} {
+				So(isBlankAbstract(abstract), ShouldBeTrue)
+			}
+		})
+		Convey("Abstracts with text should not be blank", func() {
+			So(isBlankAbstract("Paris is the capital of France."), ShouldBeFalse)
+			So(isBlankAbstract(" 東京は日本の首都"), ShouldBeFalse)
+		})
+	})
+}
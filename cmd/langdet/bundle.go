@@ -0,0 +1,230 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// bundle creates a compiled bundle of profiles, or prints the manifest of a bundle
+func bundle(args []string) {
+	config := struct {
+		Out         string  `flag:"out,Bundle file to write with the profiles of the file and directory arguments"`
+		Description string  `flag:"description,Description of the bundle, e.g. its corpora"`
+		Inspect     string  `flag:"inspect,Bundle file whose manifest is printed"`
+		Words       string  `flag:"words,Directory with a word list per language, <lang>.txt with one word per line, stored as bloom filters"`
+		FPRate      float64 `flag:"fp-rate,False positive rate of the bloom filters of the word lists"`
+	}{
+		FPRate: langdet.DefaultFalsePositiveRate,
+	}
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.Inspect != "" {
+		f, err := os.Open(config.Inspect)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		defer f.Close()
+		manifest, err := langdet.ReadBundleManifest(f)
+		if err != nil {
+			fatal(exitParse, fmt.Errorf("%s: %w", config.Inspect, err))
+		}
+		printManifest(os.Stdout, manifest)
+		return
+	}
+	if config.Out == "" || flags.NArg() == 0 {
+		fatalf(exitUsage, "-out and at least one profile file or directory, or -inspect are required arguments")
+	}
+	files, err := profileFiles(flags.Args())
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	var wordSets map[string]*langdet.BloomFilter
+	if config.Words != "" {
+		if wordSets, err = readWordLists(config.Words, config.FPRate); err != nil {
+			fatal(exitCode(err), err)
+		}
+	}
+	manifest, err := createBundle(config.Out, config.Description, files, wordSets)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	fmt.Printf("%d profiles and %d word sets written to %s\n", len(manifest.Profiles), len(manifest.WordSets), config.Out)
+}
+
+// profileFiles returns the files and the regular files of the directories of paths
+func profileFiles(paths []string) ([]string, error) {
+	files := []string{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// readWordLists returns bloom filters of the word lists of a directory by language, the names of
+// its .txt files. The lists have one word per line, empty lines and lines starting with # are
+// skipped.
+func readWordLists(dir string, falsePositiveRate float64) (map[string]*langdet.BloomFilter, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no word lists in %s", dir)
+	}
+	wordSets := make(map[string]*langdet.BloomFilter, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		words := []string{}
+		for _, line := range strings.Split(string(data), "\n") {
+			if word := strings.TrimSpace(line); word != "" && !strings.HasPrefix(word, "#") {
+				words = append(words, word)
+			}
+		}
+		wordSets[strings.TrimSuffix(filepath.Base(name), ".txt")] = langdet.NewBloomFilterOf(falsePositiveRate, words...)
+	}
+	return wordSets, nil
+}
+
+// createBundle writes the profiles of the json or binary profile files and the word sets to the
+// bundle file name, with a manifest of the description, the buildTime and the training times
+// of the profiles, the modification times of the files or the SOURCE_DATE_EPOCH for profiles
+// without CreatedAt, and returns the manifest
+func createBundle(name, description string, files []string, wordSets map[string]*langdet.BloomFilter) (langdet.BundleManifest, error) {
+	created, reproducible, err := buildTime()
+	if err != nil {
+		return langdet.BundleManifest{}, err
+	}
+	languages := []langdet.Language{}
+	trained := []time.Time{}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return langdet.BundleManifest{}, err
+		}
+		detector, err := langdet.Config{Profiles: []string{file}}.NewDetector()
+		if err != nil {
+			return langdet.BundleManifest{}, err
+		}
+		for _, language := range detector.Snapshot() {
+			languages = append(languages, language)
+			switch {
+			case !language.CreatedAt.IsZero():
+				trained = append(trained, language.CreatedAt)
+			case reproducible:
+				trained = append(trained, created)
+			default:
+				trained = append(trained, info.ModTime().UTC())
+			}
+		}
+	}
+	if len(languages) == 0 {
+		return langdet.BundleManifest{}, fmt.Errorf("no profiles for the bundle %s", name)
+	}
+	manifest := langdet.NewBundleManifest(languages)
+	manifest.Created = created
+	manifest.Description = description
+	for i := range manifest.Profiles {
+		manifest.Profiles[i].Trained = trained[i]
+	}
+	manifest.WordSets = langdet.NewBundleWordSets(wordSets)
+
+	f, err := os.Create(name)
+	if err != nil {
+		return manifest, err
+	}
+	err = langdet.WriteBundleWordSets(f, manifest, languages, wordSets)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(name)
+	}
+	return manifest, err
+}
+
+// buildTime returns the time of the SOURCE_DATE_EPOCH environment variable of reproducible
+// builds, seconds since the epoch, and true if it is set, or the current time
+func buildTime() (time.Time, bool, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Now().UTC(), false, nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+	}
+	return time.Unix(seconds, 0).UTC(), true, nil
+}
+
+// printManifest prints a bundle manifest as a table of its profiles
+func printManifest(w io.Writer, manifest langdet.BundleManifest) {
+	if !manifest.Created.IsZero() {
+		fmt.Fprintf(w, "created: %s\n", manifest.Created.Format(time.RFC3339))
+	}
+	if manifest.Description != "" {
+		fmt.Fprintf(w, "description: %s\n", manifest.Description)
+	}
+	fmt.Fprintf(w, "profiles: %d\n\n", len(manifest.Profiles))
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tDEPTH\tRANKS\tCORPUS\tTRAINED")
+	for _, profile := range manifest.Profiles {
+		corpus, trained := "-", "-"
+		if profile.CorpusSize > 0 {
+			corpus = fmt.Sprint(profile.CorpusSize)
+		}
+		if !profile.Trained.IsZero() {
+			trained = profile.Trained.Format("2006-01-02")
+		}
+		fmt.Fprintf(table, "%s\t%d\t%d\t%s\t%s\n", profile.Name, profile.Depth, profile.Ranks, corpus, trained)
+	}
+	table.Flush()
+	if len(manifest.Scripts) > 0 {
+		fmt.Fprintf(w, "\nscripts: %d\n\n", len(manifest.Scripts))
+		table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "SCRIPT\tLANGUAGES")
+		for _, route := range manifest.Scripts {
+			fmt.Fprintf(table, "%s\t%s\n", route.Script, strings.Join(route.Languages, ", "))
+		}
+		table.Flush()
+	}
+	if len(manifest.WordSets) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nword sets: %d\n\n", len(manifest.WordSets))
+	table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tWORDS\tSIZE\tFALSE POSITIVES")
+	for _, set := range manifest.WordSets {
+		fmt.Fprintf(table, "%s\t%d\t%d KB\t%.2f%%\n", set.Name, set.Words, (set.Size+1023)/1024, 100*set.FalsePositiveRate)
+	}
+	table.Flush()
+}
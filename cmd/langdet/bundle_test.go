@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBundleCommand(t *testing.T) {
+	Convey("Subject: Creating and inspecting bundles", t, func() {
+		dir := t.TempDir()
+		profiles := filepath.Join(dir, "profiles")
+		So(os.Mkdir(profiles, 0755), ShouldBeNil)
+		trained := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		for name, text := range map[string]string{"en": "the quick brown fox", "fr": "le renard brun"} {
+			data, err := json.Marshal(langdet.Analyze(text, name))
+			So(err, ShouldBeNil)
+			file := filepath.Join(profiles, name+".json")
+			So(os.WriteFile(file, data, 0644), ShouldBeNil)
+			So(os.Chtimes(file, trained, trained), ShouldBeNil)
+		}
+		files, err := profileFiles([]string{profiles})
+		So(err, ShouldBeNil)
+		words := filepath.Join(dir, "words")
+		So(os.Mkdir(words, 0755), ShouldBeNil)
+		So(os.WriteFile(filepath.Join(words, "fr.txt"), []byte("# common words\nbonjour\n\nMerci\n"), 0644), ShouldBeNil)
+		wordSets, err := readWordLists(words, 0.01)
+		So(err, ShouldBeNil)
+		name := filepath.Join(dir, "bundle.bin")
+		manifest, err := createBundle(name, "test corpora", files, wordSets)
+		So(err, ShouldBeNil)
+
+		Convey("The manifest should have the training times of the profile files", func() {
+			So(manifest.Profiles, ShouldHaveLength, 2)
+			So(manifest.Profiles[0].Name, ShouldEqual, "en")
+			So(manifest.Profiles[0].Trained.Equal(trained), ShouldBeTrue)
+			So(manifest.Created.IsZero(), ShouldBeFalse)
+		})
+		Convey("The manifest should be printed as a table", func() {
+			f, err := os.Open(name)
+			So(err, ShouldBeNil)
+			defer f.Close()
+			read, err := langdet.ReadBundleManifest(f)
+			So(err, ShouldBeNil)
+			out := &bytes.Buffer{}
+			printManifest(out, read)
+			So(out.String(), ShouldContainSubstring, "description: test corpora\nprofiles: 2\n")
+			So(out.String(), ShouldContainSubstring, "NAME  DEPTH  RANKS  CORPUS  TRAINED\n")
+			So(out.String(), ShouldContainSubstring, "2024-06-01")
+			So(out.String(), ShouldContainSubstring, "word sets: 1\n")
+			So(out.String(), ShouldContainSubstring, "NAME  WORDS  SIZE  FALSE POSITIVES\nfr    2      1 KB")
+		})
+		Convey("The word lists should be bloom filters of the bundle", func() {
+			So(wordSets["fr"].Len(), ShouldEqual, 2)
+			So(manifest.WordSets, ShouldHaveLength, 1)
+			d := langdet.NewDetector()
+			So(d.LoadBundle(name), ShouldBeNil)
+			So(d.GetClosestLanguage("merci"), ShouldEqual, "fr")
+			_, err := readWordLists(profiles, 0.01)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
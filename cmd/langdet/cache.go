@@ -0,0 +1,101 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// responseCache is a least recently used cache of detect responses by the hash of their request,
+// so that it never keeps input texts. It is safe for concurrent use.
+type responseCache struct {
+	// version identifies the profiles of the cached responses, it is part of all keys and ETags
+	version string
+	size    int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // of *cacheEntry, most recently used first
+}
+
+// cacheEntry is a cached response
+type cacheEntry struct {
+	key      string
+	response detectResponse
+}
+
+// newResponseCache returns a cache of up to size responses of the profiles identified by version
+func newResponseCache(size int, version string) *responseCache {
+	return &responseCache{version: version, size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// key returns the cache key of a detect request, a hash of the profile version, the text and
+// the query parameters that change the response
+func (c *responseCache) key(text, all, locale string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{c.version, all, locale, text}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// etag returns the ETag of the response of a cache key
+func (c *responseCache) etag(key string) string {
+	return `"` + key + `"`
+}
+
+// get returns the cached response of a key
+func (c *responseCache) get(key string) (detectResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return detectResponse{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheEntry).response, true
+}
+
+// add caches the response of a key, evicting the least recently used response if the cache is full
+func (c *responseCache) add(key string, response detectResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cacheEntry).response = response
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, response: response})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// ifNoneMatch tells whether an If-None-Match header matches etag
+func ifNoneMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// profilesVersion returns a hash of the languages and the minimum confidence of a detector, so
+// that cached responses and ETags change with the profiles
+func profilesVersion(detector *langdet.Detector) (string, error) {
+	data, err := json.Marshal(struct {
+		Languages         []langdet.Language
+		MinimumConfidence float32
+	}{detector.Snapshot(), detector.MinimumConfidence})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// calibrate learns a confidence calibration from a labeled dev set and writes it as JSON
+func calibrate(args []string) {
+	config := struct {
+		Profiles string `flag:"profiles,Directory, file or bundle with the profiles to calibrate, the embedded default profiles if empty"`
+		Config   string `flag:"config,JSON or YAML config file of the detector, instead of -profiles"`
+		Data     string `flag:"data,CSV or, for .tsv files, TSV file with a text and its expected language per row"`
+		Out      string `flag:"out,JSON file to write the calibration to"`
+	}{}
+	flags := flag.NewFlagSet("calibrate", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.Data == "" || config.Out == "" {
+		fatalf(exitUsage, "-data and -out are required arguments")
+	}
+
+	detector, err := loadDetector(config.Profiles, config.Config, 0)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	texts, expected, err := readTestSet(config.Data)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	samples := make([]langdet.CalibrationSample, len(texts))
+	for i, text := range texts {
+		samples[i] = detector.CalibrationSample(text, expected[i])
+	}
+	calibration := langdet.TrainCalibration(samples)
+	data, err := json.MarshalIndent(calibration, "", "  ")
+	if err != nil {
+		fatal(exitFailure, err)
+	}
+	if err := os.WriteFile(config.Out, append(data, '\n'), 0644); err != nil {
+		fatal(exitWrite, err)
+	}
+	printCalibration(os.Stdout, calibration)
+}
+
+// printCalibration prints the points of the curves of a calibration as a table
+func printCalibration(w io.Writer, calibration langdet.Calibration) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "LENGTH\tSAMPLES\tCONFIDENCE\tPROBABILITY\t")
+	minLength := 0
+	for _, curve := range calibration.Curves {
+		length := fmt.Sprintf("%d-%d", minLength, curve.MaxLength)
+		if curve.MaxLength == 0 {
+			length = fmt.Sprintf("%d+", minLength)
+		}
+		minLength = curve.MaxLength + 1
+		for i, point := range curve.Points {
+			if i > 0 {
+				length = ""
+			}
+			samples := ""
+			if i == 0 {
+				samples = fmt.Sprint(curve.Samples)
+			}
+			fmt.Fprintf(table, "%s\t%s\t%.2f\t%.2f\t\n", length, samples, point.Confidence, point.Probability)
+		}
+	}
+	table.Flush()
+}
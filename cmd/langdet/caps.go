@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// minCapsLetters is the minimum number of cased letters of an all-caps sentence,
+// so that acronyms alone don't make a sentence all-caps
+const minCapsLetters = 4
+
+// caseStats collects how much of a training corpus is written in upper case
+type caseStats struct {
+	Letters   int
+	Upper     int
+	Sentences int
+	AllCaps   int
+	Excluded  int
+}
+
+// add updates the statistics with a single abstract and returns it, without its all-caps
+// sentences (like headlines) if exclude is set
+func (s *caseStats) add(abstract string, exclude bool) string {
+	var kept strings.Builder
+	for _, sentence := range splitSentences(abstract) {
+		s.Sentences++
+		upper, cased := 0, 0
+		for _, r := range sentence {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			s.Letters++
+			if unicode.IsUpper(r) {
+				s.Upper++
+				upper++
+			}
+			if unicode.IsUpper(r) || unicode.IsLower(r) {
+				cased++
+			}
+		}
+		if cased >= minCapsLetters && upper == cased {
+			s.AllCaps++
+			if exclude {
+				s.Excluded++
+				continue
+			}
+		}
+		kept.WriteString(sentence)
+	}
+	return kept.String()
+}
+
+// merge adds the statistics of other to s
+func (s *caseStats) merge(other caseStats) {
+	s.Letters += other.Letters
+	s.Upper += other.Upper
+	s.Sentences += other.Sentences
+	s.AllCaps += other.AllCaps
+	s.Excluded += other.Excluded
+}
+
+// print writes a normalization report of the statistics to w
+func (s *caseStats) print(w io.Writer) {
+	if s.Letters == 0 || s.Sentences == 0 {
+		return
+	}
+	fmt.Fprintf(w, "upper case letters:  %.2f%%\n", 100*float64(s.Upper)/float64(s.Letters))
+	fmt.Fprintf(w, "all-caps sentences:  %d of %d (%.2f%%), %d excluded\n",
+		s.AllCaps, s.Sentences, 100*float64(s.AllCaps)/float64(s.Sentences), s.Excluded)
+}
+
+// splitSentences splits text after every sentence terminator or line break
+func splitSentences(text string) []string {
+	sentences := []string{}
+	start := 0
+	for i, r := range text {
+		switch r {
+		case '.', '!', '?', '\n':
+			sentences = append(sentences, text[start:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(text) {
+		sentences = append(sentences, text[start:])
+	}
+	return sentences
+}
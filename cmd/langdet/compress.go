@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"path"
+	"strings"
+)
+
+// compressions are the supported compressions with the magic bytes their streams start with, and
+// their readers
+var compressions = map[string]struct {
+	magic  []byte
+	reader func(r io.Reader) (io.Reader, error)
+}{
+	"gzip":  {magic: []byte{0x1f, 0x8b}, reader: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+	"bzip2": {magic: []byte("BZh"), reader: func(r io.Reader) (io.Reader, error) { return bzip2.NewReader(r), nil }},
+}
+
+// compressionOf returns the compression of a file name or URL path by its extension, .gz or .bz2,
+// or "" if it is not compressed
+func compressionOf(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".gz", ".gzip":
+		return "gzip"
+	case ".bz2", ".bzip2":
+		return "bzip2"
+	}
+	return ""
+}
+
+// compressionOfEncoding returns the compression of a Content-Encoding header, or ""
+func compressionOfEncoding(encoding string) string {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		return "gzip"
+	case "bzip2", "x-bzip2":
+		return "bzip2"
+	}
+	return ""
+}
+
+// decompress returns a reader of the decompressed content of rc, which closes rc. Streams that
+// don't start with the magic bytes of the compression, like responses that the HTTP client
+// already decompressed, are read as they are.
+func decompress(rc io.ReadCloser, compression string) (io.ReadCloser, error) {
+	c, ok := compressions[compression]
+	if !ok {
+		return rc, nil
+	}
+	buffered := bufio.NewReader(rc)
+	if magic, _ := buffered.Peek(len(c.magic)); !bytes.Equal(magic, c.magic) {
+		return struct {
+			io.Reader
+			io.Closer
+		}{buffered, rc}, nil
+	}
+	r, err := c.reader(buffered)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, rc}, nil
+}
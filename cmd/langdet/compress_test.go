@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const compressedDump = "<feed><doc><abstract>Compressed text</abstract></doc></feed>"
+
+// bzip2Dump is compressedDump compressed with bzip2, which the standard library can't write
+var bzip2Dump = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xf0, 0x0c, 0xac, 0x35, 0x00, 0x00,
+	0x04, 0x9d, 0x80, 0x40, 0x00, 0x80, 0x05, 0x08, 0x00, 0x3f, 0x02, 0xdc, 0x40, 0x20, 0x00, 0x50,
+	0xa6, 0x99, 0x18, 0x98, 0x98, 0x82, 0x52, 0x4f, 0x53, 0xf4, 0xa6, 0xd4, 0xd3, 0x1a, 0x4b, 0xbb,
+	0x52, 0x62, 0xd2, 0x76, 0x74, 0xf0, 0xb6, 0x49, 0xa2, 0xc7, 0xcc, 0x95, 0x39, 0xa3, 0xc9, 0x51,
+	0x7f, 0x4c, 0xee, 0x0a, 0xc6, 0x28, 0xe5, 0x1c, 0x42, 0xfc, 0x5d, 0xc9, 0x14, 0xe1, 0x42, 0x43,
+	0xc0, 0x32, 0xb0, 0xd4,
+}
+
+func gzipDump() []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	gz.Write([]byte(compressedDump))
+	gz.Close()
+	return buf.Bytes()
+}
+
+// readAbstracts returns the abstracts of a dump opened by open
+func readAbstracts(open func() (io.ReadCloser, error)) []string {
+	r, err := open()
+	So(err, ShouldBeNil)
+	defer r.Close()
+	abstracts := []string{}
+	_, err = processAbstracts(r, func(abstract string) bool {
+		abstracts = append(abstracts, abstract)
+		return true
+	})
+	So(err, ShouldBeNil)
+	return abstracts
+}
+
+func TestCompressedDumps(t *testing.T) {
+	Convey("Subject: Reading compressed dumps", t, func() {
+		dir := t.TempDir()
+		files := map[string][]byte{"dump.xml": []byte(compressedDump), "dump.xml.gz": gzipDump(), "dump.xml.bz2": bzip2Dump}
+		for name, data := range files {
+			So(os.WriteFile(filepath.Join(dir, name), data, 0644), ShouldBeNil)
+		}
+		Convey("Local files should be decompressed by their extension", func() {
+			for name := range files {
+				So(readAbstracts(openFile(filepath.Join(dir, name))), ShouldResemble, []string{"Compressed text"})
+			}
+		})
+		Convey("Downloads should be decompressed by their extension or Content-Encoding", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/encoded.xml" {
+					w.Header().Set("Content-Encoding", "bzip2")
+					w.Write(bzip2Dump)
+					return
+				}
+				http.ServeFile(w, r, filepath.Join(dir, r.URL.Path))
+			}))
+			defer server.Close()
+			for _, name := range []string{"dump.xml", "dump.xml.gz", "dump.xml.bz2", "encoded.xml"} {
+				So(readAbstracts(openURL(server.URL+"/"+name)), ShouldResemble, []string{"Compressed text"})
+			}
+		})
+		Convey("Mislabeled uncompressed files should be read as they are", func() {
+			name := filepath.Join(dir, "plain.xml.gz")
+			So(os.WriteFile(name, []byte(compressedDump), 0644), ShouldBeNil)
+			So(readAbstracts(openFile(name)), ShouldResemble, []string{"Compressed text"})
+		})
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// convert rewrites the profiles of a directory in the json or the binary format,
+// one <language>.json or <language>.bin file per language
+func convert(args []string) {
+	config := struct {
+		Profiles string `flag:"profiles,Directory with the profiles to convert"`
+		Out      string `flag:"out,Directory to write the converted profiles to"`
+		Format   string `flag:"format,Format of the converted profiles, json or binary"`
+	}{
+		Format: "binary",
+	}
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	autoflags.DefineFlagSet(fs, &config)
+	fs.Parse(args)
+	if config.Profiles == "" || config.Out == "" {
+		fatalf(exitUsage, "-profiles and -out are required arguments")
+	}
+
+	var marshal func(langdet.Language) ([]byte, error)
+	var ext string
+	switch strings.ToLower(config.Format) {
+	case "json":
+		marshal = func(lang langdet.Language) ([]byte, error) { return json.Marshal(lang) }
+		ext = ".json"
+	case "binary", "bin", "gob":
+		marshal = langdet.Language.MarshalBinary
+		ext = ".bin"
+	default:
+		fatalf(exitUsage, "unknown format %q, expected json or binary", config.Format)
+	}
+
+	detector := langdet.NewDetector()
+	if err := detector.LoadLanguagesFromDir(config.Profiles); err != nil {
+		fatal(exitCode(err), err)
+	}
+	if err := os.MkdirAll(config.Out, 0755); err != nil {
+		fatal(exitWrite, err)
+	}
+	for _, language := range detector.Snapshot() {
+		data, err := marshal(language)
+		if err != nil {
+			fatalf(exitFailure, "%s: %v", language.Name, err)
+		}
+		name := filepath.Join(config.Out, language.Name+ext)
+		if err := os.WriteFile(name, data, 0644); err != nil {
+			fatal(exitWrite, err)
+		}
+		fmt.Printf("%s: %d bytes written to %s\n", language.Name, len(data), name)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// corpusFormat is a format of the training corpora of train -corpus
+type corpusFormat struct {
+	// read calls feed with the sentences of the language lang of a corpus, until feed returns false
+	read func(r io.Reader, lang string, column int, feed func(string) bool) error
+	// url returns the URL of the corpus of a language, which is trained when train has no input
+	// files, nil if the corpus can't be downloaded
+	url func(lang string) string
+}
+
+// corpusFormats are the formats of train -corpus besides text
+var corpusFormats = map[string]corpusFormat{
+	"tatoeba":  {read: readTatoeba, url: tatoebaURL},
+	"opus":     {read: readMoses, url: opusURL},
+	"opus-tsv": {read: readOpusTSV},
+}
+
+// tatoebaURL returns the URL of the Tatoeba sentence export of a language, which is named by its
+// ISO 639-3 code
+func tatoebaURL(lang string) string {
+	code := tatoebaCode(lang)
+	return fmt.Sprintf("https://downloads.tatoeba.org/exports/per_language/%s/%s_sentences.tsv.bz2", code, code)
+}
+
+// opusURL returns the URL of the monolingual OpenSubtitles corpus of OPUS of a language, which is
+// named by its ISO 639-1 code
+func opusURL(lang string) string {
+	return fmt.Sprintf("https://object.pouta.csc.fi/OPUS-OpenSubtitles/v2018/mono/%s.txt.gz", langdet.NormalizeLanguageName(lang))
+}
+
+// tatoebaCode returns the ISO 639-3 code of a language, by which Tatoeba names languages, or the
+// language itself if it is unknown
+func tatoebaCode(lang string) string {
+	if tag, ok := langdet.LookupLanguageTag(lang); ok && tag.ISO6393 != "" {
+		return tag.ISO6393
+	}
+	return lang
+}
+
+// readTatoeba reads the sentences of a language from a Tatoeba sentence export, with lines of the
+// id, the ISO 639-3 code of the language and the text of a sentence, separated by tabs. Sentences
+// of other languages are skipped, so that the export of all languages can be read as well.
+func readTatoeba(r io.Reader, lang string, column int, feed func(string) bool) error {
+	code := tatoebaCode(lang)
+	return scanLines(r, func(line string) bool {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 || fields[1] != code {
+			return true
+		}
+		return feed(fields[2])
+	})
+}
+
+// readMoses reads the sentences of an OPUS corpus of the Moses format, one sentence per line of
+// the file of a language, without the markup of subtitles
+func readMoses(r io.Reader, lang string, column int, feed func(string) bool) error {
+	return scanLines(r, func(line string) bool {
+		if sentence := subtitleText(line); sentence != "" {
+			return feed(sentence)
+		}
+		return true
+	})
+}
+
+// readOpusTSV reads the sentences of the language of a column, starting at 1, of an OPUS corpus
+// of the TSV format, with the sentences of the languages of a sentence pair separated by tabs
+func readOpusTSV(r io.Reader, lang string, column int, feed func(string) bool) error {
+	if column < 1 {
+		column = 1
+	}
+	return scanLines(r, func(line string) bool {
+		fields := strings.Split(line, "\t")
+		if len(fields) < column {
+			return true
+		}
+		if sentence := subtitleText(fields[column-1]); sentence != "" {
+			return feed(sentence)
+		}
+		return true
+	})
+}
+
+// scanLines calls handle with the lines of r that are not empty, until handle returns false
+func scanLines(r io.Reader, handle func(line string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxTrainLineSize)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !handle(line) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// subtitleMarkup matches the formatting tags like <i> and the styling codes like {\an8} of subtitles
+var subtitleMarkup = regexp.MustCompile(`<[^>]*>|\{[^}]*\}`)
+
+// subtitleText returns the text of a subtitle line, without its markup and the dashes of dialogs
+func subtitleText(line string) string {
+	line = subtitleMarkup.ReplaceAllString(line, "")
+	line = strings.TrimLeft(strings.TrimSpace(line), "-–— ")
+	return strings.TrimSpace(line)
+}
+
+// sentenceFilter drops duplicate sentences and sentences of a length out of bounds from a
+// training corpus, see train -dedup, -min-length and -max-length
+type sentenceFilter struct {
+	dedup                bool
+	minLength, maxLength int
+	seen                 map[uint64]struct{}
+	duplicates, outside  int
+}
+
+// keep tells whether a sentence is trained. The lengths are in runes, a maxLength of 0 is no limit.
+func (f *sentenceFilter) keep(sentence string) bool {
+	length := utf8.RuneCountInString(sentence)
+	if length < f.minLength || (f.maxLength > 0 && length > f.maxLength) {
+		f.outside++
+		return false
+	}
+	if !f.dedup {
+		return true
+	}
+	// sentences are remembered by their hashes, the rare collisions only drop a sentence
+	hash := fnv.New64a()
+	io.WriteString(hash, sentence)
+	key := hash.Sum64()
+	if _, ok := f.seen[key]; ok {
+		f.duplicates++
+		return false
+	}
+	if f.seen == nil {
+		f.seen = make(map[uint64]struct{})
+	}
+	f.seen[key] = struct{}{}
+	return true
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCorpora(t *testing.T) {
+	Convey("Subject: Read the sentences of training corpora", t, func() {
+		read := func(format corpusFormat, input, lang string, column int) []string {
+			sentences := []string{}
+			err := format.read(strings.NewReader(input), lang, column, func(sentence string) bool {
+				sentences = append(sentences, sentence)
+				return true
+			})
+			So(err, ShouldBeNil)
+			return sentences
+		}
+
+		Convey("Tatoeba exports should yield the sentences of the language only", func() {
+			input := "1\ttur\tMerhaba dünya.\n2\teng\tHello world.\n\n3\ttur\tNasılsın?\tignored\n"
+			So(read(corpusFormats["tatoeba"], input, "tr", 0), ShouldResemble, []string{"Merhaba dünya.", "Nasılsın?\tignored"})
+			So(tatoebaURL("tr"), ShouldEndWith, "/tur/tur_sentences.tsv.bz2")
+		})
+		Convey("OPUS subtitles should be read without their markup", func() {
+			input := "<i>Nereye gidiyorsun?</i>\n- Eve.\n{\\an8}Tamam\n<i></i>\n"
+			So(read(corpusFormats["opus"], input, "tr", 0), ShouldResemble, []string{"Nereye gidiyorsun?", "Eve.", "Tamam"})
+			So(opusURL("tur"), ShouldEndWith, "/mono/tr.txt.gz")
+		})
+		Convey("OPUS TSV files should yield the sentences of the column", func() {
+			input := "Where are you going?\tNereye gidiyorsun?\nonly one column\n"
+			So(read(corpusFormats["opus-tsv"], input, "tr", 2), ShouldResemble, []string{"Nereye gidiyorsun?"})
+			So(read(corpusFormats["opus-tsv"], input, "en", 0), ShouldResemble, []string{"Where are you going?", "only one column"})
+		})
+		Convey("Duplicates and sentences out of the length limits should be skipped", func() {
+			filter := &sentenceFilter{dedup: true, minLength: 3, maxLength: 10}
+			kept := []string{}
+			for _, sentence := range []string{"Tamam", "ok", "Tamam", "Çok uzun bir cümle", "Evet"} {
+				if filter.keep(sentence) {
+					kept = append(kept, sentence)
+				}
+			}
+			So(kept, ShouldResemble, []string{"Tamam", "Evet"})
+			So(filter.duplicates, ShouldEqual, 1)
+			So(filter.outside, ShouldEqual, 2)
+		})
+	})
+}
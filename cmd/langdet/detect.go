@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/charset"
+	"github.com/imankulov/go-lang-detector/langdet/yamlconfig"
+)
+
+// detect prints the language of the text of stdin or of every file argument, with the profiles
+// of a directory, of a config file or the embedded default profiles
+func detect(args []string) {
+	config := struct {
+		Profiles      string  `flag:"profiles,Directory, file or bundle with the profiles to detect, the embedded default profiles if empty"`
+		Config        string  `flag:"config,JSON or YAML config file of the detector, instead of -profiles"`
+		JSON          bool    `flag:"json,Print the DetectionResults of all languages as a JSON array per input"`
+		MinConfidence float64 `flag:"min-confidence,Minimum confidence of detected languages, 0 for the default"`
+		Limit         int64   `flag:"limit,Maximum number of bytes read from every input"`
+		Tokenize      bool    `flag:"tokenize,Drop URLs, email addresses, mentions, numbers and punctuation before the detection"`
+		Lowercase     bool    `flag:"lowercase,Lowercase the texts before the detection, with -tokenize"`
+		Format        string  `flag:"format,Format of the inputs: text, html or markdown"`
+		Charsets      bool    `flag:"charsets,Decode inputs that are no valid UTF-8 from the legacy charset that detects most confidently"`
+	}{
+		Limit:  langdet.DefaultMaxDetectBytes,
+		Format: "text",
+	}
+	flags := flag.NewFlagSet("detect", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+
+	detector, err := loadDetector(config.Profiles, config.Config, config.MinConfidence)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	extract, ok := inputFormats[strings.ToLower(config.Format)]
+	if !ok {
+		fatalf(exitUsage, "unknown format %q, expected text, html or markdown", config.Format)
+	}
+	if config.Tokenize {
+		detector.Tokenizer = langdet.WordTokenizer{Lowercase: config.Lowercase}
+	}
+	if config.Charsets {
+		detector.Charsets = charset.Legacy
+	}
+
+	if flags.NArg() == 0 {
+		if err := detectInput(&detector, os.Stdin, "", extract, config.JSON, config.Limit); err != nil {
+			fatal(exitCode(err), err)
+		}
+		return
+	}
+	for _, name := range flags.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		prefix := ""
+		if flags.NArg() > 1 {
+			prefix = name + ": "
+		}
+		err = detectInput(&detector, f, prefix, extract, config.JSON, config.Limit)
+		f.Close()
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+	}
+}
+
+// loadDetector returns a detector with the profiles of a config file, of a directory, file or
+// bundle, or the embedded default profiles, in this order
+func loadDetector(profiles, configPath string, minConfidence float64) (langdet.Detector, error) {
+	var detector langdet.Detector
+	var err error
+	switch {
+	case isYAML(configPath):
+		detector, err = yamlconfig.Load(configPath)
+	case configPath != "":
+		detector, err = langdet.LoadConfig(configPath)
+	case profiles != "":
+		detector, err = langdet.Config{Profiles: []string{profiles}}.NewDetector()
+	default:
+		detector = langdet.NewDefaultLanguages()
+	}
+	if err != nil {
+		return detector, err
+	}
+	if len(detector.Snapshot()) == 0 {
+		return detector, errors.New("no profiles to detect with, use -profiles or -config")
+	}
+	if minConfidence > 0 {
+		detector.MinimumConfidence = float32(minConfidence)
+	}
+	return detector, nil
+}
+
+// isYAML tells whether a config file is a YAML file by its extension
+func isYAML(configPath string) bool {
+	ext := strings.ToLower(filepath.Ext(configPath))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// inputFormats extract the text of the inputs of the formats of detect -format
+var inputFormats = map[string]func([]byte) string{
+	"text":     func(data []byte) string { return langdet.StripPreamble(string(data)) },
+	"html":     langdet.HTMLText,
+	"markdown": func(data []byte) string { return langdet.MarkdownText(string(data)) },
+	"md":       func(data []byte) string { return langdet.MarkdownText(string(data)) },
+}
+
+// detectInput prints the closest language of the text extracted from the first limit bytes of an
+// input, decoded from the charsets of the detector if it is no valid UTF-8, after prefix, or the DetectionResults of all languages as a JSON array on a single line
+func detectInput(detector *langdet.Detector, r io.Reader, prefix string, extract func([]byte) string, asJSON bool, limit int64) error {
+	data, err := io.ReadAll(io.LimitReader(r, limit))
+	if err != nil {
+		return err
+	}
+	if len(detector.Charsets) > 0 && !utf8.Valid(data) {
+		text, _, err := detector.DecodeBytes(data)
+		if err != nil {
+			return err
+		}
+		data = []byte(text)
+	}
+	text := extract(data)
+	if !asJSON {
+		_, err = fmt.Printf("%s%s\n", prefix, detector.GetClosestLanguage(text))
+		return err
+	}
+	results, err := json.Marshal(detector.GetLanguages(text))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Printf("%s\n", results)
+	return err
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// digest prints the SHA-256 checksums of binary profiles and compiled bundles in the format of
+// sha256sum, after checking that they are reproducible, or verifies files against the checksums
+// of a previous run with -check
+func digest(args []string) {
+	config := struct {
+		Check string `flag:"check,Checksum file of a previous run, e.g. on another architecture, to verify the files against"`
+	}{}
+	flags := flag.NewFlagSet("digest", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.Check != "" {
+		f, err := os.Open(config.Check)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		defer f.Close()
+		failed, err := checkDigests(os.Stdout, f)
+		if err != nil {
+			fatal(exitParse, fmt.Errorf("%s: %w", config.Check, err))
+		}
+		if failed > 0 {
+			fatalf(exitFailure, "%d files did not match their checksums", failed)
+		}
+		return
+	}
+	if flags.NArg() == 0 {
+		fatalf(exitUsage, "profile or bundle files, or -check are required arguments")
+	}
+	for _, name := range flags.Args() {
+		sum, err := artifactDigest(name)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		fmt.Printf("%s  %s\n", sum, name)
+	}
+}
+
+// artifactDigest returns the hex encoded SHA-256 checksum of a file, which must be reproducible if
+// it is a binary profile or a compiled bundle, see langdet.CanonicalEncoding
+func artifactDigest(name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	canonical, err := langdet.CanonicalEncoding(data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	if !bytes.Equal(canonical, data) {
+		return "", fmt.Errorf("%s is not reproducible, rebuild it with this version", name)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// checkDigests verifies the files of the lines "<checksum>  <file>" of r, prints "<file>: OK" or
+// "<file>: FAILED" for every file and returns the number of failed files
+func checkDigests(w io.Writer, r io.Reader) (int, error) {
+	failed := 0
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		expected, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			return failed, fmt.Errorf("line %d is not a checksum and a file name", line)
+		}
+		status := "OK"
+		if sum, err := artifactDigest(name); err != nil || sum != strings.ToLower(expected) {
+			status = "FAILED"
+			failed++
+		}
+		fmt.Fprintf(w, "%s: %s\n", name, status)
+	}
+	return failed, scanner.Err()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDigest(t *testing.T) {
+	Convey("Subject: Reproducible bundles and their checksums", t, func() {
+		t.Setenv("SOURCE_DATE_EPOCH", "1717200000")
+		dir := t.TempDir()
+		profile := filepath.Join(dir, "en.json")
+		data, err := json.Marshal(langdet.Analyze("the quick brown fox", "en"))
+		So(err, ShouldBeNil)
+		So(os.WriteFile(profile, data, 0644), ShouldBeNil)
+		first, second := filepath.Join(dir, "first.bin"), filepath.Join(dir, "second.bin")
+		manifest, err := createBundle(first, "", []string{profile}, nil)
+		So(err, ShouldBeNil)
+		_, err = createBundle(second, "", []string{profile}, nil)
+		So(err, ShouldBeNil)
+
+		Convey("Bundles of the same profiles should have the same bytes", func() {
+			So(manifest.Created.Unix(), ShouldEqual, 1717200000)
+			So(manifest.Profiles[0].Trained.Equal(manifest.Created), ShouldBeTrue)
+			firstSum, err := artifactDigest(first)
+			So(err, ShouldBeNil)
+			secondSum, err := artifactDigest(second)
+			So(err, ShouldBeNil)
+			So(firstSum, ShouldEqual, secondSum)
+			So(firstSum, ShouldHaveLength, 64)
+		})
+		Convey("Checksum files should verify the files", func() {
+			sum, err := artifactDigest(first)
+			So(err, ShouldBeNil)
+			out := &bytes.Buffer{}
+			failed, err := checkDigests(out, strings.NewReader(fmt.Sprintf("%s  %s\n%s  %s\n", sum, first, strings.Repeat("0", 64), second)))
+			So(err, ShouldBeNil)
+			So(failed, ShouldEqual, 1)
+			So(out.String(), ShouldEqual, first+": OK\n"+second+": FAILED\n")
+			_, err = checkDigests(out, strings.NewReader("not a checksum\n"))
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Bundles that are not reproducible should be rejected", func() {
+			_, err := artifactDigest(filepath.Join(dir, "missing.bin"))
+			So(err, ShouldNotBeNil)
+			data, err := os.ReadFile(first)
+			So(err, ShouldBeNil)
+			So(os.WriteFile(second, append(data, 0), 0644), ShouldBeNil)
+			_, err = artifactDigest(second)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Invalid SOURCE_DATE_EPOCHs should be errors", func() {
+			t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+			_, err := createBundle(first, "", []string{profile}, nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// discriminate learns a discriminator of a pair of confusable languages from the labeled texts of
+// a data set and writes it as JSON
+func discriminate(args []string) {
+	config := struct {
+		Data        string `flag:"data,CSV or, for .tsv files, TSV file with a text and its expected language per row"`
+		First       string `flag:"first,Name of the first language of the pair"`
+		Second      string `flag:"second,Name of the second language of the pair"`
+		Out         string `flag:"out,JSON file to write the discriminator to"`
+		Depth       int    `flag:"depth,N-gram depth of the features, 0 for the default depth"`
+		MaxFeatures int    `flag:"max-features,Number of discriminative n-grams, 0 for the default"`
+		Epochs      int    `flag:"epochs,Number of passes over the texts, 0 for the default"`
+	}{}
+	flags := flag.NewFlagSet("discriminate", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.Data == "" || config.First == "" || config.Second == "" || config.Out == "" {
+		fatalf(exitUsage, "-data, -first, -second and -out are required arguments")
+	}
+
+	texts, expected, err := readTestSet(config.Data)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	first, second := pairTexts(texts, expected, config.First, config.Second)
+	pair := langdet.LanguagePair{First: config.First, Second: config.Second}
+	options := langdet.DiscriminatorOptions{Depth: config.Depth, MaxFeatures: config.MaxFeatures, Epochs: config.Epochs}
+	discriminator, err := langdet.TrainDiscriminator(pair, first, second, options)
+	if err != nil {
+		fatal(exitParse, err)
+	}
+	data, err := json.MarshalIndent(discriminator, "", "  ")
+	if err != nil {
+		fatal(exitFailure, err)
+	}
+	if err := os.WriteFile(config.Out, append(data, '\n'), 0644); err != nil {
+		fatal(exitWrite, err)
+	}
+	fmt.Printf("%d %s and %d %s texts, %d features, %.1f%% of the texts discriminated correctly\n",
+		len(first), config.First, len(second), config.Second, len(discriminator.Weights), 100*discriminatorAccuracy(discriminator, first, second))
+}
+
+// pairTexts returns the texts of the two languages of a pair of a labeled data set
+func pairTexts(texts, expected []string, firstName, secondName string) (first, second []string) {
+	for i, text := range texts {
+		switch expected[i] {
+		case firstName:
+			first = append(first, text)
+		case secondName:
+			second = append(second, text)
+		}
+	}
+	return first, second
+}
+
+// discriminatorAccuracy returns the fraction of the texts of both languages that a discriminator
+// assigns to their language, texts without features count as wrong
+func discriminatorAccuracy(discriminator langdet.Discriminator, first, second []string) float64 {
+	correct := 0
+	for _, text := range first {
+		if probability, ok := discriminator.Discriminate(text); ok && probability > 0.5 {
+			correct++
+		}
+	}
+	for _, text := range second {
+		if probability, ok := discriminator.Discriminate(text); ok && probability < 0.5 {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(first)+len(second))
+}
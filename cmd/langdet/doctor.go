@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// minDoctorRanks is the number of ranks below which doctor warns about a small profile
+const minDoctorRanks = 1000
+
+// doctorSamples are the sample sentences of common languages by their ISO 639-1 codes, which doctor
+// detects with the profiles of their language
+var doctorSamples = map[string][]string{
+	"ar": {"ذهبت إلى السوق صباح اليوم لشراء الخبز والحليب.", "هل يمكنك أن تساعدني في العثور على المحطة؟"},
+	"de": {"Ich bin heute Morgen zum Markt gegangen, um Brot und Milch zu kaufen.", "Kannst du mir helfen, den Bahnhof zu finden?"},
+	"en": {"I went to the market this morning to buy bread and milk.", "Can you help me find the train station?"},
+	"es": {"Esta mañana fui al mercado a comprar pan y leche.", "¿Puedes ayudarme a encontrar la estación de tren?"},
+	"fr": {"Je suis allé au marché ce matin pour acheter du pain et du lait.", "Peux-tu m'aider à trouver la gare ?"},
+	"he": {"הלכתי לשוק הבוקר כדי לקנות לחם וחלב.", "אתה יכול לעזור לי למצוא את תחנת הרכבת?"},
+	"it": {"Stamattina sono andato al mercato a comprare il pane e il latte.", "Puoi aiutarmi a trovare la stazione dei treni?"},
+	"nl": {"Ik ben vanochtend naar de markt gegaan om brood en melk te kopen.", "Kun je me helpen het treinstation te vinden?"},
+	"pl": {"Dziś rano poszedłem na targ, żeby kupić chleb i mleko.", "Czy możesz mi pomóc znaleźć dworzec kolejowy?"},
+	"pt": {"Hoje de manhã fui ao mercado comprar pão e leite.", "Você pode me ajudar a encontrar a estação de trem?"},
+	"ru": {"Сегодня утром я ходил на рынок, чтобы купить хлеб и молоко.", "Ты можешь помочь мне найти вокзал?"},
+	"sv": {"Jag gick till marknaden i morse för att köpa bröd och mjölk.", "Kan du hjälpa mig att hitta tågstationen?"},
+	"tr": {"Bu sabah ekmek ve süt almak için pazara gittim.", "Tren istasyonunu bulmama yardım edebilir misin?"},
+	"uk": {"Сьогодні вранці я ходив на ринок, щоб купити хліб і молоко.", "Чи можеш ти допомогти мені знайти вокзал?"},
+}
+
+// Levels of the findings of doctor
+const (
+	doctorError   = "error"
+	doctorWarning = "warning"
+)
+
+// doctorFinding is a problem of a profile or a pair of profiles found by doctor
+type doctorFinding struct {
+	Level   string
+	Subject string
+	Message string
+}
+
+// doctorOptions are the thresholds of the checks of doctor
+type doctorOptions struct {
+	maxOverlap float64
+	ranks      int
+}
+
+// doctor checks the profiles of a directory before they are deployed: every profile must load and
+// detect the sample sentences of its language, and pairs of profiles must not overlap suspiciously
+func doctor(args []string) {
+	config := struct {
+		Dir        string  `flag:"dir,Directory with the profiles to check"`
+		MaxOverlap float64 `flag:"max-overlap,Warn about pairs of profiles whose top ranks overlap more than this fraction"`
+		Ranks      int     `flag:"ranks,Number of top ranks of the profiles compared for their overlap"`
+		Strict     bool    `flag:"strict,Fail on warnings too"`
+	}{MaxOverlap: 0.9, Ranks: 300}
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.Dir == "" {
+		fatalf(exitUsage, "-dir is a required argument")
+	}
+	entries, err := os.ReadDir(config.Dir)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	files := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, filepath.Join(config.Dir, entry.Name()))
+		}
+	}
+
+	findings := examineProfiles(files, doctorOptions{maxOverlap: config.MaxOverlap, ranks: config.Ranks})
+	errs, warnings := printFindings(os.Stdout, findings)
+	fmt.Printf("%d profiles checked: %d errors, %d warnings\n", len(files), errs, warnings)
+	if errs > 0 || (config.Strict && warnings > 0) {
+		os.Exit(exitFailure)
+	}
+}
+
+// examineProfiles returns the findings of the checks of profile files
+func examineProfiles(files []string, options doctorOptions) []doctorFinding {
+	findings := []doctorFinding{}
+	languages := []langdet.Language{}
+	for _, file := range files {
+		language, err := readProfile(file)
+		if err != nil {
+			findings = append(findings, doctorFinding{Level: doctorError, Subject: file, Message: err.Error()})
+			continue
+		}
+		languages = append(languages, language)
+		base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if _, known := langdet.LookupLanguageTag(base); known && !sameLanguage(base, language.Name) {
+			findings = append(findings, doctorFinding{Level: doctorWarning, Subject: file, Message: fmt.Sprintf("the file is named %s, but has the profile of %s", base, language.Name)})
+		}
+		if len(language.Profile) < minDoctorRanks {
+			findings = append(findings, doctorFinding{Level: doctorWarning, Subject: language.Name, Message: fmt.Sprintf("the profile has %d ranks only", len(language.Profile))})
+		}
+	}
+
+	detector := langdet.NewDetectorWithLanguages(languages...)
+	for _, issue := range detector.Audit() {
+		findings = append(findings, doctorFinding{Level: doctorWarning, Subject: strings.Join(issue.Languages, ", "), Message: issue.String()})
+	}
+	for _, language := range languages {
+		samples := language.Samples
+		if tag, ok := langdet.LookupLanguageTag(language.Name); ok {
+			samples = append(append([]string{}, doctorSamples[tag.ISO6391]...), samples...)
+		}
+		if len(samples) == 0 {
+			findings = append(findings, doctorFinding{Level: doctorWarning, Subject: language.Name, Message: "no sample sentences to detect"})
+		}
+		for _, sample := range samples {
+			if results := detector.GetLanguages(sample); len(results) > 0 && results[0].Name != language.Name {
+				findings = append(findings, doctorFinding{Level: doctorError, Subject: language.Name, Message: fmt.Sprintf("detected as %s: %s", results[0].Name, sample)})
+			}
+		}
+	}
+	for i := range languages {
+		for j := i + 1; j < len(languages); j++ {
+			a, b := languages[i], languages[j]
+			overlap := langdet.ProfileOverlap(a, b, options.ranks)
+			if reverse := langdet.ProfileOverlap(b, a, options.ranks); reverse > overlap {
+				overlap = reverse
+			}
+			if overlap > options.maxOverlap {
+				findings = append(findings, doctorFinding{Level: doctorWarning, Subject: a.Name + ", " + b.Name, Message: fmt.Sprintf("%.0f%% of the top %d ranks overlap, the profiles may be copies or mislabeled", overlap*100, options.ranks)})
+			}
+		}
+	}
+	return findings
+}
+
+// printFindings prints the findings, errors first, and returns the number of errors and warnings
+func printFindings(w io.Writer, findings []doctorFinding) (errs, warnings int) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Level == doctorError && findings[j].Level != doctorError
+	})
+	for _, finding := range findings {
+		fmt.Fprintf(w, "%s: %s: %s\n", finding.Level, finding.Subject, finding.Message)
+		if finding.Level == doctorError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	return errs, warnings
+}
@@ -0,0 +1,73 @@
+//go:build !langdet_nodefaults
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDoctor(t *testing.T) {
+	Convey("Subject: Check a directory of profiles", t, func() {
+		dir := t.TempDir()
+		profiles := map[string]langdet.Language{}
+		for _, language := range langdet.EmbeddedLanguages() {
+			profiles[language.Name] = language
+		}
+		write := func(name string, language langdet.Language) string {
+			data, err := json.Marshal(language)
+			So(err, ShouldBeNil)
+			path := filepath.Join(dir, name)
+			So(os.WriteFile(path, data, 0644), ShouldBeNil)
+			return path
+		}
+		files := []string{write("en.json", profiles["en"]), write("fr.json", profiles["fr"]), write("de.json", profiles["de"])}
+		options := doctorOptions{maxOverlap: 0.9, ranks: 300}
+		messages := func(findings []doctorFinding, level string) []string {
+			found := []string{}
+			for _, finding := range findings {
+				if finding.Level == level {
+					found = append(found, finding.Subject+": "+finding.Message)
+				}
+			}
+			return found
+		}
+
+		Convey("Healthy profiles should detect their samples", func() {
+			So(messages(examineProfiles(files, options), doctorError), ShouldBeEmpty)
+		})
+		Convey("Broken profiles should be errors", func() {
+			broken := filepath.Join(dir, "broken.json")
+			So(os.WriteFile(broken, []byte(`{"Name": "xx"`), 0644), ShouldBeNil)
+			errs := messages(examineProfiles(append(files, broken), options), doctorError)
+			So(errs, ShouldHaveLength, 1)
+			So(errs[0], ShouldStartWith, broken)
+		})
+		Convey("Mislabeled profiles should misdetect their samples and overlap", func() {
+			mislabeled := profiles["en"]
+			mislabeled.Name = "es"
+			findings := examineProfiles(append(files, write("es.json", mislabeled)), options)
+			So(strings.Join(messages(findings, doctorError), "\n"), ShouldContainSubstring, "es: detected as")
+			So(strings.Join(messages(findings, doctorWarning), "\n"), ShouldContainSubstring, "en, es: 100% of the top 300 ranks overlap")
+		})
+		Convey("Profiles trained with the depths of their scripts should not be warned about", func() {
+			trained := []string{}
+			for _, code := range []string{"en", "ru"} {
+				trainer := &langdet.Trainer{}
+				trainer.Feed(strings.Join(doctorSamples[code], " "))
+				trained = append(trained, write(code+"-trained.json", trainer.Build(code)))
+			}
+			So(strings.Join(messages(examineProfiles(trained, options), doctorWarning), "\n"), ShouldNotContainSubstring, langdet.AuditMixedDepth)
+		})
+		Convey("Files named after another language should be warnings", func() {
+			findings := examineProfiles([]string{write("it.json", profiles["en"])}, options)
+			So(strings.Join(messages(findings, doctorWarning), "\n"), ShouldContainSubstring, "the file is named it, but has the profile of en")
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultDumpMirror is the Wikimedia dump server, which is tried after the -mirror servers
+const defaultDumpMirror = "https://dumps.wikimedia.org/"
+
+// dumpDateLink matches the links to the dated dumps of a wiki's dump index
+var dumpDateLink = regexp.MustCompile(`href="(\d{8})/?"`)
+
+// downloadAttempts is the number of times a dump download is resumed after a failure
+const downloadAttempts = 3
+
+// wikiDBName returns the database name of the wikipedia of a language code, like "zh_yuewiki"
+// for "zh-yue"
+func wikiDBName(lang string) string {
+	return strings.ReplaceAll(strings.ToLower(lang), "-", "_") + "wiki"
+}
+
+// latestDumps returns the URLs of the abstract files of the newest dump of the wikipedia of lang
+// that has them, from the first of the mirrors that has one. Dumps in progress don't have
+// abstract files yet, so older dumps are tried too.
+func latestDumps(client *http.Client, mirrors []string, lang string) ([]string, error) {
+	db := wikiDBName(lang)
+	var err error
+	for _, mirror := range mirrors {
+		var urls []string
+		if urls, err = latestMirrorDumps(client, strings.TrimSuffix(mirror, "/")+"/"+db+"/", db); err == nil {
+			return urls, nil
+		}
+		log.Printf("WARNING: no abstract dump of %s on %s: %v", db, mirror, err)
+	}
+	return nil, fmt.Errorf("no abstract dump of %s found: %w", db, err)
+}
+
+// latestMirrorDumps returns the URLs of the abstract files of the newest dump of the dump index at
+// base that has them
+func latestMirrorDumps(client *http.Client, base, db string) ([]string, error) {
+	index, err := getPage(client, base)
+	if err != nil {
+		return nil, err
+	}
+	dates := []string{}
+	for _, match := range dumpDateLink.FindAllStringSubmatch(index, -1) {
+		dates = append(dates, match[1])
+	}
+	if len(dates) == 0 {
+		return nil, fmt.Errorf("%s lists no dumps", base)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+	for _, date := range dates {
+		page, err := getPage(client, base+date+"/")
+		if err != nil {
+			return nil, err
+		}
+		if files := abstractFiles(page, db, date); len(files) > 0 {
+			urls := make([]string, len(files))
+			for i, file := range files {
+				urls[i] = base + date + "/" + file
+			}
+			return urls, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no dump with abstracts", base)
+}
+
+// abstractFiles returns the sorted names of the abstract files linked by the dump page of a date,
+// of one or many shards, preferring uncompressed files to compressed ones
+func abstractFiles(page, db, date string) []string {
+	link := regexp.MustCompile(`href="(?:[^"]*/)?(` + regexp.QuoteMeta(db+"-"+date+"-abstract") + `\d*\.xml(\.gz)?)"`)
+	plain, compressed := map[string]bool{}, map[string]bool{}
+	for _, match := range link.FindAllStringSubmatch(page, -1) {
+		if match[2] == "" {
+			plain[match[1]] = true
+		} else {
+			compressed[match[1]] = true
+		}
+	}
+	files := []string{}
+	for name := range compressed {
+		if !plain[strings.TrimSuffix(name, ".gz")] {
+			files = append(files, name)
+		}
+	}
+	for name := range plain {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// getPage returns the body of a GET request of url
+func getPage(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &httpStatusError{URL: url, Status: resp.Status, Code: resp.StatusCode}
+	}
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+// downloadDump downloads url into dir and returns the name of the file. Downloads are written to
+// a .part file first, which later calls resume with a range request, and existing complete
+// downloads are reused.
+func downloadDump(client *http.Client, url, dir string) (string, error) {
+	name := filepath.Join(dir, path.Base(url))
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	var err error
+	for attempt := 0; attempt < downloadAttempts; attempt++ {
+		if err = resumeDownload(client, url, name+".part"); err == nil {
+			return name, os.Rename(name+".part", name)
+		}
+		if exitCode(err) != exitNetwork && !errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+	}
+	return "", err
+}
+
+// resumeDownload downloads url to the file part, continuing after the bytes it already has if the
+// server supports range requests
+func resumeDownload(client *http.Client, url, part string) error {
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// the server ignored the range, start over
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the part is already complete
+		return nil
+	default:
+		return &httpStatusError{URL: url, Status: resp.Status, Code: resp.StatusCode}
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLatestDumps(t *testing.T) {
+	Convey("Subject: Looking up the newest abstract dump", t, func() {
+		pages := map[string]string{
+			"/enwiki/": `<a href="20240501/">20240501/</a> <a href="20240601/">20240601/</a> <a href="latest/">latest/</a>`,
+			"/enwiki/20240501/": `<a href="/enwiki/20240501/enwiki-20240501-abstract1.xml">1</a>
+				<a href="/enwiki/20240501/enwiki-20240501-abstract2.xml">2</a>
+				<a href="/enwiki/20240501/enwiki-20240501-abstract2.xml.gz">2</a>
+				<a href="/enwiki/20240501/enwiki-20240501-pages-articles.xml.bz2">articles</a>`,
+			// a dump in progress without abstracts yet
+			"/enwiki/20240601/": `<a href="/enwiki/20240601/enwiki-20240601-pages-articles.xml.bz2">articles</a>`,
+		}
+		mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, ok := pages[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(page))
+		}))
+		defer mirror.Close()
+		broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer broken.Close()
+
+		Convey("The newest dump with abstracts should be found on the first working mirror", func() {
+			urls, err := latestDumps(http.DefaultClient, []string{broken.URL, mirror.URL + "/"}, "en")
+			So(err, ShouldBeNil)
+			So(urls, ShouldResemble, []string{
+				mirror.URL + "/enwiki/20240501/enwiki-20240501-abstract1.xml",
+				mirror.URL + "/enwiki/20240501/enwiki-20240501-abstract2.xml",
+			})
+		})
+		Convey("Failures of all mirrors should keep the class of the last failure", func() {
+			_, err := latestDumps(http.DefaultClient, []string{mirror.URL, broken.URL}, "de")
+			So(err, ShouldNotBeNil)
+			So(exitCode(err), ShouldEqual, exitNetwork)
+		})
+		Convey("Language codes should map to database names", func() {
+			So(wikiDBName("zh-yue"), ShouldEqual, "zh_yuewiki")
+		})
+	})
+}
+
+func TestDownloadDump(t *testing.T) {
+	Convey("Subject: Resuming dump downloads", t, func() {
+		content := bytes.Repeat([]byte("<doc><abstract>Some text</abstract></doc>\n"), 100)
+		ranges := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ranges = append(ranges, r.Header.Get("Range"))
+			http.ServeContent(w, r, "dump.xml", time.Time{}, bytes.NewReader(content))
+		}))
+		defer server.Close()
+		dir := t.TempDir()
+
+		Convey("A partial download should be continued with a range request", func() {
+			part := filepath.Join(dir, "dump.xml.part")
+			So(os.WriteFile(part, content[:1000], 0644), ShouldBeNil)
+			name, err := downloadDump(http.DefaultClient, server.URL+"/dump.xml", dir)
+			So(err, ShouldBeNil)
+			So(ranges, ShouldResemble, []string{"bytes=1000-"})
+			data, err := os.ReadFile(name)
+			So(err, ShouldBeNil)
+			So(bytes.Equal(data, content), ShouldBeTrue)
+			_, err = os.Stat(part)
+			So(os.IsNotExist(err), ShouldBeTrue)
+
+			Convey("and a complete download should be reused", func() {
+				again, err := downloadDump(http.DefaultClient, server.URL+"/dump.xml", dir)
+				So(err, ShouldBeNil)
+				So(again, ShouldEqual, name)
+				So(len(ranges), ShouldEqual, 1)
+			})
+		})
+		Convey("Missing dumps should not be retried", func() {
+			requests := 0
+			missing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				http.NotFound(w, r)
+			}))
+			defer missing.Close()
+			_, err := downloadDump(http.DefaultClient, missing.URL+"/dump.xml", filepath.Join(dir, "missing"))
+			So(exitCode(err), ShouldEqual, exitFailure)
+			So(requests, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// Exit codes of the langdet command, so that scripts wrapping it can tell transient failures,
+// which may succeed when retried, from permanent ones
+const (
+	// exitFailure is any other failure
+	exitFailure = 1
+	// exitUsage is an invalid flag or argument, like a missing input file
+	exitUsage = 2
+	// exitNetwork is a failed download or a server error, which is usually transient
+	exitNetwork = 3
+	// exitParse is an input, corpus or profile that could not be parsed
+	exitParse = 4
+	// exitWrite is an output that could not be written
+	exitWrite = 5
+)
+
+// exitHints tell what to do about the failures of an exit code
+var exitHints = map[int]string{
+	exitUsage:   "run langdet -help for the usage of the commands",
+	exitNetwork: "the download failed, retrying later may help",
+	exitParse:   "check that the input has the expected format and is not truncated",
+	exitWrite:   "check that the output directory exists, is writable and has free space",
+}
+
+// httpStatusError is the error of an HTTP response that is not OK
+type httpStatusError struct {
+	URL    string
+	Status string
+	Code   int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.URL, e.Status)
+}
+
+// fatal prints err and the hint of its exit code to stderr and exits with the exit code
+func fatal(code int, err error) {
+	fmt.Fprintf(os.Stderr, "langdet: %v\n", err)
+	if hint, ok := exitHints[code]; ok {
+		fmt.Fprintf(os.Stderr, "langdet: %s\n", hint)
+	}
+	os.Exit(code)
+}
+
+// fatalf is fatal with a formatted error
+func fatalf(code int, format string, args ...interface{}) {
+	fatal(code, fmt.Errorf(format, args...))
+}
+
+// exitCode returns the exit code of the class of err: network errors and HTTP statuses that may
+// change when retried are exitNetwork, decoding errors, records without fields and profiles of
+// newer formats exitParse and missing files exitUsage
+func exitCode(err error) int {
+	var statusErr *httpStatusError
+	var urlErr *url.Error
+	var netErr *net.OpError
+	var jsonErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var xmlErr *xml.SyntaxError
+	var csvErr *csv.ParseError
+	var versionErr *langdet.FormatVersionError
+	switch {
+	case errors.As(err, &statusErr):
+		if statusErr.Code >= 500 || statusErr.Code == http.StatusTooManyRequests || statusErr.Code == http.StatusRequestTimeout {
+			return exitNetwork
+		}
+		return exitFailure
+	case errors.As(err, &urlErr), errors.As(err, &netErr):
+		return exitNetwork
+	case errors.As(err, &jsonErr), errors.As(err, &typeErr), errors.As(err, &xmlErr), errors.As(err, &csvErr), errors.As(err, &versionErr):
+		return exitParse
+	case errors.Is(err, gzip.ErrHeader), errors.Is(err, gzip.ErrChecksum), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, errMissingField):
+		return exitParse
+	case errors.Is(err, fs.ErrNotExist):
+		return exitUsage
+	}
+	return exitFailure
+}
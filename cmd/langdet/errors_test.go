@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExitCode(t *testing.T) {
+	Convey("Subject: Exit codes of failures", t, func() {
+		Convey("Server errors should be transient network failures, client errors permanent", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/busy" {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				http.NotFound(w, r)
+			}))
+			defer server.Close()
+			_, err := openURL(server.URL + "/busy")()
+			So(exitCode(err), ShouldEqual, exitNetwork)
+			_, err = openURL(server.URL + "/missing")()
+			So(exitCode(err), ShouldEqual, exitFailure)
+		})
+		Convey("Unreachable hosts should be network failures", func() {
+			server := httptest.NewServer(http.NotFoundHandler())
+			server.Close()
+			_, err := openURL(server.URL)()
+			So(exitCode(err), ShouldEqual, exitNetwork)
+		})
+		Convey("Decoding errors should be parse failures, also when wrapped", func() {
+			err := json.NewDecoder(strings.NewReader("{")).Decode(&struct{}{})
+			So(exitCode(fmt.Errorf("profile.json: %w", err)), ShouldEqual, exitParse)
+			_, err = processAbstracts(strings.NewReader("<feed><doc><abstract>text"), func(string) bool { return true })
+			So(exitCode(err), ShouldEqual, exitParse)
+			_, err = langdet.NewDetectorFromReader(strings.NewReader(`[{"Name": "en", "Profile": {"e": 1}, "FormatVersion": 99}]`))
+			So(exitCode(err), ShouldEqual, exitParse)
+		})
+		Convey("Missing inputs should be usage errors", func() {
+			_, err := os.Open("does-not-exist.txt")
+			So(exitCode(err), ShouldEqual, exitUsage)
+			So(exitCode(errors.New("other")), ShouldEqual, exitFailure)
+		})
+	})
+}
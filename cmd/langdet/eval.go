@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// eval detects the texts of a labeled test set with the profiles of a directory and reports
+// the accuracy, per-language precision and recall and the confusion matrix
+func eval(args []string) {
+	config := struct {
+		Profiles      string  `flag:"profiles,Directory with the profiles to evaluate"`
+		Data          string  `flag:"data,CSV or, for .tsv files, TSV file with a text and its expected language per row"`
+		MinConfidence float64 `flag:"min-confidence,Minimum confidence of detected languages, 0 for the default"`
+		Workers       int     `flag:"workers,Number of concurrent detections, 0 for the number of CPUs"`
+		Distance      string  `flag:"distance,Distance of the texts to the profiles: out-of-place, rank-weighted, cosine or jaccard"`
+	}{Distance: "out-of-place"}
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.Profiles == "" || config.Data == "" {
+		fatalf(exitUsage, "-profiles and -data are required arguments")
+	}
+
+	detector := langdet.NewDetector()
+	if err := detector.LoadLanguagesFromDir(config.Profiles); err != nil {
+		fatal(exitCode(err), err)
+	}
+	if config.MinConfidence > 0 {
+		detector.MinimumConfidence = float32(config.MinConfidence)
+	}
+	distance, err := langdet.ParseDistanceFunc(config.Distance)
+	if err != nil {
+		fatal(exitUsage, err)
+	}
+	detector.Distance = distance
+	texts, expected, err := readTestSet(config.Data)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	detected := make([]string, len(texts))
+	for i, set := range detector.DetectBatch(texts, config.Workers) {
+		detected[i] = set.Language
+	}
+	newEvaluation(texts, expected, detected).print(os.Stdout)
+}
+
+// readTestSet reads the texts and expected languages of a CSV or TSV file. A first row with
+// the columns "text" and "language" is skipped.
+func readTestSet(name string) (texts, languages []string, err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 2
+	r.LazyQuotes = true
+	if strings.EqualFold(filepath.Ext(name), ".tsv") {
+		r.Comma = '\t'
+	}
+	for row := 0; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if row == 0 && strings.EqualFold(record[0], "text") && strings.EqualFold(record[1], "language") {
+			continue
+		}
+		texts = append(texts, record[0])
+		languages = append(languages, strings.TrimSpace(record[1]))
+	}
+	return texts, languages, nil
+}
+
+// lengthBuckets are the upper bounds of the input lengths in characters of the accuracy buckets
+// of the evaluation, the last one has no bound
+var lengthBuckets = []struct {
+	label string
+	max   int
+}{{"0-20", 20}, {"21-50", 50}, {"51-200", 200}, {"201+", -1}}
+
+// bucketStats counts the texts of a length bucket
+type bucketStats struct {
+	total, correct, undefined int
+}
+
+// evaluation counts the detected languages of every expected language
+type evaluation struct {
+	confusion map[string]map[string]int
+	expected  []string
+	detected  []string
+	total     int
+	correct   int
+	buckets   []bucketStats
+}
+
+// newEvaluation returns the evaluation of the detected languages of texts with the expected languages
+func newEvaluation(texts, expected, detected []string) evaluation {
+	e := evaluation{confusion: make(map[string]map[string]int), total: len(expected), buckets: make([]bucketStats, len(lengthBuckets))}
+	expectedSet, detectedSet := make(map[string]bool), make(map[string]bool)
+	for i, lang := range expected {
+		if e.confusion[lang] == nil {
+			e.confusion[lang] = make(map[string]int)
+		}
+		e.confusion[lang][detected[i]]++
+		expectedSet[lang], detectedSet[detected[i]] = true, true
+		bucket := &e.buckets[lengthBucket(texts[i])]
+		bucket.total++
+		if detected[i] == lang {
+			e.correct++
+			bucket.correct++
+		} else if detected[i] == "undefined" {
+			bucket.undefined++
+		}
+	}
+	e.expected, e.detected = sortedKeys(expectedSet), sortedKeys(detectedSet)
+	return e
+}
+
+// lengthBucket returns the index of the length bucket of a text by its number of characters
+// without surrounding white space
+func lengthBucket(text string) int {
+	length := utf8.RuneCountInString(strings.TrimSpace(text))
+	for i, bucket := range lengthBuckets {
+		if bucket.max < 0 || length <= bucket.max {
+			return i
+		}
+	}
+	return len(lengthBuckets) - 1
+}
+
+// sortedKeys returns the sorted keys of a set
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// print writes the accuracy, the per-language metrics and the confusion matrix to w
+func (e evaluation) print(w io.Writer) {
+	accuracy := 0.0
+	if e.total > 0 {
+		accuracy = float64(e.correct) / float64(e.total)
+	}
+	fmt.Fprintf(w, "texts:    %d\n", e.total)
+	fmt.Fprintf(w, "accuracy: %.2f%%\n\n", 100*accuracy)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "language\ttexts\tprecision\trecall\tf1\t")
+	for _, lang := range e.expected {
+		support, truePositives, predicted := 0, e.confusion[lang][lang], 0
+		for _, count := range e.confusion[lang] {
+			support += count
+		}
+		for _, row := range e.confusion {
+			predicted += row[lang]
+		}
+		precision, recall, f1 := ratio(truePositives, predicted), ratio(truePositives, support), 0.0
+		if precision+recall > 0 {
+			f1 = 2 * precision * recall / (precision + recall)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.3f\t%.3f\t%.3f\t\n", lang, support, precision, recall, f1)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\naccuracy by length in characters:")
+	fmt.Fprintln(tw, "length\ttexts\taccuracy\tundefined\t")
+	for i, bucket := range e.buckets {
+		fmt.Fprintf(tw, "%s\t%d\t%.2f%%\t%.2f%%\t\n", lengthBuckets[i].label, bucket.total, 100*ratio(bucket.correct, bucket.total), 100*ratio(bucket.undefined, bucket.total))
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nconfusion matrix (rows expected, columns detected):")
+	fmt.Fprintf(tw, "\t%s\t\n", strings.Join(e.detected, "\t"))
+	for _, lang := range e.expected {
+		fmt.Fprintf(tw, "%s", lang)
+		for _, detected := range e.detected {
+			fmt.Fprintf(tw, "\t%d", e.confusion[lang][detected])
+		}
+		fmt.Fprintln(tw, "\t")
+	}
+	tw.Flush()
+}
+
+// ratio returns a/b, or 0 if b is 0
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
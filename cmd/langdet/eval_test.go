@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEvaluationLengthBuckets(t *testing.T) {
+	Convey("Subject: Accuracy by input length", t, func() {
+		Convey("Texts are bucketed by their characters without surrounding white space", func() {
+			So(lengthBucket(""), ShouldEqual, 0)
+			So(lengthBucket("  "+strings.Repeat("я", 20)+"\n"), ShouldEqual, 0)
+			So(lengthBucket(strings.Repeat("я", 21)), ShouldEqual, 1)
+			So(lengthBucket(strings.Repeat("a", 200)), ShouldEqual, 2)
+			So(lengthBucket(strings.Repeat("a", 201)), ShouldEqual, 3)
+		})
+		Convey("Every bucket counts its correct and undefined detections", func() {
+			texts := []string{"short", "tiny", strings.Repeat("long ", 50), strings.Repeat("long ", 50)}
+			e := newEvaluation(texts, []string{"en", "en", "de", "de"}, []string{"en", "undefined", "de", "en"})
+			So(e.buckets, ShouldResemble, []bucketStats{{total: 2, correct: 1, undefined: 1}, {}, {}, {total: 2, correct: 1}})
+
+			buf := &bytes.Buffer{}
+			e.print(buf)
+			So(buf.String(), ShouldContainSubstring, "accuracy by length in characters:")
+			So(buf.String(), ShouldContainSubstring, "0-20      2    50.00%     50.00%")
+			So(buf.String(), ShouldContainSubstring, "201+      2    50.00%      0.00%")
+		})
+	})
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// explain prints the contributions of the n-grams of a text to its distance to a language, the
+// text of the arguments or of stdin
+func explain(args []string) {
+	config := struct {
+		Profiles string `flag:"profiles,Directory, file or bundle with the profiles, the embedded default profiles if empty"`
+		Config   string `flag:"config,JSON or YAML config file of the detector, instead of -profiles"`
+		Lang     string `flag:"lang,Language to explain, the closest language of the text if empty"`
+		Top      int    `flag:"top,Number of n-grams with the highest distance to print, 0 for all"`
+	}{
+		Top: 20,
+	}
+	flags := flag.NewFlagSet("explain", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+
+	detector, err := loadDetector(config.Profiles, config.Config, 0)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	text := strings.Join(flags.Args(), " ")
+	if flags.NArg() == 0 {
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, langdet.DefaultMaxDetectBytes))
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		text = string(data)
+	}
+	lang := config.Lang
+	if lang == "" {
+		results := detector.GetLanguages(text)
+		if len(results) == 0 {
+			fatalf(exitFailure, "no language to explain")
+		}
+		lang = results[0].Name
+	}
+	explanation, err := detector.Explain(text, lang)
+	if err != nil {
+		fatal(exitUsage, err)
+	}
+	printExplanation(os.Stdout, explanation, config.Top)
+}
+
+// printExplanation writes an explanation to w, with a table of its top n-grams of the highest
+// distance, all if top is 0
+func printExplanation(w io.Writer, explanation langdet.Explanation, top int) {
+	result := explanation.Result
+	fmt.Fprintf(w, "language:   %s\n", result.Name)
+	fmt.Fprintf(w, "confidence: %.1f%%\n", result.Confidence)
+	if !explanation.Compared {
+		fmt.Fprintln(w, "the text is written in another script than the language and not compared with it")
+	}
+	fmt.Fprintf(w, "distance:   %d of %d\n", explanation.Distance, explanation.MaxDistance)
+	fmt.Fprintf(w, "n-grams:    %d compared, %d missing from the profile\n\n", len(explanation.NGrams), len(explanation.Missing))
+
+	ngrams := explanation.NGrams
+	if top > 0 && top < len(ngrams) {
+		ngrams = ngrams[:top]
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "N-GRAM\tINPUT RANK\tPROFILE RANK\tDIFF")
+	for _, ngram := range ngrams {
+		profileRank := "-"
+		if ngram.ProfileRank > 0 {
+			profileRank = fmt.Sprint(ngram.ProfileRank)
+		}
+		fmt.Fprintf(table, "%q\t%d\t%s\t%d\n", ngram.NGram, ngram.InputRank, profileRank, ngram.Diff)
+	}
+	table.Flush()
+	if len(explanation.Missing) > 0 {
+		fmt.Fprintf(w, "\nmissing: %s\n", strings.Join(quoted(explanation.Missing), " "))
+	}
+}
+
+// quoted returns the quoted strings of a slice
+func quoted(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return quoted
+}
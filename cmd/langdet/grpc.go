@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/grpcserver"
+	"github.com/imankulov/go-lang-detector/langdet/langdetpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer returns a gRPC server of the langdet.v1.Detector service with the detectors, the
+// bearer tokens, the worker pool, the response cache, the access log and the maximum message
+// size of the server, serving TLS with tlsConfig if it is not nil
+func (s *server) grpcServer(tlsConfig *tls.Config) *grpc.Server {
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(s.interceptGRPC)}
+	if s.maxBytes > 0 {
+		options = append(options, grpc.MaxRecvMsgSize(int(s.maxBytes)))
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(options...)
+	langdetpb.RegisterDetectorServer(server, &grpcDetector{Server: &grpcserver.Server{Detector: s.detector}, s: s})
+	return server
+}
+
+// grpcDetector is the langdet.v1.Detector service of the server: it routes the detections of
+// texts like POST /detect and lists the languages of the stable detector
+type grpcDetector struct {
+	*grpcserver.Server
+	s *server
+}
+
+// Detect implements langdetpb.DetectorServer with the detector of the bundle of the text. Calls
+// without top-K results and segments share the response cache of POST /detect.
+func (g *grpcDetector) Detect(ctx context.Context, request *langdetpb.DetectRequest) (*langdetpb.DetectResponse, error) {
+	text := request.GetText()
+	entry := contextLogEntry(ctx)
+	entry.InputBytes = len(text)
+	entry.Text = langdet.RedactText(text)
+	detector, bundle := g.s.route(text)
+	var response *langdetpb.DetectResponse
+	if g.s.cache != nil && request.GetTopK() == 0 && !request.GetSegments() {
+		key := g.s.cache.key(text, "", request.GetLocale())
+		cached, ok := g.s.cache.get(key)
+		if !ok {
+			cached = detectText(detector, bundle, text, request.GetLocale(), false)
+			g.s.cache.add(key, cached)
+		}
+		response = &langdetpb.DetectResponse{
+			Language:    cached.Language,
+			DisplayName: cached.DisplayName,
+			Confidence:  cached.Confidence,
+			Reliable:    cached.Reliable,
+		}
+	} else {
+		var err error
+		response, err = (&grpcserver.Server{Detector: detector}).Detect(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+	}
+	entry.Language, entry.Confidence, entry.Reliable, entry.Bundle = response.GetLanguage(), response.GetConfidence(), response.GetReliable(), bundle
+	return response, nil
+}
+
+// interceptGRPC writes the access log entries of gRPC calls, if the access log is enabled, and
+// handles them with handleGRPC
+func (s *server) interceptGRPC(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.logger == nil {
+		return s.handleGRPC(ctx, request, handler)
+	}
+	start := time.Now()
+	entry := &accessLogEntry{Time: start, Method: "GRPC", Path: info.FullMethod}
+	response, err := s.handleGRPC(context.WithValue(ctx, accessLogKey{}, entry), request, handler)
+	entry.Status = int(status.Code(err))
+	s.writeLog(entry, start)
+	return response, err
+}
+
+// handleGRPC authenticates gRPC calls like HTTP requests and runs them on the worker pool,
+// shedding them with ResourceExhausted if it is saturated
+func (s *server) handleGRPC(ctx context.Context, request interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.authorize != nil && !s.authorizedGRPC(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+	var response interface{}
+	var err error
+	runErr := s.run(ctx, func() {
+		response, err = handler(ctx, request)
+	})
+	if errors.Is(runErr, errSaturated) {
+		return nil, status.Error(codes.ResourceExhausted, runErr.Error())
+	}
+	if runErr != nil {
+		return nil, status.FromContextError(runErr).Err()
+	}
+	return response, err
+}
+
+// authorizedGRPC tells whether a call sends an accepted token as authorization metadata
+func (s *server) authorizedGRPC(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, header := range md.Get("authorization") {
+		if token, ok := parseBearer(header); ok && s.authorize(token) {
+			return true
+		}
+	}
+	return false
+}
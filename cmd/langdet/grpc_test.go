@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/langdetpb"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServeGRPC(t *testing.T) {
+	Convey("Subject: Serve the detection API over gRPC", t, func() {
+		s := newTestServer()
+		log := &bytes.Buffer{}
+		s.logger = json.NewEncoder(log)
+		canary := langdet.NewDetector()
+		canary.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say? ", "en-canary")
+		s.canary, s.canaryPercent = &canary, 100
+		s.cache = newResponseCache(10, "v1")
+
+		listener := bufconn.Listen(1 << 20)
+		server := s.grpcServer(nil)
+		go server.Serve(listener)
+		defer server.Stop()
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		So(err, ShouldBeNil)
+		defer conn.Close()
+		client := langdetpb.NewDetectorClient(conn)
+
+		Convey("Texts should be routed to the canary and logged", func() {
+			response, err := client.Detect(context.Background(), &langdetpb.DetectRequest{Text: "what is your language", TopK: 1})
+			So(err, ShouldBeNil)
+			So(response.GetLanguage(), ShouldEqual, "en-canary")
+			entry := accessLogEntry{}
+			So(json.Unmarshal(log.Bytes(), &entry), ShouldBeNil)
+			So(entry.Method, ShouldEqual, "GRPC")
+			So(entry.Path, ShouldEqual, "/langdet.v1.Detector/Detect")
+			So(entry.Status, ShouldEqual, int(codes.OK))
+			So(entry.Bundle, ShouldEqual, "canary")
+			So(entry.Language, ShouldEqual, "en-canary")
+			So(entry.InputBytes, ShouldEqual, len("what is your language"))
+		})
+		Convey("Plain detections should share the response cache", func() {
+			_, err := client.Detect(context.Background(), &langdetpb.DetectRequest{Text: "what is your language"})
+			So(err, ShouldBeNil)
+			So(s.cache.order.Len(), ShouldEqual, 1)
+			key := s.cache.key("what is your language", "", "")
+			cached, ok := s.cache.get(key)
+			So(ok, ShouldBeTrue)
+			So(cached.Bundle, ShouldEqual, "canary")
+		})
+		Convey("Messages larger than the maximum size should be rejected", func() {
+			_, err := client.Detect(context.Background(), &langdetpb.DetectRequest{Text: strings.Repeat("what is your language ", 4)})
+			So(status.Code(err), ShouldEqual, codes.ResourceExhausted)
+		})
+	})
+}
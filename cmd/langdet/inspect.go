@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// prune removes the tokens ranked beyond a maximum rank from the profiles of a directory and
+// writes them as <language>.json files
+func prune(args []string) {
+	config := struct {
+		Profiles string `flag:"profiles,Directory with the profiles to prune"`
+		Out      string `flag:"out,Directory to write the pruned profiles to"`
+		MaxRank  int    `flag:"max-rank,Highest rank to keep, 0 for the trained size of every profile"`
+	}{}
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	autoflags.DefineFlagSet(fs, &config)
+	fs.Parse(args)
+	if config.Profiles == "" || config.Out == "" {
+		fatalf(exitUsage, "-profiles and -out are required arguments")
+	}
+
+	detector := langdet.NewDetector()
+	if err := detector.LoadLanguagesFromDir(config.Profiles); err != nil {
+		fatal(exitCode(err), err)
+	}
+	if err := os.MkdirAll(config.Out, 0755); err != nil {
+		fatal(exitWrite, err)
+	}
+	for _, language := range detector.Snapshot() {
+		pruned := prunedLanguage(language, config.MaxRank)
+		writeProfile(pruned, filepath.Join(config.Out, language.Name+".json"))
+		fmt.Printf("%s: %d of %d ranks kept\n", language.Name, len(pruned.Profile), len(language.Profile))
+	}
+}
+
+// prunedLanguage returns a copy of a language pruned to maxRank, which doesn't modify the maps of
+// the language
+func prunedLanguage(language langdet.Language, maxRank int) langdet.Language {
+	language.Profile = copyCounts(language.Profile)
+	if language.Counts != nil {
+		language.Counts = copyCounts(language.Counts)
+	}
+	language.Prune(maxRank)
+	return language
+}
+
+// copyCounts returns a copy of a map of tokens
+func copyCounts(counts map[string]int) map[string]int {
+	copied := make(map[string]int, len(counts))
+	for token, count := range counts {
+		copied[token] = count
+	}
+	return copied
+}
+
+// inspect prints the statistics of the profile of a language, and its overlap with other languages
+func inspect(args []string) {
+	config := struct {
+		Profiles string `flag:"profiles,Directory, file or bundle with the profiles, the embedded default profiles if empty"`
+		Config   string `flag:"config,JSON or YAML config file of the detector, instead of -profiles"`
+		Lang     string `flag:"lang,Language to inspect"`
+		Compare  string `flag:"compare,Language to compare with, all other languages if empty"`
+		Top      int    `flag:"top,Number of top ranked n-grams to print"`
+		Ranks    int    `flag:"ranks,Number of top ranks compared for the overlap, 0 for all"`
+	}{
+		Top:   20,
+		Ranks: 300,
+	}
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	autoflags.DefineFlagSet(fs, &config)
+	fs.Parse(args)
+	if config.Lang == "" {
+		fatalf(exitUsage, "-lang is a required argument")
+	}
+	detector, err := loadDetector(config.Profiles, config.Config, 0)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	var language langdet.Language
+	others := []langdet.Language{}
+	found := false
+	for _, candidate := range detector.Snapshot() {
+		if candidate.Name == config.Lang {
+			if !found {
+				language, found = candidate, true
+			}
+			continue
+		}
+		if config.Compare == "" || candidate.Name == config.Compare {
+			others = append(others, candidate)
+		}
+	}
+	if !found {
+		fatalf(exitUsage, "no profile of language %q", config.Lang)
+	}
+	if config.Compare != "" && len(others) == 0 {
+		fatalf(exitUsage, "no profile of language %q", config.Compare)
+	}
+	printInspection(os.Stdout, language, others, config.Top, config.Ranks)
+}
+
+// printInspection writes the statistics of a language with its top ranked n-grams to w, and its
+// overlaps with the other languages, highest first
+func printInspection(w io.Writer, language langdet.Language, others []langdet.Language, top, ranks int) {
+	stats := language.Stats(top)
+	fmt.Fprintf(w, "language: %s\n", language.Name)
+	fmt.Fprintf(w, "n-grams:  %d, highest rank %d, trained size %d\n", stats.Tokens, stats.MaxRank, language.Size)
+	fmt.Fprintf(w, "entropy:  %.2f bits\n", stats.Entropy)
+	lengths := make([]int, 0, len(stats.Lengths))
+	for length := range stats.Lengths {
+		lengths = append(lengths, length)
+	}
+	sort.Ints(lengths)
+	fmt.Fprintln(w, "lengths:")
+	for _, length := range lengths {
+		fmt.Fprintf(w, "  %d letters: %d\n", length, stats.Lengths[length])
+	}
+	fmt.Fprintln(w, "top n-grams:")
+	for _, token := range stats.Top {
+		fmt.Fprintf(w, "  %5d  %q\n", token.Occurrence, token.Key)
+	}
+	if len(others) == 0 {
+		return
+	}
+	overlaps := make([]float64, len(others))
+	for i, other := range others {
+		overlaps[i] = langdet.ProfileOverlap(language, other, ranks)
+	}
+	order := make([]int, len(others))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return overlaps[order[i]] > overlaps[order[j]] })
+	if ranks > 0 {
+		fmt.Fprintf(w, "overlap of the top %d ranks:\n", ranks)
+	} else {
+		fmt.Fprintln(w, "overlap of all ranks:")
+	}
+	for _, i := range order {
+		fmt.Fprintf(w, "  %-10s %5.1f%%\n", others[i].Name, overlaps[i]*100)
+	}
+}
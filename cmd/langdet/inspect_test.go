@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInspect(t *testing.T) {
+	Convey("Subject: Inspect and prune profiles", t, func() {
+		en := langdet.Analyze("the quick brown fox jumps over the lazy dog", "en")
+		de := langdet.Analyze("der schnelle braune Fuchs springt über den faulen Hund", "de")
+		fr := langdet.Analyze("le renard brun rapide saute par-dessus le chien paresseux", "fr")
+
+		Convey("Inspections should print the statistics and the overlaps, highest first", func() {
+			var out bytes.Buffer
+			printInspection(&out, en, []langdet.Language{fr, de}, 3, 0)
+			text := out.String()
+			So(text, ShouldContainSubstring, "language: en\n")
+			So(text, ShouldContainSubstring, "entropy:")
+			So(text, ShouldContainSubstring, "top n-grams:")
+			So(text, ShouldContainSubstring, "overlap of all ranks:")
+			overlaps := []string{"de", "fr"}
+			if langdet.ProfileOverlap(en, fr, 0) > langdet.ProfileOverlap(en, de, 0) {
+				overlaps = []string{"fr", "de"}
+			}
+			So(bytes.Index(out.Bytes(), []byte("  "+overlaps[0])), ShouldBeLessThan, bytes.Index(out.Bytes(), []byte("  "+overlaps[1])))
+		})
+		Convey("Pruned copies should not change the language", func() {
+			size := len(en.Profile)
+			pruned := prunedLanguage(en, 10)
+			So(pruned.Profile, ShouldHaveLength, 10)
+			So(en.Profile, ShouldHaveLength, size)
+		})
+	})
+}
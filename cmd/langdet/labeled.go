@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// errMissingField is the error of labeled records without the text or label field
+var errMissingField = errors.New("missing field")
+
+// labeledReader reads the records of a labeled dataset: it calls feed with the text and label of
+// every record, whose fields are named textField and labelField, until feed returns false
+type labeledReader func(r io.Reader, textField, labelField string, feed func(text, label string) bool) error
+
+// labeledFormats are the readers of the formats of train -format
+var labeledFormats = map[string]labeledReader{
+	"csv":   readLabeledCSV(','),
+	"tsv":   readLabeledCSV('\t'),
+	"jsonl": readLabeledJSONL,
+}
+
+// readLabeledCSV returns the reader of CSV datasets with the separator comma, whose first row is
+// a header with the names of the columns
+func readLabeledCSV(comma rune) labeledReader {
+	return func(r io.Reader, textField, labelField string, feed func(text, label string) bool) error {
+		reader := csv.NewReader(r)
+		reader.Comma = comma
+		reader.LazyQuotes = true
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		textColumn, labelColumn := -1, -1
+		for i, name := range header {
+			switch strings.TrimSpace(name) {
+			case textField:
+				textColumn = i
+			case labelField:
+				labelColumn = i
+			}
+		}
+		if textColumn < 0 {
+			return fmt.Errorf("%w %q in the header", errMissingField, textField)
+		}
+		if labelColumn < 0 {
+			return fmt.Errorf("%w %q in the header", errMissingField, labelField)
+		}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			for _, column := range []int{textColumn, labelColumn} {
+				if column >= len(record) {
+					line, _ := reader.FieldPos(0)
+					return fmt.Errorf("line %d: %w %q", line, errMissingField, header[column])
+				}
+			}
+			if !feed(record[textColumn], strings.TrimSpace(record[labelColumn])) {
+				return nil
+			}
+		}
+	}
+}
+
+// readLabeledJSONL reads a dataset of JSON objects, one per line, whose text and label fields are
+// strings. Other fields are ignored.
+func readLabeledJSONL(r io.Reader, textField, labelField string, feed func(text, label string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxTrainLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		record := map[string]json.RawMessage{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		var text, label string
+		for _, field := range []struct {
+			name   string
+			target *string
+		}{{textField, &text}, {labelField, &label}} {
+			value, ok := record[field.name]
+			if !ok {
+				return fmt.Errorf("line %d: %w %q", line, errMissingField, field.name)
+			}
+			if err := json.Unmarshal(value, field.target); err != nil {
+				return fmt.Errorf("line %d: field %q: %w", line, field.name, err)
+			}
+		}
+		if !feed(text, strings.TrimSpace(label)) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// labelTrainer trains the profile of a label of a labeled dataset
+type labelTrainer struct {
+	trainer *langdet.Trainer
+	filter  *sentenceFilter
+	docs    int
+}
+
+// trainLabeled builds the profiles of all labels of labeled datasets in one pass and writes them
+// to the -out directory. With -validation, a random fraction of the records is held out of
+// training and detected with the profiles, to report their accuracy.
+func trainLabeled(config trainFlags, inputs []string) {
+	read, ok := labeledFormats[config.Format]
+	if !ok {
+		fatalf(exitUsage, "unknown format %q, expected csv, tsv or jsonl", config.Format)
+	}
+	if config.Out == "" || len(inputs) == 0 {
+		fatalf(exitUsage, "-format needs -out and at least one input file or directory")
+	}
+	if config.Corpus != "text" {
+		fatalf(exitUsage, "-format reads labeled datasets, it cannot be combined with -corpus")
+	}
+	if config.Variants && config.Lowercase {
+		fatalf(exitUsage, "-variants writes lowercased profiles besides the cased ones, it cannot be combined with -lowercase")
+	}
+	if config.Validation < 0 || config.Validation >= 1 {
+		fatalf(exitUsage, "-validation %v is not between 0 and 1", config.Validation)
+	}
+	// the held out texts are transliterated like the trained ones
+	transliterator := newTrainer(config).Transliterator
+	files, err := trainingFiles(inputs)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	if config.Source == "" {
+		config.Source = strings.Join(inputs, ", ")
+	}
+
+	labels := map[string]*labelTrainer{}
+	rnd := rand.New(rand.NewSource(config.Seed))
+	heldOut, heldOutLabels := []string{}, []string{}
+	records := 0
+	feed := func(text, label string) bool {
+		if label == "" || strings.TrimSpace(text) == "" {
+			return true
+		}
+		records++
+		if config.Validation > 0 && rnd.Float64() < config.Validation {
+			heldOut = append(heldOut, text)
+			heldOutLabels = append(heldOutLabels, label)
+			return true
+		}
+		l, ok := labels[label]
+		if !ok {
+			l = &labelTrainer{trainer: newTrainer(config), filter: &sentenceFilter{dedup: config.Dedup, minLength: config.MinLength, maxLength: config.MaxLength}}
+			labels[label] = l
+		}
+		if (config.Limit > 0 && l.docs >= config.Limit) || !l.filter.keep(text) {
+			return true
+		}
+		l.trainer.Feed(text)
+		l.docs++
+		return true
+	}
+	for _, name := range files {
+		if err := feedLabeled(name, read, config.TextField, config.LabelField, feed); err != nil {
+			fatal(exitCode(err), err)
+		}
+	}
+	if len(labels) == 0 {
+		fatalf(exitParse, "no labeled records with the fields %q and %q in %s", config.TextField, config.LabelField, strings.Join(files, ", "))
+	}
+
+	if err := os.MkdirAll(config.Out, 0755); err != nil {
+		fatal(exitWrite, err)
+	}
+	names := make([]string, 0, len(labels))
+	for label := range labels {
+		names = append(names, label)
+	}
+	sort.Strings(names)
+	detector := langdet.NewDetector()
+	for _, label := range names {
+		if filepath.Base(label) != label || label == "." || label == ".." {
+			fatalf(exitParse, "label %q is not a valid file name", label)
+		}
+		l := labels[label]
+		lang := l.trainer.Build(label)
+		file := filepath.Join(config.Out, label+".json")
+		writeProfile(lang, file)
+		fmt.Printf("%s: %d documents, %d n-grams written to %s\n", label, l.docs, len(lang.Profile), file)
+		if config.Variants {
+			folded := l.trainer.BuildFolded(label)
+			writeProfile(folded, foldedFile(file))
+		}
+		detector.AddLanguage(lang)
+	}
+	fmt.Printf("%d records of %d labels in %d files\n", records, len(labels), len(files))
+	if len(heldOut) == 0 {
+		return
+	}
+
+	for i, text := range heldOut {
+		if transliterator != nil {
+			text = transliterator.Transliterate(text)
+		}
+		if config.Lowercase {
+			text = strings.ToLower(text)
+		}
+		heldOut[i] = text
+	}
+	if config.Tokenize {
+		detector.Tokenizer = langdet.WordTokenizer{}
+	}
+	detected := make([]string, len(heldOut))
+	for i, set := range detector.DetectBatch(heldOut, 0) {
+		detected[i] = set.Language
+	}
+	fmt.Printf("validation on %d held out records:\n", len(heldOut))
+	newEvaluation(heldOut, heldOutLabels, detected).print(os.Stdout)
+}
+
+// feedLabeled calls feed with the records of a labeled dataset file, plain or compressed, in the
+// format of read, until feed returns false
+func feedLabeled(name string, read labeledReader, textField, labelField string, feed func(text, label string) bool) error {
+	r, err := openFile(name)()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer r.Close()
+	if err := read(r, textField, labelField, feed); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLabeledFormats(t *testing.T) {
+	Convey("Subject: Read the records of labeled datasets", t, func() {
+		read := func(format, input, textField, labelField string) ([]string, error) {
+			records := []string{}
+			err := labeledFormats[format](strings.NewReader(input), textField, labelField, func(text, label string) bool {
+				records = append(records, label+": "+text)
+				return true
+			})
+			return records, err
+		}
+
+		Convey("CSV datasets should be read by the columns of their header", func() {
+			records, err := read("csv", "labels,text\nen,\"Hello, world\"\nfr, Bonjour\n", "text", "labels")
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{"en: Hello, world", "fr:  Bonjour"})
+			records, err = read("tsv", "id\tlang\ttext\n1\tde\tGuten Tag\n", "text", "lang")
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{"de: Guten Tag"})
+		})
+		Convey("JSONL datasets should be read by their fields", func() {
+			records, err := read("jsonl", "{\"text\": \"Hello\", \"lang\": \"en\", \"id\": 1}\n\n{\"lang\": \"fr\", \"text\": \"Bonjour\"}\n", "text", "lang")
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{"en: Hello", "fr: Bonjour"})
+		})
+		Convey("Missing fields should be parse errors", func() {
+			_, err := read("csv", "text,label\nHello\n", "text", "label")
+			So(errors.Is(err, errMissingField), ShouldBeTrue)
+			_, err = read("csv", "text,label\n", "text", "lang")
+			So(errors.Is(err, errMissingField), ShouldBeTrue)
+			_, err = read("jsonl", "{\"text\": \"Hello\"}\n", "text", "lang")
+			So(errors.Is(err, errMissingField), ShouldBeTrue)
+			So(exitCode(err), ShouldEqual, exitParse)
+			_, err = read("jsonl", "{\"text\": 1, \"lang\": \"en\"}\n", "text", "lang")
+			So(exitCode(err), ShouldEqual, exitParse)
+		})
+	})
+}
@@ -2,12 +2,16 @@ package main
 
 import (
 	"encoding/json"
-	"encoding/xml"
 	"flag"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	pb "gopkg.in/cheggaaa/pb.v1"
 
@@ -15,9 +19,14 @@ import (
 	"github.com/imankulov/go-lang-detector/langdet"
 )
 
-// Doc is a document element
-type Doc struct {
-	Abstract string `xml:"abstract"`
+// stringList is a flag value that can be set multiple times
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ",") }
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
 }
 
 var help = `
@@ -27,20 +36,494 @@ Usage example to load first 10k definitions for English language, and to
 store them in an en.json file:
 
 langdet -url https://dumps.wikimedia.org/enwiki/20170120/enwiki-20170120-abstract.xml -lang en -file en.json -limit 10000
+
+-url and -input can be repeated to train one language from several dump shards,
+which are processed concurrently. -limit and -samples are split evenly between
+the shards:
+
+langdet -input enwiki-abstract1.xml -input enwiki-abstract2.xml -lang en -file en.json
+
+Dumps compressed with gzip or bzip2 are decompressed while they are read, by
+their .gz or .bz2 extension or the Content-Encoding of the download.
+
+Instead of a dated -url, -latest looks up the newest dump with abstracts of the
+wikipedia of -lang, on the -mirror servers first and then on dumps.wikimedia.org.
+With -download-dir, its files are downloaded there first; interrupted downloads
+are kept as .part files and resumed by the next run:
+
+langdet -lang en -latest -download-dir ./dumps -file en.json
+
+Add -dry-run to validate the source before a long run: it reports the number of
+records, the average abstract length, the script distribution and the number of
+distinct n-grams without writing a profile.
+
+Empty abstracts and abstracts that are fragments of templates, like "| image =
+Paris.jpg", are skipped and don't count towards -limit; the trainer reports
+their number.
+
+Abstracts are read from <doc> or <page> records, with or without namespaces,
+and a warning is printed for every source with no or very few abstracts, which
+usually means a wrong URL or a dump of another kind.
+
+To catch a wrong -url, a sample of the abstracts is detected with the embedded
+default profiles, or with the profiles of the -verify directory, and a warning
+is printed if too many of them are detected as another language than -lang.
+If there is no profile of -lang to compare with, a warning is printed before
+the dumps are read and the corpus is not verified; add -no-verify to skip the
+verification altogether.
+
+The first -limit abstracts of a dump are biased towards the articles at its
+start. Add -sample to read the whole dumps and train a uniform random sample of
+-limit of their abstracts instead; the same -seed trains the same sample:
+
+langdet -input enwiki-abstract.xml -lang en -file en.json -limit 20000 -sample -seed 7
+
+Add -script to create a script-pure profile from a wiki that mixes scripts,
+e.g. -lang sr-Latn -script Latin; letters of other scripts are ignored.
+
+Code-mixed varieties are trained from mixed text and labeled with the languages
+they mix, e.g. -lang hi-en-mixed -mix hi,en, so that Detector.DetectCodeMixing
+reports their languages.
+
+Add -keep-counts to store the raw n-gram counts with the profile, which makes
+it bigger, but lets Language.Update extend it exactly with new text later.
+
+Headlines and other all-caps sentences distort the case-sensitive n-grams; the
+trainer reports their fraction, and -exclude-caps drops them from training.
+
+Abstracts often begin with pronunciations and translations in other languages,
+like "Paris (French: [paʁi])"; -strip-parens removes parentheticals in round or
+square brackets and IPA transcriptions between slashes before training.
+
+Add -report ngrams.csv or -report ngrams.html to write the top ranked n-grams
+with their counts and coverage curve for manual inspection of the corpus.
+
+Add -samples to hold a few abstracts out of training and store their first
+sentences with the profile, where Detector.SelfTest uses them as a smoke test.
+
+To compare machines or sets of profiles, -benchmark runs a built-in
+micro-benchmark against the profiles of a directory instead of training:
+
+langdet -benchmark ./profiles -benchmark-duration 10s
+
+The detect command prints the language of the text of stdin or of every file
+argument, with the profiles of a directory or the embedded default profiles.
+With -json, it prints the results of all languages as a JSON array per input:
+
+echo "Hello, how are you?" | langdet detect
+langdet detect -profiles ./profiles -json README.md
+
+-format html or -format markdown detects the visible text of web pages or
+Markdown documents, without their tags, code blocks and link targets:
+
+langdet detect -format html page.html
+
+With -charsets, inputs that are no valid UTF-8 are decoded from the legacy
+charset, e.g. windows-1251 or KOI8-R, whose text detects most confidently:
+
+langdet detect -charsets old.txt
+
+The serve command exposes a shared detector as an HTTP/JSON API: POST /detect
+with the text as body returns the closest language, with ?all=1 the results
+of all languages, and GET /languages lists the languages. Both return the
+names of the languages in the language of a ?locale= parameter, e.g. ?locale=de:
+
+langdet serve -addr :8080 -profiles ./profiles -max-bytes 65536
+
+Requests are logged to stderr as JSON lines with the latency, the input size
+and the detected language. Texts are logged as their length and hash unless
+-log-text is set.
+
+-token, which can be repeated, or -token-file with one token per line require
+requests to send an accepted token as "Authorization: Bearer <token>" header.
+
+-cache keeps the responses of the given number of distinct requests, by the hash
+of their text, and sends ETags, so that clients and gateways can revalidate
+repeated lookups with If-None-Match instead of detecting them again.
+
+Detections run on -concurrency workers, the number of CPUs by default. Up to
+-queue requests wait for a worker, further requests are rejected with 429 Too
+Many Requests and a Retry-After header, so that bursts don't slow down all
+requests. GET /metrics exposes the busy workers, the queue length, the number
+of rejected requests, the detections by language, the number of undefined
+detections and a histogram of the detection latencies in the Prometheus text
+format:
+
+langdet serve -concurrency 4 -queue 32
+
+-canary serves a second set of profiles, e.g. the bundle of a new model, for
+-canary-percent of the texts, by the hash of the text so that a text always gets
+the same answer. Responses and access log entries tell the bundle, stable or
+canary, that detected the language:
+
+langdet serve -profiles stable.bin -canary candidate.bin -canary-percent 5
+
+-grpc additionally serves the langdet.v1.Detector gRPC service of
+langdet/langdetpb/langdet.proto, with the top-K results and the segments of a
+text, with the same tokens, as "authorization" metadata, workers, TLS
+certificates, canary, -cache, access log and -max-bytes as the HTTP API:
+
+langdet serve -addr :8080 -grpc :9090
+
+-watch reloads the profiles of a -profiles directory when its files are added,
+changed or deleted, without a restart. It cannot be combined with -cache.
+
+-tls-cert and -tls-key serve HTTPS, and -client-ca additionally requires client
+certificates signed by the given CAs:
+
+langdet serve -tls-cert server.pem -tls-key server.key -client-ca clients.pem
+
+The train command creates a profile from local plain text files, directories
+of them or gzip archives, with one document per line or, with -whole-file, one
+document per file:
+
+langdet train -lang en -file en.json -lowercase -max-ngrams 3000 ./texts corpus.txt.gz
+
+With -variants, train also writes the case-folded variant of the profile, e.g.
+en.folded.json besides en.json, from the same pass over the corpus. Detectors
+with both variants use the cased ones, or with FoldCase, the "foldCase" option of
+config files, the case-folded ones for lowercased texts:
+
+langdet train -lang de -file de.json -variants ./texts
+
+-words also ranks the most frequent words of the texts into a word profile, which
+separates closely related languages like Danish and Norwegian that share most
+n-grams. Detectors weigh the words with WordWeight, the "wordWeight" option of
+config files, e.g. 0.3:
+
+langdet train -lang nb -file nb.json -words 2000 ./texts
+
+Regional and script variants are trained as languages with BCP 47 names, e.g.
+pt-BR and pt-PT or zh-Hans and zh-Hant. With the "variants" option of config
+files, detectors find the macro-language first and then tell its variants apart,
+Chinese by its simplified or traditional characters and Portuguese by the word
+profiles of its variants:
+
+langdet train -lang pt-BR -file pt-BR.json -words 2000 ./texts-br
+
+With -tokenize, train drops URLs, email addresses, @mentions, hashtags, numbers
+and punctuation from the texts; detect these profiles with detect -tokenize.
+
+-max-capitalized drops sentences whose words are mostly capitalized, like lists
+of names and sports rosters, which add the n-grams of other languages to
+Wikipedia profiles; languages that capitalize nouns need a higher fraction:
+
+langdet train -lang en -file en.json -max-capitalized 0.6 ./texts
+
+Trainings of huge corpora can be interrupted and resumed: with -checkpoint,
+train saves its counts to the file every -checkpoint-every records and when it
+is interrupted, and -resume continues from the file, skipping the records of
+the same inputs that were read before. -dedup only knows the sentences read
+since the resume. -progress prints the trained documents and n-grams:
+
+langdet train -lang en -file en.json -checkpoint en.checkpoint -progress ./texts
+langdet train -lang en -file en.json -checkpoint en.checkpoint -resume ./texts
+
+-corpus trains from the informal sentences of Tatoeba sentence exports or OPUS
+subtitles, which fit chat and social media texts better than Wikipedia: tatoeba
+reads the exports with tab separated ids, languages and sentences, opus the
+Moses format with a sentence per line and opus-tsv the sentence pairs of the
+TSV format, of the language of -column. Without inputs, tatoeba and opus
+download the corpus of -lang. -dedup trains repeated sentences only once,
+-min-length and -max-length skip sentences by their number of characters:
+
+langdet train -corpus tatoeba -lang tr -file tr.json -dedup -min-length 10
+langdet train -corpus opus-tsv -column 2 -lang tr -file tr.json en-tr.tsv.gz
+
+-transliterate trains the transliterated variant of a language from a corpus in
+its native script, with a built-in table of ru-Latn (Russian in the Latin
+spelling of chats) or el-Latn (Greeklish). The embedded ru-Latn profile is
+trained like this, see langdet.TransliteratedLanguages:
+
+langdet train -lang ru-Latn -transliterate ru-Latn -file ru-Latn.json ./texts-ru
+
+-format trains from labeled datasets, like the CSV and JSONL files of public
+language identification datasets, with a text and its language per record: csv
+and tsv files with a header row naming their columns, or jsonl files with a
+JSON object per line. The profiles of all labels are built in one pass and
+written to <label>.json files of -out; -text-field and -label-field name the
+fields. -validation holds out a random fraction of the records, split by -seed,
+and reports the accuracy of the profiles on them like the eval command:
+
+langdet train -format jsonl -text-field text -label-field lang -out ./profiles -validation 0.1 train.jsonl
+
+Both commands rank up to 9999 n-grams; -max-ngrams trains smaller or, from big
+corpora, bigger profiles, e.g. -max-ngrams 3000 writes less than a third of the
+n-grams. Detector.ProfileSize compares fewer ranks of them. -lowercase trains case-folded profiles, in which
+"The" and "the" are the same n-grams, for pipelines that lowercase their texts.
+
+With -depth 0, both commands select the n-gram depth by the script of the
+corpus, e.g. short n-grams for Chinese and longer ones for Latin; profiles
+store their depth, so that profiles of different depths can be mixed.
+
+The train-all command lists the Wikipedias of all languages, and trains the
+profiles of those with at least -min-articles articles from their latest
+abstract dumps, -workers languages at a time. Every language is trained by its
+own process that logs to <lang>.log in -out, so that a failing dump only fails
+its language; existing profiles are skipped unless -overwrite is set. The run
+ends with a summary of the succeeded, skipped and failed languages, and exits
+with an error only if all languages failed, or with -strict if any failed. -list
+only prints the qualifying languages:
+
+langdet train-all -min-articles 100000 -out ./profiles
+
+With -config, train-all trains the languages of a YAML or JSON file instead,
+with their -url, -input or -latest sources, and optional limits and depths;
+-bundle additionally writes all trained profiles as one compiled bundle:
+
+langdet train-all -config langs.yaml -out ./profiles -bundle profiles.bin
+
+limit: 20000
+languages:
+  - lang: en
+    latest: true
+  - lang: de
+    inputs: [dewiki-abstract.xml.gz]
+    limit: 50000
+
+The eval command measures the accuracy of the profiles of a directory on a
+labeled test set, a CSV or TSV file with a text and its expected language per
+row, and reports per-language precision and recall, the accuracy by input length
+in characters, 0-20, 21-50, 51-200 and over 200, and a confusion matrix:
+
+langdet eval -profiles ./profiles -data testset.tsv
+
+-distance compares the accuracy of other distances than the out-of-place rank
+distance: rank-weighted, which weighs disagreements on the frequent n-grams of
+a text more, cosine or jaccard:
+
+langdet eval -profiles ./profiles -data testset.tsv -distance rank-weighted
+
+Raw confidences mean different things for short and long texts. The calibrate
+command learns curves per input length that map them to the probabilities that
+detections are correct, from a dev set in the format of eval, and writes them
+as JSON, for the "calibration" of a config file and
+Detector.GetClosestLanguageWithProbability:
+
+langdet calibrate -profiles ./profiles -data devset.tsv -out calibration.json
+
+Closely related languages, like Bosnian and Croatian or Bokmål and Nynorsk,
+often score alike. The discriminate command learns a discriminator of such a
+pair from the labeled texts of both languages in a data set in the format of
+eval, a logistic regression over their most discriminative n-grams, and writes
+it as JSON, for the "discriminators" of a config file. It decides between the
+two closest languages when their confidences are within a small margin:
+
+langdet discriminate -data pairs.tsv -first bs -second hr -out bs-hr.json
+
+To test the pipeline end to end without shipping real corpora, the synth command
+generates synthetic abstract files from the profiles of a directory, one
+<language>.xml file per language, which can be used as -input:
+
+langdet synth -profiles ./profiles -out ./corpus -docs 100 -words 40 -seed 1
+
+Profiles load from json or from a compact binary format, which is much faster
+to decode at startup. The convert command rewrites the profiles of a directory
+in either format:
+
+langdet convert -profiles ./profiles -out ./profiles-bin -format binary
+
+The migrate command upgrades a directory of legacy rank-only json profiles into
+a single compiled bundle, validating their depth and adding the missing depth,
+tag, direction and size. Bundles can be used as -profiles of detect and serve,
+and as profiles of configs:
+
+langdet migrate -in ./legacy-profiles -out bundle.bin
+
+The bundle command writes the profiles of files and directories into a bundle
+with a manifest of the bundle and of its profiles: their depth, number of ranks,
+corpus size, if known, and training time. -words adds the word lists of a
+directory, <lang>.txt files with one word per line, as bloom filters with the
+false positive rate -fp-rate, which single-word texts are looked up in before
+their n-grams are scored. Bundles also route every script to the languages
+written in it, so that detectors compare texts with the languages of their
+script only, without inferring the scripts of the profiles when they are
+loaded. -inspect prints the manifest of a bundle without loading its profiles:
+
+langdet bundle -out profiles.bin -description "wikipedia 2024-06" ./profiles
+langdet bundle -out profiles.bin -words ./words -fp-rate 0.001 ./profiles
+langdet bundle -inspect profiles.bin
+
+Binary profiles and bundles are reproducible: the same profiles are written with
+the same bytes on every architecture. Bundles take their creation time, and the
+training time of profiles without one, from SOURCE_DATE_EPOCH if it is set. The
+digest command prints the SHA-256 checksums of binary profiles and bundles, and
+fails for files that this version would not write byte for byte. -check verifies
+the files against the checksums of another build, e.g. on an ARM device:
+
+SOURCE_DATE_EPOCH=1717200000 langdet bundle -out profiles.bin ./profiles
+langdet digest profiles.bin > SHA256SUMS
+langdet digest -check SHA256SUMS
+
+The viz command exports the ranks, lengths and, for profiles with counts, the
+frequencies of the top n-grams of profiles, and with -matrix the pairwise
+similarity of the profiles, as CSV or as JSON for charting libraries like d3:
+
+langdet viz -out ngram_ranks.csv -matrix similarity.json ./profiles
+
+The report command walks a directory and writes the detected language of every
+text file, HTML and Markdown without their markup, and the number of files per
+language as JSON, e.g. to audit the translations of documentation. Files with
+unreliable detections count as undefined; binary and hidden files are skipped:
+
+langdet report -dir ./docs -out report.json
+
+The prune command removes the n-grams ranked beyond -max-rank, or beyond the
+trained size of every profile, and the counts of unranked n-grams, which only
+bloat the profile files:
+
+langdet prune -profiles ./profiles -out ./pruned -max-rank 5000
+
+The inspect command prints the statistics of a profile: its number of n-grams
+by length, the entropy of their frequencies and its top n-grams, and how much
+of its top ranks other profiles share, to diagnose confusable languages:
+
+langdet inspect -profiles ./profiles -lang nb -compare da -top 30
+
+The doctor command checks a directory of profiles before it is deployed. It
+loads and validates every profile, reports the issues of Detector.Audit, like
+mixed depths, and small profiles, detects the sample sentences of common
+languages shipped with the tool and the samples stored with the profiles, and
+warns about files named after another language and pairs of profiles whose top
+-ranks overlap more than -max-overlap, which are likely copies or mislabeled.
+It fails on profiles that don't load or misdetect their samples, with -strict
+on warnings too:
+
+langdet doctor -dir ./profiles
+
+The merge command combines profiles of a language trained on different corpora,
+e.g. Wikipedia and chat logs, into one profile. The profiles must be trained
+with -keep-counts and the same depth; -weight multiplies the counts of the
+profiles after the first one, e.g. to balance corpora of different sizes:
+
+langdet merge -file en.json -weight 20 en-wiki.json en-chat.json
+
+The explain command prints which n-grams of a text drive its distance to a
+language, the closest language of the text if -lang is empty: their ranks in
+the text and in the profile, their distances, highest first, and the n-grams
+missing from the profile, to debug misdetections:
+
+langdet explain -lang en -top 30 "what is your language"
+
+All commands print errors with a hint to stderr and exit with a code by the
+class of the failure, so that scripts can retry transient failures only:
+
+1  other failures
+2  invalid flags or arguments, like missing input files
+3  network failures and server errors, which may succeed when retried
+4  inputs, corpora or profiles that could not be parsed
+5  outputs that could not be written
 `
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "detect":
+			detect(os.Args[2:])
+			return
+		case "serve":
+			serve(os.Args[2:])
+			return
+		case "train":
+			train(os.Args[2:])
+			return
+		case "train-all":
+			trainAll(os.Args[2:])
+			return
+		case "synth":
+			synth(os.Args[2:])
+			return
+		case "convert":
+			convert(os.Args[2:])
+			return
+		case "eval":
+			eval(os.Args[2:])
+			return
+		case "calibrate":
+			calibrate(os.Args[2:])
+			return
+		case "discriminate":
+			discriminate(os.Args[2:])
+			return
+		case "viz":
+			viz(os.Args[2:])
+			return
+		case "migrate":
+			migrate(os.Args[2:])
+			return
+		case "bundle":
+			bundle(os.Args[2:])
+			return
+		case "digest":
+			digest(os.Args[2:])
+			return
+		case "report":
+			report(os.Args[2:])
+			return
+		case "prune":
+			prune(os.Args[2:])
+			return
+		case "inspect":
+			inspect(os.Args[2:])
+			return
+		case "merge":
+			merge(os.Args[2:])
+			return
+		case "doctor":
+			doctor(os.Args[2:])
+			return
+		case "explain":
+			explain(os.Args[2:])
+			return
+		}
+	}
+
 	config := struct {
-		URL   string `flag:"url,URL with wikipedia abstract pages"`
-		Lang  string `flag:"lang,Language to parse"`
-		File  string `flag:"file,Output filename"`
-		Depth int    `flag:"depth,Occurence map depth"`
-		Limit int    `flag:"limit,Maximum number of abstracts to process"`
-		Help  bool   `flag:"help,This help"`
+		Lang     string `flag:"lang,Language to parse"`
+		File     string `flag:"file,Output filename"`
+		Depth    int    `flag:"depth,Occurence map depth, 0 to select it by the script of the corpus"`
+		Limit    int    `flag:"limit,Maximum number of abstracts to process"`
+		Sample   bool   `flag:"sample,Train a random sample of -limit abstracts of the whole dumps instead of the first ones"`
+		Seed     int64  `flag:"seed,Seed of the random generator of -sample"`
+		Samples  int    `flag:"samples,Number of abstracts held out of training and stored as sample sentences"`
+		DryRun   bool   `flag:"dry-run,Only report corpus statistics without writing a profile"`
+		NoCaps   bool   `flag:"exclude-caps,Exclude all-caps sentences like headlines from training"`
+		Parens   bool   `flag:"strip-parens,Remove parentheticals and IPA transcriptions from the abstracts"`
+		Mix      string `flag:"mix,Comma separated languages of a code-mixed variety, e.g. hi,en for -lang hi-en-mixed"`
+		Script   string `flag:"script,Only train with letters of this unicode script, e.g. Cyrillic"`
+		Report   string `flag:"report,Write the ranked n-grams with counts and coverage to this .csv or .html file"`
+		Counts   bool   `flag:"keep-counts,Store the raw n-gram counts with the profile, so that it can be updated later"`
+		Size     int    `flag:"max-ngrams,Number of ranked n-grams of the profile, 0 for the default size"`
+		Tokens   int    `flag:"max-tokens,Older name of -max-ngrams"`
+		Lower    bool   `flag:"lowercase,Lowercase the abstracts before training, for detectors that lowercase their input"`
+		Source   string `flag:"source,Description of the training corpus stored with the profile, the dumps if empty"`
+		Top      int    `flag:"report-top,Number of top ranked n-grams in the report"`
+		Latest   bool   `flag:"latest,Train from the newest abstract dump of the wikipedia of -lang instead of -url or -input"`
+		Download string `flag:"download-dir,Directory to download the -latest dump to, resuming interrupted downloads, instead of streaming it"`
+		Verify   string `flag:"verify,Directory with existing profiles to verify the language of the corpus, the embedded default profiles if empty"`
+		NoVerify bool   `flag:"no-verify,Do not verify the language of the corpus"`
+		Help     bool   `flag:"help,This help"`
+
+		Benchmark         string        `flag:"benchmark,Directory with profiles to benchmark instead of training"`
+		BenchmarkDuration time.Duration `flag:"benchmark-duration,Duration of the benchmark"`
+
+		VerifySamples   int     `flag:"verify-samples,Number of abstracts to verify"`
+		VerifyThreshold float64 `flag:"verify-threshold,Warn if this fraction of verified abstracts is detected as another language"`
 	}{
-		Depth: 3,
-		Limit: 20000,
+		Depth:           3,
+		Limit:           20000,
+		Seed:            1,
+		Top:             1000,
+		VerifySamples:   200,
+		VerifyThreshold: 0.3,
+
+		BenchmarkDuration: 5 * time.Second,
 	}
+	var urls, inputs stringList
+	flag.Var(&urls, "url", "URL with wikipedia abstract pages, can be repeated")
+	flag.Var(&inputs, "input", "Local file with wikipedia abstract pages, can be repeated")
+	var mirrors stringList
+	flag.Var(&mirrors, "mirror", "Mirror of dumps.wikimedia.org to look up the -latest dump on first, can be repeated")
 	autoflags.Define(&config)
 	flag.Parse()
 
@@ -48,65 +531,284 @@ func main() {
 		fmt.Println(help)
 		return
 	}
+	if config.Benchmark != "" {
+		detector := langdet.NewDetector()
+		if err := detector.LoadLanguagesFromDir(config.Benchmark); err != nil {
+			fatal(exitCode(err), err)
+		}
+		fmt.Println(detector.Benchmark(config.BenchmarkDuration))
+		return
+	}
 
 	// validate parameters
-	if config.URL == "" {
-		log.Fatalf("-url is a required argument\n%s", help)
+	if len(urls) == 0 && len(inputs) == 0 && !config.Latest {
+		fatalf(exitUsage, "-url or -input is a required argument")
 	}
 	if config.Lang == "" {
-		log.Fatalf("-lang is a required argument\n%s", help)
+		fatalf(exitUsage, "-lang is a required argument")
 	}
-	if config.File == "" {
-		log.Fatalf("-file is a required argument\n%s", help)
+	if config.File == "" && !config.DryRun {
+		fatalf(exitUsage, "-file is a required argument")
 	}
-
-	// Create lang structure
-	occurenceMap := make(map[string]int)
-
-	// download and parse Wikipedia article
-	resp, err := http.Get(config.URL)
-	if err != nil {
-		log.Fatal(err)
+	if config.Sample && config.Limit <= 0 {
+		fatalf(exitUsage, "-sample requires a positive -limit")
 	}
-	defer resp.Body.Close()
-	decoder := xml.NewDecoder(resp.Body)
-	bar := pb.StartNew(config.Limit)
-	for processed := 0; processed < config.Limit; {
-		t, _ := decoder.Token()
-		if t == nil {
-			break
+	if config.Report != "" && !isReportFormat(config.Report) {
+		fatalf(exitUsage, "-report %q must be a .csv or .html file", config.Report)
+	}
+	// the profile to verify the corpus with is resolved before any dump is read
+	var verifier *corpusVerifier
+	if !config.NoVerify {
+		verifier = newCorpusVerifier(config.Verify, config.Lang)
+	}
+	if config.Latest {
+		latest, err := latestDumps(http.DefaultClient, append(mirrors, defaultDumpMirror), config.Lang)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		for _, u := range latest {
+			if config.Download == "" {
+				urls = append(urls, u)
+				continue
+			}
+			name, err := downloadDump(http.DefaultClient, u, config.Download)
+			if err != nil {
+				fatal(exitCode(err), err)
+			}
+			inputs = append(inputs, name)
+		}
+	}
+	var script *unicode.RangeTable
+	if config.Script != "" {
+		script = unicode.Scripts[config.Script]
+		if script == nil {
+			fatalf(exitUsage, "-script %q is not a known unicode script", config.Script)
 		}
+	}
 
-		switch se := t.(type) {
-		case xml.StartElement:
-			if se.Name.Local == "doc" {
-				var d Doc
-				err = decoder.DecodeElement(&d, &se)
-				if err != nil {
-					log.Fatal(err)
+	// process all shards concurrently, every shard with its own trainer
+	shards := make([]func() (io.ReadCloser, error), 0, len(urls)+len(inputs))
+	for _, u := range urls {
+		shards = append(shards, openURL(u))
+	}
+	for _, in := range inputs {
+		shards = append(shards, openFile(in))
+	}
+	shardNames := append(append([]string{}, urls...), inputs...)
+	records := make([]int, len(shards))
+	blank := make([]int, len(shards))
+	stopped := make([]bool, len(shards))
+	trainers := make([]*langdet.Trainer, len(shards))
+	stats := make([]corpusStats, len(shards))
+	cases := make([]caseStats, len(shards))
+	samples := make([][]string, len(shards))
+	heldOut := make([][]string, len(shards))
+	errs := make([]error, len(shards))
+	bar := pb.StartNew(config.Limit)
+	var wg sync.WaitGroup
+	for i, open := range shards {
+		wg.Add(1)
+		go func(i int, open func() (io.ReadCloser, error)) {
+			defer wg.Done()
+			trainers[i] = &langdet.Trainer{Depth: config.Depth, KeepCounts: config.Counts, MaxRanks: maxNGrams(config.Size, config.Tokens), Lowercase: config.Lower}
+			body, err := open()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer body.Close()
+			// every shard has its own share of the limits, so that the trained abstracts don't
+			// depend on the scheduling of the shards
+			limit, samplesLimit := shardShare(config.Limit, len(shards), i), shardShare(config.Samples, len(shards), i)
+			trainAbstract := func(abstract string) {
+				bar.Increment()
+				if script != nil {
+					abstract = filterScript(abstract, script)
 				}
+				abstract = cases[i].add(abstract, config.NoCaps)
 				// for every abstract record, update occurrence map
-				langdet.UpdateOccurenceMap(occurenceMap, d.Abstract, config.Depth)
+				trainers[i].Feed(abstract)
+				if config.DryRun {
+					stats[i].add(abstract)
+				}
+				if verifier != nil && len(samples[i]) < config.VerifySamples {
+					samples[i] = append(samples[i], abstract)
+				}
+			}
+			// with -sample, the whole shard is read and a sample of its abstracts is trained
+			// afterwards, with a seed per shard so that it doesn't depend on the scheduling
+			var sample *reservoir
+			if config.Sample {
+				sample = newReservoir(limit, config.Seed+int64(i))
+			}
+			processed := 0
+			records[i], errs[i] = processAbstracts(body, func(abstract string) bool {
+				// blank abstracts don't count towards the limit
+				if isBlankAbstract(abstract) {
+					blank[i]++
+					return true
+				}
+				if config.Parens {
+					abstract = stripParentheticals(abstract)
+				}
+				if sentence := firstSentence(abstract); sentence != "" && len(heldOut[i]) < samplesLimit {
+					heldOut[i] = append(heldOut[i], sentence)
+					return true
+				}
+				if sample != nil {
+					sample.add(abstract)
+					return true
+				}
+				if processed >= limit {
+					stopped[i] = true
+					return false
+				}
 				processed++
-				bar.Increment()
+				trainAbstract(abstract)
+				return true
+			})
+			if sample != nil && errs[i] == nil {
+				for _, abstract := range sample.sample {
+					trainAbstract(abstract)
+				}
 			}
+		}(i, open)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+	}
+	for i, name := range shardNames {
+		if warning := dumpWarning(name, records[i], stopped[i]); warning != "" {
+			log.Print(warning)
+		}
+	}
+
+	// merge the counts of all shards
+	trainer := trainers[0]
+	for _, shardTrainer := range trainers[1:] {
+		trainer.Merge(shardTrainer)
+	}
+	occurenceMap := trainer.Counts()
+
+	totalBlank := 0
+	for _, shardBlank := range blank {
+		totalBlank += shardBlank
+	}
+	totalCases := caseStats{}
+	for _, shardCases := range cases {
+		totalCases.merge(shardCases)
+	}
+	if config.DryRun {
+		total := corpusStats{}
+		for _, shardStats := range stats {
+			total.merge(shardStats)
 		}
+		bar.Finish()
+		total.print(os.Stdout, len(occurenceMap))
+		fmt.Printf("Skipped %d empty or template-only abstracts\n", totalBlank)
+		totalCases.print(os.Stdout)
+		return
+	}
+
+	if verifier != nil {
+		verifySamples := []string{}
+		for _, shardSamples := range samples {
+			verifySamples = append(verifySamples, shardSamples...)
+		}
+		if len(verifySamples) > config.VerifySamples {
+			verifySamples = verifySamples[:config.VerifySamples]
+		}
+		verifier.verify(config.Lang, verifySamples, config.VerifyThreshold)
 	}
 
 	// bulid a language object
-	ranked := langdet.CreateRankLookupMap(occurenceMap)
-	lang := langdet.Language{Name: config.Lang, Profile: ranked}
+	trainer.Source = config.Source
+	if trainer.Source == "" {
+		trainer.Source = "wikipedia abstracts of " + strings.Join(shardNames, ", ")
+	}
+	lang := trainer.Build(config.Lang)
+	ranked := lang.Profile
+	if config.Mix != "" {
+		lang.Mix = strings.Split(config.Mix, ",")
+	}
+	for _, shardSamples := range heldOut {
+		lang.Samples = append(lang.Samples, shardSamples...)
+	}
+
+	if config.Report != "" {
+		err := writeReport(config.Report, config.Lang, buildReport(occurenceMap, ranked, config.Top))
+		if err != nil {
+			fatal(exitWrite, err)
+		}
+	}
 
 	// save it to the file
 	langJSON, err := json.Marshal(lang)
 	if err != nil {
-		log.Fatal(err)
+		fatal(exitFailure, err)
 	}
-	err = ioutil.WriteFile(config.File, langJSON, 0644)
+	err = os.WriteFile(config.File, langJSON, 0644)
 	if err != nil {
-		log.Fatal(err)
+		fatal(exitWrite, err)
 	}
 
 	bar.FinishPrint("Languge processing is done")
+	fmt.Printf("Skipped %d empty or template-only abstracts\n", totalBlank)
+	totalCases.print(os.Stdout)
+
+}
+
+// shardShare returns the share of shard i of n shards of a total limit. The shares differ by at
+// most one and add up to the total.
+func shardShare(total, n, i int) int {
+	share := total / n
+	if i < total%n {
+		share++
+	}
+	return share
+}
+
+// openURL returns a function downloading the wikipedia abstracts from url
+func openURL(url string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, &httpStatusError{URL: url, Status: resp.Status, Code: resp.StatusCode}
+		}
+		compression := compressionOf(resp.Request.URL.Path)
+		if !resp.Uncompressed {
+			if encoding := compressionOfEncoding(resp.Header.Get("Content-Encoding")); encoding != "" {
+				compression = encoding
+			}
+		}
+		return decompress(resp.Body, compression)
+	}
+}
 
+// openFile returns a function opening the local file with wikipedia abstracts, decompressing .gz
+// and .bz2 files
+func openFile(name string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		return decompress(f, compressionOf(name))
+	}
+}
+
+// firstSentence returns the first sentence of an abstract
+func firstSentence(abstract string) string {
+	abstract = strings.TrimSpace(abstract)
+	if i := strings.Index(abstract, ". "); i >= 0 {
+		return abstract[:i+1]
+	}
+	return abstract
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// merge combines profiles of the same language trained on different corpora into one profile
+func merge(args []string) {
+	config := struct {
+		File   string  `flag:"file,Output filename"`
+		Lang   string  `flag:"lang,Language of the merged profile, the language of the first profile if empty"`
+		Weight float64 `flag:"weight,Weight of the counts of the profiles after the first one"`
+	}{
+		Weight: 1,
+	}
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.File == "" || flags.NArg() < 2 {
+		fatalf(exitUsage, "-file and at least two profile files are required arguments")
+	}
+
+	merged, err := mergeProfiles(flags.Args(), config.Weight)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	if config.Lang != "" {
+		merged.Name = config.Lang
+	}
+	writeProfile(merged, config.File)
+	fmt.Printf("%s: %d profiles merged, %d n-grams written to %s\n", merged.Name, flags.NArg(), len(merged.Profile), config.File)
+}
+
+// mergeProfiles returns the language of the first profile file merged with the languages of the
+// other files, whose counts are multiplied by weight
+func mergeProfiles(files []string, weight float64) (langdet.Language, error) {
+	var merged langdet.Language
+	for i, file := range files {
+		language, err := readProfile(file)
+		if err != nil {
+			return merged, err
+		}
+		if i == 0 {
+			merged = language
+			continue
+		}
+		if err := merged.Merge(language, weight); err != nil {
+			return merged, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return merged, nil
+}
+
+// readProfile returns the single language of a json or binary profile file
+func readProfile(file string) (langdet.Language, error) {
+	detector, err := langdet.Config{Profiles: []string{file}}.NewDetector()
+	if err != nil {
+		return langdet.Language{}, err
+	}
+	languages := detector.Snapshot()
+	if len(languages) != 1 {
+		return langdet.Language{}, fmt.Errorf("%s has %d languages, expected a single profile", file, len(languages))
+	}
+	return languages[0], nil
+}
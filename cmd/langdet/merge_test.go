@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMerge(t *testing.T) {
+	Convey("Subject: Merge profile files", t, func() {
+		dir := t.TempDir()
+		write := func(name string, language langdet.Language) string {
+			data, err := json.Marshal(language)
+			So(err, ShouldBeNil)
+			path := filepath.Join(dir, name)
+			So(os.WriteFile(path, data, 0644), ShouldBeNil)
+			return path
+		}
+		train := func(text string) langdet.Language {
+			trainer := &langdet.Trainer{Depth: 3, KeepCounts: true}
+			trainer.Feed(text)
+			return trainer.Build("en")
+		}
+		wiki := write("wiki.json", train("the capital of the region"))
+		chat := write("chat.json", train("see you tomorrow"))
+
+		Convey("Profiles with counts should be merged into the first one", func() {
+			merged, err := mergeProfiles([]string{wiki, chat}, 1)
+			So(err, ShouldBeNil)
+			So(merged.Name, ShouldEqual, "en")
+			So(merged.Profile, ShouldContainKey, "see")
+			So(merged.Profile, ShouldContainKey, "cap")
+		})
+		Convey("Profiles without counts should fail with their file", func() {
+			plain := write("plain.json", langdet.Analyze("see you tomorrow", "en"))
+			_, err := mergeProfiles([]string{wiki, plain}, 1)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "plain.json")
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// migrate upgrades the legacy profiles of a directory into a single compiled bundle
+func migrate(args []string) {
+	config := struct {
+		In  string `flag:"in,Directory with the legacy json or binary profiles"`
+		Out string `flag:"out,Bundle file to write"`
+	}{}
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	autoflags.DefineFlagSet(fs, &config)
+	fs.Parse(args)
+	if config.In == "" || config.Out == "" {
+		fatalf(exitUsage, "-in and -out are required arguments")
+	}
+
+	f, err := os.Create(config.Out)
+	if err != nil {
+		fatal(exitWrite, err)
+	}
+	languages, err := langdet.Migrate(os.DirFS(config.In), ".", f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(config.Out)
+		fatal(exitCode(err), err)
+	}
+	for _, language := range languages {
+		fmt.Printf("%s: depth %d, %d ranks\n", language.Name, language.Depth, len(language.Profile))
+	}
+	fmt.Printf("%d profiles written to %s\n", len(languages), config.Out)
+}
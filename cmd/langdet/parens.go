@@ -0,0 +1,65 @@
+package main
+
+import "strings"
+
+// closingBrackets are the closing brackets of the opening brackets of parentheticals
+var closingBrackets = map[rune]rune{'(': ')', '[': ']', '（': '）'}
+
+// stripParentheticals removes parentheticals in round or square brackets, including nested ones,
+// and IPA transcriptions between slashes from an abstract. Wikipedia abstracts begin with
+// pronunciations and translations like "Paris (French: [paʁi])", which are written in other
+// languages than the abstract. Brackets that are not closed are kept.
+func stripParentheticals(abstract string) string {
+	var kept strings.Builder
+	var open []rune
+	start := 0
+	for i, r := range abstract {
+		if _, ok := closingBrackets[r]; ok {
+			if len(open) == 0 {
+				start = i
+			}
+			open = append(open, closingBrackets[r])
+			continue
+		}
+		if len(open) > 0 {
+			if r == open[len(open)-1] {
+				open = open[:len(open)-1]
+			}
+			continue
+		}
+		kept.WriteRune(r)
+	}
+	if len(open) > 0 {
+		kept.WriteString(abstract[start:])
+	}
+	return strings.Join(strings.Fields(stripIPA(kept.String())), " ")
+}
+
+// stripIPA removes the words between slashes that contain IPA letters or stress marks,
+// like "/ˈpærɪs/"
+func stripIPA(text string) string {
+	parts := strings.Split(text, "/")
+	var kept strings.Builder
+	kept.WriteString(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if i+1 < len(parts) && isIPA(parts[i]) {
+			kept.WriteString(parts[i+1])
+			i++
+			continue
+		}
+		kept.WriteString("/")
+		kept.WriteString(parts[i])
+	}
+	return kept.String()
+}
+
+// isIPA tells whether a text contains letters of the IPA extensions or spacing modifier letters
+// blocks, like ə or the stress mark ˈ
+func isIPA(text string) bool {
+	for _, r := range text {
+		if r >= '\u0250' && r <= '\u02FF' {
+			return true
+		}
+	}
+	return false
+}
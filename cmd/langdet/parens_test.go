@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStripParentheticals(t *testing.T) {
+	Convey("Subject: Strip parentheticals and IPA from abstracts", t, func() {
+		Convey("Nested parentheticals should be removed", func() {
+			So(stripParentheticals("Paris (French: [paʁi] (listen)) is the capital of France."), ShouldEqual, "Paris is the capital of France.")
+			So(stripParentheticals("日本（にほん）は国"), ShouldEqual, "日本は国")
+		})
+		Convey("IPA between slashes should be removed, other slashes kept", func() {
+			So(stripParentheticals("Paris /ˈpærɪs/ is a city and/or town"), ShouldEqual, "Paris is a city and/or town")
+		})
+		Convey("Brackets that are not closed should be kept", func() {
+			So(stripParentheticals("Open (bracket never closed"), ShouldEqual, "Open (bracket never closed")
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// errSaturated is returned for jobs that are shed because the queue of a workerPool is full
+var errSaturated = errors.New("too many concurrent requests")
+
+// workerPool runs jobs on a fixed number of workers, with a bounded queue of waiting jobs, so that
+// bursts of requests are shed instead of starting a goroutine each and collapsing the latency of all
+type workerPool struct {
+	workers   int
+	jobs      chan poolJob
+	busy      int64 // atomic
+	completed uint64
+	rejected  uint64
+	canceled  uint64
+}
+
+// poolJob is a job of a workerPool, done is closed when it has run
+type poolJob struct {
+	ctx  context.Context
+	run  func()
+	done chan struct{}
+}
+
+// newWorkerPool starts a pool of workers with a queue of up to queue waiting jobs
+func newWorkerPool(workers, queue int) *workerPool {
+	p := &workerPool{workers: workers, jobs: make(chan poolJob, queue)}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// work runs queued jobs, skipping those whose requests were canceled while they waited
+func (p *workerPool) work() {
+	for job := range p.jobs {
+		if job.ctx.Err() != nil {
+			atomic.AddUint64(&p.canceled, 1)
+			close(job.done)
+			continue
+		}
+		atomic.AddInt64(&p.busy, 1)
+		job.run()
+		atomic.AddInt64(&p.busy, -1)
+		atomic.AddUint64(&p.completed, 1)
+		close(job.done)
+	}
+}
+
+// do runs fn on a worker and waits for it. It returns errSaturated without running fn if the queue
+// is full, and the error of ctx if it is done before fn has run.
+func (p *workerPool) do(ctx context.Context, fn func()) error {
+	job := poolJob{ctx: ctx, run: fn, done: make(chan struct{})}
+	select {
+	case p.jobs <- job:
+	default:
+		atomic.AddUint64(&p.rejected, 1)
+		return errSaturated
+	}
+	select {
+	case <-job.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// writeMetrics writes the saturation metrics of the pool in the Prometheus text format
+func (p *workerPool) writeMetrics(w io.Writer) {
+	metrics := []struct {
+		name, kind, help string
+		value            interface{}
+	}{
+		{"langdet_workers", "gauge", "Number of detection workers.", p.workers},
+		{"langdet_workers_busy", "gauge", "Number of workers running a detection.", atomic.LoadInt64(&p.busy)},
+		{"langdet_queue_length", "gauge", "Number of detections waiting for a worker.", len(p.jobs)},
+		{"langdet_queue_capacity", "gauge", "Maximum number of detections waiting for a worker.", cap(p.jobs)},
+		{"langdet_detections_total", "counter", "Number of detections run by the workers.", atomic.LoadUint64(&p.completed)},
+		{"langdet_rejected_total", "counter", "Number of requests shed with 429 because the queue was full.", atomic.LoadUint64(&p.rejected)},
+		{"langdet_canceled_total", "counter", "Number of queued detections skipped because their request was canceled.", atomic.LoadUint64(&p.canceled)},
+	}
+	for _, metric := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", metric.name, metric.help, metric.name, metric.kind, metric.name, metric.value)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reportRow is a single ranked n-gram of the report
+type reportRow struct {
+	Rank     int
+	NGram    string
+	Count    int
+	Coverage float64 // fraction of all n-gram occurrences covered by this and the higher ranked n-grams
+}
+
+// buildReport returns the top ranked n-grams of a language with their counts and coverage
+func buildReport(occurenceMap, ranked map[string]int, top int) []reportRow {
+	total := 0
+	for _, count := range occurenceMap {
+		total += count
+	}
+	rows := make([]reportRow, 0, len(ranked))
+	for token, rank := range ranked {
+		rows = append(rows, reportRow{Rank: rank, NGram: token, Count: occurenceMap[token]})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Rank < rows[j].Rank })
+	if top > 0 && len(rows) > top {
+		rows = rows[:top]
+	}
+	covered := 0
+	for i := range rows {
+		covered += rows[i].Count
+		rows[i].Coverage = float64(covered) / float64(total)
+	}
+	return rows
+}
+
+// isReportFormat tells whether the extension of fileName is a supported report format
+func isReportFormat(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv", ".html", ".htm":
+		return true
+	}
+	return false
+}
+
+// writeReport writes the report as CSV or HTML to fileName, depending on its extension
+func writeReport(fileName, lang string, rows []reportRow) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		err = writeCSVReport(f, rows)
+	case ".html", ".htm":
+		err = writeHTMLReport(f, lang, rows)
+	default:
+		err = fmt.Errorf("unknown report format %q, use .csv or .html", filepath.Ext(fileName))
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func writeCSVReport(w io.Writer, rows []reportRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"rank", "ngram", "count", "coverage"})
+	for _, row := range rows {
+		cw.Write([]string{strconv.Itoa(row.Rank), row.NGram, strconv.Itoa(row.Count), strconv.FormatFloat(row.Coverage, 'f', 6, 64)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return strconv.FormatFloat(100*f, 'f', 2, 64) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>n-gram report: {{.Lang}}</title>
+<style>body{font-family:sans-serif}td,th{padding:2px 8px;text-align:right}td.ngram{text-align:left;font-family:monospace}</style>
+</head>
+<body>
+<h1>n-gram report: {{.Lang}}</h1>
+<h2>Coverage</h2>
+<svg width="600" height="300" viewBox="0 0 600 300" style="border:1px solid #ccc">
+<polyline fill="none" stroke="steelblue" stroke-width="2" points="{{.Curve}}"/>
+</svg>
+<p>x: rank 1 to {{len .Rows}}, y: fraction of all n-gram occurrences covered</p>
+<h2>Ranked n-grams</h2>
+<table>
+<tr><th>rank</th><th>n-gram</th><th>count</th><th>coverage</th></tr>
+{{range .Rows}}<tr><td>{{.Rank}}</td><td class="ngram">{{.NGram}}</td><td>{{.Count}}</td><td>{{percent .Coverage}}%</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func writeHTMLReport(w io.Writer, lang string, rows []reportRow) error {
+	points := make([]string, len(rows))
+	for i, row := range rows {
+		x := 600 * float64(i+1) / float64(len(rows))
+		y := 300 * (1 - row.Coverage)
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return reportTemplate.Execute(w, struct {
+		Lang  string
+		Rows  []reportRow
+		Curve string
+	}{lang, rows, strings.Join(points, " ")})
+}
@@ -0,0 +1,29 @@
+package main
+
+import "math/rand"
+
+// reservoir keeps a uniform random sample of up to size of the abstracts added to it, so that the
+// trained abstracts of -sample are representative of the whole dump (reservoir sampling)
+type reservoir struct {
+	size   int
+	rnd    *rand.Rand
+	seen   int
+	sample []string
+}
+
+// newReservoir returns a reservoir of a size whose sample is determined by the seed
+func newReservoir(size int, seed int64) *reservoir {
+	return &reservoir{size: size, rnd: rand.New(rand.NewSource(seed)), sample: make([]string, 0, size)}
+}
+
+// add offers an abstract to the sample, which keeps it with the probability size/seen
+func (r *reservoir) add(abstract string) {
+	r.seen++
+	if len(r.sample) < r.size {
+		r.sample = append(r.sample, abstract)
+		return
+	}
+	if j := r.rnd.Intn(r.seen); j < r.size {
+		r.sample[j] = abstract
+	}
+}
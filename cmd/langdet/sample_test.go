@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReservoir(t *testing.T) {
+	Convey("Subject: Sample abstracts of a whole dump", t, func() {
+		abstracts := make([]string, 100)
+		for i := range abstracts {
+			abstracts[i] = fmt.Sprint("abstract ", i)
+		}
+		sampled := func(size int, seed int64) []string {
+			r := newReservoir(size, seed)
+			for _, abstract := range abstracts {
+				r.add(abstract)
+			}
+			return r.sample
+		}
+
+		Convey("All abstracts should be kept if there are fewer than the size", func() {
+			So(sampled(200, 1), ShouldResemble, abstracts)
+		})
+		Convey("The same seed should sample the same abstracts", func() {
+			So(sampled(10, 1), ShouldHaveLength, 10)
+			So(sampled(10, 1), ShouldResemble, sampled(10, 1))
+			So(sampled(10, 1), ShouldNotResemble, sampled(10, 2))
+		})
+		Convey("The sample should not be biased towards the first abstracts", func() {
+			late := 0
+			for seed := int64(0); seed < 100; seed++ {
+				for _, abstract := range sampled(10, seed) {
+					var i int
+					fmt.Sscanf(abstract, "abstract %d", &i)
+					if i >= 50 {
+						late++
+					}
+				}
+			}
+			// half of the 1000 sampled abstracts are expected from the second half of the dump
+			So(late, ShouldBeBetween, 400, 600)
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// filterScript replaces all letters of text that are not written in script with spaces,
+// so that words of other scripts don't create tokens
+func filterScript(text string, script *unicode.RangeTable) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) && !unicode.Is(script, r) {
+			return ' '
+		}
+		return r
+	}, text)
+}
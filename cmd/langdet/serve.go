@@ -0,0 +1,533 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// detectResponse is the response of POST /detect
+type detectResponse struct {
+	// Language is the closest language, or undefined if the detection is not reliable
+	Language string
+	// DisplayName is the name of Language in the language of the ?locale= parameter
+	DisplayName string
+	// Confidence is the confidence of the closest language between 0 and 1
+	Confidence float64
+	Reliable   bool
+	// Script and Direction are the unicode script and writing direction of most letters of the
+	// text, see langdet.TextInfo
+	Script    string `json:",omitempty"`
+	Direction string
+	// Bundle is the profiles that detected the language, stable or canary, if a canary is served
+	Bundle string `json:",omitempty"`
+	// Results are the DetectionResults of all languages, with ?all=1 only
+	Results []langdet.DetectionResult `json:",omitempty"`
+}
+
+// languageResponse is an element of the response of GET /languages
+type languageResponse struct {
+	Name        string
+	Tag         langdet.LanguageTag
+	DisplayName string
+	Direction   string
+}
+
+// accessLogEntry is a JSON line of the access log. The text is redacted unless -log-text is set.
+// gRPC calls are logged with the method GRPC, their full method name as path and their gRPC status
+// code as status.
+type accessLogEntry struct {
+	Time       time.Time
+	Method     string
+	Path       string
+	Status     int
+	Latency    float64 // milliseconds
+	InputBytes int
+	Language   string  `json:",omitempty"`
+	Confidence float64 `json:",omitempty"`
+	Reliable   bool    `json:",omitempty"`
+	Bundle     string  `json:",omitempty"`
+	Text       string  `json:",omitempty"`
+}
+
+// accessLogKey is the context key of the accessLogEntry of a request
+type accessLogKey struct{}
+
+// statusRecorder records the status of a response for the access log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// server serves the detection API with a shared detector, which is safe for concurrent use
+type server struct {
+	detector *langdet.Detector
+	// canary detects canaryPercent percent of the texts, it is nil if no canary is served
+	canary        *langdet.Detector
+	canaryPercent int
+	maxBytes      int64
+	// authorize validates the bearer tokens of requests, requests are not authenticated if it is nil
+	authorize func(token string) bool
+	// cache caches the responses of /detect and enables ETags, it is nil if caching is disabled
+	cache *responseCache
+	// pool runs the detections with bounded concurrency, they run on the goroutine of their request
+	// if it is nil
+	pool *workerPool
+	// stats collects the detections of the detector and the canary for /metrics, they are not
+	// collected if it is nil
+	stats  *langdet.DetectorStats
+	logger *json.Encoder
+	logMu  sync.Mutex
+}
+
+// serve runs the HTTP/JSON detection API
+func serve(args []string) {
+	config := struct {
+		Addr          string  `flag:"addr,Address to listen on"`
+		Profiles      string  `flag:"profiles,Directory, file or bundle with the profiles to detect, the embedded default profiles if empty"`
+		Config        string  `flag:"config,JSON or YAML config file of the detector, instead of -profiles"`
+		MinConfidence float64 `flag:"min-confidence,Minimum confidence of detected languages, 0 for the default"`
+		MaxBytes      int64   `flag:"max-bytes,Maximum size of a request body in bytes"`
+		AccessLog     bool    `flag:"access-log,Write a JSON line per request to stderr"`
+		LogText       bool    `flag:"log-text,Include the input texts in the access log instead of their length and hash"`
+		TLSCert       string  `flag:"tls-cert,Certificate file to serve HTTPS, with -tls-key"`
+		TLSKey        string  `flag:"tls-key,Private key file of -tls-cert"`
+		ClientCA      string  `flag:"client-ca,CA certificates file to require and verify client certificates (mTLS)"`
+		TokenFile     string  `flag:"token-file,File with accepted bearer tokens, one per line"`
+		Cache         int     `flag:"cache,Number of cached /detect responses, 0 to disable caching and ETags"`
+		Watch         bool    `flag:"watch,Reload the profiles of the -profiles directory when its files change"`
+		Concurrency   int     `flag:"concurrency,Number of detections that run concurrently"`
+		Queue         int     `flag:"queue,Number of requests waiting for a detection before further requests are rejected with 429"`
+		Canary        string  `flag:"canary,Directory, file or bundle with the canary profiles that detect -canary-percent of the texts"`
+		CanaryPercent int     `flag:"canary-percent,Percentage of the texts detected with the -canary profiles"`
+		GRPC          string  `flag:"grpc,Address to serve the gRPC API on besides the HTTP API, e.g. :9090"`
+	}{
+		Addr:        ":8080",
+		MaxBytes:    1 << 20,
+		AccessLog:   true,
+		Concurrency: runtime.NumCPU(),
+		Queue:       64,
+	}
+	var tokens stringList
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Var(&tokens, "token", "Accepted bearer token, can be repeated; requests are not authenticated without tokens")
+	flags.Parse(args)
+	for _, token := range tokens {
+		if strings.TrimSpace(token) == "" {
+			fatalf(exitUsage, "-token must not be empty")
+		}
+	}
+	if config.TokenFile != "" {
+		fileTokens, err := readTokens(config.TokenFile)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		tokens = append(tokens, fileTokens...)
+	}
+
+	langdet.PIISafe = !config.LogText
+	detector, err := loadDetector(config.Profiles, config.Config, config.MinConfidence)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	stats := &langdet.DetectorStats{}
+	detector.Collector = stats
+	if config.Watch {
+		if info, err := os.Stat(config.Profiles); err != nil || !info.IsDir() || config.Cache > 0 {
+			fatalf(exitUsage, "-watch requires a -profiles directory and no -cache")
+		}
+		go watchProfiles(&detector, config.Profiles)
+	}
+	if config.Concurrency < 1 || config.Queue < 0 {
+		fatalf(exitUsage, "-concurrency must be at least 1 and -queue must not be negative")
+	}
+	s := &server{detector: &detector, maxBytes: config.MaxBytes, pool: newWorkerPool(config.Concurrency, config.Queue), stats: stats}
+	if config.Canary != "" {
+		if config.CanaryPercent < 0 || config.CanaryPercent > 100 {
+			fatalf(exitUsage, "-canary-percent must be between 0 and 100")
+		}
+		canary, err := loadDetector(config.Canary, "", config.MinConfidence)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		canary.Collector = stats
+		s.canary, s.canaryPercent = &canary, config.CanaryPercent
+		log.Printf("routing %d%% of the texts to %d canary languages", config.CanaryPercent, len(canary.Snapshot()))
+	}
+	if config.AccessLog {
+		s.logger = json.NewEncoder(os.Stderr)
+	}
+	if len(tokens) > 0 {
+		s.authorize = staticTokens(tokens)
+	}
+	if config.Cache > 0 {
+		version, err := profilesVersion(&detector)
+		if err != nil {
+			fatal(exitFailure, err)
+		}
+		if s.canary != nil {
+			canaryVersion, err := profilesVersion(s.canary)
+			if err != nil {
+				fatal(exitFailure, err)
+			}
+			version = fmt.Sprintf("%s+%s@%d", version, canaryVersion, s.canaryPercent)
+		}
+		s.cache = newResponseCache(config.Cache, version)
+	}
+	httpServer := &http.Server{
+		Addr:              config.Addr,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if config.TLSCert == "" && config.ClientCA != "" {
+		fatalf(exitUsage, "-client-ca requires -tls-cert and -tls-key")
+	}
+	if config.TLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(config.TLSCert, config.TLSKey)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		httpServer.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	if config.ClientCA != "" {
+		pool, err := readCertPool(config.ClientCA)
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+		httpServer.TLSConfig.ClientCAs = pool
+		httpServer.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if config.GRPC != "" {
+		listener, err := net.Listen("tcp", config.GRPC)
+		if err != nil {
+			fatal(exitFailure, err)
+		}
+		log.Printf("serving the gRPC API on %s", config.GRPC)
+		go func() {
+			fatal(exitFailure, s.grpcServer(httpServer.TLSConfig).Serve(listener))
+		}()
+	}
+
+	if httpServer.TLSConfig == nil {
+		log.Printf("serving %d languages on http://%s", len(detector.Snapshot()), config.Addr)
+		fatal(exitFailure, httpServer.ListenAndServe())
+	}
+	log.Printf("serving %d languages on https://%s", len(detector.Snapshot()), config.Addr)
+	fatal(exitFailure, httpServer.ListenAndServeTLS("", ""))
+}
+
+// watchProfiles reloads the languages of the detector when the profile files of dir change and
+// logs the reloads
+func watchProfiles(detector *langdet.Detector, dir string) {
+	err := detector.WatchDir(context.Background(), dir, 0, func(event langdet.ReloadEvent) {
+		if event.Err != nil {
+			log.Printf("WARNING: could not reload %s: %v", event.File, event.Err)
+			return
+		}
+		log.Printf("%s language %s from %s", event.Op, event.Language, event.File)
+	})
+	fatal(exitCode(err), err)
+}
+
+// handler returns the handler of all endpoints of the server
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/detect", s.handleDetect)
+	mux.HandleFunc("/languages", s.handleLanguages)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return s.logRequests(s.authenticate(mux))
+}
+
+// authenticate requires a bearer token accepted by the authorize function of the server, if it has one
+func (s *server) authenticate(next http.Handler) http.Handler {
+	if s.authorize == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := bearerToken(r); ok && s.authorize(token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer realm="langdet"`)
+		writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+	})
+}
+
+// bearerToken returns the token of the Bearer authorization header of a request
+func bearerToken(r *http.Request) (string, bool) {
+	return parseBearer(r.Header.Get("Authorization"))
+}
+
+// parseBearer returns the token of a Bearer authorization header
+func parseBearer(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// staticTokens returns an authorize function accepting a fixed set of tokens
+func staticTokens(tokens []string) func(token string) bool {
+	return func(token string) bool {
+		accepted := false
+		for _, t := range tokens {
+			if t != "" && subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+				accepted = true
+			}
+		}
+		return accepted
+	}
+}
+
+// logRequests writes an access log entry for every request, if the access log is enabled.
+// Handlers add the outcome of detections to the entry of their request.
+func (s *server) logRequests(next http.Handler) http.Handler {
+	if s.logger == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &accessLogEntry{Time: start, Method: r.Method, Path: r.URL.Path}
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), accessLogKey{}, entry)))
+		entry.Status = recorder.status
+		s.writeLog(entry, start)
+	})
+}
+
+// writeLog writes an access log entry of a request that started at start
+func (s *server) writeLog(entry *accessLogEntry, start time.Time) {
+	entry.Latency = float64(time.Since(start)) / float64(time.Millisecond)
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	s.logger.Encode(entry)
+}
+
+// logEntry returns the access log entry of a request, or a discarded one if it is not logged
+func logEntry(r *http.Request) *accessLogEntry {
+	return contextLogEntry(r.Context())
+}
+
+// contextLogEntry returns the access log entry of the context of a request or gRPC call, or a
+// discarded one if it is not logged
+func contextLogEntry(ctx context.Context) *accessLogEntry {
+	if entry, ok := ctx.Value(accessLogKey{}).(*accessLogEntry); ok {
+		return entry
+	}
+	return &accessLogEntry{}
+}
+
+// handleDetect detects the language of the text of a POST body
+func (s *server) handleDetect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "use POST with the text as body")
+		return
+	}
+	entry := logEntry(r)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBytes))
+	entry.InputBytes = len(body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	text := string(body)
+	entry.Text = langdet.RedactText(text)
+
+	var key string
+	if s.cache != nil {
+		key = s.cache.key(text, r.URL.Query().Get("all"), r.URL.Query().Get("locale"))
+		etag := s.cache.etag(key)
+		w.Header().Set("ETag", etag)
+		if ifNoneMatch(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if response, ok := s.cache.get(key); ok {
+			entry.Language, entry.Confidence, entry.Reliable, entry.Bundle = response.Language, response.Confidence, response.Reliable, response.Bundle
+			writeJSON(w, http.StatusOK, response)
+			return
+		}
+	}
+
+	detector, bundle := s.route(text)
+	response := detectResponse{}
+	err = s.run(r.Context(), func() {
+		response = detectText(detector, bundle, text, r.URL.Query().Get("locale"), r.URL.Query().Get("all") == "1")
+	})
+	if errors.Is(err, errSaturated) {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	entry.Language, entry.Confidence, entry.Reliable, entry.Bundle = response.Language, response.Confidence, response.Reliable, response.Bundle
+	if s.cache != nil {
+		s.cache.add(key, response)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// detectText returns the response of the detection of a text with the detector of a bundle, with
+// the display name in the language of locale and with the results of all languages if all is set
+func detectText(detector *langdet.Detector, bundle, text, locale string, all bool) detectResponse {
+	name, confidence, reliable := detector.GetClosestLanguageWithConfidence(text)
+	if !reliable {
+		name = "undefined"
+	}
+	info := langdet.DetectTextInfo(text)
+	response := detectResponse{
+		Language:    name,
+		DisplayName: langdet.DefaultMeta.DisplayName(name, locale),
+		Confidence:  confidence,
+		Reliable:    reliable,
+		Script:      info.Script,
+		Direction:   info.Direction,
+		Bundle:      bundle,
+	}
+	if all {
+		response.Results = detector.GetLanguages(text)
+	}
+	return response
+}
+
+// route returns the detector of a text and the name of its bundle, stable or canary, or no name if
+// no canary is served. Texts are routed by their hash, so that the same text is always detected
+// with the same profiles.
+func (s *server) route(text string) (*langdet.Detector, string) {
+	if s.canary == nil {
+		return s.detector, ""
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(text))
+	if int(hash.Sum32()%100) < s.canaryPercent {
+		return s.canary, "canary"
+	}
+	return s.detector, "stable"
+}
+
+// run runs a detection on the worker pool of the server, or directly if it has none
+func (s *server) run(ctx context.Context, detection func()) error {
+	if s.pool == nil {
+		detection()
+		return nil
+	}
+	return s.pool.do(ctx, detection)
+}
+
+// handleMetrics exposes the saturation metrics of the worker pool and the detection counters and
+// latencies in the Prometheus text format
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if s.pool != nil {
+		s.pool.writeMetrics(w)
+	}
+	if s.stats != nil {
+		s.stats.WritePrometheus(w)
+	}
+}
+
+// handleLanguages lists the languages of the detector, with their display names in the language
+// of the ?locale= parameter
+func (s *server) handleLanguages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	locale := r.URL.Query().Get("locale")
+	languages := []languageResponse{}
+	for _, language := range s.detector.Snapshot() {
+		languages = append(languages, languageResponse{
+			Name:        language.Name,
+			Tag:         language.LookupTag(),
+			DisplayName: langdet.DefaultMeta.DisplayName(language.Name, locale),
+			Direction:   language.TextDirection(),
+		})
+	}
+	writeJSON(w, http.StatusOK, languages)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, struct{ Error string }{message})
+}
+
+// readCertPool returns a pool with the PEM encoded certificates of a file
+func readCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("no certificates found in " + path)
+	}
+	return pool, nil
+}
+
+// readTokens returns the tokens of a token file, one per line. Blank lines are ignored, and a file
+// without tokens is an error, as it would disable the authentication.
+func readTokens(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tokens := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			tokens = append(tokens, line)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("no tokens found in " + path)
+	}
+	return tokens, nil
+}
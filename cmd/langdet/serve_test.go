@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newTestServer returns a server with an english and a french language
+func newTestServer() *server {
+	d := langdet.NewDetector()
+	d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say? ", "en")
+	d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "fr")
+	return &server{detector: &d, maxBytes: 64}
+}
+
+// request sends a request to the handler of a server and returns the response
+func request(s *server, method, target, body string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, values := range header {
+		r.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, r)
+	return w
+}
+
+func TestServe(t *testing.T) {
+	Convey("Subject: Serve the detection API", t, func() {
+		s := newTestServer()
+
+		Convey("POST /detect should return the closest language", func() {
+			w := request(s, http.MethodPost, "/detect?all=1", "what is your language", nil)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			response := detectResponse{}
+			So(json.NewDecoder(w.Body).Decode(&response), ShouldBeNil)
+			So(response.Language, ShouldEqual, "en")
+			So(response.Reliable, ShouldBeTrue)
+			So(response.Script, ShouldEqual, "Latin")
+			So(response.Direction, ShouldEqual, langdet.LeftToRight)
+			So(len(response.Results), ShouldEqual, 2)
+		})
+		Convey("Bodies larger than the maximum size should be rejected", func() {
+			w := request(s, http.MethodPost, "/detect", strings.Repeat("what is your language ", 4), nil)
+			So(w.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+		})
+		Convey("GET /languages should list the languages", func() {
+			w := request(s, http.MethodGet, "/languages", "", nil)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			languages := []languageResponse{}
+			So(json.NewDecoder(w.Body).Decode(&languages), ShouldBeNil)
+			So(len(languages), ShouldEqual, 2)
+			So(languages[1].Name, ShouldEqual, "fr")
+			So(languages[1].DisplayName, ShouldEqual, "French")
+		})
+		Convey("Display names should be localized by the locale parameter", func() {
+			w := request(s, http.MethodGet, "/languages?locale=de-AT", "", nil)
+			languages := []languageResponse{}
+			So(json.NewDecoder(w.Body).Decode(&languages), ShouldBeNil)
+			So(languages[1].DisplayName, ShouldEqual, "Französisch")
+			w = request(s, http.MethodPost, "/detect?locale=fr", "what is your language", nil)
+			response := detectResponse{}
+			So(json.NewDecoder(w.Body).Decode(&response), ShouldBeNil)
+			So(response.DisplayName, ShouldEqual, "anglais")
+		})
+		Convey("Requests should be logged with redacted texts", func() {
+			log := &bytes.Buffer{}
+			s.logger = json.NewEncoder(log)
+			langdet.PIISafe = true
+			defer func() { langdet.PIISafe = false }()
+
+			request(s, http.MethodPost, "/detect", "what is your language", nil)
+			entry := accessLogEntry{}
+			So(json.Unmarshal(log.Bytes(), &entry), ShouldBeNil)
+			So(entry.Status, ShouldEqual, http.StatusOK)
+			So(entry.InputBytes, ShouldEqual, 21)
+			So(entry.Language, ShouldEqual, "en")
+			So(entry.Text, ShouldEqual, langdet.RedactText("what is your language"))
+			So(log.String(), ShouldNotContainSubstring, "what is your language")
+		})
+		Convey("Requests should require an accepted bearer token if there are tokens", func() {
+			s.authorize = staticTokens([]string{"secret"})
+			So(request(s, http.MethodGet, "/languages", "", nil).Code, ShouldEqual, http.StatusUnauthorized)
+			for _, authorization := range []string{"secret", "Bearer", "Bearer ", "Bearer other", "Basic secret"} {
+				header := http.Header{"Authorization": {authorization}}
+				So(request(s, http.MethodGet, "/languages", "", header).Code, ShouldEqual, http.StatusUnauthorized)
+			}
+			header := http.Header{"Authorization": {"Bearer secret"}}
+			So(request(s, http.MethodGet, "/languages", "", header).Code, ShouldEqual, http.StatusOK)
+		})
+		Convey("Token files should not accept empty tokens", func() {
+			path := filepath.Join(t.TempDir(), "tokens")
+			os.WriteFile(path, []byte("one\n\n  two \n"), 0600)
+			tokens, err := readTokens(path)
+			So(err, ShouldBeNil)
+			So(tokens, ShouldResemble, []string{"one", "two"})
+			os.WriteFile(path, []byte("\n \n"), 0600)
+			_, err = readTokens(path)
+			So(err, ShouldNotBeNil)
+			So(staticTokens([]string{""})(""), ShouldBeFalse)
+		})
+		Convey("Cached responses should be revalidated by their ETag", func() {
+			s.cache = newResponseCache(1, "v1")
+			w := request(s, http.MethodPost, "/detect", "what is your language", nil)
+			etag := w.Header().Get("ETag")
+			So(etag, ShouldNotBeEmpty)
+			So(s.cache.order.Len(), ShouldEqual, 1)
+			cached := request(s, http.MethodPost, "/detect", "what is your language", nil)
+			So(cached.Body.String(), ShouldEqual, w.Body.String())
+			header := http.Header{"If-None-Match": {`"other", ` + etag}}
+			So(request(s, http.MethodPost, "/detect", "what is your language", header).Code, ShouldEqual, http.StatusNotModified)
+			w = request(s, http.MethodPost, "/detect?locale=de", "what is your language", header)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("ETag"), ShouldNotEqual, etag)
+			So(s.cache.order.Len(), ShouldEqual, 1)
+
+			version, err := profilesVersion(s.detector)
+			So(err, ShouldBeNil)
+			s.detector.MinimumConfidence = 0.9
+			changed, _ := profilesVersion(s.detector)
+			So(changed, ShouldNotEqual, version)
+		})
+		Convey("Detections should be shed with 429 when the workers and the queue are full", func() {
+			s.pool = newWorkerPool(1, 1)
+			So(request(s, http.MethodPost, "/detect", "what is your language", nil).Code, ShouldEqual, http.StatusOK)
+
+			release := make(chan struct{})
+			running := make(chan struct{})
+			go s.pool.do(context.Background(), func() {
+				close(running)
+				<-release
+			})
+			<-running
+			queued := make(chan error, 1)
+			go func() { queued <- s.pool.do(context.Background(), func() {}) }()
+			for len(s.pool.jobs) == 0 {
+				runtime.Gosched()
+			}
+			w := request(s, http.MethodPost, "/detect", "what is your language", nil)
+			So(w.Code, ShouldEqual, http.StatusTooManyRequests)
+			So(w.Header().Get("Retry-After"), ShouldEqual, "1")
+			close(release)
+			So(<-queued, ShouldBeNil)
+
+			metrics := request(s, http.MethodGet, "/metrics", "", nil).Body.String()
+			So(metrics, ShouldContainSubstring, "langdet_workers 1\n")
+			So(metrics, ShouldContainSubstring, "langdet_rejected_total 1\n")
+			So(metrics, ShouldContainSubstring, "langdet_detections_total 3\n")
+		})
+		Convey("The detections of the detector should be exposed in /metrics", func() {
+			s.stats = &langdet.DetectorStats{}
+			s.detector.Collector = s.stats
+			request(s, http.MethodPost, "/detect", "what is your language", nil)
+			metrics := request(s, http.MethodGet, "/metrics", "", nil).Body.String()
+			So(metrics, ShouldContainSubstring, "langdet_detector_detections_total{method=\"closest\"} 1\n")
+			So(metrics, ShouldContainSubstring, "langdet_detector_languages_total{language=\"en\"} 1\n")
+			So(metrics, ShouldContainSubstring, "langdet_detector_latency_seconds_count 1\n")
+		})
+		Convey("A percentage of the texts should be routed to the canary", func() {
+			canary := langdet.NewDetector()
+			canary.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say? ", "en-canary")
+			s.canary = &canary
+			detect := func(text string) detectResponse {
+				response := detectResponse{}
+				So(json.NewDecoder(request(s, http.MethodPost, "/detect", text, nil).Body).Decode(&response), ShouldBeNil)
+				return response
+			}
+			So(detect("what is your language").Bundle, ShouldEqual, "stable")
+			So(detect("what is your language").Language, ShouldEqual, "en")
+			s.canaryPercent = 100
+			So(detect("what is your language").Bundle, ShouldEqual, "canary")
+			So(detect("what is your language").Language, ShouldEqual, "en-canary")
+
+			s.canaryPercent = 30
+			canaried := 0
+			for i := 0; i < 200; i++ {
+				if _, bundle := s.route(fmt.Sprintf("text %d", i)); bundle == "canary" {
+					canaried++
+				}
+			}
+			So(canaried, ShouldBeBetween, 40, 80)
+			So(detect("what is your language").Bundle, ShouldEqual, detect("what is your language").Bundle)
+		})
+		Convey("Other methods should not be allowed", func() {
+			So(request(s, http.MethodGet, "/detect", "", nil).Code, ShouldEqual, http.StatusMethodNotAllowed)
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"unicode"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// corpusStats collects statistics about the abstracts of a training corpus
+type corpusStats struct {
+	Records int
+	Runes   int
+	Letters int
+	Scripts map[string]int
+}
+
+// add updates the statistics with a single abstract
+func (s *corpusStats) add(abstract string) {
+	if s.Scripts == nil {
+		s.Scripts = make(map[string]int)
+	}
+	s.Records++
+	for _, r := range abstract {
+		s.Runes++
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		s.Letters++
+		s.Scripts[langdet.ScriptOf(r)]++
+	}
+}
+
+// merge adds the statistics of other to s
+func (s *corpusStats) merge(other corpusStats) {
+	if s.Scripts == nil {
+		s.Scripts = make(map[string]int)
+	}
+	s.Records += other.Records
+	s.Runes += other.Runes
+	s.Letters += other.Letters
+	for script, count := range other.Scripts {
+		s.Scripts[script] += count
+	}
+}
+
+// print writes a report of the statistics to w
+func (s *corpusStats) print(w io.Writer, distinctNGrams int) {
+	fmt.Fprintf(w, "records:             %d\n", s.Records)
+	if s.Records > 0 {
+		fmt.Fprintf(w, "avg abstract length: %.1f characters\n", float64(s.Runes)/float64(s.Records))
+	}
+	fmt.Fprintf(w, "distinct n-grams:    %d\n", distinctNGrams)
+	fmt.Fprintln(w, "scripts:")
+	scripts := make([]string, 0, len(s.Scripts))
+	for script := range s.Scripts {
+		scripts = append(scripts, script)
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		if s.Scripts[scripts[i]] == s.Scripts[scripts[j]] {
+			return scripts[i] < scripts[j]
+		}
+		return s.Scripts[scripts[i]] > s.Scripts[scripts[j]]
+	})
+	for _, script := range scripts {
+		fmt.Fprintf(w, "    %-12s %6.2f%%\n", script, 100*float64(s.Scripts[script])/float64(s.Letters))
+	}
+}
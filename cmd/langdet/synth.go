@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// maxSynthWordLength is the maximum number of letters of a synthetic word
+const maxSynthWordLength = 12
+
+// synth generates a synthetic corpus of wikipedia abstract files from the profiles of a directory,
+// one <language>.xml file per language, to test the training pipeline end to end
+func synth(args []string) {
+	config := struct {
+		Profiles string `flag:"profiles,Directory with the profiles to sample from"`
+		Out      string `flag:"out,Directory to write the <language>.xml abstract files to"`
+		Docs     int    `flag:"docs,Number of abstracts per language"`
+		Words    int    `flag:"words,Number of words per abstract"`
+		Seed     int64  `flag:"seed,Seed of the random generator"`
+	}{
+		Docs:  100,
+		Words: 40,
+		Seed:  1,
+	}
+	fs := flag.NewFlagSet("synth", flag.ExitOnError)
+	autoflags.DefineFlagSet(fs, &config)
+	fs.Parse(args)
+	if config.Profiles == "" || config.Out == "" {
+		fatalf(exitUsage, "-profiles and -out are required arguments")
+	}
+
+	detector := langdet.NewDetector()
+	if err := detector.LoadLanguagesFromDir(config.Profiles); err != nil {
+		fatal(exitCode(err), err)
+	}
+	if err := os.MkdirAll(config.Out, 0755); err != nil {
+		fatal(exitWrite, err)
+	}
+	rnd := rand.New(rand.NewSource(config.Seed))
+	for _, language := range detector.Snapshot() {
+		chain := newSynthChain(language.Profile)
+		if len(chain["__"]) == 0 {
+			log.Printf("%s: the profile has no 3-grams to sample words from, skipped", language.Name)
+			continue
+		}
+		name := filepath.Join(config.Out, language.Name+".xml")
+		if err := writeSynthCorpus(name, chain, rnd, config.Docs, config.Words); err != nil {
+			fatal(exitWrite, err)
+		}
+		fmt.Printf("%s: %d abstracts written to %s\n", language.Name, config.Docs, name)
+	}
+}
+
+// synthChain maps two letters to the letters that follow them in the 3-grams of a profile,
+// weighted by their rank. Word boundaries are the "_" padding of the profile.
+type synthChain map[string][]synthNext
+
+type synthNext struct {
+	letter string
+	weight int
+}
+
+// newSynthChain creates the chain of the 3-grams of a profile
+func newSynthChain(profile map[string]int) synthChain {
+	maxRank := 0
+	for _, rank := range profile {
+		if rank > maxRank {
+			maxRank = rank
+		}
+	}
+	tokens := make([]string, 0, len(profile))
+	for token := range profile {
+		if utf8.ValidString(token) && utf8.RuneCountInString(token) == 3 {
+			tokens = append(tokens, token)
+		}
+	}
+	// sorted for a reproducible corpus with the same seed
+	sort.Strings(tokens)
+	chain := synthChain{}
+	for _, token := range tokens {
+		letters := []rune(token)
+		state := string(letters[:2])
+		chain[state] = append(chain[state], synthNext{string(letters[2]), maxRank - profile[token] + 1})
+	}
+	return chain
+}
+
+// word generates a word starting at the word boundary, or an empty string
+func (c synthChain) word(rnd *rand.Rand) string {
+	var b strings.Builder
+	state := "__"
+	for i := 0; i < maxSynthWordLength; i++ {
+		next := c[state]
+		total := 0
+		for _, n := range next {
+			total += n.weight
+		}
+		if total == 0 {
+			break
+		}
+		pick := rnd.Intn(total)
+		letter := ""
+		for _, n := range next {
+			if pick -= n.weight; pick < 0 {
+				letter = n.letter
+				break
+			}
+		}
+		if letter == "_" {
+			break
+		}
+		b.WriteString(letter)
+		state = string([]rune(state)[1:]) + letter
+	}
+	return b.String()
+}
+
+// writeSynthCorpus writes docs abstracts of the given number of words to a wikipedia abstract file
+func writeSynthCorpus(name string, chain synthChain, rnd *rand.Rand, docs, words int) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "<feed>")
+	for i := 0; i < docs; i++ {
+		abstract := make([]string, 0, words)
+		for len(abstract) < words {
+			if word := chain.word(rnd); word != "" {
+				abstract = append(abstract, word)
+			}
+		}
+		fmt.Fprintf(w, "<doc>\n<title>Synthetic %d</title>\n<abstract>", i+1)
+		xml.EscapeText(w, []byte(strings.Join(abstract, " ")+"."))
+		fmt.Fprintln(w, "</abstract>\n</doc>")
+	}
+	fmt.Fprintln(w, "</feed>")
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
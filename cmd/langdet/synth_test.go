@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSynth(t *testing.T) {
+	Convey("Subject: Synthetic corpora", t, func() {
+		chain := newSynthChain(map[string]int{"__a": 1, "_ab": 2, "_ac": 3, "ab_": 4, "ac_": 5, "abc": 6, "bc_": 7})
+		Convey("Words should follow the 3-grams of the profile", func() {
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				So(chain.word(rnd), ShouldBeIn, []string{"ab", "ac", "abc"})
+			}
+		})
+		Convey("The same seed should write the same corpus", func() {
+			dir := t.TempDir()
+			write := func(name string, seed int64) []byte {
+				name = filepath.Join(dir, name)
+				So(writeSynthCorpus(name, chain, rand.New(rand.NewSource(seed)), 5, 10), ShouldBeNil)
+				data, err := os.ReadFile(name)
+				So(err, ShouldBeNil)
+				return data
+			}
+			first := write("first.xml", 1)
+			So(write("second.xml", 1), ShouldResemble, first)
+			So(write("other.xml", 2), ShouldNotResemble, first)
+		})
+	})
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// maxTrainLineSize is the maximum size of a document in line mode
+const maxTrainLineSize = 16 * 1024 * 1024
+
+// trainFlags are the flags of the train command
+type trainFlags struct {
+	Lang       string `flag:"lang,Language of the texts"`
+	File       string `flag:"file,Output filename"`
+	Depth      int    `flag:"depth,Occurence map depth, 0 to select it by the script of the texts"`
+	MaxNGrams  int    `flag:"max-ngrams,Number of ranked n-grams of the profile, 0 for the default size"`
+	MaxTokens  int    `flag:"max-tokens,Older name of -max-ngrams"`
+	Words      int    `flag:"words,Number of ranked words of the word profile, 0 for no word profile"`
+	Limit      int    `flag:"limit,Maximum number of documents to process, 0 for all"`
+	Lowercase  bool   `flag:"lowercase,Lowercase the texts before training, for detectors that lowercase their input"`
+	Variants   bool   `flag:"variants,Also write the case-folded variant of the profile to <file>.folded.json, in the same pass"`
+	Tokenize   bool   `flag:"tokenize,Drop URLs, email addresses, mentions, numbers and punctuation before training"`
+	WholeFile  bool   `flag:"whole-file,Treat every file as a single document instead of one document per line"`
+	KeepCounts bool   `flag:"keep-counts,Store the raw n-gram counts with the profile, so that it can be updated later"`
+	Source     string `flag:"source,Description of the training corpus stored with the profile, the input paths if empty"`
+	Corpus     string `flag:"corpus,Format of the inputs: text, tatoeba, opus or opus-tsv; tatoeba and opus are downloaded without inputs"`
+	Column     int    `flag:"column,Column of the language in opus-tsv inputs, starting at 1"`
+	Dedup      bool   `flag:"dedup,Train duplicate sentences only once"`
+	MinLength  int    `flag:"min-length,Minimum number of characters of trained sentences"`
+	MaxLength  int    `flag:"max-length,Maximum number of characters of trained sentences, 0 for no limit"`
+	Translit   string `flag:"transliterate,Transliterate the texts before training with a built-in table: ru-Latn or el-Latn"`
+
+	MaxCapitalized float64 `flag:"max-capitalized,Drop sentences whose fraction of capitalized words besides the first is above this, e.g. lists of names, 0 keeps all"`
+
+	Format     string  `flag:"format,Format of labeled datasets with a text and a label per record: csv, tsv or jsonl; the profiles of all labels are written to -out"`
+	TextField  string  `flag:"text-field,Field or header column of the texts of -format"`
+	LabelField string  `flag:"label-field,Field or header column of the labels of -format"`
+	Out        string  `flag:"out,Directory to write the <label>.json profiles of -format to"`
+	Validation float64 `flag:"validation,Fraction of the records of -format held out of training to report the accuracy of the profiles on"`
+	Seed       int64   `flag:"seed,Seed of the random split of -validation"`
+
+	Checkpoint      string `flag:"checkpoint,File to save the state of the training to every -checkpoint-every records and when interrupted"`
+	CheckpointEvery int64  `flag:"checkpoint-every,Number of records read between the saved checkpoints"`
+	Resume          bool   `flag:"resume,Resume the training from -checkpoint, skipping the records read before"`
+	Progress        bool   `flag:"progress,Print the number of trained documents and n-grams to stderr while training"`
+}
+
+// train creates a profile from local plain text files, directories of them or gzip archives,
+// with one document per line or one document per file
+func train(args []string) {
+	config := trainFlags{
+		Depth:      4,
+		Corpus:     "text",
+		Column:     1,
+		TextField:  "text",
+		LabelField: "label",
+		Seed:       1,
+
+		CheckpointEvery: 100000,
+	}
+	flags := flag.NewFlagSet("train", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.Format != "" {
+		trainLabeled(config, flags.Args())
+		return
+	}
+	format, ok := corpusFormats[config.Corpus]
+	if !ok && config.Corpus != "text" {
+		fatalf(exitUsage, "unknown corpus format %q, expected text, tatoeba, opus or opus-tsv", config.Corpus)
+	}
+	download := flags.NArg() == 0 && format.url != nil
+	if config.Lang == "" || config.File == "" || (flags.NArg() == 0 && !download) {
+		fatalf(exitUsage, "-lang, -file and at least one input file or directory are required arguments")
+	}
+	if config.Variants && config.Lowercase {
+		fatalf(exitUsage, "-variants writes a lowercased profile besides the cased one, it cannot be combined with -lowercase")
+	}
+	if config.Resume && config.Checkpoint == "" {
+		fatalf(exitUsage, "-resume needs the -checkpoint to resume from")
+	}
+	if config.CheckpointEvery <= 0 {
+		fatalf(exitUsage, "-checkpoint-every %d is not positive", config.CheckpointEvery)
+	}
+
+	inputs := flags.Args()
+	if download {
+		inputs = []string{format.url(config.Lang)}
+	}
+	files := inputs
+	if !download {
+		var err error
+		if files, err = trainingFiles(inputs); err != nil {
+			fatal(exitCode(err), err)
+		}
+	}
+	if config.Source == "" {
+		config.Source = strings.Join(inputs, ", ")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	trainer := newTrainer(config)
+	if config.Progress {
+		trainer.Progress = func(progress langdet.TrainProgress) {
+			fmt.Fprintf(os.Stderr, "%s: %d documents, %d n-grams\n", config.Lang, progress.Documents, progress.NGrams)
+		}
+	}
+	// records are the records read from the inputs, the records read before the checkpoint are
+	// skipped when training resumes
+	records, skip := int64(0), int64(0)
+	if config.Resume {
+		skip = readCheckpoint(trainer, config.Checkpoint)
+		fmt.Printf("%s: resuming after %d records, %d documents\n", config.Lang, skip, trainer.Documents())
+	}
+	docs := trainer.Documents()
+	filter := &sentenceFilter{dedup: config.Dedup, minLength: config.MinLength, maxLength: config.MaxLength}
+	feed := func(text string) bool {
+		if ctx.Err() != nil || (config.Limit > 0 && docs >= config.Limit) {
+			return false
+		}
+		if records++; records <= skip || !filter.keep(text) {
+			return true
+		}
+		trainer.Feed(text)
+		docs++
+		if config.Checkpoint != "" && records%config.CheckpointEvery == 0 {
+			writeCheckpoint(trainer, config.Checkpoint, records)
+		}
+		return true
+	}
+	for _, name := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		var err error
+		switch {
+		case download:
+			fmt.Printf("%s: downloading %s\n", config.Lang, name)
+			err = feedCorpus(openURL(name), name, format, config.Lang, config.Column, feed)
+		case format.read != nil:
+			err = feedCorpus(openFile(name), name, format, config.Lang, config.Column, feed)
+		default:
+			err = feedFile(name, config.WholeFile, feed)
+		}
+		if err != nil {
+			fatal(exitCode(err), err)
+		}
+	}
+	if ctx.Err() != nil {
+		if config.Checkpoint == "" {
+			fatalf(exitFailure, "%s: interrupted after %d records", config.Lang, records)
+		}
+		writeCheckpoint(trainer, config.Checkpoint, records)
+		fatalf(exitFailure, "%s: interrupted after %d records, resume with -resume -checkpoint %s", config.Lang, records, config.Checkpoint)
+	}
+
+	lang := trainer.Build(config.Lang)
+	writeProfile(lang, config.File)
+	fmt.Printf("%s: %d documents of %d files, %d n-grams written to %s\n", config.Lang, docs, len(files), len(lang.Profile), config.File)
+	if filter.duplicates > 0 || filter.outside > 0 {
+		fmt.Printf("%s: %d duplicate sentences and %d sentences out of the length limits skipped\n", config.Lang, filter.duplicates, filter.outside)
+	}
+	if dropped := trainer.Dropped(); dropped > 0 {
+		fmt.Printf("%s: %d capitalized sentences dropped\n", config.Lang, dropped)
+	}
+	if config.Variants {
+		folded := trainer.BuildFolded(config.Lang)
+		writeProfile(folded, foldedFile(config.File))
+		fmt.Printf("%s: %d case-folded n-grams written to %s\n", config.Lang, len(folded.Profile), foldedFile(config.File))
+	}
+}
+
+// newTrainer returns a trainer of the options of the flags
+func newTrainer(config trainFlags) *langdet.Trainer {
+	trainer := &langdet.Trainer{Depth: config.Depth, KeepCounts: config.KeepCounts, MaxRanks: maxNGrams(config.MaxNGrams, config.MaxTokens), Source: config.Source, WordRanks: config.Words, MaxCapitalized: config.MaxCapitalized, Lowercase: config.Lowercase}
+	if config.Tokenize {
+		trainer.Tokenizer = langdet.WordTokenizer{}
+	}
+	if config.Translit != "" {
+		if trainer.Transliterator = langdet.Transliterations[config.Translit]; trainer.Transliterator == nil {
+			fatalf(exitUsage, "unknown transliteration %q, expected ru-Latn or el-Latn", config.Translit)
+		}
+	}
+	return trainer
+}
+
+// writeCheckpoint saves the state of a trainer after records read records to a checkpoint file,
+// which is replaced only once the new state is written completely
+func writeCheckpoint(trainer *langdet.Trainer, name string, records int64) {
+	f, err := os.Create(name + ".part")
+	if err != nil {
+		fatal(exitWrite, err)
+	}
+	err = trainer.WriteCheckpoint(f, records)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(name+".part", name)
+	}
+	if err != nil {
+		fatal(exitWrite, err)
+	}
+}
+
+// readCheckpoint restores the state of a trainer from a checkpoint file and returns the number of
+// records read before it was saved
+func readCheckpoint(trainer *langdet.Trainer, name string) int64 {
+	f, err := os.Open(name)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	defer f.Close()
+	records, err := trainer.ReadCheckpoint(f)
+	if err != nil {
+		fatalf(exitParse, "%s: %v", name, err)
+	}
+	return records
+}
+
+// writeProfile writes a language as a JSON profile file
+func writeProfile(lang langdet.Language, name string) {
+	langJSON, err := json.Marshal(lang)
+	if err != nil {
+		fatal(exitFailure, err)
+	}
+	if err := os.WriteFile(name, langJSON, 0644); err != nil {
+		fatal(exitWrite, err)
+	}
+}
+
+// foldedFile returns the file name of the case-folded variant of a profile file, with .folded
+// before its extension
+func foldedFile(name string) string {
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + ".folded" + ext
+}
+
+// trainingFiles returns the paths of the files and of all regular files of the directories of paths
+func trainingFiles(paths []string) ([]string, error) {
+	files := []string{}
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(name string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.Type().IsRegular() {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// feedFile calls feed with the documents of a plain text or, for .gz and .bz2 files, compressed
+// file, until feed returns false
+func feedFile(name string, wholeFile bool, feed func(text string) bool) error {
+	r, err := openFile(name)()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer r.Close()
+
+	if wholeFile {
+		text, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		feed(string(text))
+		return nil
+	}
+	if err := scanLines(r, feed); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// feedCorpus calls feed with the sentences of the language lang of the corpus file of a
+// corpusFormat, opened by open, until feed returns false
+func feedCorpus(open func() (io.ReadCloser, error), name string, format corpusFormat, lang string, column int, feed func(text string) bool) error {
+	r, err := open()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer r.Close()
+	if err := format.read(r, lang, column, feed); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// maxNGrams returns the number of ranked n-grams of -max-ngrams, or of its older name -max-tokens
+func maxNGrams(maxNGrams, maxTokens int) int {
+	if maxNGrams > 0 {
+		return maxNGrams
+	}
+	return maxTokens
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	pb "gopkg.in/cheggaaa/pb.v1"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// wikiMatrixURL lists the wikis of all languages of the Wikimedia projects
+var wikiMatrixURL = "https://meta.wikimedia.org/w/api.php?action=sitematrix&smtype=language&format=json&formatversion=2"
+
+// defaultDumpURL is the abstract dump of a wiki, formatted with its database name like "enwiki"
+const defaultDumpURL = "https://dumps.wikimedia.org/%[1]s/latest/%[1]s-latest-abstract.xml"
+
+// wiki is a Wikipedia of one language
+type wiki struct {
+	Code     string
+	DBName   string
+	URL      string
+	Articles int
+}
+
+// wikiStatisticsURL returns the URL of the site statistics of the wiki at site
+func wikiStatisticsURL(site string) string {
+	return site + "/w/api.php?action=query&meta=siteinfo&siprop=statistics&format=json&formatversion=2"
+}
+
+// trainAll trains the profiles of all Wikipedia languages with enough articles, or of the
+// languages of a config file
+func trainAll(args []string) {
+	config := struct {
+		MinArticles int    `flag:"min-articles,Minimum number of articles of a wikipedia to train its language"`
+		Config      string `flag:"config,YAML or JSON file with the languages to train and their sources, instead of all wikipedias"`
+		Out         string `flag:"out,Directory to write the <lang>.json profiles and <lang>.log training logs to"`
+		Bundle      string `flag:"bundle,Also write all profiles of -out as a compiled bundle to this file"`
+		Workers     int    `flag:"workers,Number of languages trained at the same time"`
+		Limit       int    `flag:"limit,Maximum number of abstracts to process per language"`
+		DumpURL     string `flag:"dump-url,URL of the abstract dump, with %[1]s for the database name of the wiki like enwiki"`
+		Verify      string `flag:"verify,Directory with existing profiles to verify the corpora of their languages, the embedded default profiles if empty"`
+		List        bool   `flag:"list,Only list the qualifying languages with their number of articles"`
+		Overwrite   bool   `flag:"overwrite,Train languages whose profile already exists in -out again"`
+		Strict      bool   `flag:"strict,Exit with an error if any language fails, not only if all fail"`
+	}{
+		MinArticles: 100000,
+		Workers:     2,
+		Limit:       20000,
+		DumpURL:     defaultDumpURL,
+	}
+	fs := flag.NewFlagSet("train-all", flag.ExitOnError)
+	autoflags.DefineFlagSet(fs, &config)
+	fs.Parse(args)
+	if config.Out == "" && !config.List {
+		fatalf(exitUsage, "-out is a required argument")
+	}
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	verifier := langdet.NewDefaultLanguages()
+	if config.Verify != "" {
+		verifier = langdet.NewDetector()
+		if err := verifier.LoadLanguagesFromDir(config.Verify); err != nil {
+			fatal(exitCode(err), err)
+		}
+	}
+	verifyArgs := func(lang string) []string {
+		if hasProfile(&verifier, lang) {
+			return []string{"-verify", config.Verify}
+		}
+		return []string{"-no-verify"}
+	}
+
+	jobs := []trainingJob{}
+	if config.Config != "" {
+		languages, err := readTrainingConfig(config.Config)
+		if err != nil {
+			fatal(exitUsage, err)
+		}
+		for _, language := range languages.Languages {
+			jobs = append(jobs, trainingJob{Lang: language.Lang, Args: append(language.args(languages), verifyArgs(language.Lang)...)})
+		}
+	} else {
+		wikis, err := listWikis(wikiMatrixURL, 8)
+		if err != nil {
+			fatal(exitCode(err), fmt.Errorf("could not list the wikipedias: %w", err))
+		}
+		wikis = qualifyingWikis(wikis, config.MinArticles)
+		if config.List {
+			for _, w := range wikis {
+				fmt.Printf("%s\t%d\n", w.Code, w.Articles)
+			}
+			return
+		}
+		for _, w := range wikis {
+			trainArgs := []string{"-url", fmt.Sprintf(config.DumpURL, w.DBName), "-limit", strconv.Itoa(config.Limit)}
+			jobs = append(jobs, trainingJob{Lang: w.Code, Args: append(trainArgs, verifyArgs(w.Code)...)})
+		}
+	}
+	if err := os.MkdirAll(config.Out, 0755); err != nil {
+		fatal(exitWrite, err)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		fatal(exitFailure, err)
+	}
+
+	results := trainLanguages(self, jobs, config.Out, config.Workers, config.Overwrite)
+	code := summarizeTraining(os.Stdout, results, config.Strict)
+	if config.Bundle != "" {
+		if err := writeTrainedBundle(config.Bundle, config.Out, results); err != nil {
+			fatal(exitWrite, err)
+		}
+	}
+	os.Exit(code)
+}
+
+// trainingJob is the training of one language of a multi-language run
+type trainingJob struct {
+	Lang string
+	// Args are the arguments of the trainer besides -lang and -file
+	Args []string
+}
+
+// trainLanguages runs the jobs with the given number of concurrent workers, writes the profiles
+// and the training logs to the directory out, and shows the progress of the languages. Every
+// language is trained by its own process of the trainer executable, so that a failing dump only
+// fails its language. Languages whose profile exists are skipped unless overwrite is set.
+func trainLanguages(executable string, jobs []trainingJob, out string, workers int, overwrite bool) []trainingResult {
+	results := make([]trainingResult, len(jobs))
+	bar := pb.StartNew(len(jobs))
+	bar.Prefix("languages ")
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				job := jobs[i]
+				results[i].Code = job.Lang
+				file := filepath.Join(out, job.Lang+".json")
+				if _, err := os.Stat(file); err == nil && !overwrite {
+					log.Printf("%s: %s exists, skipped", job.Lang, file)
+					results[i].Skipped = true
+				} else {
+					trainArgs := append([]string{"-lang", job.Lang, "-file", file}, job.Args...)
+					if results[i].Err = runTraining(executable, trainArgs, filepath.Join(out, job.Lang+".log")); results[i].Err != nil {
+						log.Printf("%s: %v", job.Lang, results[i].Err)
+					}
+				}
+				bar.Increment()
+			}
+		}()
+	}
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	bar.Finish()
+	return results
+}
+
+// writeTrainedBundle writes the profiles of out of the trained and skipped languages of results as
+// a compiled bundle to the file name
+func writeTrainedBundle(name, out string, results []trainingResult) error {
+	files := []string{}
+	for _, result := range results {
+		if result.Err == nil {
+			files = append(files, filepath.Join(out, result.Code+".json"))
+		}
+	}
+	_, err := createBundle(name, "", files, nil)
+	return err
+}
+
+// trainingResult is the outcome of training one language of a multi-language run
+type trainingResult struct {
+	Code    string
+	Skipped bool
+	Err     error
+}
+
+// summarizeTraining prints the succeeded, skipped and failed languages of a multi-language run
+// and returns its exit code: 1 if all trained languages failed, or with strict if any failed
+func summarizeTraining(w io.Writer, results []trainingResult, strict bool) int {
+	succeeded, skipped, failed := []string{}, []string{}, []string{}
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			skipped = append(skipped, result.Code)
+		case result.Err != nil:
+			failed = append(failed, result.Code)
+		default:
+			succeeded = append(succeeded, result.Code)
+		}
+	}
+	fmt.Fprintf(w, "succeeded (%d): %s\n", len(succeeded), strings.Join(succeeded, " "))
+	if len(skipped) > 0 {
+		fmt.Fprintf(w, "skipped (%d): %s\n", len(skipped), strings.Join(skipped, " "))
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(w, "failed (%d): %s\n", len(failed), strings.Join(failed, " "))
+		for _, result := range results {
+			if result.Err != nil && !result.Skipped {
+				fmt.Fprintf(w, "  %s: %v\n", result.Code, result.Err)
+			}
+		}
+	}
+	if len(failed) > 0 && (strict || len(succeeded) == 0) {
+		return exitFailure
+	}
+	return 0
+}
+
+// runTraining runs the trainer executable with args and writes its output to the log file
+func runTraining(executable string, args []string, logFile string) error {
+	out, err := os.Create(logFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout, cmd.Stderr = out, out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v, see %s", err, logFile)
+	}
+	return nil
+}
+
+// hasProfile tells whether the detector has a profile of the language lang
+func hasProfile(detector *langdet.Detector, lang string) bool {
+	for _, language := range detector.Snapshot() {
+		if sameLanguage(language.Name, lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// listWikis returns the open Wikipedias of the site matrix at matrixURL with their number of
+// articles, fetching the statistics of the wikis with the given number of concurrent requests.
+// Wikis whose statistics can't be fetched are skipped with a warning.
+func listWikis(matrixURL string, workers int) ([]wiki, error) {
+	matrix := struct {
+		Sitematrix map[string]json.RawMessage `json:"sitematrix"`
+	}{}
+	if err := getJSON(matrixURL, &matrix); err != nil {
+		return nil, err
+	}
+	wikis := []wiki{}
+	for key, raw := range matrix.Sitematrix {
+		if key == "count" || key == "specials" {
+			continue
+		}
+		language := struct {
+			Code string `json:"code"`
+			Site []struct {
+				URL    string `json:"url"`
+				DBName string `json:"dbname"`
+				Code   string `json:"code"`
+				Closed bool   `json:"closed"`
+			} `json:"site"`
+		}{}
+		if err := json.Unmarshal(raw, &language); err != nil {
+			return nil, fmt.Errorf("invalid site matrix entry %s: %v", key, err)
+		}
+		for _, site := range language.Site {
+			if site.Code == "wiki" && !site.Closed {
+				wikis = append(wikis, wiki{Code: language.Code, DBName: site.DBName, URL: site.URL})
+			}
+		}
+	}
+
+	ok := make([]bool, len(wikis))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				statistics := struct {
+					Query struct {
+						Statistics struct {
+							Articles int `json:"articles"`
+						} `json:"statistics"`
+					} `json:"query"`
+				}{}
+				if err := getJSON(wikiStatisticsURL(wikis[i].URL), &statistics); err != nil {
+					log.Printf("WARNING: skipped %s: %v", wikis[i].Code, err)
+					continue
+				}
+				wikis[i].Articles, ok[i] = statistics.Query.Statistics.Articles, true
+			}
+		}()
+	}
+	for i := range wikis {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	listed := []wiki{}
+	for i, w := range wikis {
+		if ok[i] {
+			listed = append(listed, w)
+		}
+	}
+	sort.Slice(listed, func(i, j int) bool { return listed[i].Code < listed[j].Code })
+	return listed, nil
+}
+
+// qualifyingWikis returns the wikis with at least minArticles articles
+func qualifyingWikis(wikis []wiki, minArticles int) []wiki {
+	qualifying := []wiki{}
+	for _, w := range wikis {
+		if w.Articles >= minArticles {
+			qualifying = append(qualifying, w)
+		}
+	}
+	return qualifying
+}
+
+// getJSON decodes the JSON response of a GET request of url into v
+func getJSON(url string, v interface{}) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &httpStatusError{URL: url, Status: resp.Status, Code: resp.StatusCode}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTrainAll(t *testing.T) {
+	Convey("Subject: Listing the wikipedias to train", t, func() {
+		articles := map[string]int{"en": 6000000, "de": 2800000, "kw": 5000}
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/sitematrix" {
+				fmt.Fprintf(w, `{"sitematrix": {"count": 4,
+					"0": {"code": "en", "site": [{"url": "%[1]s/en", "dbname": "enwiki", "code": "wiki"},
+						{"url": "%[1]s/enwiktionary", "dbname": "enwiktionary", "code": "wiktionary"}]},
+					"1": {"code": "de", "site": [{"url": "%[1]s/de", "dbname": "dewiki", "code": "wiki"}]},
+					"2": {"code": "kw", "site": [{"url": "%[1]s/kw", "dbname": "kwwiki", "code": "wiki"}]},
+					"3": {"code": "mo", "site": [{"url": "%[1]s/mo", "dbname": "mowiki", "code": "wiki", "closed": true}]},
+					"specials": [{"url": "%[1]s/meta", "dbname": "metawiki", "code": "meta"}]}}`, server.URL)
+				return
+			}
+			code := strings.TrimSuffix(r.URL.Path, "/w/api.php")[1:]
+			count, ok := articles[code]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprintf(w, `{"query": {"statistics": {"articles": %d}}}`, count)
+		}))
+		defer server.Close()
+
+		wikis, err := listWikis(server.URL+"/sitematrix", 2)
+		So(err, ShouldBeNil)
+		Convey("Open wikipedias should be listed with their number of articles", func() {
+			So(wikis, ShouldResemble, []wiki{
+				{Code: "de", DBName: "dewiki", URL: server.URL + "/de", Articles: 2800000},
+				{Code: "en", DBName: "enwiki", URL: server.URL + "/en", Articles: 6000000},
+				{Code: "kw", DBName: "kwwiki", URL: server.URL + "/kw", Articles: 5000},
+			})
+		})
+		Convey("Tiny wikipedias should be skipped", func() {
+			qualifying := qualifyingWikis(wikis, 100000)
+			So(len(qualifying), ShouldEqual, 2)
+			So(qualifying[0].Code, ShouldEqual, "de")
+			So(qualifying[1].Code, ShouldEqual, "en")
+		})
+	})
+
+	Convey("Subject: Summary of a multi-language training run", t, func() {
+		failure := errors.New("exit status 1")
+		results := []trainingResult{{Code: "de"}, {Code: "en", Skipped: true}, {Code: "kw", Err: failure}}
+		Convey("The languages should be reported by their outcome", func() {
+			out := &bytes.Buffer{}
+			summarizeTraining(out, results, false)
+			So(out.String(), ShouldContainSubstring, "succeeded (1): de\n")
+			So(out.String(), ShouldContainSubstring, "skipped (1): en\n")
+			So(out.String(), ShouldContainSubstring, "failed (1): kw\n  kw: exit status 1\n")
+		})
+		Convey("The run should only fail if all languages failed, unless it is strict", func() {
+			So(summarizeTraining(&bytes.Buffer{}, results, false), ShouldEqual, 0)
+			So(summarizeTraining(&bytes.Buffer{}, results, true), ShouldEqual, 1)
+			So(summarizeTraining(&bytes.Buffer{}, results[1:], false), ShouldEqual, 1)
+			So(summarizeTraining(&bytes.Buffer{}, results[:2], true), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Subject: Config files of the languages to train", t, func() {
+		dir := t.TempDir()
+		write := func(name, content string) string {
+			name = filepath.Join(dir, name)
+			So(os.WriteFile(name, []byte(content), 0644), ShouldBeNil)
+			return name
+		}
+		Convey("Languages should be trained with their sources and the limits and depths of the config", func() {
+			config, err := readTrainingConfig(write("langs.yaml", `
+limit: 5000
+depth: 3
+languages:
+  - lang: en
+    urls: [https://example.org/enwiki-abstract.xml.gz]
+  - lang: de
+    inputs: [dumps/dewiki.xml, /data/dewiki2.xml]
+    limit: 100
+    depth: 0
+  - lang: fr
+    latest: true
+`))
+			So(err, ShouldBeNil)
+			So(config.Languages[0].args(config), ShouldResemble, []string{"-url", "https://example.org/enwiki-abstract.xml.gz", "-limit", "5000", "-depth", "3"})
+			So(config.Languages[1].args(config), ShouldResemble, []string{
+				"-input", filepath.Join(dir, "dumps/dewiki.xml"), "-input", "/data/dewiki2.xml", "-limit", "100", "-depth", "0",
+			})
+			So(config.Languages[2].args(config), ShouldResemble, []string{"-latest", "-limit", "5000", "-depth", "3"})
+		})
+		Convey("JSON configs should be read too", func() {
+			config, err := readTrainingConfig(write("langs.json", `{"languages": [{"lang": "en", "latest": true}]}`))
+			So(err, ShouldBeNil)
+			So(config.Languages[0].args(config), ShouldResemble, []string{"-latest"})
+		})
+		Convey("Invalid configs should be rejected", func() {
+			for _, content := range []string{
+				"languages: []",
+				"languages: [{lang: en}]",
+				"languages: [{latest: true}]",
+				"languages: [{lang: en, latest: true}, {lang: en, latest: true}]",
+				"languages: [{lang: en, latest: true, size: 3}]",
+			} {
+				_, err := readTrainingConfig(write("invalid.yaml", content))
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+	Convey("Subject: Bundles of the trained languages", t, func() {
+		dir := t.TempDir()
+		for _, name := range []string{"en", "fr"} {
+			data, err := json.Marshal(langdet.Analyze("some training text of "+name, name))
+			So(err, ShouldBeNil)
+			So(os.WriteFile(filepath.Join(dir, name+".json"), data, 0644), ShouldBeNil)
+		}
+		results := []trainingResult{{Code: "en"}, {Code: "fr", Skipped: true}, {Code: "de", Err: errors.New("exit status 3")}}
+		name := filepath.Join(dir, "bundle.bin")
+		So(writeTrainedBundle(name, dir, results), ShouldBeNil)
+		f, err := os.Open(name)
+		So(err, ShouldBeNil)
+		defer f.Close()
+		languages, err := langdet.ReadBundle(f)
+		So(err, ShouldBeNil)
+		So(len(languages), ShouldEqual, 2)
+	})
+}
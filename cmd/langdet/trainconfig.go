@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// trainingConfig is a YAML or JSON config file of train-all with the languages to train
+type trainingConfig struct {
+	// Limit is the maximum number of abstracts per language, 0 for the default of the trainer
+	Limit int `json:"limit" yaml:"limit"`
+	// Depth is the n-gram depth of all languages, the default of the trainer if missing
+	Depth *int `json:"depth" yaml:"depth"`
+	// Languages are the languages to train
+	Languages []languageTraining `json:"languages" yaml:"languages"`
+}
+
+// languageTraining is the training of one language of a trainingConfig
+type languageTraining struct {
+	Lang string `json:"lang" yaml:"lang"`
+	// URLs are the URLs of dumps of the language, like -url
+	URLs []string `json:"urls" yaml:"urls"`
+	// Inputs are local dump files of the language, like -input. Relative paths are relative to
+	// the directory of the config file.
+	Inputs []string `json:"inputs" yaml:"inputs"`
+	// Latest trains from the newest dump of the wikipedia of the language, like -latest
+	Latest bool `json:"latest" yaml:"latest"`
+	// Limit and Depth override the Limit and Depth of the config
+	Limit int  `json:"limit" yaml:"limit"`
+	Depth *int `json:"depth" yaml:"depth"`
+}
+
+// readTrainingConfig reads and validates a training config, a YAML file if it has a .yaml or
+// .yml extension and JSON otherwise. Unknown fields are errors.
+func readTrainingConfig(name string) (trainingConfig, error) {
+	config := trainingConfig{}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return config, err
+	}
+	if isYAML(name) {
+		err = yaml.UnmarshalStrict(data, &config)
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&config)
+	}
+	if err != nil {
+		return config, fmt.Errorf("could not parse %s: %w", name, err)
+	}
+	if len(config.Languages) == 0 {
+		return config, fmt.Errorf("%s has no languages", name)
+	}
+	seen := make(map[string]bool)
+	for i, language := range config.Languages {
+		switch {
+		case language.Lang == "":
+			return config, fmt.Errorf("%s: language %d has no lang", name, i+1)
+		case seen[language.Lang]:
+			return config, fmt.Errorf("%s: language %q is listed twice", name, language.Lang)
+		case len(language.URLs) == 0 && len(language.Inputs) == 0 && !language.Latest:
+			return config, fmt.Errorf("%s: language %q has no urls, inputs or latest", name, language.Lang)
+		}
+		seen[language.Lang] = true
+		for j, input := range language.Inputs {
+			if !filepath.IsAbs(input) {
+				config.Languages[i].Inputs[j] = filepath.Join(filepath.Dir(name), input)
+			}
+		}
+	}
+	return config, nil
+}
+
+// args returns the arguments of the trainer for the language besides -lang and -file
+func (l languageTraining) args(config trainingConfig) []string {
+	args := []string{}
+	for _, u := range l.URLs {
+		args = append(args, "-url", u)
+	}
+	for _, input := range l.Inputs {
+		args = append(args, "-input", input)
+	}
+	if l.Latest {
+		args = append(args, "-latest")
+	}
+	limit, depth := config.Limit, config.Depth
+	if l.Limit != 0 {
+		limit = l.Limit
+	}
+	if l.Depth != nil {
+		depth = l.Depth
+	}
+	if limit != 0 {
+		args = append(args, "-limit", strconv.Itoa(limit))
+	}
+	if depth != nil {
+		args = append(args, "-depth", strconv.Itoa(*depth))
+	}
+	return args
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// fileLanguage is the detected language of a file of a treeReport
+type fileLanguage struct {
+	File       string // path relative to the directory of the report, with slashes
+	Language   string // closest language, or undefined if the detection is not reliable
+	Closest    string `json:",omitempty"` // closest language of unreliable detections
+	Confidence float64
+}
+
+// treeReport is the detected language of the text files of a directory tree with the number of
+// files per language
+type treeReport struct {
+	Dir       string
+	Files     []fileLanguage
+	Languages map[string]int
+	Skipped   []string `json:",omitempty"` // files that are not text
+}
+
+// report walks a directory, detects the language of every text file and writes the files with
+// their language and the number of files per language as JSON
+func report(args []string) {
+	config := struct {
+		Dir      string `flag:"dir,Directory to walk"`
+		Out      string `flag:"out,JSON file to write the report to, stdout if empty"`
+		Profiles string `flag:"profiles,Directory, file or bundle with the profiles to detect, the embedded default profiles if empty"`
+		Config   string `flag:"config,JSON or YAML config file of the detector, instead of -profiles"`
+		Limit    int64  `flag:"limit,Maximum number of bytes read from every file"`
+	}{
+		Limit: langdet.DefaultMaxDetectBytes,
+	}
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if config.Dir == "" {
+		fatalf(exitUsage, "-dir is a required argument")
+	}
+
+	detector, err := loadDetector(config.Profiles, config.Config, 0)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	tree, err := buildTreeReport(&detector, config.Dir, config.Limit)
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	data, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		fatal(exitFailure, err)
+	}
+	if config.Out == "" {
+		os.Stdout.Write(append(data, '\n'))
+		return
+	}
+	if err := os.WriteFile(config.Out, append(data, '\n'), 0644); err != nil {
+		fatal(exitWrite, err)
+	}
+}
+
+// buildTreeReport detects the language of the text extracted from the first limit bytes of every
+// text file below dir, in lexical order. Hidden files and directories are skipped.
+func buildTreeReport(detector *langdet.Detector, dir string, limit int64) (treeReport, error) {
+	tree := treeReport{Dir: dir, Languages: map[string]int{}}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		data, err := readHead(path, limit)
+		if err != nil {
+			return err
+		}
+		if !isTextLike(data) {
+			tree.Skipped = append(tree.Skipped, rel)
+			return nil
+		}
+		file := fileLanguage{File: rel}
+		name, confidence, reliable := detector.GetClosestLanguageWithConfidence(fileText(path, data))
+		file.Language, file.Confidence = name, confidence
+		if !reliable {
+			file.Language, file.Closest = "undefined", name
+		}
+		tree.Files = append(tree.Files, file)
+		tree.Languages[file.Language]++
+		return nil
+	})
+	return tree, err
+}
+
+// readHead returns the first limit bytes of a file
+func readHead(path string, limit int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, limit))
+}
+
+// isTextLike tells whether the beginning of a file looks like text: it has no NUL bytes and is
+// valid UTF-8, except for a rune cut off at the end
+func isTextLike(data []byte) bool {
+	if len(data) == 0 || bytes.IndexByte(data, 0) >= 0 {
+		return false
+	}
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if utf8.Valid(data) {
+			return true
+		}
+		data = data[:len(data)-1]
+	}
+	return false
+}
+
+// fileText returns the text of a file for the detection, without the markup of HTML and Markdown
+// files by their extension, and without the preamble
+func fileText(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm", ".xhtml":
+		return langdet.StripPreamble(langdet.HTMLText(data))
+	case ".md", ".markdown":
+		return langdet.MarkdownText(string(data))
+	}
+	return langdet.StripPreamble(string(data))
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTreeReport(t *testing.T) {
+	Convey("Subject: Language report of a directory tree", t, func() {
+		dir := t.TempDir()
+		files := map[string]string{
+			"en.txt":          "The dog and the cat play in the garden every day.",
+			"docs/de.md":      "# Titel\n\nDer Hund und die Katze spielen jeden Tag im Garten.",
+			"docs/index.html": "<html><body><p>The children go to school every day.</p></body></html>",
+			"image.png":       "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+			".git/config":     "[core]",
+		}
+		for name, text := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			So(os.MkdirAll(filepath.Dir(path), 0755), ShouldBeNil)
+			So(os.WriteFile(path, []byte(text), 0644), ShouldBeNil)
+		}
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("The dog and the cat play in the garden. The children go to school every day.", "english")
+		d.AddLanguageFromText("Der Hund und die Katze spielen im Garten. Die Kinder gehen jeden Tag in die Schule.", "german")
+
+		tree, err := buildTreeReport(&d, dir, langdet.DefaultMaxDetectBytes)
+		So(err, ShouldBeNil)
+		Convey("Text files should be detected in lexical order", func() {
+			So(tree.Files, ShouldHaveLength, 3)
+			So(tree.Files[0].File, ShouldEqual, "docs/de.md")
+			So(tree.Files[0].Language, ShouldEqual, "german")
+			So(tree.Files[1].File, ShouldEqual, "docs/index.html")
+			So(tree.Files[1].Language, ShouldEqual, "english")
+			So(tree.Files[2].File, ShouldEqual, "en.txt")
+			So(tree.Languages, ShouldResemble, map[string]int{"english": 2, "german": 1})
+		})
+		Convey("Binary and hidden files should be skipped", func() {
+			So(tree.Skipped, ShouldResemble, []string{"image.png"})
+		})
+	})
+}
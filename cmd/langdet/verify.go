@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// corpusVerifier detects the language of sampled abstracts to catch a corpus of another language
+type corpusVerifier struct {
+	detector langdet.Detector
+	// profile is the name of the profile of the trained language
+	profile string
+}
+
+// newCorpusVerifier returns the verifier of a corpus of lang with the profiles from dir, or with
+// the embedded default profiles if dir is empty. It is resolved before the corpus is read and
+// returns nil with a warning if there is no profile of lang to compare with.
+func newCorpusVerifier(dir, lang string) *corpusVerifier {
+	verifier := &corpusVerifier{detector: langdet.NewDefaultLanguages()}
+	source := "the default profiles"
+	if dir != "" {
+		verifier.detector = langdet.NewDetector()
+		source = dir
+		if err := verifier.detector.LoadLanguagesFromDir(dir); err != nil {
+			fatal(exitCode(err), fmt.Errorf("could not verify the corpus: %w", err))
+		}
+	}
+	for _, language := range verifier.detector.Snapshot() {
+		if sameLanguage(language.Name, lang) {
+			verifier.profile = language.Name
+		}
+	}
+	if verifier.profile == "" {
+		log.Printf("WARNING: the corpus is not verified: there is no %q profile in %s, use -verify with a directory of profiles including it", lang, source)
+		return nil
+	}
+	return verifier
+}
+
+// verify detects the language of the sampled abstracts and warns if more than threshold of them
+// are detected as another language than lang
+func (v *corpusVerifier) verify(lang string, samples []string, threshold float64) {
+	detected := make(map[string]int)
+	disagreeing, defined := 0, 0
+	for _, sample := range samples {
+		result := v.detector.GetClosestLanguage(sample)
+		if result == "undefined" {
+			continue
+		}
+		defined++
+		if result != v.profile {
+			disagreeing++
+			detected[result]++
+		}
+	}
+	if defined == 0 {
+		log.Printf("Could not verify the corpus: none of %d abstracts was detected confidently", len(samples))
+		return
+	}
+	fraction := float64(disagreeing) / float64(defined)
+	if fraction <= threshold {
+		return
+	}
+	others := make([]string, 0, len(detected))
+	for name := range detected {
+		others = append(others, name)
+	}
+	sort.Slice(others, func(i, j int) bool { return detected[others[i]] > detected[others[j]] })
+	log.Printf("WARNING: %.0f%% of %d verified abstracts are not detected as %q, but as %v; is the source correct?",
+		100*fraction, defined, lang, others)
+}
+
+// sameLanguage tells whether two language names, like "en" and "english", name the same language
+func sameLanguage(a, b string) bool {
+	if a == b {
+		return true
+	}
+	tagA, okA := langdet.LookupLanguageTag(a)
+	tagB, okB := langdet.LookupLanguageTag(b)
+	return okA && okB && tagA == tagB
+}
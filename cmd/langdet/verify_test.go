@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCorpusVerifier(t *testing.T) {
+	Convey("Subject: Resolve the profile to verify a corpus with", t, func() {
+		dir := t.TempDir()
+		data, err := json.Marshal(langdet.Analyze("Hello I am english text, what is your language?", "en"))
+		So(err, ShouldBeNil)
+		So(os.WriteFile(filepath.Join(dir, "en.json"), data, 0644), ShouldBeNil)
+
+		Convey("The profile of the language should be found by its name or code", func() {
+			verifier := newCorpusVerifier(dir, "english")
+			So(verifier, ShouldNotBeNil)
+			So(verifier.profile, ShouldEqual, "en")
+		})
+		Convey("Languages without a profile should not be verified", func() {
+			So(newCorpusVerifier(dir, "es"), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/artyom/autoflags"
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// vizRow is a ranked n-gram of a profile, the rows of the viz export
+type vizRow struct {
+	Language string `json:"language"`
+	Rank     int    `json:"rank"`
+	NGram    string `json:"ngram"`
+	Length   int    `json:"length"`
+	// Count and Frequency are only known for profiles with Counts
+	Count     int     `json:"count,omitempty"`
+	Frequency float64 `json:"frequency,omitempty"`
+}
+
+// similarityMatrix is the pairwise similarity of profiles, Values[i][j] is the similarity of
+// the top n-grams of Languages[i] to the profile of Languages[j]
+type similarityMatrix struct {
+	Languages []string    `json:"languages"`
+	Values    [][]float64 `json:"values"`
+}
+
+// viz exports the ranks of the n-grams of profiles and their pairwise similarity as CSV or JSON,
+// for charts of the profiles, e.g. with d3
+func viz(args []string) {
+	config := struct {
+		Out    string `flag:"out,File to write the ranked n-grams to, .csv or .json"`
+		Matrix string `flag:"matrix,File to write the pairwise similarity of the profiles to, .csv or .json"`
+		Top    int    `flag:"top,Number of top ranked n-grams per profile, 0 for all"`
+	}{
+		Top: 1000,
+	}
+	flags := flag.NewFlagSet("viz", flag.ExitOnError)
+	autoflags.DefineFlagSet(flags, &config)
+	flags.Parse(args)
+	if flags.NArg() == 0 || (config.Out == "" && config.Matrix == "") {
+		fatalf(exitUsage, "at least one profile file or directory and -out or -matrix are required arguments")
+	}
+	for _, name := range []string{config.Out, config.Matrix} {
+		if ext := strings.ToLower(filepath.Ext(name)); name != "" && ext != ".csv" && ext != ".json" {
+			fatalf(exitUsage, "%s must be a .csv or .json file", name)
+		}
+	}
+
+	detector, err := langdet.Config{Profiles: flags.Args()}.NewDetector()
+	if err != nil {
+		fatal(exitCode(err), err)
+	}
+	languages := detector.Snapshot()
+	if config.Out != "" {
+		rows := []vizRow{}
+		for _, language := range languages {
+			rows = append(rows, vizRows(language, config.Top)...)
+		}
+		err := writeViz(config.Out, rows, func(w io.Writer) error { return writeVizCSV(w, rows) })
+		if err != nil {
+			fatal(exitWrite, err)
+		}
+		fmt.Printf("%d n-grams of %d profiles written to %s\n", len(rows), len(languages), config.Out)
+	}
+	if config.Matrix != "" {
+		matrix := similarities(languages)
+		err := writeViz(config.Matrix, matrix, func(w io.Writer) error { return writeMatrixCSV(w, matrix) })
+		if err != nil {
+			fatal(exitWrite, err)
+		}
+		fmt.Printf("similarity of %d profiles written to %s\n", len(languages), config.Matrix)
+	}
+}
+
+// vizRows returns the top ranked n-grams of a language, best first
+func vizRows(language langdet.Language, top int) []vizRow {
+	total := 0
+	for _, count := range language.Counts {
+		total += count
+	}
+	rows := make([]vizRow, 0, len(language.Profile))
+	for token, rank := range language.Profile {
+		row := vizRow{Language: language.Name, Rank: rank, NGram: token, Length: utf8.RuneCountInString(token)}
+		if total > 0 {
+			row.Count = language.Counts[token]
+			row.Frequency = float64(row.Count) / float64(total)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Rank != rows[j].Rank {
+			return rows[i].Rank < rows[j].Rank
+		}
+		return rows[i].NGram < rows[j].NGram
+	})
+	if top > 0 && len(rows) > top {
+		rows = rows[:top]
+	}
+	return rows
+}
+
+// similarities returns the pairwise similarity of languages: one minus the out-of-place distance
+// of the top DefaultMaxInputRanks n-grams of one profile to the other profile, relative to the
+// maximum distance. Profiles are most similar to themselves, with a similarity of 1.
+func similarities(languages []langdet.Language) similarityMatrix {
+	matrix := similarityMatrix{Languages: []string{}, Values: [][]float64{}}
+	for _, a := range languages {
+		compared := 0
+		for _, rank := range a.Profile {
+			if rank <= langdet.DefaultMaxInputRanks {
+				compared++
+			}
+		}
+		row := make([]float64, len(languages))
+		for j, b := range languages {
+			if compared > 0 {
+				distance := langdet.GetDistance(a.Profile, b.Profile, langdet.DefaultMaxInputRanks)
+				row[j] = 1 - float64(distance)/float64(compared*langdet.DefaultMaxInputRanks)
+			}
+		}
+		matrix.Languages = append(matrix.Languages, a.Name)
+		matrix.Values = append(matrix.Values, row)
+	}
+	return matrix
+}
+
+// writeViz writes value to fileName with writeCSV for .csv files, or as JSON otherwise
+func writeViz(fileName string, value interface{}, writeCSV func(io.Writer) error) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(fileName), ".csv") {
+		err = writeCSV(f)
+	} else {
+		err = json.NewEncoder(f).Encode(value)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func writeVizCSV(w io.Writer, rows []vizRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"language", "rank", "ngram", "length", "count", "frequency"})
+	for _, row := range rows {
+		cw.Write([]string{row.Language, strconv.Itoa(row.Rank), row.NGram, strconv.Itoa(row.Length),
+			strconv.Itoa(row.Count), strconv.FormatFloat(row.Frequency, 'f', 6, 64)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeMatrixCSV(w io.Writer, matrix similarityMatrix) error {
+	cw := csv.NewWriter(w)
+	cw.Write(append([]string{"language"}, matrix.Languages...))
+	for i, values := range matrix.Values {
+		record := []string{matrix.Languages[i]}
+		for _, value := range values {
+			record = append(record, strconv.FormatFloat(value, 'f', 4, 64))
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestViz(t *testing.T) {
+	Convey("Subject: Export profiles for charts", t, func() {
+		en := langdet.Language{Name: "en", Profile: map[string]int{"t": 1, "h": 2, "e": 3}, Counts: map[string]int{"t": 5, "h": 3, "e": 2}}
+		fr := langdet.Language{Name: "fr", Profile: map[string]int{"e": 1, "s": 2}}
+		Convey("The n-grams should be ordered by rank, with frequencies if there are counts", func() {
+			rows := vizRows(en, 2)
+			So(rows, ShouldResemble, []vizRow{
+				{Language: "en", Rank: 1, NGram: "t", Length: 1, Count: 5, Frequency: 0.5},
+				{Language: "en", Rank: 2, NGram: "h", Length: 1, Count: 3, Frequency: 0.3},
+			})
+			So(vizRows(fr, 0)[1].Count, ShouldEqual, 0)
+		})
+		Convey("Profiles should be most similar to themselves", func() {
+			matrix := similarities([]langdet.Language{en, fr})
+			So(matrix.Languages, ShouldResemble, []string{"en", "fr"})
+			So(matrix.Values[0][0], ShouldEqual, 1)
+			So(matrix.Values[1][1], ShouldEqual, 1)
+			So(matrix.Values[0][1], ShouldBeLessThan, 1)
+			So(matrix.Values[1][0], ShouldBeLessThan, 1)
+		})
+	})
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"github.com/imankulov/go-lang-detector/langdet"
-	"io/ioutil"
 	"os"
 )
 
@@ -14,10 +13,12 @@ Arabic, English, French, German, Hebrew, Russian, Turkish
 func main() {
 
 	//sample using Reader to Initialize default languages
-	//	analyzedInput, _ := ioutil.ReadFile("default_languages2.json")
+	//	analyzedInput, _ := os.ReadFile("default_languages2.json")
 	//	s := string(analyzedInput[:1652088])
-	//	langdet.InitWithDefaultFromReader(strings.NewReader(s))
-	//	detector := langdet.NewDefaultLanguages()
+	//	detector := langdet.NewDetector()
+	//	if err := detector.LoadLanguagesFromReader(strings.NewReader(s)); err != nil {
+	//		panic(err)
+	//	}
 
 	//sample by manually analyzing languages
 	//	 Analyze different languages from files and and write to analyzed.json:
@@ -36,14 +37,14 @@ func main() {
 	fullResults := detector.GetLanguages(testString)
 	fmt.Println("GetLanguages returns:")
 	for _, r := range fullResults {
-		fmt.Println("    ", r.Name, r.Confidence, "%")
+		fmt.Printf("     %s %.1f %%\n", r.Name, r.Confidence)
 	}
 
 }
 
 // GetTextFromFile returns the content of file (identified by given fileName) as text
 func GetTextFromFile(fileName string) string {
-	text, err := ioutil.ReadFile(fileName)
+	text, err := os.ReadFile(fileName)
 	if err != nil {
 		panic(err)
 	}
@@ -52,7 +53,7 @@ func GetTextFromFile(fileName string) string {
 
 // WriteToFile writes a content into a file with specified name
 func WriteToFile(content []byte, fileName string) {
-	err := ioutil.WriteFile(fileName, content, os.ModePerm)
+	err := os.WriteFile(fileName, content, os.ModePerm)
 	if err != nil {
 		panic(err)
 	}
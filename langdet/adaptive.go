@@ -0,0 +1,147 @@
+package langdet
+
+import (
+	"bytes"
+	"io"
+)
+
+// DefaultAdaptiveChunkSize is the number of bytes that DetectAdaptive reads between two scorings
+// when the ChunkSize of the AdaptiveOptions is 0
+const DefaultAdaptiveChunkSize = 4 * 1024
+
+// DefaultStableChunks is the number of consecutive chunks that the closest language must lead for
+// DetectAdaptive to stop reading, when the StableChunks of the AdaptiveOptions is 0
+const DefaultStableChunks = 3
+
+// AdaptiveOptions configure DetectAdaptive
+type AdaptiveOptions struct {
+	// ChunkSize is the number of bytes read between two scorings, 0 means DefaultAdaptiveChunkSize
+	ChunkSize int
+	// StableChunks is the number of consecutive chunks after which the same closest language with
+	// the MinLead is final, 0 means DefaultStableChunks
+	StableChunks int
+	// MinLead is the minimum difference between the confidences, between 0 and 1, of the closest
+	// and the second closest language for a chunk to count as stable. 0 means the MinMargin of
+	// the detector, negative values accept any lead.
+	MinLead float64
+	// MaxBytes is the maximum number of bytes read if the closest language never stabilizes, 0
+	// means DefaultMaxDetectBytes and negative values read the whole input
+	MaxBytes int64
+}
+
+// AdaptiveResult is the result of Detector.DetectAdaptive
+type AdaptiveResult struct {
+	DetectionResult
+	// ConsumedBytes is the number of bytes read from the input
+	ConsumedBytes int64
+	// Stable tells whether the reading stopped because the closest language was stable, and not
+	// at the end of the input or after MaxBytes
+	Stable bool
+	// Lead is the difference between the confidences of the closest and the second closest
+	// language, between 0 and 1, or the confidence of the closest language if it is the only one
+	Lead float64
+	// Reliable tells whether the result is confident enough, like the one of
+	// GetClosestLanguageWithConfidence
+	Reliable bool
+}
+
+// DetectAdaptive returns the closest language of the text of a reader like
+// GetClosestLanguageFromReader, but instead of reading up to a fixed limit, it scores the text
+// read so far after every chunk and stops once the same language led the others by MinLead for
+// StableChunks chunks in a row. Long documents of a single language are detected from their
+// first few chunks. Chunks are split after their last space or newline, so that words are never
+// split.
+func (d *Detector) DetectAdaptive(reader io.Reader, options AdaptiveOptions) (AdaptiveResult, error) {
+	options = d.adaptiveDefaults(options)
+	if options.MaxBytes > 0 {
+		reader = io.LimitReader(reader, options.MaxBytes)
+	}
+	depth := d.inputDepth(d.snapshot())
+	occ := make(map[string]int)
+	var lookupMap map[string]int
+	var results []DetectionResult
+	result := AdaptiveResult{DetectionResult: DetectionResult{Name: "undefined"}}
+	buf := make([]byte, options.ChunkSize)
+	pending := []byte{}
+	leader, stable := "", 0
+	for {
+		n, err := io.ReadFull(reader, buf)
+		result.ConsumedBytes += int64(n)
+		pending = append(pending, buf[:n]...)
+		end := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !end {
+			return result, err
+		}
+		analyzed := 0
+		if i := bytes.LastIndexAny(pending, " \n"); i >= 0 {
+			analyzed = i + 1
+		}
+		if end && result.ConsumedBytes != options.MaxBytes {
+			// a word that may be cut by MaxBytes is ignored
+			analyzed = len(pending)
+		}
+		if analyzed > 0 {
+			UpdateOccurenceMap(occ, tokenize(d.Tokenizer, string(pending[:analyzed])), depth)
+			pending = append(pending[:0], pending[analyzed:]...)
+			lookupMap = CreateRankLookupMap(occ)
+			results = d.closestFromTable(lookupMap)
+			if len(results) > 0 {
+				result.Lead = margin(results)
+				switch {
+				case result.Lead < options.MinLead:
+					leader, stable = "", 0
+				case results[0].Name == leader:
+					stable++
+				default:
+					leader, stable = results[0].Name, 1
+				}
+			}
+		}
+		if stable >= options.StableChunks {
+			result.Stable = true
+			break
+		}
+		if end {
+			break
+		}
+	}
+	if len(results) == 0 {
+		return result, nil
+	}
+	result.DetectionResult = results[0]
+	result.Reliable = d.reliable(lookupMap, results[0])
+	if result.Reliable {
+		d.recent.see(results[0].Name)
+	}
+	return result, nil
+}
+
+// adaptiveDefaults returns options with the defaults of their zero values
+func (d *Detector) adaptiveDefaults(options AdaptiveOptions) AdaptiveOptions {
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = DefaultAdaptiveChunkSize
+	}
+	if options.StableChunks <= 0 {
+		options.StableChunks = DefaultStableChunks
+	}
+	if options.MinLead == 0 {
+		options.MinLead = d.MinMargin
+		if options.MinLead == 0 {
+			options.MinLead = DefaultMinMargin
+		}
+	}
+	if options.MaxBytes == 0 {
+		options.MaxBytes = DefaultMaxDetectBytes
+	}
+	return options
+}
+
+// margin returns the difference between the confidences of the closest and the second closest of
+// sorted results, between 0 and 1, or the confidence of the only result
+func margin(results []DetectionResult) float64 {
+	margin := results[0].Confidence / 100
+	if len(results) > 1 {
+		margin -= results[1].Confidence / 100
+	}
+	return margin
+}
@@ -0,0 +1,55 @@
+package langdet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDetectAdaptive(t *testing.T) {
+	Convey("Subject: Detect long documents until the closest language is stable", t, func() {
+		d := langdet.NewDefaultLanguages()
+		document := strings.Repeat("This is a long english document, which repeats the same sentence again and again. ", 500)
+
+		Convey("Documents of one language should be detected from their first chunks", func() {
+			res, err := d.DetectAdaptive(strings.NewReader(document), langdet.AdaptiveOptions{ChunkSize: 256})
+			So(err, ShouldBeNil)
+			So(res.Name, ShouldEqual, "en")
+			So(res.Stable, ShouldBeTrue)
+			So(res.Reliable, ShouldBeTrue)
+			So(res.ConsumedBytes, ShouldEqual, 3*256)
+			So(res.Lead, ShouldBeGreaterThan, langdet.DefaultMinMargin)
+		})
+		Convey("Unstable documents should be read up to MaxBytes", func() {
+			options := langdet.AdaptiveOptions{ChunkSize: 256, StableChunks: 1000, MaxBytes: 10000}
+			res, err := d.DetectAdaptive(strings.NewReader(document), options)
+			So(err, ShouldBeNil)
+			So(res.Name, ShouldEqual, "en")
+			So(res.Stable, ShouldBeFalse)
+			So(res.ConsumedBytes, ShouldEqual, 10000)
+
+			options.MaxBytes = -1
+			res, err = d.DetectAdaptive(strings.NewReader(document), options)
+			So(err, ShouldBeNil)
+			So(res.ConsumedBytes, ShouldEqual, len(document))
+		})
+		Convey("The closest language should only be stable with a clear lead", func() {
+			res, err := d.DetectAdaptive(strings.NewReader(document), langdet.AdaptiveOptions{ChunkSize: 256, MinLead: 0.99})
+			So(err, ShouldBeNil)
+			So(res.Stable, ShouldBeFalse)
+			So(res.ConsumedBytes, ShouldEqual, len(document))
+		})
+		Convey("Short and empty inputs should be read completely", func() {
+			res, err := d.DetectAdaptive(strings.NewReader("Hello, how are you doing today?"), langdet.AdaptiveOptions{})
+			So(err, ShouldBeNil)
+			So(res.Name, ShouldEqual, "en")
+			So(res.ConsumedBytes, ShouldEqual, 31)
+			res, err = d.DetectAdaptive(strings.NewReader(""), langdet.AdaptiveOptions{})
+			So(err, ShouldBeNil)
+			So(res.Name, ShouldEqual, "undefined")
+			So(res.Reliable, ShouldBeFalse)
+		})
+	})
+}
@@ -1,26 +1,46 @@
 package langdet
 
 import (
-	"bytes"
 	"sort"
-	"strings"
-	"unicode/utf8"
+
+	"github.com/imankulov/go-lang-detector/langdet/ngram"
 )
 
 // maxSampleSize represents the maximum number of tokens per sample, low number can
 // cause bad accuracy, but better performance.
-// -1 for no maximum
-var maxSampleSize = 10000
+const maxSampleSize = 10000
+
+// maxOccurenceMapHint is the maximum initial size of the maps of CreateOccurenceMap, bigger texts
+// repeat most of their n-grams
+const maxOccurenceMapHint = 1024
+
+// StripFormatControls tells whether zero-width and bidi control characters are removed from texts
+// before they are analyzed. They often appear in copy-pasted text and split n-grams, that are
+// otherwise identical. It applies to training and detection alike, so it should only be changed
+// before any profile is created.
+var StripFormatControls = true
 
 // Analyze creates the language profile from a given Text and returns it in a Language struct.
 func Analyze(text, name string) Language {
 	theMap := CreateOccurenceMap(text, nDepth)
 	ranked := CreateRankLookupMap(theMap)
-	return Language{Name: name, Profile: ranked}
+	tokens := 0
+	for _, count := range theMap {
+		tokens += count
+	}
+	language := Language{Name: name, Profile: ranked, Size: DefaultProfileSize, TokenCount: tokens, FormatVersion: ProfileFormatVersion}
+	language.infer()
+	return language
 }
 
 // CreateRankLookupMap creates the map [token] rank from a map [token] occurrence
 func CreateRankLookupMap(input map[string]int) map[string]int {
+	return rankLookupMap(input, DefaultProfileSize)
+}
+
+// rankLookupMap creates the map [token] rank of the maxRanks most frequent tokens of a map
+// [token] occurrence
+func rankLookupMap(input map[string]int, maxRanks int) map[string]int {
 	tokens := make([]Token, len(input))
 	counter := 0
 	for k, v := range input {
@@ -30,93 +50,35 @@ func CreateRankLookupMap(input map[string]int) map[string]int {
 	sort.Sort(ByOccurrence(tokens))
 	result := make(map[string]int)
 	length := len(tokens)
-	locMaxL := maxSampleSize
-	if locMaxL < 0 {
-		locMaxL = length
-	}
-	for i := length - 1; i >= 0 && i > length-locMaxL; i-- {
+	for i := length - 1; i >= 0 && i >= length-maxRanks; i-- {
 		result[tokens[i].Key] = length - i
 	}
 	return result
 }
 
 // CreateOccurenceMap creates a map[token]occurrence from a given text and up to a given gram depth
-// gramDepth=1 means only 1-letter tokens are created, gramDepth=2 means 1- and 2-letters token are created, etc.
+// gramDepth=0 means only 1-letter tokens are created, gramDepth=1 means 1- and 2-letters token are created, etc.
 func CreateOccurenceMap(text string, gramDepth int) map[string]int {
-	result := make(map[string]int)
+	// short texts have about one distinct n-gram per letter and depth, sizing the map up front
+	// saves growing it
+	hint := len(text) * (gramDepth + 1) / 2
+	if hint > maxOccurenceMapHint {
+		hint = maxOccurenceMapHint
+	}
+	result := make(map[string]int, hint)
 	UpdateOccurenceMap(result, text, gramDepth)
 	return result
 }
 
 // UpdateOccurenceMap updates a map[token]occurence from the text. Useful to iterate over the
-// list of strings to add them
+// list of strings to add them. The n-grams are extracted by the ngram package, whose Extract
+// customizes padding and case.
 func UpdateOccurenceMap(occurenceMap map[string]int, text string, gramDepth int) {
-	text = cleanText(text)
-	tokens := strings.Split(text, " ")
-	for _, token := range tokens {
-		analyseToken(occurenceMap, token, gramDepth)
-	}
-}
-
-// analyseToken analyses a token to a certain gramDepth and stores the result in resultMap
-func analyseToken(resultMap map[string]int, token string, gramDepth int) {
-	if len(token) == 0 {
-		return
-	}
-	for i := 1; i <= gramDepth+1; i++ {
-		generateNthGrams(resultMap, token, i)
-	}
-}
-
-// generateNthGrams creates n-gram tokens from the input string and
-// adds the mapping from token to its number of occurrences to the resultMap
-func generateNthGrams(resultMap map[string]int, text string, n int) {
-	padding := createPadding(n - 1)
-	text = padding + text + padding
-	upperBound := utf8.RuneCountInString(text) - (n - 1)
-	for p := 0; p < upperBound; p++ {
-		currentToken := text[p : p+n]
-		resultMap[currentToken]++
-	}
-}
-
-// createPadding surrounds text with a padding
-func createPadding(length int) string {
-	var buffer bytes.Buffer
-	padding := "_"
-	for i := 0; i < length; i++ {
-		buffer.WriteString(padding)
-	}
-	return buffer.String()
+	updateOccurences(occurenceMap, text, gramDepth, StripFormatControls)
 }
 
-// cleanText removes newlines, special characters and numbers from a input text
-func cleanText(text string) string {
-	text = strings.Replace(text, "\n", " ", -1)
-	text = strings.Replace(text, ",", " ", -1)
-	text = strings.Replace(text, "#", " ", -1)
-	text = strings.Replace(text, "/", " ", -1)
-	text = strings.Replace(text, "\\", " ", -1)
-	text = strings.Replace(text, ".", " ", -1)
-	text = strings.Replace(text, "!", " ", -1)
-	text = strings.Replace(text, "?", " ", -1)
-	text = strings.Replace(text, ":", " ", -1)
-	text = strings.Replace(text, ";", " ", -1)
-	text = strings.Replace(text, "-", " ", -1)
-	text = strings.Replace(text, "'", " ", -1)
-	text = strings.Replace(text, "\"", " ", -1)
-	text = strings.Replace(text, "_", " ", -1)
-	text = strings.Replace(text, "*", " ", -1)
-	text = strings.Replace(text, "1", "", -1)
-	text = strings.Replace(text, "2", "", -1)
-	text = strings.Replace(text, "3", "", -1)
-	text = strings.Replace(text, "4", "", -1)
-	text = strings.Replace(text, "5", "", -1)
-	text = strings.Replace(text, "6", "", -1)
-	text = strings.Replace(text, "7", "", -1)
-	text = strings.Replace(text, "8", "", -1)
-	text = strings.Replace(text, "9", "", -1)
-	text = strings.Replace(text, "0", "", -1)
-	text = strings.Replace(text, "  ", " ", -1)
-	return text
+// updateOccurences updates a map[token]occurence from the text, stripping format controls if strip
+// is set, see the ngram package
+func updateOccurences(occurenceMap map[string]int, text string, gramDepth int, strip bool) {
+	ngram.Update(occurenceMap, text, gramDepth, ngram.Options{KeepFormatControls: !strip})
 }
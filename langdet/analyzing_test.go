@@ -3,6 +3,7 @@ package langdet_test
 import (
 	"github.com/imankulov/go-lang-detector/langdet"
 	. "github.com/smartystreets/goconvey/convey"
+	"strings"
 	"testing"
 )
 
@@ -79,3 +80,110 @@ func TestRanking(t *testing.T) {
 	})
 
 }
+
+func TestRerank(t *testing.T) {
+	Convey("Subject: Rerank an edited profile", t, func() {
+		Convey("Edited ranks should become contiguous and keep their order", func() {
+			language := langdet.Language{Name: "x", Profile: map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}}
+			language.Profile["d"] = 0
+			delete(language.Profile, "b")
+			language.Profile["e"] = 10
+			language.Rerank()
+			So(language.Profile, ShouldResemble, map[string]int{"d": 1, "a": 2, "c": 3, "e": 4})
+		})
+		Convey("Tokens of the same rank should be ordered by the token", func() {
+			language := langdet.Language{Name: "x", Profile: map[string]int{"b": 1, "a": 1, "c": 1}}
+			language.Rerank()
+			So(language.Profile, ShouldResemble, map[string]int{"a": 1, "b": 2, "c": 3})
+		})
+		Convey("Edited counts should be ranked again", func() {
+			language := langdet.Language{Name: "x", Profile: map[string]int{"a": 1, "b": 2, "c": 3},
+				Counts: map[string]int{"a": 30, "b": 20, "c": 10}}
+			language.Counts["c"] = 100
+			language.Counts["d"] = 25
+			delete(language.Counts, "a")
+			language.Rerank()
+			So(language.Profile, ShouldResemble, map[string]int{"c": 1, "d": 2, "b": 3})
+		})
+	})
+}
+
+func TestStripFormatControls(t *testing.T) {
+	Convey("Subject: Strip zero-width and bidi control characters", t, func() {
+		plain := langdet.CreateOccurenceMap("hello world", 3)
+		Convey("Control characters should not change the n-grams", func() {
+			So(langdet.CreateOccurenceMap("hel\u200Dlo\u200Bworld\u200E", 3), ShouldResemble, plain)
+			So(langdet.CreateOccurenceMap("\u202Ehello\u202C \u2067world\u2069", 3), ShouldResemble, plain)
+		})
+		Convey("Control characters should be kept if stripping is disabled", func() {
+			langdet.StripFormatControls = false
+			defer func() { langdet.StripFormatControls = true }()
+			So(langdet.CreateOccurenceMap("hel\u200Dlo world", 3), ShouldNotResemble, plain)
+		})
+	})
+}
+
+// referenceOccurences counts the n-grams of a text by cleaning it with one replacement per
+// character and padding every n-gram depth separately
+func referenceOccurences(text string, gramDepth int) map[string]int {
+	for _, separator := range []string{"\n", ",", "#", "/", "\\", ".", "!", "?", ":", ";", "-", "'", "\"", "_", "*"} {
+		text = strings.Replace(text, separator, " ", -1)
+	}
+	for _, digit := range "0123456789" {
+		text = strings.Replace(text, string(digit), "", -1)
+	}
+	result := make(map[string]int)
+	for _, token := range strings.Split(text, " ") {
+		if token == "" {
+			continue
+		}
+		for n := 1; n <= gramDepth+1; n++ {
+			padding := strings.Repeat("_", n-1)
+			letters := []rune(padding + token + padding)
+			for p := 0; p < len(letters)-(n-1); p++ {
+				result[string(letters[p:p+n])]++
+			}
+		}
+	}
+	return result
+}
+
+func TestOccurenceMapReference(t *testing.T) {
+	Convey("Subject: Occurrence maps should count like the definition of n-grams", t, func() {
+		texts := append([]string{
+			"", "a", "  two   spaces\tand tabs ", "under_score and dash-ed", "x1y22z 123 4five",
+			"Grüße, 東京!", "\xff\xfeinvalid", "\"quoted\" *stars* #tag path/to\\file",
+		}, benchmarkMessages...)
+		for _, text := range texts {
+			for depth := 0; depth <= 5; depth++ {
+				So(langdet.CreateOccurenceMap(text, depth), ShouldResemble, referenceOccurences(text, depth))
+			}
+		}
+		So(langdet.CreateOccurenceMap("text", -1), ShouldBeEmpty)
+	})
+}
+
+// benchmarkMessages are short messages of several scripts, like the texts of chat pipelines
+var benchmarkMessages = []string{
+	"Hello, how are you doing today? Let's meet at 5pm.",
+	"Je ne sais pas ce que tu dis, mais c'est très intéressant!",
+	"Wir sind am Wochenende ans Meer gefahren.",
+	"На выходных мы ездили на море, и погода была очень хорошей",
+	"ذهبنا إلى البحر في عطلة نهاية الأسبوع",
+}
+
+func BenchmarkCreateOccurenceMapShort(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = langdet.CreateOccurenceMap(benchmarkMessages[n%len(benchmarkMessages)], 4)
+	}
+}
+
+func BenchmarkCreateOccurenceMapLong(b *testing.B) {
+	text := strings.Repeat(strings.Join(benchmarkMessages, " "), 50)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(text)))
+	for n := 0; n < b.N; n++ {
+		_ = langdet.CreateOccurenceMap(text, 4)
+	}
+}
@@ -0,0 +1,181 @@
+package langdet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of inconsistencies reported by Detector.Audit
+const (
+	// AuditDuplicateName is reported if several languages share the same name
+	AuditDuplicateName = "duplicate-name"
+	// AuditEmptyProfile is reported for languages without any token in their profile
+	AuditEmptyProfile = "empty-profile"
+	// AuditMixedDepth is reported if profiles of the same script were created with different n-gram
+	// depths. Profiles of different scripts may differ, see DepthForScript.
+	AuditMixedDepth = "mixed-depth"
+	// AuditDepthMismatch is reported for profiles created with a deeper n-gram depth than the Depth
+	// of the detector, whose longest tokens are never matched
+	AuditDepthMismatch = "depth-mismatch"
+	// AuditMixedNormalization is reported if some profiles were created from lowercased text and others were not
+	AuditMixedNormalization = "mixed-normalization"
+	// AuditRankCutoff is reported for profiles that have fewer ranks than the detector compares,
+	// or whose ranks are not contiguous
+	AuditRankCutoff = "rank-cutoff"
+)
+
+// AuditIssue describes an inconsistency between the languages of a Detector that is likely to hurt accuracy
+type AuditIssue struct {
+	Kind      string
+	Languages []string
+	Message   string
+}
+
+// String returns a human readable description of the issue
+func (i AuditIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+}
+
+// Audit reports inconsistencies between the languages of this Detector, like mixed n-gram depths,
+// mixed normalization settings, rank cutoff mismatches or duplicate names. Normalization, and the
+// depth of profiles without a Depth, are inferred from the tokens of the profiles.
+// It returns no issues if the languages are consistent.
+func (d *Detector) Audit() []AuditIssue {
+	issues := []AuditIssue{}
+	languages := d.snapshot()
+
+	byName := make(map[string]int)
+	// byDepth are the names of the languages by their depth, by the main script of the languages
+	byDepth := make(map[string]map[int][]string)
+	byCase := make(map[bool][]string)
+	for _, language := range languages {
+		byName[language.Name]++
+		if len(language.Profile) == 0 {
+			issues = append(issues, AuditIssue{
+				Kind:      AuditEmptyProfile,
+				Languages: []string{language.Name},
+				Message:   fmt.Sprintf("language %q has an empty profile", language.Name),
+			})
+			continue
+		}
+		depth, cased, maxRank := inspectProfile(language.Profile)
+		if language.Depth > 0 {
+			depth = language.Depth
+		}
+		script := ""
+		if scripts := language.TextScripts(); len(scripts) > 0 {
+			script = scripts[0]
+		}
+		if byDepth[script] == nil {
+			byDepth[script] = make(map[int][]string)
+		}
+		byDepth[script][depth] = append(byDepth[script][depth], language.Name)
+		byCase[cased] = append(byCase[cased], language.Name)
+		if inputDepth := d.inputDepth(languages); depth > inputDepth {
+			issues = append(issues, AuditIssue{
+				Kind:      AuditDepthMismatch,
+				Languages: []string{language.Name},
+				Message:   fmt.Sprintf("language %q was created with depth %d, but the detector uses depth %d", language.Name, depth, inputDepth),
+			})
+		}
+		if compared := d.comparedRanks(); compared > 0 && len(language.Profile) < compared {
+			issues = append(issues, AuditIssue{
+				Kind:      AuditRankCutoff,
+				Languages: []string{language.Name},
+				Message:   fmt.Sprintf("language %q has %d ranks, fewer than the %d compared input ranks", language.Name, len(language.Profile), compared),
+			})
+		} else if maxRank != len(language.Profile) {
+			issues = append(issues, AuditIssue{
+				Kind:      AuditRankCutoff,
+				Languages: []string{language.Name},
+				Message:   fmt.Sprintf("language %q has %d tokens, but ranks up to %d", language.Name, len(language.Profile), maxRank),
+			})
+		}
+	}
+
+	duplicates := []string{}
+	for name, count := range byName {
+		if count > 1 {
+			duplicates = append(duplicates, name)
+		}
+	}
+	sort.Strings(duplicates)
+	for _, name := range duplicates {
+		issues = append(issues, AuditIssue{
+			Kind:      AuditDuplicateName,
+			Languages: []string{name},
+			Message:   fmt.Sprintf("language %q is loaded %d times", name, byName[name]),
+		})
+	}
+
+	scripts := []string{}
+	for script := range byDepth {
+		scripts = append(scripts, script)
+	}
+	sort.Strings(scripts)
+	for _, script := range scripts {
+		if len(byDepth[script]) < 2 {
+			continue
+		}
+		depths := []int{}
+		for depth := range byDepth[script] {
+			depths = append(depths, depth)
+		}
+		sort.Ints(depths)
+		names := []string{}
+		details := ""
+		for _, depth := range depths {
+			names = append(names, byDepth[script][depth]...)
+			details += fmt.Sprintf(" depth %d: %v;", depth, byDepth[script][depth])
+		}
+		issues = append(issues, AuditIssue{
+			Kind:      AuditMixedDepth,
+			Languages: names,
+			Message:   fmt.Sprintf("profiles of the %s script were created with different n-gram depths:%s", script, details[:len(details)-1]),
+		})
+	}
+
+	if len(byCase) > 1 {
+		issues = append(issues, AuditIssue{
+			Kind:      AuditMixedNormalization,
+			Languages: append(append([]string{}, byCase[false]...), byCase[true]...),
+			Message:   fmt.Sprintf("profiles %v were created from lowercased text, but %v were not", byCase[false], byCase[true]),
+		})
+	}
+	return issues
+}
+
+// inspectProfile infers the n-gram depth a profile was created with, whether it contains
+// upper case tokens and its highest rank. The depth is inferred from the longest tokens with the
+// "_" padding of word boundaries, which every depth creates, so that hand-made tokens don't count.
+func inspectProfile(profile map[string]int) (depth int, cased bool, maxRank int) {
+	maxLength, maxPadded := 0, 0
+	for token, rank := range profile {
+		l := utf8.RuneCountInString(token)
+		if l > maxLength {
+			maxLength = l
+		}
+		if l > maxPadded && strings.Contains(token, "_") {
+			maxPadded = l
+		}
+		if rank > maxRank {
+			maxRank = rank
+		}
+		if !cased {
+			for _, r := range token {
+				if unicode.IsUpper(r) {
+					cased = true
+					break
+				}
+			}
+		}
+	}
+	// a depth of n creates tokens of up to n+1 letters, only a depth of 0 creates no padding
+	if maxPadded > 0 {
+		maxLength = maxPadded
+	}
+	return maxLength - 1, cased, maxRank
+}
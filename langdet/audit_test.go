@@ -0,0 +1,61 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func issueKinds(issues []langdet.AuditIssue) []string {
+	kinds := []string{}
+	for _, issue := range issues {
+		kinds = append(kinds, issue.Kind)
+	}
+	return kinds
+}
+
+func TestAudit(t *testing.T) {
+	Convey("Subject: Audit the languages of a detector", t, func() {
+		d := langdet.NewDetector()
+		d.MaxInputRanks = 5
+		Convey("Consistent languages should not report any issue", func() {
+			d.AddLanguageFromText("This is an english sentence", "en")
+			d.AddLanguageFromText("Das ist ein deutscher Satz", "de")
+			So(d.Audit(), ShouldBeEmpty)
+		})
+		Convey("Duplicate names should be reported", func() {
+			d.AddLanguageFromText("This is an english sentence", "en")
+			d.AddLanguageFromText("This is another english sentence", "en")
+			So(issueKinds(d.Audit()), ShouldResemble, []string{langdet.AuditDuplicateName})
+		})
+		Convey("Profiles of different depths should be reported", func() {
+			d.AddLanguageFromText("This is an english sentence", "en")
+			d.AddLanguage(langdet.Language{Name: "de", Profile: langdet.CreateRankLookupMap(langdet.CreateOccurenceMap("Das ist ein deutscher Satz", 2))})
+			So(issueKinds(d.Audit()), ShouldResemble, []string{langdet.AuditMixedDepth})
+			d.Depth = 3
+			kinds := issueKinds(d.Audit())
+			So(kinds, ShouldContain, langdet.AuditMixedDepth)
+			So(kinds, ShouldContain, langdet.AuditDepthMismatch)
+		})
+		Convey("Profiles of different scripts may have different depths", func() {
+			for name, text := range map[string]string{"en": "This is an english sentence", "ru": "Это предложение на русском языке"} {
+				trainer := &langdet.Trainer{}
+				trainer.Feed(text)
+				d.AddLanguage(trainer.Build(name))
+			}
+			So(d.Snapshot()[0].Depth, ShouldNotEqual, d.Snapshot()[1].Depth)
+			So(d.Audit(), ShouldBeEmpty)
+		})
+		Convey("Profiles of lowercased and original text should be reported", func() {
+			d.AddLanguageFromText("This is an english sentence", "en")
+			d.AddLanguageFromText("das ist ein deutscher satz", "de")
+			So(issueKinds(d.Audit()), ShouldResemble, []string{langdet.AuditMixedNormalization})
+		})
+		Convey("Small and empty profiles should be reported", func() {
+			d.MaxInputRanks = 0
+			d.AddLanguageFromText("This is an english sentence", "en")
+			d.AddLanguage(langdet.Language{Name: "xx"})
+			So(issueKinds(d.Audit()), ShouldResemble, []string{langdet.AuditRankCutoff, langdet.AuditEmptyProfile})
+		})
+	})
+}
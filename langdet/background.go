@@ -0,0 +1,58 @@
+package langdet
+
+import "sort"
+
+// BackgroundLanguage is the name of the languages built by NewBackgroundModel
+const BackgroundLanguage = "background"
+
+// NewBackgroundModel builds a background model of none of the detectable languages from the
+// profiles of other languages, for Detector.Background. The tokens of all profiles are weighted by
+// the inverse of their ranks, as their frequencies follow Zipf's law, and ranked by their sums.
+func NewBackgroundModel(languages ...Language) Language {
+	weights := make(map[string]float64)
+	depth := 0
+	for _, language := range languages {
+		for token, rank := range language.Profile {
+			weights[token] += 1 / float64(rank)
+		}
+		if language.Depth > depth {
+			depth = language.Depth
+		}
+	}
+	tokens := make([]string, 0, len(weights))
+	for token := range weights {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if weights[tokens[i]] == weights[tokens[j]] {
+			return tokens[i] < tokens[j]
+		}
+		return weights[tokens[i]] > weights[tokens[j]]
+	})
+	if len(tokens) > maxSampleSize {
+		tokens = tokens[:maxSampleSize]
+	}
+	background := Language{Name: BackgroundLanguage, Depth: depth, Profile: make(map[string]int, len(tokens))}
+	for i, token := range tokens {
+		background.Profile[token] = i + 1
+	}
+	background.infer()
+	return background
+}
+
+// closerToBackground tells whether the text of a lookupMap is at least as close to the Background
+// model of the detector as result, the result of a language
+func (d *Detector) closerToBackground(lookupMap map[string]int, result DetectionResult) bool {
+	background, ok := d.backgroundResult(lookupMap)
+	return ok && background.Confidence >= result.Confidence
+}
+
+// backgroundResult returns the result of the Background model of the detector for the text of a
+// lookupMap, and false if the detector has no Background model
+func (d *Detector) backgroundResult(lookupMap map[string]int) (DetectionResult, bool) {
+	if d.Background == nil {
+		return DetectionResult{}, false
+	}
+	input := newDepthLookups(lookupMap).forDepth(d.Background.Depth)
+	return d.score(input, d.maxInputRanks(len(input)), *d.Background), true
+}
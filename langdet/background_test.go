@@ -0,0 +1,44 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestBackgroundModel(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	de := "Ich spreche Deutsch und du? Ich weiß nicht, was du sagst. Wie heißt deine Sprache?"
+	Convey("Subject: Detect texts of languages that are not loaded", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+		d.MinimumConfidence = 0.01
+		background := langdet.NewBackgroundModel(langdet.Analyze(de, "german"))
+		text := "Ich weiß nicht, wie deine Sprache heißt"
+
+		Convey("Without a background model the closest language should be returned", func() {
+			So(d.GetClosestLanguage(text), ShouldNotEqual, "undefined")
+		})
+		Convey("Texts closer to the background model should be undefined", func() {
+			d.Background = &background
+			So(d.GetClosestLanguage(text), ShouldEqual, "undefined")
+			So(d.GetClosestLanguage("what is your language"), ShouldEqual, "english")
+			ok, _ := d.Verify(text, "english")
+			So(ok, ShouldBeFalse)
+		})
+		Convey("Background models should rank the tokens of all languages", func() {
+			mixed := langdet.NewBackgroundModel(langdet.Analyze(en, "english"), langdet.Analyze(de, "german"))
+			So(mixed.Name, ShouldEqual, langdet.BackgroundLanguage)
+			So(mixed.Depth, ShouldEqual, 4)
+			ranks := make(map[int]bool)
+			for _, rank := range mixed.Profile {
+				ranks[rank] = true
+			}
+			So(ranks[1] && ranks[len(mixed.Profile)], ShouldBeTrue)
+			So(len(ranks), ShouldEqual, len(mixed.Profile))
+			So(len(mixed.Profile), ShouldBeGreaterThan, len(background.Profile))
+		})
+	})
+}
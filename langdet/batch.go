@@ -0,0 +1,137 @@
+package langdet
+
+import (
+	"runtime"
+	"sync"
+)
+
+// DetectionResultSet is the detection of a text of a batch
+type DetectionResultSet struct {
+	// Language is the closest language like the result of GetClosestLanguage, or undefined if the
+	// detection is not reliable, or InsufficientInput for texts shorter than MinInputLength
+	Language string
+	// Confidence is the confidence of the closest language, between 0 and 1
+	Confidence float64
+	// Reliable tells whether the closest language is confident enough, like the result of
+	// GetClosestLanguageWithConfidence
+	Reliable bool
+	// Results are the DetectionResults of all languages like the results of GetLanguages
+	Results []DetectionResult
+}
+
+// occurenceMaps are the reused occurrence maps of the texts of batches
+var occurenceMaps = sync.Pool{New: func() interface{} { return make(map[string]int) }}
+
+// DetectBatch returns the closest language and the results of all languages of every text, in
+// the order of the texts. The texts are detected concurrently by workers goroutines, or GOMAXPROCS
+// goroutines if workers is not positive. The languages and their options are looked up once per
+// batch and the occurrence maps of the texts are reused. If DedupBatch is set, repeated texts are
+// detected only once, their sets share the Results.
+func (d *Detector) DetectBatch(texts []string, workers int) []DetectionResultSet {
+	sets := make([]DetectionResultSet, len(texts))
+	// unique are the indexes of the texts to detect, the others are copied from first
+	unique := make([]int, 0, len(texts))
+	var first map[string]int
+	if d.DedupBatch {
+		first = make(map[string]int, len(texts))
+	}
+	for i, text := range texts {
+		if first != nil {
+			if _, ok := first[text]; ok {
+				continue
+			}
+			first[text] = i
+		}
+		unique = append(unique, i)
+	}
+
+	batch := d.newBatch()
+	concurrently(len(unique), workers, func(j int) {
+		i := unique[j]
+		sets[i] = batch.detect(texts[i])
+	})
+
+	if first != nil {
+		for i, text := range texts {
+			sets[i] = sets[first[text]]
+		}
+	}
+	return sets
+}
+
+// batch has what the detections of the texts of a batch share
+type batch struct {
+	d         *Detector
+	languages []Language
+	depth     int
+	options   map[string]LanguageOptions
+}
+
+// newBatch returns the batch of the current languages of the detector
+func (d *Detector) newBatch() *batch {
+	languages := d.snapshot()
+	return &batch{d: d, languages: languages, depth: d.inputDepth(languages), options: d.languageOptions()}
+}
+
+// detect returns the DetectionResultSet of a text
+func (b *batch) detect(text string) DetectionResultSet {
+	d := b.d
+	prepared := d.prepare(text)
+	occ := occurenceMaps.Get().(map[string]int)
+	UpdateOccurenceMap(occ, prepared, b.depth)
+	lookupMap := CreateRankLookupMap(occ)
+	clear(occ)
+	occurenceMaps.Put(occ)
+
+	scorer := &candidateScorer{d: d, input: lookupMap, words: d.wordLookup(prepared), lookups: newDepthLookups(lookupMap), script: d.inputScript(lookupMap), options: b.options}
+	set := DetectionResultSet{Language: "undefined", Results: make([]DetectionResult, len(b.languages))}
+	for i, language := range b.languages {
+		set.Results[i] = scorer.score(language)
+	}
+	sortResults(set.Results)
+	groupResults(set.Results)
+	d.discriminate(prepared, set.Results)
+
+	name, confidence, reliable, ok := d.closestWithoutNGrams(prepared)
+	if !ok && len(set.Results) > 0 {
+		name, confidence, reliable = set.Results[0].Name, set.Results[0].Confidence/100, d.reliable(lookupMap, set.Results[0])
+		if reliable {
+			d.recent.see(name)
+		}
+	}
+	if d.ReportUnknown {
+		set.Results = withUnknown(set.Results, d.unknownResult(lookupMap, set.Results))
+	}
+	if d.VariantClassifiers != nil {
+		name = d.classifyVariant(text, name, confidence, reliable).Variant
+	}
+	if reliable || name == InsufficientInput {
+		set.Language = name
+	}
+	set.Confidence, set.Reliable = confidence, reliable
+	return set
+}
+
+// concurrently calls f with the indexes 0 to n-1 in workers goroutines, or GOMAXPROCS goroutines
+// if workers is not positive, and returns when all calls returned
+func concurrently(n, workers int, f func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,47 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDetectBatch(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Detect a batch of texts", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+		texts := []string{en, fr, en, "", fr, en}
+		expected := []string{"english", "french", "english", "undefined", "french", "english"}
+
+		languages := func(sets []langdet.DetectionResultSet) []string {
+			names := make([]string, len(sets))
+			for i, set := range sets {
+				names[i] = set.Language
+			}
+			return names
+		}
+
+		Convey("Results should keep the order of the texts", func() {
+			So(languages(d.DetectBatch(texts, 3)), ShouldResemble, expected)
+			So(d.DetectBatch(nil, 0), ShouldBeEmpty)
+		})
+		Convey("Result sets should match the detection of single texts", func() {
+			for i, set := range d.DetectBatch(texts, 2) {
+				name, confidence, reliable := d.GetClosestLanguageWithConfidence(texts[i])
+				So(set.Confidence, ShouldEqual, confidence)
+				So(set.Reliable, ShouldEqual, reliable)
+				if reliable {
+					So(set.Language, ShouldEqual, name)
+				}
+				So(set.Results, ShouldResemble, d.GetLanguages(texts[i]))
+			}
+		})
+		Convey("Deduplicated texts should be fanned out to all their positions", func() {
+			d.DedupBatch = true
+			So(languages(d.DetectBatch(texts, 0)), ShouldResemble, expected)
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package langdet
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// benchmarkTexts are the texts of different lengths and languages detected by Detector.Benchmark
+var benchmarkTexts = []string{
+	"do not care about quantity",
+	"ont permis d'identifier",
+	"Der schnelle braune Fuchs springt über den faulen Hund.",
+	"Съешь же ещё этих мягких французских булок, да выпей чаю.",
+	"The implementation is based on the paper N-Gram-Based Text Categorization. A language profile " +
+		"maps n-gram tokens to their occurrence rank, so for the most frequent token of the analyzed " +
+		"text the rank is one. Texts are compared to profiles by the out-of-place distance of the ranks.",
+	"Les profils de langue sont créés à partir d'articles de Wikipédia choisis au hasard. " +
+		"Le texte peut contenir des caractères spéciaux et des listes, mais la langue ne devrait " +
+		"pas changer sur de longues parties du texte.",
+}
+
+// BenchmarkResult reports the throughput and latency of a Detector with its currently loaded languages
+type BenchmarkResult struct {
+	Languages   int
+	Detections  int
+	Duration    time.Duration
+	Throughput  float64 // detections per second
+	MeanLatency time.Duration
+	P50Latency  time.Duration
+	P99Latency  time.Duration
+	MaxLatency  time.Duration
+}
+
+// String returns a human readable report of the benchmark
+func (r BenchmarkResult) String() string {
+	return fmt.Sprintf("%d languages, %d detections in %v: %.1f detections/s, latency mean %v, p50 %v, p99 %v, max %v",
+		r.Languages, r.Detections, r.Duration, r.Throughput, r.MeanLatency, r.P50Latency, r.P99Latency, r.MaxLatency)
+}
+
+// Benchmark runs a built-in micro-benchmark against the currently loaded languages for about
+// the given duration, but at least one detection of every built-in text, and reports the
+// throughput and latency of the detections. It can be used to compare machines or sets of profiles in place.
+func (d *Detector) Benchmark(duration time.Duration) BenchmarkResult {
+	latencies := []time.Duration{}
+	start := time.Now()
+	for i := 0; i < len(benchmarkTexts) || time.Since(start) < duration; i++ {
+		begin := time.Now()
+		// without the Cache and the Collector, which would measure cache hits and skew the stats
+		d.languagesPrepared(d.prepare(benchmarkTexts[i%len(benchmarkTexts)]))
+		latencies = append(latencies, time.Since(begin))
+	}
+	elapsed := time.Since(start)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	result := BenchmarkResult{
+		Languages:   len(d.snapshot()),
+		Detections:  len(latencies),
+		Duration:    elapsed,
+		Throughput:  float64(len(latencies)) / elapsed.Seconds(),
+		MeanLatency: total / time.Duration(len(latencies)),
+		P50Latency:  latencies[len(latencies)/2],
+		P99Latency:  latencies[len(latencies)*99/100],
+		MaxLatency:  latencies[len(latencies)-1],
+	}
+	return result
+}
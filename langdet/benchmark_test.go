@@ -0,0 +1,29 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+func TestBenchmark(t *testing.T) {
+	Convey("Subject: Benchmark the loaded languages", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("This is an english sentence", "en")
+		d.AddLanguageFromText("Je parles français et toi?", "fr")
+		d.Cache = langdet.NewResultCache(10)
+		result := d.Benchmark(10 * time.Millisecond)
+		Convey("Benchmark should report throughput and latencies", func() {
+			So(result.Languages, ShouldEqual, 2)
+			So(result.Detections, ShouldBeGreaterThan, 0)
+			So(result.Duration, ShouldBeGreaterThanOrEqualTo, 10*time.Millisecond)
+			So(result.Throughput, ShouldBeGreaterThan, 0)
+			So(result.P50Latency, ShouldBeLessThanOrEqualTo, result.MaxLatency)
+			So(result.String(), ShouldContainSubstring, "2 languages")
+		})
+		Convey("Benchmark should not measure cached results", func() {
+			So(d.Cache.Stats().Hits+d.Cache.Stats().Misses, ShouldEqual, 0)
+		})
+	})
+}
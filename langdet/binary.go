@@ -0,0 +1,141 @@
+package langdet
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"sort"
+)
+
+// binaryMagic starts every Language in the binary format of MarshalBinary. Since version 2, the
+// maps of the language are encoded as slices sorted by token, so that a language always has the
+// same bytes.
+var binaryMagic = []byte("LDP\x02")
+
+// binaryMagicV1 starts the languages of the first binary format, which encoded maps in random order
+var binaryMagicV1 = []byte("LDP\x01")
+
+// languageData has the fields of Language without its methods, so that gob doesn't call
+// MarshalBinary recursively
+type languageData Language
+
+// languageRecord is the binary encoding of a Language. Gob encodes integers independently of the
+// byte order and the word size of the machine, and the maps of the language are sorted slices,
+// so that the encoding is identical on every architecture.
+type languageRecord struct {
+	// Language has no Profile, Counts and WordProfile
+	Language    languageData
+	Profile     []tokenValue
+	Counts      []tokenValue
+	WordProfile []tokenValue
+}
+
+// tokenValue is an entry of a map[token]value of a languageRecord
+type tokenValue struct {
+	Token string
+	Value int
+}
+
+// newLanguageRecord returns the record of a language
+func newLanguageRecord(l Language) languageRecord {
+	record := languageRecord{Language: languageData(l), Profile: sortedTokens(l.Profile), Counts: sortedTokens(l.Counts), WordProfile: sortedTokens(l.WordProfile)}
+	record.Language.Profile = nil
+	record.Language.Counts = nil
+	record.Language.WordProfile = nil
+	return record
+}
+
+// language returns the language of a record
+func (r languageRecord) language() Language {
+	l := Language(r.Language)
+	l.Profile = tokenMap(r.Profile)
+	l.Counts = tokenMap(r.Counts)
+	l.WordProfile = tokenMap(r.WordProfile)
+	return l
+}
+
+// sortedTokens returns the entries of a map[token]value sorted by token, nil if it is empty
+func sortedTokens(values map[string]int) []tokenValue {
+	if len(values) == 0 {
+		return nil
+	}
+	tokens := make([]tokenValue, 0, len(values))
+	for token, value := range values {
+		tokens = append(tokens, tokenValue{Token: token, Value: value})
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Token < tokens[j].Token })
+	return tokens
+}
+
+// tokenMap returns the map[token]value of entries, nil if there are none
+func tokenMap(tokens []tokenValue) map[string]int {
+	if len(tokens) == 0 {
+		return nil
+	}
+	values := make(map[string]int, len(tokens))
+	for _, token := range tokens {
+		values[token.Token] = token.Value
+	}
+	return values
+}
+
+// isBinaryLanguage tells whether data starts like a Language in a binary format of any version
+func isBinaryLanguage(data []byte) bool {
+	return bytes.HasPrefix(data, binaryMagic) || bytes.HasPrefix(data, binaryMagicV1)
+}
+
+// MarshalBinary encodes the language in a compact binary format, that decodes much faster
+// than JSON. LoadLanguagesFromDir loads files of both formats. The encoding is reproducible: the
+// same language has the same bytes on every run and architecture, so that compiled profiles can
+// be verified by their checksums.
+func (l Language) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	err := gob.NewEncoder(&buf).Encode(newLanguageRecord(l))
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary decodes a language encoded by MarshalBinary, also of the first binary format
+func (l *Language) UnmarshalBinary(data []byte) error {
+	switch {
+	case bytes.HasPrefix(data, binaryMagic):
+		record := languageRecord{}
+		if err := gob.NewDecoder(bytes.NewReader(data[len(binaryMagic):])).Decode(&record); err != nil {
+			return err
+		}
+		*l = record.language()
+	case bytes.HasPrefix(data, binaryMagicV1):
+		decoded := languageData{}
+		if err := gob.NewDecoder(bytes.NewReader(data[len(binaryMagicV1):])).Decode(&decoded); err != nil {
+			return err
+		}
+		*l = Language(decoded)
+	default:
+		return errors.New("not a binary language profile")
+	}
+	return nil
+}
+
+// CanonicalEncoding returns the bytes that MarshalBinary writes for the language of a binary
+// profile, or that WriteBundleWordSets writes for the contents of a compiled bundle. Artifacts
+// that equal their canonical encoding are reproducible: every architecture builds them with the
+// same bytes, e.g. to compare their checksums. Other data is returned unchanged.
+func CanonicalEncoding(data []byte) ([]byte, error) {
+	switch {
+	case isBinaryLanguage(data):
+		language := Language{}
+		if err := language.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return language.MarshalBinary()
+	case bytes.HasPrefix(data, bundleMagic):
+		manifest, languages, wordSets, err := readBundle(bytes.NewReader(data), true)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		err = WriteBundleWordSets(&buf, manifest, languages, wordSets)
+		return buf.Bytes(), err
+	}
+	return data, nil
+}
@@ -0,0 +1,83 @@
+package langdet_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBinaryFormat(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Binary language profiles", t, func() {
+		english := langdet.Analyze(en, "english")
+		french := langdet.Analyze(fr, "french")
+
+		Convey("Languages should survive a round trip", func() {
+			data, err := english.MarshalBinary()
+			So(err, ShouldBeNil)
+			decoded := langdet.Language{}
+			So(decoded.UnmarshalBinary(data), ShouldBeNil)
+			So(decoded, ShouldResemble, english)
+		})
+		Convey("Languages should have the same bytes on every run and architecture", func() {
+			data, err := english.MarshalBinary()
+			So(err, ShouldBeNil)
+			for i := 0; i < 10; i++ {
+				again, _ := english.MarshalBinary()
+				So(bytes.Equal(again, data), ShouldBeTrue)
+			}
+			golden := langdet.Language{Name: "xx", Profile: map[string]int{"a": 1, "b": 2, "ab": 3}, Counts: map[string]int{"a": 7, "b": 5, "ab": 4}, Depth: 1, Size: 3}
+			data, err = golden.MarshalBinary()
+			So(err, ShouldBeNil)
+			So(fmt.Sprintf("%x", sha256.Sum256(data)), ShouldEqual, "dd7f3e5ff702765bd8287a57fc6bac1acc8367b5457fbc633d5232e2f5247259")
+		})
+		Convey("Languages of the first binary format should still load", func() {
+			type v1Language struct {
+				Name    string
+				Profile map[string]int
+			}
+			buf := bytes.NewBufferString("LDP\x01")
+			So(gob.NewEncoder(buf).Encode(v1Language{Name: "english", Profile: map[string]int{"_th": 1, "the": 2}}), ShouldBeNil)
+			decoded := langdet.Language{}
+			So(decoded.UnmarshalBinary(buf.Bytes()), ShouldBeNil)
+			So(decoded.Profile, ShouldResemble, map[string]int{"_th": 1, "the": 2})
+
+			Convey("but they should not be reproducible", func() {
+				canonical, err := langdet.CanonicalEncoding(buf.Bytes())
+				So(err, ShouldBeNil)
+				So(bytes.Equal(canonical, buf.Bytes()), ShouldBeFalse)
+				data, _ := english.MarshalBinary()
+				canonical, err = langdet.CanonicalEncoding(data)
+				So(err, ShouldBeNil)
+				So(bytes.Equal(canonical, data), ShouldBeTrue)
+			})
+		})
+		Convey("Data without the binary header should be rejected", func() {
+			data, _ := json.Marshal(english)
+			So((&langdet.Language{}).UnmarshalBinary(data), ShouldNotBeNil)
+		})
+		Convey("Directories may mix json and binary profiles", func() {
+			binary, _ := english.MarshalBinary()
+			text, _ := json.Marshal(french)
+			fsys := fstest.MapFS{
+				"profiles/english.bin": {Data: binary},
+				"profiles/french.json": {Data: text},
+				"profiles/broken.bin":  {Data: binary[:len(binary)/2]},
+			}
+			d := langdet.NewDetector()
+			So(d.LoadLanguagesFromFS(fsys, "profiles"), ShouldNotBeNil)
+			delete(fsys, "profiles/broken.bin")
+			So(d.LoadLanguagesFromFS(fsys, "profiles"), ShouldBeNil)
+			So(len(*d.Languages), ShouldEqual, 2)
+			So(d.GetClosestLanguage(en), ShouldEqual, "english")
+			So(d.GetClosestLanguage(fr), ShouldEqual, "french")
+		})
+	})
+}
@@ -0,0 +1,133 @@
+package langdet
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+)
+
+// DefaultFalsePositiveRate is the false positive rate of bloom filters when none is given
+const DefaultFalsePositiveRate = 0.01
+
+// BloomFilter is a compact WordSet of a large vocabulary, e.g. of the 100000 most common words of
+// a language in about 120 KB at a false positive rate of 1%. Contains never misses an added word,
+// but also reports other words with the false positive rate the filter was sized for. Words are
+// added lowercased. Contains is safe for concurrent use, Add is not.
+type BloomFilter struct {
+	bits   []uint64
+	hashes int
+	words  int
+}
+
+// NewBloomFilter returns an empty BloomFilter sized for the given number of words and false
+// positive rate between 0 and 1, DefaultFalsePositiveRate if it is out of range
+func NewBloomFilter(words int, falsePositiveRate float64) *BloomFilter {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = DefaultFalsePositiveRate
+	}
+	if words < 1 {
+		words = 1
+	}
+	// the optimal number of bits and hash functions of a bloom filter
+	bits := math.Ceil(-float64(words) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	hashes := int(math.Round(bits / float64(words) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	return &BloomFilter{bits: make([]uint64, (int(bits)+63)/64), hashes: hashes}
+}
+
+// NewBloomFilterOf returns a BloomFilter of the words with the given false positive rate
+func NewBloomFilterOf(falsePositiveRate float64, words ...string) *BloomFilter {
+	filter := NewBloomFilter(len(words), falsePositiveRate)
+	for _, word := range words {
+		filter.Add(word)
+	}
+	return filter
+}
+
+// Add adds the lowercased word to the filter
+func (f *BloomFilter) Add(word string) {
+	h1, h2 := bloomHashes(strings.ToLower(word))
+	size := uint64(len(f.bits)) * 64
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % size
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+	f.words++
+}
+
+// Contains tells whether the word was probably added to the filter
+func (f *BloomFilter) Contains(word string) bool {
+	if len(f.bits) == 0 {
+		return false
+	}
+	h1, h2 := bloomHashes(word)
+	size := uint64(len(f.bits)) * 64
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % size
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of words added to the filter
+func (f *BloomFilter) Len() int {
+	return f.words
+}
+
+// Size returns the size of the filter in bytes
+func (f *BloomFilter) Size() int {
+	return len(f.bits) * 8
+}
+
+// FalsePositiveRate returns the expected false positive rate of the filter with its words
+func (f *BloomFilter) FalsePositiveRate() float64 {
+	size := float64(len(f.bits) * 64)
+	if size == 0 {
+		return 1
+	}
+	return math.Pow(1-math.Exp(-float64(f.hashes*f.words)/size), float64(f.hashes))
+}
+
+// bloomHashes returns the two hashes of a word that the hash functions of a BloomFilter are
+// derived from, the halves of its 64 bit FNV-1a hash, the second one odd
+func bloomHashes(word string) (uint64, uint64) {
+	hash := uint64(14695981039346656037)
+	for i := 0; i < len(word); i++ {
+		hash ^= uint64(word[i])
+		hash *= 1099511628211
+	}
+	return hash & 0xffffffff, hash>>32 | 1
+}
+
+// MarshalBinary encodes the filter for compiled bundles
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 16+len(f.bits)*8)
+	data = binary.LittleEndian.AppendUint64(data, uint64(f.hashes))
+	data = binary.LittleEndian.AppendUint64(data, uint64(f.words))
+	for _, word := range f.bits {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a filter encoded by MarshalBinary
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 || len(data)%8 != 0 {
+		return errors.New("invalid bloom filter")
+	}
+	f.hashes = int(binary.LittleEndian.Uint64(data))
+	f.words = int(binary.LittleEndian.Uint64(data[8:]))
+	f.bits = make([]uint64, (len(data)-16)/8)
+	for i := range f.bits {
+		f.bits[i] = binary.LittleEndian.Uint64(data[16+8*i:])
+	}
+	if f.hashes < 1 || len(f.bits) == 0 {
+		return errors.New("invalid bloom filter")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package langdet_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBloomFilter(t *testing.T) {
+	Convey("Subject: Bloom filters of large vocabularies", t, func() {
+		words := make([]string, 100000)
+		for i := range words {
+			words[i] = fmt.Sprintf("word%d", i)
+		}
+		filter := langdet.NewBloomFilterOf(0.01, words...)
+
+		Convey("Added words should never be missed", func() {
+			for _, word := range words {
+				if !filter.Contains(word) {
+					So(word, ShouldBeEmpty)
+				}
+			}
+			So(filter.Len(), ShouldEqual, len(words))
+			So(langdet.NewBloomFilterOf(0.01, "Danke").Contains("danke"), ShouldBeTrue)
+		})
+		Convey("Other words should be false positives at about the requested rate", func() {
+			positives := 0
+			for i := 0; i < 100000; i++ {
+				if filter.Contains(fmt.Sprintf("other%d", i)) {
+					positives++
+				}
+			}
+			So(float64(positives)/100000, ShouldBeLessThan, 0.015)
+			So(filter.FalsePositiveRate(), ShouldAlmostEqual, 0.01, 0.001)
+			So(filter.Size(), ShouldBeBetween, 110000, 130000)
+		})
+		Convey("Filters should survive their binary encoding", func() {
+			data, err := filter.MarshalBinary()
+			So(err, ShouldBeNil)
+			decoded := &langdet.BloomFilter{}
+			So(decoded.UnmarshalBinary(data), ShouldBeNil)
+			So(decoded, ShouldResemble, filter)
+			So(decoded.UnmarshalBinary(data[:10]), ShouldNotBeNil)
+		})
+		Convey("Empty filters should contain nothing", func() {
+			So((&langdet.BloomFilter{}).Contains("word"), ShouldBeFalse)
+			So(langdet.NewBloomFilter(0, 0).Contains("word"), ShouldBeFalse)
+		})
+	})
+}
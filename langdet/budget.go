@@ -0,0 +1,45 @@
+package langdet
+
+import "time"
+
+// BudgetResult is the result of Detector.DetectWithBudget
+type BudgetResult struct {
+	DetectionResult
+	// Scored is the number of languages that were scored within the budget
+	Scored int
+	// Truncated is true if not all languages were scored within the budget
+	Truncated bool
+	// Reliable tells whether the result is confident enough, like the one of
+	// GetClosestLanguageWithConfidence, including the check against the Background model
+	Reliable bool
+}
+
+// DetectWithBudget returns the best DetectionResult found within maxDuration. The languages are
+// scored in the order of the cheap pre-filter of Candidates, so that the languages of the script of
+// the text that share the most frequent n-grams with it, weighed by their priors, are scored first,
+// and the most recently detected ones of them before others. At least one language is scored. The result is flagged as Truncated if not all languages could be scored within the budget.
+func (d *Detector) DetectWithBudget(text string, maxDuration time.Duration) BudgetResult {
+	deadline := time.Now().Add(maxDuration)
+	it := d.Candidates(text)
+	best := BudgetResult{DetectionResult: DetectionResult{Name: "undefined"}}
+	found := false
+	for !found || time.Now().Before(deadline) {
+		result, ok := it.Next()
+		if !ok {
+			break
+		}
+		if !found || result.Confidence > best.Confidence {
+			best.DetectionResult = result
+		}
+		found = true
+	}
+	best.Scored = it.Scored()
+	best.Truncated = len(it.pending) > 0
+	if found {
+		best.Reliable = d.reliable(it.scorer.input, best.DetectionResult)
+	}
+	if best.Reliable {
+		d.recent.see(best.Name)
+	}
+	return best
+}
@@ -0,0 +1,71 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+func TestDetectWithBudget(t *testing.T) {
+	Convey("Subject: Detect within a time budget", t, func() {
+		s := "Hello I am english text, what is your language? I really dont know you say?"
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Je parles français et toi?", "french")
+		d.AddLanguageFromText("Das ist ein deutscher Satz, ich weiss nicht was du sagst", "german")
+		d.AddLanguageFromText("Bu bir Türkçe cümle, ne dediğini bilmiyorum", "turkish")
+		d.AddLanguageFromText(s, "english")
+		d.AddLanguageFromText("Esta es una frase en español, no sé lo que dices", "spanish")
+		Convey("With enough time all languages should be scored", func() {
+			res := d.DetectWithBudget(s, time.Minute)
+			So(res.Name, ShouldEqual, "english")
+			So(res.Scored, ShouldEqual, 5)
+			So(res.Truncated, ShouldBeFalse)
+		})
+		Convey("Without time the most promising languages should be scored", func() {
+			res := d.DetectWithBudget(s, 0)
+			So(res.Name, ShouldEqual, "english")
+			So(res.Scored, ShouldBeLessThan, 5)
+			So(res.Truncated, ShouldBeTrue)
+		})
+		Convey("Results should be reliable like full detections", func() {
+			d.MinMatchedTokens = 1000
+			So(d.DetectWithBudget(s, time.Minute).Reliable, ShouldBeFalse)
+			d.MinMatchedTokens = 0
+			So(d.DetectWithBudget(s, time.Minute).Reliable, ShouldBeTrue)
+			background := langdet.Analyze(s, langdet.BackgroundLanguage)
+			d.Background = &background
+			So(d.DetectWithBudget(s, time.Minute).Reliable, ShouldBeFalse)
+		})
+		Convey("Languages of other scripts should be scored last", func() {
+			d.AddLanguageFromText("Это русское предложение, я не знаю, что ты говоришь", "russian")
+			it := d.Candidates(s)
+			for i := 0; i < 5; i++ {
+				result, _ := it.Next()
+				So(result.Name, ShouldNotEqual, "russian")
+			}
+		})
+		Convey("Recently detected languages should be scored first among equal candidates", func() {
+			_, _, reliable := d.GetClosestLanguageWithConfidence("Esta es una frase en español, no sé lo que dices")
+			So(reliable, ShouldBeTrue)
+			it := d.Candidates("ðþ")
+			it.Lookahead = 0
+			first, _ := it.Next()
+			So(first.Name, ShouldEqual, "spanish")
+		})
+		Convey("Priors should move languages forward", func() {
+			text := "Esta es una frase en español, no sé lo que dices"
+			d.SetLanguageOptions("german", langdet.LanguageOptions{Prior: 100})
+			it := d.Candidates(text)
+			it.Lookahead = 0
+			first, _ := it.Next()
+			So(first.Name, ShouldEqual, "german")
+		})
+		Convey("Without languages the result should be undefined", func() {
+			empty := langdet.NewDetector()
+			res := empty.DetectWithBudget(s, time.Minute)
+			So(res.Name, ShouldEqual, "undefined")
+			So(res.Truncated, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,312 @@
+package langdet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// BundleVersion is the version of the compiled bundles written by WriteBundle. ReadBundle
+// rejects bundles of newer versions. Version 2 added the BundleManifest, version 3 the word sets,
+// version 4 made the encoding reproducible, version 5 added the script routes.
+const BundleVersion = 5
+
+// bundleMagic starts every compiled bundle
+var bundleMagic = []byte("LDB\x01")
+
+// bundleData is the first gob encoded value of a compiled bundle. Since version 2, it is followed
+// by the languages as a second value, so that the manifest can be read without the profiles, and
+// since version 3 by the bloom filters of the word sets by language name. Since version 4, the
+// languages are languageRecords and the word sets bundleWordSets sorted by name, so that the same
+// bundle has the same bytes on every architecture.
+type bundleData struct {
+	Version  int
+	Manifest BundleManifest
+	// Languages are the languages of version 1 bundles
+	Languages []languageData
+}
+
+// bundleWordSet is the bloom filter of the words of a language of a compiled bundle
+type bundleWordSet struct {
+	Name   string
+	Filter *BloomFilter
+}
+
+// BundleManifest describes a compiled bundle and its profiles, so that bundles can be inspected
+// without loading their profiles, see ReadBundleManifest
+type BundleManifest struct {
+	// Created is the time the bundle was created, zero if unknown
+	Created time.Time
+	// Description describes the bundle, e.g. its corpora
+	Description string
+	// Profiles describe the profiles of the bundle in their order
+	Profiles []BundleProfile
+	// WordSets describe the word sets of the bundle, ordered by their language
+	WordSets []BundleWordSet
+	// Scripts route the scripts of texts to the languages of the bundle written in them, ordered by
+	// script, for the script filter of detectors, see NewBundleScripts
+	Scripts []BundleScript
+}
+
+// BundleScript is the route of a unicode script to the languages written in it
+type BundleScript struct {
+	Script string
+	// Languages are the names of the languages written in the script, in the order of the profiles
+	Languages []string
+}
+
+// BundleWordSet describes the bloom filter of the words of a language of a compiled bundle
+type BundleWordSet struct {
+	Name string
+	// Words is the number of words of the filter
+	Words int
+	// Size is the size of the filter in bytes
+	Size int
+	// FalsePositiveRate is the expected false positive rate of the filter
+	FalsePositiveRate float64
+}
+
+// BundleProfile describes a profile of a compiled bundle
+type BundleProfile struct {
+	Name  string
+	Depth int
+	// Ranks is the number of ranked n-grams of the profile
+	Ranks int
+	// CorpusSize is the number of n-grams the profile was trained from, 0 if it is unknown
+	CorpusSize int
+	// Trained is the time the profile was trained, zero if it is unknown
+	Trained time.Time
+}
+
+// NewBundleManifest returns the manifest of a bundle of languages, with the depth, the number of
+// ranks, the corpus size and the training time of every profile that has them
+func NewBundleManifest(languages []Language) BundleManifest {
+	manifest := BundleManifest{Profiles: make([]BundleProfile, len(languages))}
+	for i, language := range languages {
+		corpusSize := language.TokenCount
+		if corpusSize == 0 {
+			for _, count := range language.Counts {
+				corpusSize += count
+			}
+		}
+		manifest.Profiles[i] = BundleProfile{Name: language.Name, Depth: language.Depth, Ranks: len(language.Profile), CorpusSize: corpusSize, Trained: language.CreatedAt}
+	}
+	return manifest
+}
+
+// WriteBundle writes languages to w as a compiled bundle, a single versioned binary file of
+// many profiles that loads much faster than their JSON files, with their NewBundleManifest
+func WriteBundle(w io.Writer, languages []Language) error {
+	return WriteBundleManifest(w, NewBundleManifest(languages), languages)
+}
+
+// WriteBundleManifest writes languages to w as a compiled bundle with the given manifest, which
+// should describe the languages, e.g. a NewBundleManifest with the training times
+func WriteBundleManifest(w io.Writer, manifest BundleManifest, languages []Language) error {
+	return WriteBundleWordSets(w, manifest, languages, nil)
+}
+
+// NewBundleWordSets returns the descriptions of the bloom filters of word sets by language name
+// for a BundleManifest
+func NewBundleWordSets(wordSets map[string]*BloomFilter) []BundleWordSet {
+	var sets []BundleWordSet
+	for name, filter := range wordSets {
+		sets = append(sets, BundleWordSet{Name: name, Words: filter.Len(), Size: filter.Size(), FalsePositiveRate: filter.FalsePositiveRate()})
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Name < sets[j].Name })
+	return sets
+}
+
+// NewBundleScripts returns the routes of the scripts of languages, as in TextScripts, to the
+// languages written in them for a BundleManifest
+func NewBundleScripts(languages []Language) []BundleScript {
+	routes := map[string][]string{}
+	for _, language := range languages {
+		for _, script := range language.TextScripts() {
+			routes[script] = append(routes[script], language.Name)
+		}
+	}
+	scripts := make([]BundleScript, 0, len(routes))
+	for script, names := range routes {
+		scripts = append(scripts, BundleScript{Script: script, Languages: names})
+	}
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].Script < scripts[j].Script })
+	return scripts
+}
+
+// applyScripts sets the Scripts of the languages without Scripts to the scripts that route to
+// them, so that the script filter doesn't infer them from the profiles when texts are detected.
+// The scripts of a language are then ordered by name instead of by frequency.
+func applyScripts(languages []Language, scripts []BundleScript) {
+	routed := map[string][]string{}
+	for _, route := range scripts {
+		for _, name := range route.Languages {
+			routed[name] = append(routed[name], route.Script)
+		}
+	}
+	for i := range languages {
+		if languages[i].Scripts == nil {
+			if scripts, ok := routed[languages[i].Name]; ok {
+				languages[i].Scripts = scripts
+			}
+		}
+	}
+}
+
+// WriteBundleWordSets writes languages and the bloom filters of word sets by language name to w
+// as a compiled bundle with the given manifest, whose WordSets are set to the NewBundleWordSets
+// of the filters and whose Scripts are set to the NewBundleScripts of the languages. LoadBundle
+// sets the word sets of the detector, see SetWordSet. The same arguments always write the same
+// bytes.
+func WriteBundleWordSets(w io.Writer, manifest BundleManifest, languages []Language, wordSets map[string]*BloomFilter) error {
+	manifest.WordSets = NewBundleWordSets(wordSets)
+	manifest.Scripts = NewBundleScripts(languages)
+	data := make([]languageRecord, len(languages))
+	for i, language := range languages {
+		data[i] = newLanguageRecord(language)
+	}
+	sets := make([]bundleWordSet, len(manifest.WordSets))
+	for i, set := range manifest.WordSets {
+		sets[i] = bundleWordSet{Name: set.Name, Filter: wordSets[set.Name]}
+	}
+	if _, err := w.Write(bundleMagic); err != nil {
+		return err
+	}
+	encoder := gob.NewEncoder(w)
+	if err := encoder.Encode(bundleData{Version: BundleVersion, Manifest: manifest}); err != nil {
+		return err
+	}
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+	return encoder.Encode(sets)
+}
+
+// ReadBundle reads the languages of a compiled bundle written by WriteBundle
+func ReadBundle(r io.Reader) ([]Language, error) {
+	_, languages, _, err := readBundle(r, true)
+	return languages, err
+}
+
+// ReadBundleWordSets reads the languages and the bloom filters of the word sets by language name
+// of a compiled bundle, which has no word sets before version 3
+func ReadBundleWordSets(r io.Reader) ([]Language, map[string]*BloomFilter, error) {
+	_, languages, wordSets, err := readBundle(r, true)
+	return languages, wordSets, err
+}
+
+// ReadBundleManifest reads the manifest of a compiled bundle without its profiles. The manifests
+// of version 1 bundles are built from their profiles.
+func ReadBundleManifest(r io.Reader) (BundleManifest, error) {
+	manifest, _, _, err := readBundle(r, false)
+	return manifest, err
+}
+
+// readBundle reads the manifest and, with profiles, the languages and word sets of a compiled bundle
+func readBundle(r io.Reader, profiles bool) (BundleManifest, []Language, map[string]*BloomFilter, error) {
+	reader := bufio.NewReader(r)
+	if magic, _ := reader.Peek(len(bundleMagic)); !bytes.Equal(magic, bundleMagic) {
+		return BundleManifest{}, nil, nil, errors.New("not a compiled bundle")
+	}
+	reader.Discard(len(bundleMagic))
+	decoder := gob.NewDecoder(reader)
+	data := bundleData{}
+	if err := decoder.Decode(&data); err != nil {
+		return BundleManifest{}, nil, nil, fmt.Errorf("could not decode bundle: %w", err)
+	}
+	if data.Version > BundleVersion {
+		return BundleManifest{}, nil, nil, fmt.Errorf("bundle version %d is newer than the supported version %d", data.Version, BundleVersion)
+	}
+	languages := []Language{}
+	for _, language := range data.Languages {
+		languages = append(languages, Language(language))
+	}
+	wordSets := map[string]*BloomFilter{}
+	if data.Version >= 2 && profiles {
+		var err error
+		if languages, wordSets, err = decodeBundleProfiles(decoder, data.Version); err != nil {
+			return BundleManifest{}, nil, nil, err
+		}
+	}
+	for i := range languages {
+		if err := languages[i].upgrade(); err != nil {
+			return BundleManifest{}, nil, nil, err
+		}
+	}
+	if data.Version < 2 {
+		data.Manifest = NewBundleManifest(languages)
+	}
+	if data.Version < 5 && profiles {
+		data.Manifest.Scripts = NewBundleScripts(languages)
+	}
+	applyScripts(languages, data.Manifest.Scripts)
+	return data.Manifest, languages, wordSets, nil
+}
+
+// decodeBundleProfiles decodes the languages and word sets that follow the bundleData of a bundle
+// of a version since 2
+func decodeBundleProfiles(decoder *gob.Decoder, version int) ([]Language, map[string]*BloomFilter, error) {
+	languages := []Language{}
+	wordSets := map[string]*BloomFilter{}
+	if version < 4 {
+		data := []languageData{}
+		if err := decoder.Decode(&data); err != nil {
+			return nil, nil, fmt.Errorf("could not decode bundle profiles: %w", err)
+		}
+		for _, language := range data {
+			languages = append(languages, Language(language))
+		}
+		if version == 3 {
+			if err := decoder.Decode(&wordSets); err != nil {
+				return nil, nil, fmt.Errorf("could not decode bundle word sets: %w", err)
+			}
+		}
+		return languages, wordSets, nil
+	}
+	records := []languageRecord{}
+	if err := decoder.Decode(&records); err != nil {
+		return nil, nil, fmt.Errorf("could not decode bundle profiles: %w", err)
+	}
+	for _, record := range records {
+		languages = append(languages, record.language())
+	}
+	sets := []bundleWordSet{}
+	if err := decoder.Decode(&sets); err != nil {
+		return nil, nil, fmt.Errorf("could not decode bundle word sets: %w", err)
+	}
+	for _, set := range sets {
+		wordSets[set.Name] = set.Filter
+	}
+	return languages, wordSets, nil
+}
+
+// LoadBundle replaces the languages of this Detector with the languages of a compiled bundle file,
+// and sets the word sets of the bundle
+func (d *Detector) LoadBundle(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open bundle: %w", err)
+	}
+	defer f.Close()
+	return d.LoadBundleFromReader(f)
+}
+
+// LoadBundleFromReader replaces the languages of this Detector with the languages of a compiled
+// bundle, and sets the word sets of the bundle
+func (d *Detector) LoadBundleFromReader(r io.Reader) error {
+	languages, wordSets, err := ReadBundleWordSets(r)
+	if err != nil {
+		return err
+	}
+	d.update(func([]Language) []Language { return languages })
+	for name, filter := range wordSets {
+		d.SetWordSet(name, filter)
+	}
+	return nil
+}
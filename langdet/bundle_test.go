@@ -0,0 +1,150 @@
+package langdet_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// gobBundle returns a compiled bundle of a gob encoded value
+func gobBundle(value interface{}) []byte {
+	buf := bytes.NewBufferString("LDB\x01")
+	So(gob.NewEncoder(buf).Encode(value), ShouldBeNil)
+	return buf.Bytes()
+}
+
+func TestBundle(t *testing.T) {
+	Convey("Subject: Compiled bundles with a manifest", t, func() {
+		trainer := &langdet.Trainer{KeepCounts: true}
+		trainer.Feed("the quick brown fox jumps over the lazy dog")
+		english := trainer.Build("english")
+		french := langdet.Analyze("le renard brun saute par-dessus le chien", "french")
+		languages := []langdet.Language{english, french}
+
+		Convey("Manifests should describe the profiles", func() {
+			manifest := langdet.NewBundleManifest(languages)
+			So(manifest.Profiles, ShouldHaveLength, 2)
+			So(manifest.Profiles[0].Name, ShouldEqual, "english")
+			So(manifest.Profiles[0].Depth, ShouldEqual, english.Depth)
+			So(manifest.Profiles[0].Ranks, ShouldEqual, len(english.Profile))
+			So(manifest.Profiles[0].CorpusSize, ShouldEqual, english.TokenCount)
+			So(manifest.Profiles[0].Trained, ShouldEqual, english.CreatedAt)
+			So(manifest.Profiles[1].CorpusSize, ShouldEqual, french.TokenCount)
+			So(manifest.Profiles[1].Trained.IsZero(), ShouldBeTrue)
+
+			french.TokenCount = 0
+			So(langdet.NewBundleManifest([]langdet.Language{french}).Profiles[0].CorpusSize, ShouldEqual, 0)
+		})
+		Convey("Manifests should be read without the profiles", func() {
+			manifest := langdet.NewBundleManifest(languages)
+			manifest.Created = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+			manifest.Description = "test corpora"
+			var buf bytes.Buffer
+			So(langdet.WriteBundleManifest(&buf, manifest, languages), ShouldBeNil)
+			read, err := langdet.ReadBundleManifest(bytes.NewReader(buf.Bytes()))
+			So(err, ShouldBeNil)
+			So(read.Created.Equal(manifest.Created), ShouldBeTrue)
+			So(read.Description, ShouldEqual, "test corpora")
+			So(read.Profiles, ShouldResemble, manifest.Profiles)
+
+			Convey("and bundles should have word sets", func() {
+				var withWords bytes.Buffer
+				wordSets := map[string]*langdet.BloomFilter{"french": langdet.NewBloomFilterOf(0.01, "bonjour")}
+				So(langdet.WriteBundleWordSets(&withWords, manifest, languages, wordSets), ShouldBeNil)
+				read, err := langdet.ReadBundleManifest(bytes.NewReader(withWords.Bytes()))
+				So(err, ShouldBeNil)
+				So(read.WordSets, ShouldHaveLength, 1)
+				So(read.WordSets[0].Name, ShouldEqual, "french")
+				So(read.WordSets[0].Words, ShouldEqual, 1)
+				_, sets, err := langdet.ReadBundleWordSets(bytes.NewReader(withWords.Bytes()))
+				So(err, ShouldBeNil)
+				So(sets["french"].Contains("bonjour"), ShouldBeTrue)
+
+				d := langdet.NewDetector()
+				So(d.LoadBundleFromReader(bytes.NewReader(withWords.Bytes())), ShouldBeNil)
+				name, confidence, _ := d.GetClosestLanguageWithConfidence("Bonjour")
+				So(name, ShouldEqual, "french")
+				So(confidence, ShouldEqual, 1)
+			})
+			Convey("and bundles should route scripts to their languages", func() {
+				russian := langdet.Analyze("быстрая коричневая лиса прыгает через ленивую собаку", "russian")
+				russian.Scripts = nil
+				var withRussian bytes.Buffer
+				So(langdet.WriteBundleManifest(&withRussian, manifest, append(languages, russian)), ShouldBeNil)
+				read, err := langdet.ReadBundleManifest(bytes.NewReader(withRussian.Bytes()))
+				So(err, ShouldBeNil)
+				So(read.Scripts, ShouldResemble, []langdet.BundleScript{
+					{Script: "Cyrillic", Languages: []string{"russian"}},
+					{Script: "Latin", Languages: []string{"english", "french"}},
+				})
+				bundled, err := langdet.ReadBundle(bytes.NewReader(withRussian.Bytes()))
+				So(err, ShouldBeNil)
+				So(bundled[2].Scripts, ShouldResemble, []string{"Cyrillic"})
+			})
+			Convey("and the bundle should load from a file", func() {
+				name := filepath.Join(t.TempDir(), "bundle.bin")
+				So(os.WriteFile(name, buf.Bytes(), 0644), ShouldBeNil)
+				d := langdet.NewDetector()
+				So(d.LoadBundle(name), ShouldBeNil)
+				So(d.Snapshot(), ShouldHaveLength, 2)
+				So(d.GetClosestLanguage("the lazy dog jumps over the fox"), ShouldEqual, "english")
+				So(d.LoadBundle(name+".missing"), ShouldNotBeNil)
+			})
+		})
+		Convey("Version 1 bundles should still load, with a manifest of their profiles", func() {
+			type v1Language struct {
+				Name    string
+				Profile map[string]int
+			}
+			data := gobBundle(struct {
+				Version   int
+				Languages []v1Language
+			}{1, []v1Language{{Name: "english", Profile: map[string]int{"_th": 1, "the": 2}}}})
+			read, err := langdet.ReadBundle(bytes.NewReader(data))
+			So(err, ShouldBeNil)
+			So(read, ShouldHaveLength, 1)
+			So(read[0].Profile, ShouldResemble, map[string]int{"_th": 1, "the": 2})
+			manifest, err := langdet.ReadBundleManifest(bytes.NewReader(data))
+			So(err, ShouldBeNil)
+			So(manifest.Profiles, ShouldHaveLength, 1)
+			So(manifest.Profiles[0].Name, ShouldEqual, "english")
+		})
+		Convey("Version 3 bundles should still load with their word sets", func() {
+			type v3Language struct {
+				Name    string
+				Profile map[string]int
+			}
+			buf := bytes.NewBufferString("LDB\x01")
+			encoder := gob.NewEncoder(buf)
+			So(encoder.Encode(struct{ Version int }{3}), ShouldBeNil)
+			So(encoder.Encode([]v3Language{{Name: "english", Profile: map[string]int{"_th": 1, "the": 2}}}), ShouldBeNil)
+			So(encoder.Encode(map[string]*langdet.BloomFilter{"english": langdet.NewBloomFilterOf(0.01, "hello")}), ShouldBeNil)
+			read, sets, err := langdet.ReadBundleWordSets(bytes.NewReader(buf.Bytes()))
+			So(err, ShouldBeNil)
+			So(read, ShouldHaveLength, 1)
+			So(read[0].Profile, ShouldResemble, map[string]int{"_th": 1, "the": 2})
+			So(sets["english"].Contains("hello"), ShouldBeTrue)
+		})
+		Convey("Bundles should have the same bytes every time they are written", func() {
+			manifest := langdet.NewBundleManifest(languages)
+			wordSets := map[string]*langdet.BloomFilter{"french": langdet.NewBloomFilterOf(0.01, "bonjour"), "english": langdet.NewBloomFilterOf(0.01, "hello")}
+			var first bytes.Buffer
+			So(langdet.WriteBundleWordSets(&first, manifest, languages, wordSets), ShouldBeNil)
+			for i := 0; i < 10; i++ {
+				var again bytes.Buffer
+				So(langdet.WriteBundleWordSets(&again, manifest, languages, wordSets), ShouldBeNil)
+				So(bytes.Equal(again.Bytes(), first.Bytes()), ShouldBeTrue)
+			}
+		})
+		Convey("Newer bundles should be rejected", func() {
+			_, err := langdet.ReadBundle(bytes.NewReader(gobBundle(struct{ Version int }{langdet.BundleVersion + 1})))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
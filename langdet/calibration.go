@@ -0,0 +1,212 @@
+package langdet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultCalibrationLengths are the maximum text lengths in runes that TrainCalibration learns
+// separate curves for, texts longer than the last one share one more curve
+var DefaultCalibrationLengths = []int{20, 50, 200, 1000}
+
+// calibrationBins is the maximum number of points of a calibration curve
+const calibrationBins = 10
+
+// minBinSamples is the minimum number of samples of a point of a calibration curve
+const minBinSamples = 20
+
+// CalibrationSample is a detection of a labeled text that a Calibration is trained from
+type CalibrationSample struct {
+	// Length is the length of the text in runes
+	Length int
+	// Confidence is the raw confidence of the closest language, between 0 and 1
+	Confidence float64
+	// Correct tells whether the closest language is the expected language of the text
+	Correct bool
+}
+
+// CalibrationPoint maps a raw confidence to the probability that the detection is correct
+type CalibrationPoint struct {
+	Confidence  float64 `json:"confidence"`
+	Probability float64 `json:"probability"`
+}
+
+// CalibrationCurve maps the raw confidences of the texts of up to a length to probabilities
+type CalibrationCurve struct {
+	// MaxLength is the maximum length in runes of the texts of the curve, 0 for any length
+	MaxLength int `json:"maxLength"`
+	// Samples is the number of samples the curve was trained from
+	Samples int `json:"samples"`
+	// Points are ordered by their confidences, their probabilities never decrease
+	Points []CalibrationPoint `json:"points"`
+}
+
+// Calibration maps the raw confidences of detections to calibrated probabilities that they are
+// correct. Raw confidences are rank distances, which mean different things for languages and text
+// lengths: a confidence of 0.75 is a guess for a few words, but certain for a document. A
+// Calibration has a curve per text length, learned from a labeled dev set by TrainCalibration.
+type Calibration struct {
+	// Curves are ordered by their MaxLength, the curve of any length last
+	Curves []CalibrationCurve `json:"curves"`
+}
+
+// TrainCalibration learns the curves of the DefaultCalibrationLengths from the samples of a
+// labeled dev set. The samples of every length are split into bins of similar confidences, whose
+// accuracies are the probabilities of their mean confidences, made monotonic by pooling adjacent
+// bins. Lengths without samples get no curve.
+func TrainCalibration(samples []CalibrationSample) Calibration {
+	calibration := Calibration{}
+	lengths := append(append([]int{}, DefaultCalibrationLengths...), 0)
+	for i, maxLength := range lengths {
+		minLength := 0
+		if i > 0 {
+			minLength = lengths[i-1] + 1
+		}
+		curve := CalibrationCurve{MaxLength: maxLength}
+		bucket := []CalibrationSample{}
+		for _, sample := range samples {
+			if sample.Length >= minLength && (maxLength == 0 || sample.Length <= maxLength) {
+				bucket = append(bucket, sample)
+			}
+		}
+		if len(bucket) == 0 {
+			continue
+		}
+		curve.Samples = len(bucket)
+		curve.Points = calibrationPoints(bucket)
+		calibration.Curves = append(calibration.Curves, curve)
+	}
+	return calibration
+}
+
+// calibrationPoints returns the points of a curve of samples, with isotonic probabilities
+func calibrationPoints(samples []CalibrationSample) []CalibrationPoint {
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Confidence < samples[j].Confidence })
+	bins := len(samples) / minBinSamples
+	if bins > calibrationBins {
+		bins = calibrationBins
+	}
+	if bins < 1 {
+		bins = 1
+	}
+	// pooled bins of the confidence and correct sums and their numbers of samples
+	type bin struct {
+		confidence, correct float64
+		samples             int
+	}
+	pooled := []bin{}
+	for b := 0; b < bins; b++ {
+		current := bin{}
+		for _, sample := range samples[b*len(samples)/bins : (b+1)*len(samples)/bins] {
+			current.confidence += sample.Confidence
+			if sample.Correct {
+				current.correct++
+			}
+			current.samples++
+		}
+		// pool adjacent violators: merge with the previous bins while they are more accurate
+		for len(pooled) > 0 {
+			last := pooled[len(pooled)-1]
+			if last.correct/float64(last.samples) < current.correct/float64(current.samples) {
+				break
+			}
+			current = bin{confidence: last.confidence + current.confidence, correct: last.correct + current.correct, samples: last.samples + current.samples}
+			pooled = pooled[:len(pooled)-1]
+		}
+		pooled = append(pooled, current)
+	}
+	points := make([]CalibrationPoint, len(pooled))
+	for i, b := range pooled {
+		points[i] = CalibrationPoint{Confidence: b.confidence / float64(b.samples), Probability: b.correct / float64(b.samples)}
+	}
+	return points
+}
+
+// Probability returns the calibrated probability of a raw confidence of a text of a length in
+// runes, interpolated between the points of the curve of the length or, without one, of the next
+// longer length. Confidences below the first point are scaled down to 0, those above the last
+// point get its probability. Without curves, the confidence is returned.
+func (c Calibration) Probability(confidence float64, length int) float64 {
+	if len(c.Curves) == 0 {
+		return confidence
+	}
+	curve := c.Curves[len(c.Curves)-1]
+	for _, candidate := range c.Curves {
+		if candidate.MaxLength == 0 || length <= candidate.MaxLength {
+			curve = candidate
+			break
+		}
+	}
+	points := curve.Points
+	if len(points) == 0 {
+		return confidence
+	}
+	if confidence <= points[0].Confidence {
+		if points[0].Confidence <= 0 {
+			return points[0].Probability
+		}
+		return points[0].Probability * confidence / points[0].Confidence
+	}
+	for i := 1; i < len(points); i++ {
+		if confidence <= points[i].Confidence {
+			previous := points[i-1]
+			share := (confidence - previous.Confidence) / (points[i].Confidence - previous.Confidence)
+			return previous.Probability + share*(points[i].Probability-previous.Probability)
+		}
+	}
+	return points[len(points)-1].Probability
+}
+
+// ReadCalibration decodes a JSON Calibration, like the files of the calibrate command
+func ReadCalibration(r io.Reader) (Calibration, error) {
+	calibration := Calibration{}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&calibration); err != nil {
+		return calibration, err
+	}
+	return calibration, nil
+}
+
+// LoadCalibration reads a JSON Calibration file
+func LoadCalibration(path string) (Calibration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Calibration{}, err
+	}
+	defer f.Close()
+	calibration, err := ReadCalibration(f)
+	if err != nil {
+		return calibration, fmt.Errorf("could not parse calibration %s: %w", path, err)
+	}
+	return calibration, nil
+}
+
+// CalibrationSample returns the sample of the detection of a text whose language is expected,
+// for TrainCalibration
+func (d *Detector) CalibrationSample(text, expected string) CalibrationSample {
+	name, confidence, _ := d.GetClosestLanguageWithConfidence(text)
+	return CalibrationSample{Length: textLength(text), Confidence: confidence, Correct: name == expected}
+}
+
+// GetClosestLanguageWithProbability returns the closest language to a text like
+// GetClosestLanguageWithConfidence, with the probability of the Calibration of this detector
+// that it is correct instead of its raw confidence. Without a Calibration, it returns the raw
+// confidence.
+func (d *Detector) GetClosestLanguageWithProbability(text string) (string, float64, bool) {
+	name, confidence, reliable := d.GetClosestLanguageWithConfidence(text)
+	if d.Calibration == nil || name == "undefined" {
+		return name, confidence, reliable
+	}
+	return name, d.Calibration.Probability(confidence, textLength(text)), reliable
+}
+
+// textLength returns the length of a text in runes, without its leading and trailing spaces
+func textLength(text string) int {
+	return utf8.RuneCountInString(strings.TrimSpace(text))
+}
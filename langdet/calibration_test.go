@@ -0,0 +1,82 @@
+package langdet_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCalibration(t *testing.T) {
+	Convey("Subject: Calibrate confidences by text length", t, func() {
+		samples := []langdet.CalibrationSample{}
+		for i := 0; i < 200; i++ {
+			confidence := float64(i) / 200
+			// short texts are right half of the time, long texts whenever they are confident
+			samples = append(samples, langdet.CalibrationSample{Length: 10, Confidence: confidence, Correct: i%2 == 0})
+			samples = append(samples, langdet.CalibrationSample{Length: 5000, Confidence: confidence, Correct: confidence > 0.3})
+		}
+		calibration := langdet.TrainCalibration(samples)
+
+		Convey("Lengths with samples should get a curve", func() {
+			So(calibration.Curves, ShouldHaveLength, 2)
+			So(calibration.Curves[0].MaxLength, ShouldEqual, 20)
+			So(calibration.Curves[0].Samples, ShouldEqual, 200)
+			So(calibration.Curves[1].MaxLength, ShouldEqual, 0)
+			So(len(calibration.Curves[1].Points), ShouldBeLessThanOrEqualTo, 10)
+		})
+		Convey("The same confidence should mean different probabilities for short and long texts", func() {
+			So(calibration.Probability(0.75, 10), ShouldAlmostEqual, 0.5, 0.05)
+			So(calibration.Probability(0.75, 5000), ShouldAlmostEqual, 1, 0.01)
+			So(calibration.Probability(0.1, 5000), ShouldBeLessThan, 0.1)
+			So(calibration.Probability(0.75, 30), ShouldEqual, calibration.Probability(0.75, 5000))
+		})
+		Convey("Probabilities should never decrease with the confidence", func() {
+			for _, curve := range calibration.Curves {
+				for i := 1; i < len(curve.Points); i++ {
+					So(curve.Points[i].Probability, ShouldBeGreaterThanOrEqualTo, curve.Points[i-1].Probability)
+					So(curve.Points[i].Confidence, ShouldBeGreaterThan, curve.Points[i-1].Confidence)
+				}
+			}
+		})
+		Convey("Without curves confidences should be returned", func() {
+			So(langdet.Calibration{}.Probability(0.75, 10), ShouldEqual, 0.75)
+		})
+	})
+	Convey("Subject: Detect with calibrated probabilities", t, func() {
+		d := langdet.NewDefaultLanguages()
+		texts := map[string]string{"en": "Hello, how are you doing today?", "de": "Ich weiß nicht, was du sagst", "fr": "Je ne sais pas ce que tu dis"}
+		samples := []langdet.CalibrationSample{}
+		for language, text := range texts {
+			sample := d.CalibrationSample(text, language)
+			So(sample.Correct, ShouldBeTrue)
+			So(sample.Length, ShouldBeGreaterThan, 20)
+			samples = append(samples, sample)
+		}
+		samples = append(samples, d.CalibrationSample("Hello, how are you doing today?", "de"))
+		calibration := langdet.TrainCalibration(samples)
+
+		_, confidence, _ := d.GetClosestLanguageWithProbability(texts["en"])
+		_, raw, _ := d.GetClosestLanguageWithConfidence(texts["en"])
+		So(confidence, ShouldEqual, raw)
+		d.Calibration = &calibration
+		name, probability, reliable := d.GetClosestLanguageWithProbability(texts["en"])
+		So(name, ShouldEqual, "en")
+		So(reliable, ShouldBeTrue)
+		So(probability, ShouldBeBetweenOrEqual, 0.5, 0.75)
+
+		Convey("Config files should load calibrations", func() {
+			dir := t.TempDir()
+			So(os.WriteFile(filepath.Join(dir, "calibration.json"), []byte(`{"curves":[{"maxLength":0,"samples":4,"points":[{"confidence":0.5,"probability":0.9}]}]}`), 0644), ShouldBeNil)
+			So(os.WriteFile(filepath.Join(dir, "langdet.json"), []byte(`{"calibration":"calibration.json"}`), 0644), ShouldBeNil)
+			configured, err := langdet.LoadConfig(filepath.Join(dir, "langdet.json"))
+			So(err, ShouldBeNil)
+			So(configured.Calibration.Probability(0.9, 100), ShouldEqual, 0.9)
+			_, err = langdet.ReadCalibration(strings.NewReader(`{"curve":[]}`))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
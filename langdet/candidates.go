@@ -0,0 +1,146 @@
+package langdet
+
+import (
+	"sort"
+	"sync"
+)
+
+// prefilterTokens is the number of top ranked input tokens the cheap pre-filter of
+// Candidates looks up in the profiles
+const prefilterTokens = 20
+
+// DefaultCandidateLookahead is the default number of candidates that are scored ahead
+// of the returned one
+const DefaultCandidateLookahead = 2
+
+// CandidateIterator returns the languages of a Detector one by one, best first,
+// scoring them only when they are requested. Create it with Detector.Candidates.
+type CandidateIterator struct {
+	// Lookahead is the number of candidates that are scored ahead of the returned one.
+	// The order of the results is exact as long as the pre-filter doesn't misplace a
+	// language by more than Lookahead positions.
+	Lookahead int
+
+	scorer  *candidateScorer
+	pending []Language
+	scored  []DetectionResult
+	count   int
+}
+
+// Candidates returns an iterator over the DetectionResults of all languages of this Detector,
+// that scores the languages lazily in the order of a cheap pre-filter: the number of top input
+// tokens found in a profile, weighed by the Prior of the language, and for equal numbers the
+// language that was detected most recently first. This allows interactive UIs to show more
+// candidates on demand without scoring the full set of languages upfront. The results are the ones of GetLanguages:
+// languages of other scripts than the text come last without confidence, and the confidences
+// are weighed by the LanguageOptions of the languages.
+func (d *Detector) Candidates(text string) *CandidateIterator {
+	languages := d.snapshot()
+	prepared := d.prepare(text)
+	occ := CreateOccurenceMap(prepared, d.inputDepth(languages))
+	lookupMap := CreateRankLookupMap(occ)
+	it := &CandidateIterator{
+		Lookahead: DefaultCandidateLookahead,
+		scorer:    d.newCandidateScorer(lookupMap),
+	}
+	it.scorer.words = d.wordLookup(prepared)
+
+	top := make([]string, 0, prefilterTokens)
+	for token, rank := range lookupMap {
+		if rank <= prefilterTokens {
+			top = append(top, token)
+		}
+	}
+	prefilter := make([]float64, len(languages))
+	seen := make([]uint64, len(languages))
+	for i, language := range languages {
+		if !it.scorer.comparable(language) {
+			prefilter[i] = -1
+			continue
+		}
+		for _, token := range top {
+			if _, ok := language.Profile[token]; ok {
+				prefilter[i]++
+			}
+		}
+		if prior := it.scorer.options[language.Name].Prior; prior > 0 {
+			prefilter[i] *= prior
+		}
+		seen[i] = d.recent.lastSeen(language.Name)
+	}
+	order := make([]int, len(prefilter))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if prefilter[order[i]] != prefilter[order[j]] {
+			return prefilter[order[i]] > prefilter[order[j]]
+		}
+		return seen[order[i]] > seen[order[j]]
+	})
+	for _, i := range order {
+		it.pending = append(it.pending, languages[i])
+	}
+	return it
+}
+
+// Next returns the next best DetectionResult, or false if all languages have been returned
+func (it *CandidateIterator) Next() (DetectionResult, bool) {
+	for len(it.pending) > 0 && len(it.scored) <= it.Lookahead {
+		it.scored = append(it.scored, it.scorer.score(it.pending[0]))
+		it.pending = it.pending[1:]
+		it.count++
+	}
+	if len(it.scored) == 0 {
+		return DetectionResult{}, false
+	}
+	best := 0
+	for i, result := range it.scored {
+		if result.Confidence > it.scored[best].Confidence {
+			best = i
+		}
+	}
+	result := it.scored[best]
+	it.scored = append(it.scored[:best], it.scored[best+1:]...)
+	return result, true
+}
+
+// Scored returns the number of languages that have been scored so far
+func (it *CandidateIterator) Scored() int {
+	return it.count
+}
+
+// recentLanguages records the order in which languages were detected reliably. It is safe for
+// concurrent use, and a nil *recentLanguages records nothing.
+type recentLanguages struct {
+	mu    sync.Mutex
+	clock uint64
+	seen  map[string]uint64
+}
+
+// newRecentLanguages returns an empty recentLanguages
+func newRecentLanguages() *recentLanguages {
+	return &recentLanguages{seen: make(map[string]uint64)}
+}
+
+// see records a detection of the named language
+func (r *recentLanguages) see(name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock++
+	r.seen[name] = r.clock
+}
+
+// lastSeen returns a number that is bigger for more recently detected languages, 0 if the named
+// language was not detected yet
+func (r *recentLanguages) lastSeen(name string) uint64 {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.seen[name]
+}
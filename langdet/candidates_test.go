@@ -0,0 +1,78 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestCandidates(t *testing.T) {
+	Convey("Subject: Lazily iterate over candidate languages", t, func() {
+		s := "Hello I am english text, what is your language? I really dont know you say?"
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Je parles français et toi?", "french")
+		d.AddLanguageFromText("Das ist ein deutscher Satz, ich weiss nicht was du sagst", "german")
+		d.AddLanguageFromText("Bu bir Türkçe cümle, ne dediğini bilmiyorum", "turkish")
+		d.AddLanguageFromText(s, "english")
+		d.AddLanguageFromText("Esta es una frase en español, no sé lo que dices", "spanish")
+		it := d.Candidates(s)
+		Convey("The first candidate should be the best one, without scoring all languages", func() {
+			first, ok := it.Next()
+			So(ok, ShouldBeTrue)
+			So(first.Name, ShouldEqual, "english")
+			So(it.Scored(), ShouldBeLessThan, 5)
+		})
+		Convey("All candidates should be returned exactly once in order of confidence", func() {
+			names := map[string]bool{}
+			last := 101.0
+			for {
+				result, ok := it.Next()
+				if !ok {
+					break
+				}
+				So(result.Confidence, ShouldBeLessThanOrEqualTo, last)
+				last = result.Confidence
+				names[result.Name] = true
+			}
+			So(len(names), ShouldEqual, 5)
+			So(it.Scored(), ShouldEqual, 5)
+		})
+		Convey("The results should be the ones of GetLanguages", func() {
+			d.AddLanguageFromText("Это русское предложение, я не знаю, что ты говоришь", "russian")
+			d.SetLanguageOptions("german", langdet.LanguageOptions{Prior: 1.5})
+			expected := map[string]float64{}
+			for _, result := range d.GetLanguages(s) {
+				expected[result.Name] = result.Confidence
+			}
+			it := d.Candidates(s)
+			results := []langdet.DetectionResult{}
+			for result, ok := it.Next(); ok; result, ok = it.Next() {
+				So(result.Confidence, ShouldEqual, expected[result.Name])
+				results = append(results, result)
+			}
+			So(len(results), ShouldEqual, 6)
+			So(results[5].Name, ShouldEqual, "russian")
+			So(results[5].Confidence, ShouldEqual, 0)
+		})
+		Convey("The words of the text should count with a WordWeight", func() {
+			train := func(text, name string) langdet.Language {
+				trainer := &langdet.Trainer{Depth: 3, WordRanks: 100}
+				trainer.Feed(text)
+				return trainer.Build(name)
+			}
+			d := langdet.NewDetector()
+			d.AddLanguage(train("Jeg kan ikke lide det. Hvad er det for noget? Vi skal hjem nu.", "danish"),
+				train("Jeg liker det ikke. Hva er det for noe? Vi skal hjem nå.", "norwegian"))
+			d.WordWeight = 0.5
+			text := "Hva er det? Jeg liker det ikke."
+			expected := d.GetLanguages(text)
+			it := d.Candidates(text)
+			for _, want := range expected {
+				result, ok := it.Next()
+				So(ok, ShouldBeTrue)
+				So(result.Name, ShouldEqual, want.Name)
+				So(result.Confidence, ShouldEqual, want.Confidence)
+			}
+		})
+	})
+}
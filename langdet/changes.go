@@ -0,0 +1,136 @@
+package langdet
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultChangeWindow is the number of runes of the windows of DetectChanges
+const DefaultChangeWindow = 200
+
+// changeWindowSteps is the number of steps a window of DetectChanges slides by its own length, the
+// windows overlap by all but one step
+const changeWindowSteps = 4
+
+// ChangePoint is an offset of a text where its dominant language changes, see DetectChanges
+type ChangePoint struct {
+	// Offset is the byte offset of the change in the text, at the start of a word
+	Offset int
+	// From and To are the dominant languages before and after the change
+	From, To string
+	// Confidence is the smoothed confidence of To after the change, between 0 and 1
+	Confidence float64
+}
+
+// DetectChanges returns where the dominant language of a long text changes, e.g. where the
+// speaker of a transcript switches the language. A window of windowRunes runes, 0 for
+// DefaultChangeWindow, slides over the text by a quarter of its length, the confidences of the
+// languages of every window are smoothed with the windows next to it, and the language of the
+// highest smoothed confidence dominates the window. Changes are reported between the windows of
+// different dominant languages. Texts of up to one window have no changes.
+func (d *Detector) DetectChanges(text string, windowRunes int) []ChangePoint {
+	changes := []ChangePoint{}
+	if windowRunes <= 0 {
+		windowRunes = DefaultChangeWindow
+	}
+	step := windowRunes / changeWindowSteps
+	if step < 1 {
+		step = 1
+	}
+	// offsets are the byte offsets of the runes of the text and of its end
+	offsets := make([]int, 0, len(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	runes := len(offsets)
+	offsets = append(offsets, len(text))
+	if runes <= windowRunes || len(d.snapshot()) == 0 {
+		return changes
+	}
+
+	starts := []int{}
+	for start := 0; ; start += step {
+		if start+windowRunes >= runes {
+			starts = append(starts, runes-windowRunes)
+			break
+		}
+		starts = append(starts, start)
+	}
+	scores := make([]map[string]float64, len(starts))
+	for i, start := range starts {
+		scores[i] = make(map[string]float64)
+		window := text[offsets[start]:offsets[start+windowRunes]]
+		if strings.IndexFunc(window, unicode.IsLetter) < 0 {
+			continue
+		}
+		for _, result := range d.GetLanguages(window) {
+			scores[i][result.Name] = result.Confidence / 100
+		}
+	}
+
+	previous, previousCenter := "", 0
+	for i, start := range starts {
+		smoothed := smoothScores(scores, i)
+		dominant, confidence := dominantLanguage(smoothed)
+		if dominant == "" {
+			continue
+		}
+		center := start + windowRunes/2
+		if previous != "" && dominant != previous {
+			offset := wordStart(text, offsets[(previousCenter+center)/2])
+			changes = append(changes, ChangePoint{Offset: offset, From: previous, To: dominant, Confidence: confidence})
+		}
+		previous, previousCenter = dominant, center
+	}
+	return changes
+}
+
+// smoothScores returns the mean confidences of the languages of a window and of the windows next
+// to it
+func smoothScores(scores []map[string]float64, i int) map[string]float64 {
+	smoothed := make(map[string]float64)
+	windows := 0
+	for j := i - 1; j <= i+1; j++ {
+		if j < 0 || j >= len(scores) || len(scores[j]) == 0 {
+			continue
+		}
+		windows++
+		for name, confidence := range scores[j] {
+			smoothed[name] += confidence
+		}
+	}
+	for name := range smoothed {
+		smoothed[name] /= float64(windows)
+	}
+	return smoothed
+}
+
+// dominantLanguage returns the language of the highest confidence, the first by name of the same
+// confidence, or an empty name if no language has a confidence
+func dominantLanguage(confidences map[string]float64) (string, float64) {
+	names := make([]string, 0, len(confidences))
+	for name := range confidences {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	dominant, highest := "", 0.0
+	for _, name := range names {
+		if confidences[name] > highest {
+			dominant, highest = name, confidences[name]
+		}
+	}
+	return dominant, highest
+}
+
+// wordStart returns the byte offset of the start of the word at offset, or offset for texts
+// without spaces before it
+func wordStart(text string, offset int) int {
+	space := strings.LastIndexFunc(text[:offset], unicode.IsSpace)
+	if space < 0 {
+		return offset
+	}
+	_, size := utf8.DecodeRuneInString(text[space:])
+	return space + size
+}
@@ -0,0 +1,39 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+)
+
+func TestDetectChanges(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis. "
+	Convey("Subject: Detect where the language of a text changes", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+		english, french := strings.Repeat(en, 4), strings.Repeat(fr, 5)
+		text := english + french + english
+
+		Convey("Changes should be found near the switches of the language", func() {
+			changes := d.DetectChanges(text, 100)
+			So(changes, ShouldHaveLength, 2)
+			So(changes[0].From, ShouldEqual, "english")
+			So(changes[0].To, ShouldEqual, "french")
+			So(changes[0].Offset, ShouldAlmostEqual, len(english), 60)
+			So(changes[1].From, ShouldEqual, "french")
+			So(changes[1].To, ShouldEqual, "english")
+			So(changes[1].Offset, ShouldAlmostEqual, len(english+french), 60)
+			So(changes[1].Confidence, ShouldBeBetween, 0, 1)
+			So(text[changes[0].Offset-1], ShouldEqual, ' ')
+		})
+		Convey("Texts of a single language or window should have no changes", func() {
+			empty := langdet.NewDetector()
+			So(d.DetectChanges(english, 100), ShouldBeEmpty)
+			So(d.DetectChanges(en+fr, 0), ShouldBeEmpty)
+			So(empty.DetectChanges(text, 100), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,72 @@
+// Package charset has the legacy charsets of langdet.Detector.Charsets, so that DetectBytes can
+// detect the languages of legacy documents. It is a separate package, so that the langdet package
+// does not depend on golang.org/x/text.
+package charset
+
+import (
+	"fmt"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// charset is a langdet.Charset of an encoding of golang.org/x/text
+type charset struct {
+	name     string
+	encoding encoding.Encoding
+}
+
+// Name returns the name of the charset
+func (c charset) Name() string {
+	return c.name
+}
+
+// Decode returns the UTF-8 text of data
+func (c charset) Decode(data []byte) (string, error) {
+	decoded, err := c.encoding.NewDecoder().Bytes(data)
+	return string(decoded), err
+}
+
+// The single-byte charsets of legacy documents of european and middle eastern languages
+var (
+	ISO88591    langdet.Charset = charset{name: "iso-8859-1", encoding: charmap.ISO8859_1}
+	ISO88592    langdet.Charset = charset{name: "iso-8859-2", encoding: charmap.ISO8859_2}
+	ISO88595    langdet.Charset = charset{name: "iso-8859-5", encoding: charmap.ISO8859_5}
+	ISO88596    langdet.Charset = charset{name: "iso-8859-6", encoding: charmap.ISO8859_6}
+	ISO88597    langdet.Charset = charset{name: "iso-8859-7", encoding: charmap.ISO8859_7}
+	ISO88598    langdet.Charset = charset{name: "iso-8859-8", encoding: charmap.ISO8859_8}
+	ISO88599    langdet.Charset = charset{name: "iso-8859-9", encoding: charmap.ISO8859_9}
+	ISO885915   langdet.Charset = charset{name: "iso-8859-15", encoding: charmap.ISO8859_15}
+	Windows1250 langdet.Charset = charset{name: "windows-1250", encoding: charmap.Windows1250}
+	Windows1251 langdet.Charset = charset{name: "windows-1251", encoding: charmap.Windows1251}
+	Windows1252 langdet.Charset = charset{name: "windows-1252", encoding: charmap.Windows1252}
+	Windows1253 langdet.Charset = charset{name: "windows-1253", encoding: charmap.Windows1253}
+	Windows1254 langdet.Charset = charset{name: "windows-1254", encoding: charmap.Windows1254}
+	Windows1255 langdet.Charset = charset{name: "windows-1255", encoding: charmap.Windows1255}
+	Windows1256 langdet.Charset = charset{name: "windows-1256", encoding: charmap.Windows1256}
+	KOI8R       langdet.Charset = charset{name: "koi8-r", encoding: charmap.KOI8R}
+	KOI8U       langdet.Charset = charset{name: "koi8-u", encoding: charmap.KOI8U}
+)
+
+// Legacy are the charsets that are sniffed by default. The Windows charsets come first, they are
+// supersets of the ISO charsets of the same languages, which decode most texts identically.
+var Legacy = []langdet.Charset{
+	Windows1252, Windows1250, Windows1251, KOI8R, KOI8U, Windows1253, Windows1254, Windows1255, Windows1256,
+	ISO88591, ISO88592, ISO88595, ISO88596, ISO88597, ISO88598, ISO88599, ISO885915,
+}
+
+// Lookup returns the charset of a name or alias of the WHATWG encoding standard, e.g. "latin1" or
+// the charset of a Content-Type header
+func Lookup(name string) (langdet.Charset, error) {
+	e, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown charset %q: %w", name, err)
+	}
+	canonical, err := htmlindex.Name(e)
+	if err != nil {
+		canonical = name
+	}
+	return charset{name: canonical, encoding: e}, nil
+}
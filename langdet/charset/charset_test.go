@@ -0,0 +1,55 @@
+package charset_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/charset"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestLegacyCharsets(t *testing.T) {
+	Convey("Subject: Detect legacy documents", t, func() {
+		d := langdet.NewDefaultLanguages()
+		d.Charsets = charset.Legacy
+		encode := func(e *charmap.Charmap, text string) []byte {
+			data, err := e.NewEncoder().Bytes([]byte(text))
+			So(err, ShouldBeNil)
+			return data
+		}
+		russian := "Большинство жителей деревни работают в соседнем городе"
+		german := "Wir sind am Wochenende ans Meer gefahren und das Wetter war sehr schön, größer als gedacht"
+
+		Convey("The charsets of legacy texts should be sniffed", func() {
+			for _, test := range []struct {
+				data     []byte
+				text     string
+				language string
+				charsets []string
+			}{
+				{data: encode(charmap.Windows1251, russian), text: russian, language: "ru", charsets: []string{"windows-1251"}},
+				{data: encode(charmap.KOI8R, russian), text: russian, language: "ru", charsets: []string{"koi8-r", "koi8-u"}},
+				{data: encode(charmap.ISO8859_1, german), text: german, language: "de", charsets: []string{"windows-1252"}},
+			} {
+				text, name, err := d.DecodeBytes(test.data)
+				So(err, ShouldBeNil)
+				So(text, ShouldEqual, test.text)
+				So(name, ShouldBeIn, test.charsets)
+				language, err := d.DetectBytes(test.data)
+				So(err, ShouldBeNil)
+				So(language, ShouldEqual, test.language)
+			}
+		})
+		Convey("Charsets should be looked up by their aliases", func() {
+			latin1, err := charset.Lookup("latin1")
+			So(err, ShouldBeNil)
+			So(latin1.Name(), ShouldEqual, "windows-1252")
+			text, err := latin1.Decode([]byte("sch\xf6n"))
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, "schön")
+			_, err = charset.Lookup("klingon")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
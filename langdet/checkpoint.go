@@ -0,0 +1,50 @@
+package langdet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// TrainerCheckpoint is the saved state of a Trainer, so that an interrupted training of a huge
+// corpus can resume where it stopped instead of starting over
+type TrainerCheckpoint struct {
+	Depth     int
+	WordRanks int `json:",omitempty"`
+	Documents int
+	Dropped   int `json:",omitempty"`
+	// Position is where the caller stopped reading its corpus, e.g. the number of read records,
+	// which the trainer doesn't know
+	Position int64 `json:",omitempty"`
+	Counts   map[string]int
+	Words    map[string]int `json:",omitempty"`
+}
+
+// WriteCheckpoint writes the state of the trainer with the position of the caller in its corpus
+// as JSON, see ReadCheckpoint
+func (t *Trainer) WriteCheckpoint(w io.Writer, position int64) error {
+	return json.NewEncoder(w).Encode(TrainerCheckpoint{
+		Depth: t.Depth, WordRanks: t.WordRanks, Documents: t.documents, Dropped: t.dropped,
+		Position: position, Counts: t.counts(), Words: t.words,
+	})
+}
+
+// ReadCheckpoint restores the state of a trainer written by WriteCheckpoint, replacing the counts
+// fed so far, and returns the position of the caller. The Depth and WordRanks of the trainer must
+// be those of the checkpoint.
+func (t *Trainer) ReadCheckpoint(r io.Reader) (int64, error) {
+	checkpoint := TrainerCheckpoint{}
+	if err := json.NewDecoder(r).Decode(&checkpoint); err != nil {
+		return 0, fmt.Errorf("could not read checkpoint: %w", err)
+	}
+	if checkpoint.Depth != t.Depth || checkpoint.WordRanks != t.WordRanks {
+		return 0, fmt.Errorf("checkpoint of depth %d and %d word ranks does not fit a trainer of depth %d and %d word ranks",
+			checkpoint.Depth, checkpoint.WordRanks, t.Depth, t.WordRanks)
+	}
+	if checkpoint.Counts == nil {
+		checkpoint.Counts = make(map[string]int)
+	}
+	t.occurences, t.words = checkpoint.Counts, checkpoint.Words
+	t.documents, t.dropped = checkpoint.Documents, checkpoint.Dropped
+	return checkpoint.Position, nil
+}
@@ -0,0 +1,95 @@
+package langdet
+
+import (
+	"sort"
+	"strings"
+)
+
+// codeMixingWindow is the number of words of the windows that are detected separately
+// to find code-mixing
+const codeMixingWindow = 6
+
+// CodeMixing describes how likely a text mixes several languages
+type CodeMixing struct {
+	// Languages are the languages the text is mixed of, most frequent first
+	Languages []string
+	// Likelihood is 0 for a text of a single language and 1 for an evenly mixed text
+	Likelihood float64
+	// Mixed is true if the Likelihood is at least 0.5
+	Mixed bool
+}
+
+// DetectCodeMixing reports how likely a text is code-mixed (e.g. Hinglish or Spanglish) rather than
+// forcing it into a single pure language. The text is split into short windows of words that are
+// detected separately. If a profile of a mixed variety (a Language with Mix) matches the whole
+// text best, its component languages are reported.
+func (d *Detector) DetectCodeMixing(text string) CodeMixing {
+	result := CodeMixing{Languages: []string{}}
+	if len(d.snapshot()) == 0 {
+		return result
+	}
+
+	counts := make(map[string]int)
+	windows := 0
+	words := strings.Fields(text)
+	for start := 0; start < len(words); start += codeMixingWindow {
+		end := start + codeMixingWindow
+		if end > len(words) {
+			end = len(words)
+		}
+		if name := d.closestPure(strings.Join(words[start:end], " ")); name != "" {
+			counts[name]++
+			windows++
+		}
+	}
+	for name := range counts {
+		result.Languages = append(result.Languages, name)
+	}
+	sort.Slice(result.Languages, func(i, j int) bool {
+		a, b := result.Languages[i], result.Languages[j]
+		if counts[a] == counts[b] {
+			return a < b
+		}
+		return counts[a] > counts[b]
+	})
+	if windows > 0 {
+		dominantShare := float64(counts[result.Languages[0]]) / float64(windows)
+		result.Likelihood = 2 * (1 - dominantShare)
+	}
+
+	// a matching profile of a code-mixed variety is the strongest evidence
+	if best := d.GetLanguages(text); len(best) > 0 {
+		if mixed := d.language(best[0].Name); mixed != nil && len(mixed.Mix) > 0 {
+			if confidence := best[0].Confidence / 100; confidence > result.Likelihood {
+				result.Likelihood = confidence
+			}
+			result.Languages = append([]string{}, mixed.Mix...)
+		}
+	}
+	if result.Likelihood > 1 {
+		result.Likelihood = 1
+	}
+	result.Mixed = result.Likelihood >= 0.5
+	return result
+}
+
+// closestPure returns the name of the closest language to text, that is not a code-mixed variety
+func (d *Detector) closestPure(text string) string {
+	for _, result := range d.GetLanguages(text) {
+		if language := d.language(result.Name); language != nil && len(language.Mix) == 0 {
+			return result.Name
+		}
+	}
+	return ""
+}
+
+// language returns the first language of this Detector with the given name, or nil
+func (d *Detector) language(name string) *Language {
+	languages := d.snapshot()
+	for i := range languages {
+		if languages[i].Name == name {
+			return &languages[i]
+		}
+	}
+	return nil
+}
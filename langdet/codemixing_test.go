@@ -0,0 +1,42 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDetectCodeMixing(t *testing.T) {
+	english := "Hello I am english text, what is your language? I really dont know what you say, but I like it."
+	french := "Je parles français et toi? Je ne sais pas ce que tu dis, mais je crois que tu parles bien."
+	Convey("Subject: Detect code-mixing", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(english, "en")
+		d.AddLanguageFromText(french, "fr")
+		Convey("A text of a single language should not be mixed", func() {
+			res := d.DetectCodeMixing(english)
+			So(res.Mixed, ShouldBeFalse)
+			So(res.Languages[0], ShouldEqual, "en")
+		})
+		Convey("A text alternating between languages should be mixed", func() {
+			res := d.DetectCodeMixing(english + " " + french)
+			So(res.Mixed, ShouldBeTrue)
+			So(res.Languages, ShouldContain, "en")
+			So(res.Languages, ShouldContain, "fr")
+		})
+		Convey("A matching profile of a mixed variety should report its languages", func() {
+			mixed := langdet.Analyze("Je parles english and toi parles français what you say", "fr-en-mixed")
+			mixed.Mix = []string{"fr", "en"}
+			d.AddLanguage(mixed)
+			res := d.DetectCodeMixing("Je parles english and toi parles français what you say")
+			So(res.Mixed, ShouldBeTrue)
+			So(res.Languages, ShouldResemble, []string{"fr", "en"})
+		})
+		Convey("A detector without languages should not report anything", func() {
+			empty := langdet.NewDetector()
+			res := empty.DetectCodeMixing(english)
+			So(res.Mixed, ShouldBeFalse)
+			So(res.Languages, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,28 @@
+package langdet
+
+// LanguageCode is the name of a language profile, the ISO 639-1 code for the default languages
+type LanguageCode string
+
+// Codes of the default languages
+const (
+	Arabic  LanguageCode = "ar"
+	English LanguageCode = "en"
+	French  LanguageCode = "fr"
+	German  LanguageCode = "de"
+	Hebrew  LanguageCode = "he"
+	Russian LanguageCode = "ru"
+	Turkish LanguageCode = "tr"
+)
+
+// DefaultLanguageCodes are the codes of all default languages
+var DefaultLanguageCodes = []LanguageCode{Arabic, English, French, German, Hebrew, Russian, Turkish}
+
+// String returns the code as string, as it is used by Language.Name and DetectionResult.Name
+func (c LanguageCode) String() string {
+	return string(c)
+}
+
+// Is tells whether name is the name of the language with this code
+func (c LanguageCode) Is(name string) bool {
+	return string(c) == name
+}
@@ -0,0 +1,25 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLanguageCodes(t *testing.T) {
+	Convey("Subject: Language code constants", t, func() {
+		Convey("Every default language should have metadata", func() {
+			for _, code := range langdet.DefaultLanguageCodes {
+				meta, ok := langdet.DefaultMeta.Get(code.String())
+				So(ok, ShouldBeTrue)
+				So(meta.Name, ShouldEqual, code.String())
+			}
+		})
+		Convey("Codes should match detection results", func() {
+			d := langdet.NewDetector()
+			d.AddLanguageFromText("Hello I am english text", langdet.English.String())
+			So(langdet.English.Is(d.GetLanguages("english text")[0].Name), ShouldBeTrue)
+			So(langdet.French.Is(d.GetLanguages("english text")[0].Name), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,204 @@
+package langdet
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// CompiledProfile is an immutable language profile for the pure scoring function ScoreText.
+// It shares no memory with the Language it was compiled from and it can't be changed afterwards,
+// so it can be shared between goroutines and serialized to JSON or gob, e.g. to distribute it to
+// the workers of data-processing frameworks. Its tokens are stored as a sorted slice, which takes
+// about a quarter of the memory of the map of a Language, at the cost of twice as slow lookups.
+type CompiledProfile struct {
+	name      string
+	tag       LanguageTag
+	depth     int
+	direction string
+	group     string
+	ranks     compactRanks
+}
+
+// Compile returns the immutable profile of a language
+func Compile(language Language) CompiledProfile {
+	tag := language.LookupTag()
+	return CompiledProfile{name: language.Name, tag: tag, depth: language.Depth, direction: language.TextDirection(), group: language.group(tag), ranks: newCompactRanks(language.Profile)}
+}
+
+// CompileAll returns the immutable profiles of languages
+func CompileAll(languages []Language) []CompiledProfile {
+	profiles := make([]CompiledProfile, len(languages))
+	for i, language := range languages {
+		profiles[i] = Compile(language)
+	}
+	return profiles
+}
+
+// Compiled returns the immutable profiles of the current languages of this Detector
+func (d *Detector) Compiled() []CompiledProfile {
+	return CompileAll(d.snapshot())
+}
+
+// Name returns the name of the language of the profile
+func (p CompiledProfile) Name() string {
+	return p.name
+}
+
+// Tag returns the ISO 639 tag of the language of the profile
+func (p CompiledProfile) Tag() LanguageTag {
+	return p.tag
+}
+
+// Direction returns the text direction of the language of the profile
+func (p CompiledProfile) Direction() string {
+	return p.direction
+}
+
+// Group returns the group of the language of the profile, see Language.LanguageGroup
+func (p CompiledProfile) Group() string {
+	return p.group
+}
+
+// Len returns the number of tokens of the profile
+func (p CompiledProfile) Len() int {
+	return p.ranks.size()
+}
+
+// Rank returns the rank of a token, or false if the token is not part of the profile
+func (p CompiledProfile) Rank(token string) (int, bool) {
+	return p.ranks.rank(token)
+}
+
+// Language returns a mutable copy of the profile as a Language
+func (p CompiledProfile) Language() Language {
+	return Language{Name: p.name, Tag: p.tag, Depth: p.depth, Direction: p.direction, Group: p.group, Profile: p.ranks.profile()}
+}
+
+// MarshalJSON encodes the profile like its Language
+func (p CompiledProfile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Language())
+}
+
+// UnmarshalJSON decodes a profile from the JSON of a Language
+func (p *CompiledProfile) UnmarshalJSON(data []byte) error {
+	language := Language{}
+	if err := json.Unmarshal(data, &language); err != nil {
+		return err
+	}
+	*p = Compile(language)
+	return nil
+}
+
+// GobEncode encodes the profile like its Language
+func (p CompiledProfile) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(p.Language())
+	return buf.Bytes(), err
+}
+
+// GobDecode decodes a profile from the gob of a Language
+func (p *CompiledProfile) GobDecode(data []byte) error {
+	language := Language{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&language); err != nil {
+		return err
+	}
+	*p = Compile(language)
+	return nil
+}
+
+// ScoreOptions are the settings of ScoreText, the zero value uses the defaults
+type ScoreOptions struct {
+	// MaxInputRanks caps the number of compared input tokens like Detector.MaxInputRanks
+	MaxInputRanks int
+	// ProfileSize caps the number of compared profile tokens like Detector.ProfileSize
+	ProfileSize int
+	// KeepFormatControls keeps zero-width and bidi control characters in the text,
+	// see StripFormatControls
+	KeepFormatControls bool
+}
+
+// ScoreText compares a text with profiles and returns their DetectionResults, most confident first.
+// Unlike the methods of Detector, it only depends on its arguments, neither on package settings
+// nor on shared state, so it is safe to call from anywhere.
+func ScoreText(text string, profiles []CompiledProfile, options ScoreOptions) []DetectionResult {
+	depth := 0
+	for _, profile := range profiles {
+		if profile.depth > depth {
+			depth = profile.depth
+		}
+	}
+	if depth == 0 {
+		depth = nDepth
+	}
+	occurences := make(map[string]int)
+	updateOccurences(occurences, text, depth, !options.KeepFormatControls)
+	lookups := newDepthLookups(CreateRankLookupMap(occurences))
+	results := make([]DetectionResult, len(profiles))
+	for i, profile := range profiles {
+		input := lookups.forDepth(profile.depth)
+		result := DetectionResult{Name: profile.name, Tag: profile.tag, Direction: profile.direction, Group: profile.group}
+		results[i] = scoreRanks(input, inputRanks(options.MaxInputRanks, len(input)), options.ProfileSize, result, profile.ranks)
+	}
+	sortResults(results)
+	groupResults(results)
+	return results
+}
+
+// compactRanks is a compact ranker: the concatenation of the sorted tokens of a profile, the
+// offsets of the tokens in it and their ranks. Without a string header and a map entry per token,
+// it takes a few bytes more than the tokens themselves.
+type compactRanks struct {
+	tokens  string
+	offsets []uint32
+	ranks   []uint32
+}
+
+// newCompactRanks returns the compactRanks of a map[token]rank
+func newCompactRanks(profile map[string]int) compactRanks {
+	sorted := make([]string, 0, len(profile))
+	length := 0
+	for token := range profile {
+		sorted = append(sorted, token)
+		length += len(token)
+	}
+	sort.Strings(sorted)
+	var tokens strings.Builder
+	tokens.Grow(length)
+	c := compactRanks{offsets: make([]uint32, len(sorted)+1), ranks: make([]uint32, len(sorted))}
+	for i, token := range sorted {
+		tokens.WriteString(token)
+		c.offsets[i+1] = uint32(tokens.Len())
+		c.ranks[i] = uint32(profile[token])
+	}
+	c.tokens = tokens.String()
+	return c
+}
+
+// token returns the i-th token
+func (c compactRanks) token(i int) string {
+	return c.tokens[c.offsets[i]:c.offsets[i+1]]
+}
+
+func (c compactRanks) rank(token string) (int, bool) {
+	i := sort.Search(len(c.ranks), func(i int) bool { return c.token(i) >= token })
+	if i < len(c.ranks) && c.token(i) == token {
+		return int(c.ranks[i]), true
+	}
+	return 0, false
+}
+
+func (c compactRanks) size() int {
+	return len(c.ranks)
+}
+
+// profile returns the ranks as a new map[token]rank
+func (c compactRanks) profile() map[string]int {
+	profile := make(map[string]int, len(c.ranks))
+	for i, rank := range c.ranks {
+		profile[c.token(i)] = int(rank)
+	}
+	return profile
+}
@@ -0,0 +1,51 @@
+package langdet_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestScoreText(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Score texts with compiled profiles", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+		profiles := d.Compiled()
+
+		Convey("Results should equal the results of the detector", func() {
+			So(langdet.ScoreText(en, profiles, langdet.ScoreOptions{}), ShouldResemble, d.GetLanguages(en))
+			d.MaxInputRanks = 10
+			So(langdet.ScoreText(fr, profiles, langdet.ScoreOptions{MaxInputRanks: 10}), ShouldResemble, d.GetLanguages(fr))
+		})
+		Convey("Compiled profiles should not change with their language", func() {
+			language := langdet.Analyze(en, "english")
+			profile := langdet.Compile(language)
+			size := profile.Len()
+			rank, _ := profile.Rank("e")
+			language.Profile["e"] = rank + 100
+			language.Profile["new"] = 1
+			So(profile.Len(), ShouldEqual, size)
+			So(profile.Language().Profile["e"], ShouldEqual, rank)
+		})
+		Convey("Compiled profiles should survive JSON and gob encoding", func() {
+			data, err := json.Marshal(profiles)
+			So(err, ShouldBeNil)
+			decoded := []langdet.CompiledProfile{}
+			So(json.Unmarshal(data, &decoded), ShouldBeNil)
+			So(decoded, ShouldResemble, profiles)
+
+			var buf bytes.Buffer
+			So(gob.NewEncoder(&buf).Encode(profiles), ShouldBeNil)
+			decoded = nil
+			So(gob.NewDecoder(&buf).Decode(&decoded), ShouldBeNil)
+			So(decoded, ShouldResemble, profiles)
+			So(decoded[1].Name(), ShouldEqual, "french")
+		})
+	})
+}
@@ -0,0 +1,264 @@
+package langdet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config describes a fully configured Detector, so that its behavior can be changed by
+// configuration management instead of code changes. Zero values keep the defaults.
+// The yaml tags are used by the yamlconfig package.
+type Config struct {
+	// Profiles are directories with json profiles, json files with a single Language or an array
+	// of Languages, or compiled bundles with their word sets, see WriteBundle. Relative paths of a
+	// loaded config file are relative to its directory.
+	Profiles []string `json:"profiles" yaml:"profiles"`
+	// Languages restricts the detector to these candidate languages, all loaded ones if empty
+	Languages []string `json:"languages" yaml:"languages"`
+	// Background is a profile of the Background model of the detector. If it has several languages,
+	// like a directory of profiles, the background model is built from them by NewBackgroundModel.
+	Background string `json:"background" yaml:"background"`
+	// Calibration is a JSON Calibration file of the detector, e.g. written by the calibrate command
+	Calibration string `json:"calibration" yaml:"calibration"`
+	// Discriminators are JSON LogisticDiscriminator files of pairs of confusable languages, e.g.
+	// written by the discriminate command, see Detector.Discriminators
+	Discriminators []string `json:"discriminators" yaml:"discriminators"`
+
+	// Preset is the name of a preset of Presets, applied before the other options
+	Preset              string  `json:"preset" yaml:"preset"`
+	MinimumConfidence   float32 `json:"minimumConfidence" yaml:"minimumConfidence"`
+	MaxInputRanks       int     `json:"maxInputRanks" yaml:"maxInputRanks"`
+	ProfileSize         int     `json:"profileSize" yaml:"profileSize"`
+	Depth               int     `json:"depth" yaml:"depth"`
+	SymbolicThreshold   float64 `json:"symbolicThreshold" yaml:"symbolicThreshold"`
+	MinMatchedTokens    int     `json:"minMatchedTokens" yaml:"minMatchedTokens"`
+	MinMatchedRatio     float64 `json:"minMatchedRatio" yaml:"minMatchedRatio"`
+	MinMargin           float64 `json:"minMargin" yaml:"minMargin"`
+	MinInputLength      int     `json:"minInputLength" yaml:"minInputLength"`
+	DisableScriptFilter bool    `json:"disableScriptFilter" yaml:"disableScriptFilter"`
+	DedupBatch          bool    `json:"dedupBatch" yaml:"dedupBatch"`
+	ParallelScoring     bool    `json:"parallelScoring" yaml:"parallelScoring"`
+	MaxAnalyzedRunes    int     `json:"maxAnalyzedRunes" yaml:"maxAnalyzedRunes"`
+	// Sampling is the strategy of sampling texts above MaxAnalyzedRunes, prefix or windows, see
+	// Detector.Sampling
+	Sampling string `json:"sampling" yaml:"sampling"`
+	// StripPreamble removes preambles from the texts before the detection, see Detector.StripPreamble
+	StripPreamble bool `json:"stripPreamble" yaml:"stripPreamble"`
+	// FoldCase detects lowercased texts with the case-folded variants of languages, see
+	// Detector.FoldCase
+	FoldCase bool `json:"foldCase" yaml:"foldCase"`
+	// WordWeight is the share of the confidence from the words of the input, see Detector.WordWeight
+	WordWeight float64 `json:"wordWeight" yaml:"wordWeight"`
+	// Variants disambiguates the variants of macro-languages with DefaultVariantClassifiers
+	Variants bool `json:"variants" yaml:"variants"`
+	// Pairs discriminates the pairs of confusable languages of DefaultDiscriminators, the
+	// Discriminators files replace the discriminators of the same pairs
+	Pairs bool `json:"pairs" yaml:"pairs"`
+	// ReportUnknown adds an UnknownLanguage result to GetLanguages, see Detector.ReportUnknown
+	ReportUnknown bool `json:"reportUnknown" yaml:"reportUnknown"`
+	// ResultCache is the number of cached detection results, 0 disables caching, see Detector.Cache
+	ResultCache int `json:"resultCache" yaml:"resultCache"`
+	// LanguageOptions are the options of single languages by name, see Detector.SetLanguageOptions
+	LanguageOptions map[string]LanguageOptions `json:"languageOptions" yaml:"languageOptions"`
+}
+
+// LoadConfig reads a JSON config file and returns the Detector it describes. Use the yamlconfig
+// package for YAML config files.
+func LoadConfig(configPath string) (Detector, error) {
+	switch strings.ToLower(filepath.Ext(configPath)) {
+	case ".yaml", ".yml":
+		return Detector{}, fmt.Errorf("could not load config %s: YAML configs are loaded by the yamlconfig package", configPath)
+	}
+	f, err := os.Open(configPath)
+	if err != nil {
+		return Detector{}, err
+	}
+	defer f.Close()
+	config, err := ReadConfig(f)
+	if err != nil {
+		return Detector{}, fmt.Errorf("could not parse config %s: %w", configPath, err)
+	}
+	config.ResolvePaths(filepath.Dir(configPath))
+	return config.NewDetector()
+}
+
+// ReadConfig decodes a JSON config. Unknown fields are errors, so that misspelled options are
+// not silently ignored.
+func ReadConfig(r io.Reader) (Config, error) {
+	config := Config{}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&config)
+	return config, err
+}
+
+// ResolvePaths makes the relative profile, background, calibration and discriminator paths of this
+// config relative to dir, the directory of the config file
+func (c *Config) ResolvePaths(dir string) {
+	for i, profile := range c.Profiles {
+		if !filepath.IsAbs(profile) {
+			c.Profiles[i] = filepath.Join(dir, profile)
+		}
+	}
+	if c.Background != "" && !filepath.IsAbs(c.Background) {
+		c.Background = filepath.Join(dir, c.Background)
+	}
+	if c.Calibration != "" && !filepath.IsAbs(c.Calibration) {
+		c.Calibration = filepath.Join(dir, c.Calibration)
+	}
+	for i, discriminator := range c.Discriminators {
+		if !filepath.IsAbs(discriminator) {
+			c.Discriminators[i] = filepath.Join(dir, discriminator)
+		}
+	}
+}
+
+// NewDetector returns the Detector described by this config
+func (c Config) NewDetector() (Detector, error) {
+	d := NewDetector()
+	if c.Preset != "" {
+		if err := d.ApplyPreset(c.Preset); err != nil {
+			return d, err
+		}
+	}
+	if c.MinimumConfidence != 0 {
+		d.MinimumConfidence = c.MinimumConfidence
+	}
+	if c.MaxInputRanks != 0 {
+		d.MaxInputRanks = c.MaxInputRanks
+	}
+	if c.ProfileSize != 0 {
+		d.ProfileSize = c.ProfileSize
+	}
+	d.Depth = c.Depth
+	d.SymbolicThreshold = c.SymbolicThreshold
+	d.MinMatchedTokens = c.MinMatchedTokens
+	d.MinMatchedRatio = c.MinMatchedRatio
+	d.MinMargin = c.MinMargin
+	d.MinInputLength = c.MinInputLength
+	d.DisableScriptFilter = c.DisableScriptFilter
+	d.DedupBatch = c.DedupBatch
+	d.ParallelScoring = c.ParallelScoring
+	d.MaxAnalyzedRunes = c.MaxAnalyzedRunes
+	if c.Sampling != "" {
+		sampling, err := ParseSamplingStrategy(c.Sampling)
+		if err != nil {
+			return d, err
+		}
+		d.Sampling = sampling
+	}
+	d.StripPreamble = c.StripPreamble
+	d.FoldCase = c.FoldCase
+	d.ReportUnknown = c.ReportUnknown
+	d.WordWeight = c.WordWeight
+	if c.Variants {
+		d.VariantClassifiers = DefaultVariantClassifiers()
+	}
+	if c.ResultCache > 0 {
+		d.Cache = NewResultCache(c.ResultCache)
+	}
+	for name, options := range c.LanguageOptions {
+		d.SetLanguageOptions(name, options)
+	}
+
+	for _, profile := range c.Profiles {
+		languages, wordSets, err := loadProfiles(profile)
+		if err != nil {
+			return d, err
+		}
+		d.AddLanguage(languages...)
+		for name, filter := range wordSets {
+			d.SetWordSet(name, filter)
+		}
+	}
+	if c.Background != "" {
+		languages, _, err := loadProfiles(c.Background)
+		if err != nil {
+			return d, err
+		}
+		background := languages[0]
+		if len(languages) != 1 {
+			background = NewBackgroundModel(languages...)
+		}
+		d.Background = &background
+	}
+	if c.Calibration != "" {
+		calibration, err := LoadCalibration(c.Calibration)
+		if err != nil {
+			return d, err
+		}
+		d.Calibration = &calibration
+	}
+	if c.Pairs {
+		d.Discriminators = DefaultDiscriminators()
+	}
+	for _, path := range c.Discriminators {
+		discriminator, err := LoadDiscriminator(path)
+		if err != nil {
+			return d, err
+		}
+		if d.Discriminators == nil {
+			d.Discriminators = make(map[LanguagePair]Discriminator)
+		}
+		// a discriminator of the reversed pair would be consulted first
+		delete(d.Discriminators, LanguagePair{First: discriminator.Second, Second: discriminator.First})
+		d.Discriminators[discriminator.Pair()] = discriminator
+	}
+
+	if len(c.Languages) > 0 {
+		candidates := make([]Language, 0, len(c.Languages))
+		for _, name := range c.Languages {
+			found := false
+			// all variants of the candidates are kept
+			for _, language := range d.allLanguages() {
+				if language.Name == name {
+					candidates = append(candidates, language)
+					found = true
+				}
+			}
+			if !found {
+				return d, fmt.Errorf("candidate language %q is not loaded", name)
+			}
+		}
+		d.update(func([]Language) []Language { return candidates })
+	}
+	return d, nil
+}
+
+// loadProfiles loads the languages of a profile directory, of a json file with a single Language
+// or an array of Languages, or of a compiled bundle with its word sets
+func loadProfiles(profilePath string) ([]Language, map[string]*BloomFilter, error) {
+	info, err := os.Stat(profilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		d := NewDetector()
+		err := d.LoadLanguagesFromDir(profilePath)
+		return d.allLanguages(), nil, err
+	}
+	data, err := os.ReadFile(profilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	languages := []Language{}
+	var wordSets map[string]*BloomFilter
+	if bytes.HasPrefix(data, bundleMagic) {
+		languages, wordSets, err = ReadBundleWordSets(bytes.NewReader(data))
+	} else if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		err = decodeLanguages(strings.NewReader(trimmed), &languages)
+	} else {
+		var language Language
+		language, err = decodeLanguageFile(os.DirFS(filepath.Dir(profilePath)), filepath.Base(profilePath))
+		languages = append(languages, language)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load profile %s: %w", profilePath, err)
+	}
+	return languages, wordSets, nil
+}
@@ -0,0 +1,77 @@
+package langdet_test
+
+import (
+	"errors"
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	Convey("Subject: Load a detector from a config file", t, func() {
+		dir := t.TempDir()
+		os.Mkdir(filepath.Join(dir, "profiles"), 0755)
+		os.WriteFile(filepath.Join(dir, "profiles", "en.json"), []byte(`{"Profile":{"t":1},"Name":"en"}`), 0644)
+		os.WriteFile(filepath.Join(dir, "profiles", "fr.json"), []byte(`{"Profile":{"e":1},"Name":"fr"}`), 0644)
+		os.WriteFile(filepath.Join(dir, "more.json"), []byte(`[{"Profile":{"d":1},"Name":"de"},{"Profile":{"r":1},"Name":"ru"}]`), 0644)
+
+		Convey("A JSON config should load all languages if there are no candidates", func() {
+			configPath := filepath.Join(dir, "langdet.json")
+			os.WriteFile(configPath, []byte(`{"profiles": ["profiles", "more.json"], "maxInputRanks": 50, "background": "more.json"}`), 0644)
+			d, err := langdet.LoadConfig(configPath)
+			So(err, ShouldBeNil)
+			So(len(*d.Languages), ShouldEqual, 4)
+			So(d.MaxInputRanks, ShouldEqual, 50)
+			So(d.Background.Name, ShouldEqual, langdet.BackgroundLanguage)
+			So(d.MinimumConfidence, ShouldEqual, langdet.DefaultMinimumConfidence)
+		})
+		Convey("JSON configs should configure preprocessing and detector options", func() {
+			configPath := filepath.Join(dir, "options.json")
+			os.WriteFile(configPath, []byte(`{"profiles": ["profiles"], "stripPreamble": true, "disableScriptFilter": true, "dedupBatch": true, "foldCase": true, "maxAnalyzedRunes": 1000, "sampling": "windows",
+				"languageOptions": {"en": {"minConfidence": 0.75, "prior": 1.5}}}`), 0644)
+			d, err := langdet.LoadConfig(configPath)
+			So(err, ShouldBeNil)
+			So(d.StripPreamble, ShouldBeTrue)
+			So(d.DisableScriptFilter, ShouldBeTrue)
+			So(d.DedupBatch, ShouldBeTrue)
+			So(d.FoldCase, ShouldBeTrue)
+			So(d.MaxAnalyzedRunes, ShouldEqual, 1000)
+			So(d.Sampling, ShouldEqual, langdet.SampleWindows)
+			So(d.LanguageOptions("en"), ShouldResemble, langdet.LanguageOptions{MinConfidence: 0.75, Prior: 1.5})
+		})
+		Convey("JSON configs should load discriminators of pairs of languages", func() {
+			os.WriteFile(filepath.Join(dir, "en-fr.json"), []byte(`{"first": "en", "second": "fr", "depth": 4, "bias": 0, "weights": {"_t": 1}}`), 0644)
+			configPath := filepath.Join(dir, "pairs.json")
+			os.WriteFile(configPath, []byte(`{"profiles": ["profiles"], "pairs": true, "discriminators": ["en-fr.json"]}`), 0644)
+			d, err := langdet.LoadConfig(configPath)
+			So(err, ShouldBeNil)
+			So(d.Discriminators, ShouldContainKey, langdet.LanguagePair{First: "nb", Second: "nn"})
+			So(d.Discriminators, ShouldContainKey, langdet.LanguagePair{First: "en", Second: "fr"})
+			os.WriteFile(configPath, []byte(`{"profiles": ["profiles"], "discriminators": ["nowhere.json"]}`), 0644)
+			_, err = langdet.LoadConfig(configPath)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Unknown candidates and options should be errors", func() {
+			configPath := filepath.Join(dir, "bad.json")
+			os.WriteFile(configPath, []byte(`{"profiles": ["profiles"], "languages": ["xx"]}`), 0644)
+			_, err := langdet.LoadConfig(configPath)
+			So(err, ShouldNotBeNil)
+			os.WriteFile(configPath, []byte(`{"threshold": 1}`), 0644)
+			_, err = langdet.LoadConfig(configPath)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Missing profiles should be wrapped errors", func() {
+			configPath := filepath.Join(dir, "missing.json")
+			os.WriteFile(configPath, []byte(`{"profiles": ["nowhere"]}`), 0644)
+			_, err := langdet.LoadConfig(configPath)
+			So(errors.Is(err, fs.ErrNotExist), ShouldBeTrue)
+		})
+		Convey("YAML configs should be left to the yamlconfig package", func() {
+			_, err := langdet.LoadConfig(filepath.Join(dir, "langdet.yaml"))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
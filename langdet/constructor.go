@@ -0,0 +1,128 @@
+package langdet
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Option configures the detector of NewDetectorWithOptions
+type Option func(d *Detector) error
+
+// WithLanguages detects the given languages instead of the default languages. The languages are
+// copied, so that later changes of the slice don't affect the detector.
+func WithLanguages(languages ...Language) Option {
+	return func(d *Detector) error {
+		if len(languages) == 0 {
+			return errors.New("WithLanguages needs at least one language")
+		}
+		copied := make([]Language, len(languages))
+		for i, language := range languages {
+			if err := language.checkMetadata(); err != nil {
+				return err
+			}
+			copied[i] = language
+			copied[i].infer()
+		}
+		d.Languages = &copied
+		return nil
+	}
+}
+
+// WithMinimumConfidence sets the minimum confidence, between 0 and 1, of reliable detections
+func WithMinimumConfidence(confidence float64) Option {
+	return func(d *Detector) error {
+		if confidence <= 0 || confidence > 1 {
+			return fmt.Errorf("minimum confidence %v is not above 0 and up to 1", confidence)
+		}
+		d.MinimumConfidence = float32(confidence)
+		return nil
+	}
+}
+
+// WithDistanceFunc compares the input tokens with the profiles by distance instead of OutOfPlace,
+// see Detector.Distance
+func WithDistanceFunc(distance DistanceFunc) Option {
+	return func(d *Detector) error {
+		if distance == nil {
+			return errors.New("WithDistanceFunc needs a DistanceFunc")
+		}
+		d.Distance = distance
+		return nil
+	}
+}
+
+// WithTokenizer splits the texts into words with tokenizer before they are analyzed, see
+// Detector.Tokenizer
+func WithTokenizer(tokenizer Tokenizer) Option {
+	return func(d *Detector) error {
+		if tokenizer == nil {
+			return errors.New("WithTokenizer needs a Tokenizer")
+		}
+		d.Tokenizer = tokenizer
+		return nil
+	}
+}
+
+// WithProfileSize compares the top size ranks of the profiles only, see Detector.ProfileSize
+func WithProfileSize(size int) Option {
+	return func(d *Detector) error {
+		if size < 1 {
+			return fmt.Errorf("profile size %d is not positive", size)
+		}
+		d.ProfileSize = size
+		return nil
+	}
+}
+
+// LanguageDetector is a detector whose settings are fixed when it is created by
+// NewDetectorWithOptions, so that they can't be misused later. It is safe for concurrent use.
+type LanguageDetector struct {
+	d Detector
+}
+
+// NewDetectorWithOptions returns a LanguageDetector of the default languages and settings,
+// changed by the options. Invalid options are errors.
+func NewDetectorWithOptions(opts ...Option) (*LanguageDetector, error) {
+	d := NewDetector()
+	d.Languages = nil
+	for _, opt := range opts {
+		if err := opt(&d); err != nil {
+			return nil, err
+		}
+	}
+	if d.Languages == nil {
+		d.Languages = NewDefaultLanguages().Languages
+	}
+	return &LanguageDetector{d: d}, nil
+}
+
+// GetClosestLanguage returns the closest language of a text like Detector.GetClosestLanguage
+func (l *LanguageDetector) GetClosestLanguage(text string) string {
+	return l.d.GetClosestLanguage(text)
+}
+
+// GetClosestLanguageWithConfidence returns the closest language of a text, its confidence and
+// whether it is reliable like Detector.GetClosestLanguageWithConfidence
+func (l *LanguageDetector) GetClosestLanguageWithConfidence(text string) (string, float64, bool) {
+	return l.d.GetClosestLanguageWithConfidence(text)
+}
+
+// GetLanguages returns the DetectionResults of all languages like Detector.GetLanguages
+func (l *LanguageDetector) GetLanguages(text string) []DetectionResult {
+	return l.d.GetLanguages(text)
+}
+
+// DetectBatch detects the languages of texts like Detector.DetectBatch
+func (l *LanguageDetector) DetectBatch(texts []string, workers int) []DetectionResultSet {
+	return l.d.DetectBatch(texts, workers)
+}
+
+// GetResultSet returns the ResultSet of a text like Detector.GetResultSet
+func (l *LanguageDetector) GetResultSet(text string) ResultSet {
+	return l.d.GetResultSet(text)
+}
+
+// ListLanguages returns the names of the detected languages
+func (l *LanguageDetector) ListLanguages() []string {
+	return l.d.ListLanguages()
+}
@@ -0,0 +1,51 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewDetectorWithOptions(t *testing.T) {
+	Convey("Subject: Create detectors with functional options", t, func() {
+		en := langdet.Analyze("Hello I am english text, what is your language? I really dont know you say?", "english")
+		fr := langdet.Analyze("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+
+		Convey("The options should configure the detector", func() {
+			languages := []langdet.Language{en, fr}
+			d, err := langdet.NewDetectorWithOptions(
+				langdet.WithLanguages(languages...),
+				langdet.WithMinimumConfidence(0.1),
+				langdet.WithDistanceFunc(langdet.Cosine{}),
+				langdet.WithTokenizer(langdet.WordTokenizer{Lowercase: true}),
+				langdet.WithProfileSize(300),
+			)
+			So(err, ShouldBeNil)
+			languages[0].Name = "changed"
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french"})
+			So(d.GetClosestLanguage("WHAT IS YOUR LANGUAGE"), ShouldEqual, "english")
+			So(d.DetectBatch([]string{"je ne sais pas"}, 1)[0].Language, ShouldEqual, "french")
+		})
+		Convey("Detectors without languages should detect the default languages", func() {
+			d, err := langdet.NewDetectorWithOptions()
+			So(err, ShouldBeNil)
+			defaults := langdet.NewDefaultLanguages()
+			So(d.ListLanguages(), ShouldResemble, defaults.ListLanguages())
+		})
+		Convey("Invalid options should be errors", func() {
+			for _, opt := range []langdet.Option{
+				langdet.WithLanguages(),
+				langdet.WithMinimumConfidence(0),
+				langdet.WithMinimumConfidence(1.5),
+				langdet.WithDistanceFunc(nil),
+				langdet.WithTokenizer(nil),
+				langdet.WithProfileSize(-1),
+			} {
+				d, err := langdet.NewDetectorWithOptions(opt)
+				So(err, ShouldNotBeNil)
+				So(d, ShouldBeNil)
+			}
+		})
+	})
+}
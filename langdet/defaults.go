@@ -0,0 +1,25 @@
+//go:build !langdet_nodefaults
+
+package langdet
+
+import _ "embed"
+
+//go:generate go run gen_defaults.go
+
+// embeddedLanguages are the profiles of the default languages, trained from 600 KB of real text per
+// language by gen_defaults.go. Build with the langdet_nodefaults tag to leave them out of the binary.
+//
+//go:embed default_languages.json
+var embeddedLanguages []byte
+
+// embeddedTransliteratedLanguages are the profiles of TransliteratedLanguages, trained from the
+// transliterated texts of the same corpus
+//
+//go:embed transliterated_languages.json
+var embeddedTransliteratedLanguages []byte
+
+func init() {
+	// the profiles are decoded on first use by loadDefaults and TransliteratedLanguages
+	embeddedDefaults = embeddedLanguages
+	embeddedTransliterated = embeddedTransliteratedLanguages
+}
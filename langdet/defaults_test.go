@@ -0,0 +1,141 @@
+//go:build !langdet_nodefaults
+
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+)
+
+func TestDefaultLanguages(t *testing.T) {
+	Convey("Subject: Detect with the embedded default languages", t, func() {
+		d := langdet.NewDefaultLanguages()
+		Convey("All default languages should be loaded", func() {
+			So(len(*d.Languages), ShouldEqual, len(langdet.DefaultLanguageCodes))
+			for i, code := range langdet.DefaultLanguageCodes {
+				So(code.Is((*d.Languages)[i].Name), ShouldBeTrue)
+			}
+		})
+		Convey("Held-out texts of the default languages should be detected reliably", func() {
+			texts := map[langdet.LanguageCode][]string{
+				langdet.Arabic: {
+					"ذهبنا إلى البحر في عطلة نهاية الأسبوع وكان الجو جميلا جدا",
+					"يعمل معظم سكان القرية في المدينة القريبة",
+				},
+				langdet.English: {
+					"We went to the sea at the weekend and the weather was very nice",
+					"do not care about quantity",
+				},
+				langdet.French: {
+					"Nous sommes allés à la mer ce week-end et il faisait très beau",
+					"ont permis d'identifier",
+				},
+				langdet.German: {
+					"Wir sind am Wochenende ans Meer gefahren und das Wetter war sehr schön",
+					"Die meisten Leute arbeiten in der nahen Stadt",
+				},
+				langdet.Hebrew: {
+					"נסענו לים בסוף השבוע ומזג האוויר היה נעים מאוד",
+					"רוב תושבי הכפר עובדים בעיר הסמוכה",
+				},
+				langdet.Russian: {
+					"На выходных мы ездили на море, и погода была очень хорошей",
+					"Большинство жителей деревни работают в соседнем городе",
+				},
+				langdet.Turkish: {
+					"Hafta sonu denize gittik ve hava çok güzeldi",
+					"Köylülerin çoğu yakındaki şehirde çalışıyor",
+				},
+			}
+			for code, sentences := range texts {
+				for _, text := range sentences {
+					name, confidence, reliable := d.GetClosestLanguageWithConfidence(text)
+					So(name, ShouldEqual, code.String())
+					So(reliable, ShouldBeTrue)
+					So(confidence, ShouldBeGreaterThanOrEqualTo, float64(langdet.DefaultMinimumConfidence))
+					So(d.GetClosestLanguage(text), ShouldEqual, code.String())
+				}
+			}
+		})
+		Convey("Short texts should be detected by both detection paths", func() {
+			texts := map[string]langdet.LanguageCode{
+				"where is my order": langdet.English,
+				"cheap flights":     langdet.English,
+				"merci beaucoup":    langdet.French,
+				"où est la gare":    langdet.French,
+				"guten Morgen":      langdet.German,
+				"wie spät ist es":   langdet.German,
+				"iyi akşamlar":      langdet.Turkish,
+				"nasılsın":          langdet.Turkish,
+				"как дела":          langdet.Russian,
+				"מה שלומך":          langdet.Hebrew,
+				"صباح الخير":        langdet.Arabic,
+				"ich weiß es nicht": langdet.German,
+				"je ne sais pas":    langdet.French,
+				"I do not know":     langdet.English,
+				"bilmiyorum":        langdet.Turkish,
+				"das ist gut":       langdet.German,
+			}
+			rankCorrect, shortCorrect := 0, 0
+			for text, code := range texts {
+				if name, _, _ := d.GetClosestLanguageWithConfidence(text); name == code.String() {
+					rankCorrect++
+				}
+				if name, _, _ := d.DetectShort(text); name == code.String() {
+					shortCorrect++
+				}
+			}
+			t.Logf("short texts: %d of %d detected by rank distance, %d by DetectShort", rankCorrect, len(texts), shortCorrect)
+			So(rankCorrect, ShouldBeGreaterThanOrEqualTo, len(texts)-2)
+			So(shortCorrect, ShouldBeGreaterThanOrEqualTo, len(texts)-2)
+		})
+		Convey("Transliterated Russian should be detected with the transliterated languages", func() {
+			texts := []string{"privet kak dela", "ya ne znayu chto skazat", "mozhno mne chashku kofe"}
+			for _, text := range texts {
+				So(d.GetClosestLanguage(text), ShouldNotEqual, "ru-Latn")
+			}
+			transliterated := langdet.TransliteratedLanguages()
+			So(transliterated, ShouldHaveLength, 1)
+			So(transliterated[0].Name, ShouldEqual, "ru-Latn")
+			So(transliterated[0].Scripts[0], ShouldEqual, "Latin")
+			withTransliterated := langdet.NewDefaultLanguages()
+			withTransliterated.AddLanguage(transliterated...)
+			for _, text := range texts {
+				So(withTransliterated.GetClosestLanguage(text), ShouldEqual, "ru-Latn")
+			}
+			So(withTransliterated.GetClosestLanguage("where is the train station"), ShouldEqual, "en")
+		})
+	})
+}
+
+func TestDefault(t *testing.T) {
+	Convey("Subject: The default detector", t, func() {
+		Convey("Concurrent first uses should see all default languages", func() {
+			var wg sync.WaitGroup
+			names := make([]string, 8)
+			for i := range names {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					names[i] = langdet.Default().GetClosestLanguage("Wir sind am Wochenende ans Meer gefahren")
+				}(i)
+			}
+			wg.Wait()
+			for _, name := range names {
+				So(name, ShouldEqual, langdet.German.String())
+			}
+			So(langdet.Default(), ShouldEqual, &langdet.DefaultDetector)
+			So(langdet.Default().Snapshot(), ShouldHaveLength, len(langdet.DefaultLanguageCodes))
+		})
+		Convey("Detectors of the embedded languages should not share the default languages", func() {
+			d := langdet.NewDetectorWithLanguages(langdet.EmbeddedLanguages()...)
+			So(d.ListLanguages(), ShouldResemble, langdet.Default().ListLanguages())
+			d.RemoveLanguage(langdet.German.String())
+			So(d.GetClosestLanguage("Wir sind am Wochenende ans Meer gefahren"), ShouldNotEqual, langdet.German.String())
+			So(langdet.Default().ListLanguages(), ShouldContain, langdet.German.String())
+			So(langdet.EmbeddedLanguages(), ShouldHaveLength, len(langdet.DefaultLanguageCodes))
+		})
+	})
+}
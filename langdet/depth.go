@@ -0,0 +1,84 @@
+package langdet
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Depths of DepthForScript
+const (
+	ideographicDepth = 1
+	alphabetDepth    = 2
+	latinDepth       = 3
+)
+
+// DepthForScript returns the n-gram depth that suits a unicode script, as in unicode.Scripts.
+// Ideographic scripts carry much information per letter and need 1 and 2 letter tokens only,
+// most alphabets need up to 3 letters and Latin, which is shared by many similar languages,
+// needs up to 4 letters. Trainers with a Depth of 0 use it to select their depth.
+func DepthForScript(script string) int {
+	switch script {
+	case "Han", "Hiragana", "Katakana", "Yi":
+		return ideographicDepth
+	case "Latin":
+		return latinDepth
+	}
+	return alphabetDepth
+}
+
+// inputDepth returns the n-gram depth of the input tokens that are compared with languages,
+// the Depth of the detector or the deepest depth of the languages
+func (d *Detector) inputDepth(languages []Language) int {
+	if d.Depth > 0 {
+		return d.Depth
+	}
+	depth := 0
+	for _, language := range languages {
+		if language.Depth > depth {
+			depth = language.Depth
+		}
+	}
+	if depth == 0 {
+		return nDepth
+	}
+	return depth
+}
+
+// depthLookups derives the lookup maps of an input for profiles of smaller n-gram depths than the
+// input, which must not be compared with the longer tokens of the input
+type depthLookups struct {
+	full    map[string]int
+	byDepth map[int]map[string]int
+}
+
+// newDepthLookups returns the depthLookups of a lookupMap map[token]rank
+func newDepthLookups(lookupMap map[string]int) *depthLookups {
+	return &depthLookups{full: lookupMap, byDepth: make(map[int]map[string]int)}
+}
+
+// forDepth returns the lookup map of the input tokens of up to depth+1 letters, which are ranked
+// like the occurrence map of the input at that depth would be. A depth of 0 means all tokens.
+func (l *depthLookups) forDepth(depth int) map[string]int {
+	if depth <= 0 {
+		return l.full
+	}
+	if lookupMap, ok := l.byDepth[depth]; ok {
+		return lookupMap
+	}
+	tokens := make([]Token, 0, len(l.full))
+	for token, rank := range l.full {
+		if utf8.RuneCountInString(token) <= depth+1 {
+			tokens = append(tokens, Token{Key: token, Occurrence: rank})
+		}
+	}
+	lookupMap := l.full
+	if len(tokens) < len(l.full) {
+		sort.Sort(ByOccurrence(tokens))
+		lookupMap = make(map[string]int, len(tokens))
+		for i, token := range tokens {
+			lookupMap[token.Key] = i + 1
+		}
+	}
+	l.byDepth[depth] = lookupMap
+	return lookupMap
+}
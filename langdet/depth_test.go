@@ -0,0 +1,68 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestProfileDepth(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Profiles of different n-gram depths", t, func() {
+		trainer := &langdet.Trainer{Depth: 2}
+		trainer.Feed(fr)
+		french := trainer.Build("french")
+		english := langdet.Analyze(en, "english")
+
+		Convey("Languages should carry their depth", func() {
+			So(english.Depth, ShouldEqual, 4)
+			So(french.Depth, ShouldEqual, 2)
+			inferred := langdet.NewDetector()
+			inferred.AddLanguage(langdet.Language{Name: "french", Profile: french.Profile})
+			So((*inferred.Languages)[0].Depth, ShouldEqual, 2)
+		})
+		Convey("Every profile should be compared with the input tokens of its depth", func() {
+			mixed := langdet.NewDetector()
+			mixed.AddLanguage(english, french)
+			alone := langdet.NewDetector()
+			alone.AddLanguage(french)
+			text := "Je ne sais pas ce que tu dis"
+			results := mixed.GetLanguages(text)
+			So(results[0], ShouldResemble, alone.GetLanguages(text)[0])
+			So(mixed.GetClosestLanguage(en), ShouldEqual, "english")
+			So(mixed.Audit(), ShouldNotBeEmpty)
+		})
+		Convey("The depth of the detector should limit the input tokens", func() {
+			d := langdet.NewDetector()
+			d.AddLanguage(english)
+			d.Depth = 2
+			results := d.GetLanguages("what is your language")
+			So(results[0].InputTokens, ShouldEqual, len(langdet.CreateOccurenceMap("what is your language", 2)))
+		})
+	})
+}
+
+func TestScriptDepth(t *testing.T) {
+	Convey("Subject: Select the n-gram depth by script", t, func() {
+		Convey("Scripts should have suitable depths", func() {
+			So(langdet.DepthForScript("Han"), ShouldEqual, 1)
+			So(langdet.DepthForScript("Cyrillic"), ShouldEqual, 2)
+			So(langdet.DepthForScript("Latin"), ShouldEqual, 3)
+		})
+		Convey("Trainers without a depth should build profiles of the depth of their script", func() {
+			for text, depth := range map[string]int{
+				"我不知道你在说什么。你说什么语言？":                1,
+				"Я не знаю, что ты говоришь.":      2,
+				"I really dont know what you say.": 3,
+			} {
+				trainer := &langdet.Trainer{KeepCounts: true}
+				trainer.Feed(text)
+				language := trainer.Build("language")
+				So(language.Depth, ShouldEqual, depth)
+				So(language.Counts, ShouldResemble, langdet.CreateOccurenceMap(text, depth))
+				So(language.Profile, ShouldResemble, langdet.CreateRankLookupMap(language.Counts))
+			}
+		})
+	})
+}
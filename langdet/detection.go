@@ -1,198 +1,1018 @@
 package langdet
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"io/fs"
+	"os"
 	"path"
-	"sort"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 )
 
 // the depth of n-gram tokens that are created. if nDepth=1, only 1-letter tokens are created
 const nDepth = 4
 
-// DefaultMinimumConfidence is the minimum confidence that a language-match must have to be returned as detected language
-var DefaultMinimumConfidence float32 = 0.7
+// DefaultMaxInputRanks is the default number of top ranked tokens of an input text
+// that are compared with the language profiles
+const DefaultMaxInputRanks = 300
+
+// maxTokenDistance is the distance of an input token that is missing in a profile or too far from
+// its rank there. It is the same for all profiles, the maximum profile size, so that the distances
+// of differently sized profiles are on a common scale.
+const maxTokenDistance = maxSampleSize
+
+// maxLoadWorkers is the maximum number of profile files that are decoded concurrently
+const maxLoadWorkers = 8
+
+// DefaultMinimumConfidence is the minimum confidence that a language-match must have to be returned as detected language.
+// It is calibrated on held-out sentences of a few words, of which it accepts about 95% of the correct
+// detections, while it rejects most texts of languages unknown to the detector.
+var DefaultMinimumConfidence float32 = 0.6
 
 var defaultLanguages = []Language{}
 
-// DefaultDetector is a default detector instance
-var DefaultDetector = Detector{&defaultLanguages, DefaultMinimumConfidence}
+// embeddedDefaults are the JSON profiles of the default languages, nil if they are not embedded
+var embeddedDefaults []byte
+
+// embeddedOnce decodes the embeddedDefaults into the decodedDefaults on their first use, which
+// are never modified
+var (
+	embeddedOnce    sync.Once
+	decodedDefaults []Language
+)
+
+// defaultsOnce loads the decodedDefaults into the defaultLanguages on their first use
+var defaultsOnce sync.Once
+
+// embeddedTransliterated are the JSON profiles of TransliteratedLanguages, nil if they are not embedded
+var embeddedTransliterated []byte
+
+// transliteratedOnce decodes the embeddedTransliterated into the transliteratedLanguages on their
+// first use
+var (
+	transliteratedOnce      sync.Once
+	transliteratedLanguages []Language
+)
+
+// DefaultDetector is a default detector instance. Its languages are loaded from the embedded
+// profiles on first use, which is safe from concurrent goroutines, see Default.
+//
+// Deprecated: the DefaultDetector is shared by all users of the package, so that loading languages
+// into it affects all of them. Create a detector of its own with NewDetectorWithLanguages and
+// EmbeddedLanguages instead.
+var DefaultDetector = Detector{Languages: &defaultLanguages, MinimumConfidence: DefaultMinimumConfidence, mu: &sync.RWMutex{}, recent: newRecentLanguages(), fuzzy: newFuzzyIndexes()}
+
+// Default returns the DefaultDetector with its default languages loaded. It is safe to call from
+// many goroutines, including goroutines started by init functions, the embedded profiles are
+// decoded once.
+func Default() *Detector {
+	loadDefaults()
+	return &DefaultDetector
+}
+
+// loadDefaults loads the embedded default languages once. It panics if they cannot be decoded.
+func loadDefaults() {
+	defaultsOnce.Do(func() {
+		languages := EmbeddedLanguages()
+		DefaultDetector.mu.Lock()
+		defaultLanguages = languages
+		DefaultDetector.mu.Unlock()
+	})
+}
+
+// EmbeddedLanguages returns the embedded profiles of the default languages, unlike the languages of
+// the DefaultDetector unaffected by LoadDefault. The profiles are decoded once, each call returns a
+// copy of its own. It returns no languages if they are not embedded, see the langdet_nodefaults
+// build tag, and panics if they cannot be decoded.
+func EmbeddedLanguages() []Language {
+	embeddedOnce.Do(func() {
+		if embeddedDefaults == nil {
+			return
+		}
+		if err := decodeEmbeddedLanguages(embeddedDefaults, &decodedDefaults); err != nil {
+			panic(fmt.Sprintf("Could not unmarshall embedded languages: %v", err))
+		}
+	})
+	return append([]Language{}, decodedDefaults...)
+}
+
+// TransliteratedLanguages returns the embedded profiles of the transliterated variants of default
+// languages, like "ru-Latn" for Russian in Latin script, trained from the texts of the default
+// languages with the Transliterations. They are not default languages, as they are needed by few
+// applications only; add them to detect transliterated texts:
+//
+//	detector := langdet.NewDefaultLanguages()
+//	detector.AddLanguage(langdet.TransliteratedLanguages()...)
+//
+// It returns no languages if the default languages are not embedded.
+func TransliteratedLanguages() []Language {
+	transliteratedOnce.Do(func() {
+		if embeddedTransliterated == nil {
+			return
+		}
+		if err := decodeEmbeddedLanguages(embeddedTransliterated, &transliteratedLanguages); err != nil {
+			panic(fmt.Sprintf("Could not unmarshall embedded languages: %v", err))
+		}
+	})
+	return append([]Language(nil), transliteratedLanguages...)
+}
 
 // InitWithDefault initializes the default languages with a provided file
-// containing Marshalled array of Languages
+// containing Marshalled array of Languages. It panics if the file cannot be loaded.
+//
+// Deprecated: use Detector.LoadLanguagesFile on a detector of its own, which returns an error
+// instead.
 func InitWithDefault(filePath string) {
-	analyzedInput, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		panic(fmt.Sprintf("Could not open languages file: %v", err))
+	if err := LoadDefault(filePath); err != nil {
+		panic(err.Error())
+	}
+}
+
+// InitWithDefaultFromFS initializes the default languages with the named file of fsys
+// containing Marshalled array of Languages. It panics if the file cannot be loaded.
+//
+// Deprecated: use Detector.LoadLanguagesFromReader with the opened file of fsys on a detector of its
+// own, which returns an error instead.
+func InitWithDefaultFromFS(fsys fs.FS, name string) {
+	if err := LoadDefaultFromFS(fsys, name); err != nil {
+		panic(err.Error())
 	}
-	parseExistingLanguageMap(&analyzedInput, &defaultLanguages)
 }
 
 // InitWithDefaultFromReader initializes the default languages with a provided Reader
-// containing Marshalled array of Languages
+// containing Marshalled array of Languages. It panics if the languages cannot be decoded.
+//
+// Deprecated: use Detector.LoadLanguagesFromReader on a detector of its own, which returns an error
+// instead.
 func InitWithDefaultFromReader(reader io.Reader) {
-	analyzedInput, err := ioutil.ReadAll(reader)
+	if err := LoadDefaultFromReader(reader); err != nil {
+		panic(err.Error())
+	}
+}
+
+// LoadDefault replaces the default languages with the languages of a provided file
+// containing Marshalled array of Languages
+//
+// Deprecated: the default languages are shared by all users of the package, use
+// Detector.LoadLanguagesFile on a detector of its own.
+func LoadDefault(filePath string) error {
+	f, err := os.Open(filePath)
 	if err != nil {
-		panic(fmt.Sprintf("Could not process languages io.Reader: %v", err))
+		return fmt.Errorf("could not open languages file: %w", err)
 	}
-	parseExistingLanguageMap(&analyzedInput, &defaultLanguages)
+	defer f.Close()
+	return LoadDefaultFromReader(f)
 }
 
-func parseExistingLanguageMap(bytes *[]byte, targetLanguages *[]Language) {
-	err := json.Unmarshal(*bytes, targetLanguages)
+// LoadDefaultFromFS replaces the default languages with the languages of the named file of fsys
+// containing Marshalled array of Languages
+//
+// Deprecated: the default languages are shared by all users of the package, use
+// Detector.LoadLanguagesFromReader with the opened file of fsys on a detector of its own.
+func LoadDefaultFromFS(fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
 	if err != nil {
-		panic(fmt.Sprintf("Could not unmarshall languages: %v", err))
+		return fmt.Errorf("could not open languages file: %w", err)
+	}
+	defer f.Close()
+	return LoadDefaultFromReader(f)
+}
+
+// LoadDefaultFromReader replaces the default languages with the languages of a provided Reader
+// containing Marshalled array of Languages. The default languages are unchanged on errors.
+//
+// Deprecated: the default languages are shared by all users of the package, use
+// Detector.LoadLanguagesFromReader on a detector of its own.
+func LoadDefaultFromReader(reader io.Reader) error {
+	languages := []Language{}
+	if err := decodeLanguages(reader, &languages); err != nil {
+		return fmt.Errorf("could not unmarshall languages: %w", err)
+	}
+	// the loaded languages replace the embedded ones, which are not decoded anymore
+	defaultsOnce.Do(func() {})
+	DefaultDetector.update(func([]Language) []Language { return languages })
+	return nil
+}
+
+// decodeLanguages stream-decodes and validates a Marshalled array of Languages from reader into
+// targetLanguages, see Language.Validate. Objects with duplicate keys are rejected.
+func decodeLanguages(reader io.Reader, targetLanguages *[]Language) error {
+	if err := decodeCheckingKeys(reader, targetLanguages); err != nil {
+		return err
+	}
+	return upgradeLanguages(*targetLanguages)
+}
+
+// decodeLanguageBytes is decodeLanguages of the data of a reader
+func decodeLanguageBytes(data []byte, targetLanguages *[]Language) error {
+	if err := checkDuplicateKeys(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	return decodeEmbeddedLanguages(data, targetLanguages)
+}
+
+// decodeEmbeddedLanguages is decodeLanguages without the check of duplicate keys, which takes
+// longer than the decoding, for the embedded profiles that are generated by this package
+func decodeEmbeddedLanguages(data []byte, targetLanguages *[]Language) error {
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(targetLanguages); err != nil {
+		return err
 	}
+	return upgradeLanguages(*targetLanguages)
+}
+
+// upgradeLanguages upgrades and validates decoded languages in place, see Language.upgrade
+func upgradeLanguages(languages []Language) error {
+	for i := range languages {
+		if err := languages[i].upgrade(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Detector has an array of detectable Languages and methods to determine the closest Language to a text.
+//
+// Detectors returned by the constructors of this package are safe for concurrent use: languages can
+// be added or loaded while other goroutines detect texts. Mutators replace the languages with an
+// updated copy, so that detections use a consistent snapshot. The configuration fields, like
+// MinimumConfidence, must be set before the detector is used concurrently. Copies of a Detector
+// share its languages.
 type Detector struct {
+	// Languages are the detectable languages, which must only be changed by the methods of the
+	// Detector once it is used concurrently
 	Languages         *[]Language
 	MinimumConfidence float32
+	// MaxInputRanks caps the number of top ranked input tokens that are compared with the
+	// language profiles. Inputs with fewer tokens use all of them, a negative value disables
+	// the cap and 0 means DefaultMaxInputRanks.
+	// Every compared token adds at most the maximum profile size of 10000 ranks to the distance,
+	// for a token that is missing in the profile, so the maximum possible distance, which the
+	// confidence is relative to, is the number of compared tokens times 10000. A bigger cap makes
+	// confidences of long texts less sensitive to single mismatching tokens.
+	MaxInputRanks int
+	// ProfileSize is the number of top ranked tokens of the profiles that are compared with the
+	// input tokens, tokens of higher ranks count as missing. 0 compares all tokens. Profiles are
+	// as big as they were trained, see Language.Size; smaller sizes are less sensitive to rare
+	// n-grams. It applies to the OutOfPlace distance only.
+	ProfileSize int
+	// Depth is the n-gram depth of the input tokens. 0 means the deepest depth of the languages.
+	// Languages of smaller depths are compared with the input tokens of their own depth only, so
+	// that profiles trained with different depths can be mixed.
+	Depth int
+	// SymbolicThreshold is the minimum fraction of emoji and symbols of a text, for which
+	// GetClosestLanguage returns SymbolicLanguage. 0 means DefaultSymbolicThreshold, a negative
+	// value disables the detection of symbolic texts.
+	SymbolicThreshold float64
+	// MinMatchedTokens is the minimum number of compared input tokens that must be found in the
+	// profile of the closest language for GetClosestLanguage to return it.
+	MinMatchedTokens int
+	// MinMatchedRatio is the minimum fraction of compared input tokens that must be found in the
+	// profile of the closest language for GetClosestLanguage to return it. Gibberish shares few
+	// n-grams with any language, so both options reduce false positives on such input.
+	MinMatchedRatio float64
+	// MinMargin is the minimum difference between the confidences, between 0 and 1, of the closest
+	// and the second closest language for TopK to call the detection reliable. 0 means
+	// DefaultMinMargin, negative values disable the check.
+	MinMargin float64
+	// DisableScriptFilter disables the comparison of texts with the languages of their script
+	// only. By default, languages that are not written in the script of most letters of the
+	// text get no confidence, which saves time and avoids bogus matches.
+	DisableScriptFilter bool
+	// Background is a model of texts in none of the languages, e.g. built by NewBackgroundModel or
+	// trained from a mix of other languages. Detections of texts that are at least as close to it
+	// as to their closest language are not reliable, so that texts of languages that are not loaded
+	// come back undefined instead of as the closest loaded language.
+	Background *Language
+	// StripPreamble makes the detection methods remove preambles like shebang lines and XML
+	// declarations from texts first, see StripPreamble.
+	StripPreamble bool
+	// Tokenizer splits the texts into the words that are analyzed, after the preamble is stripped.
+	// nil analyzes all words, like the trainers of the default profiles. See WordTokenizer.
+	Tokenizer Tokenizer
+	// Distance compares the input tokens with the profiles, nil means OutOfPlace. Its similarities
+	// are the confidences of the languages, so the MinimumConfidence may need to be adjusted.
+	Distance DistanceFunc
+	// ShortTexts makes GetClosestLanguage and GetClosestLanguageWithConfidence detect texts shorter
+	// than ShortTextLength runes with DetectShort. Compare both on short texts of your domain first:
+	// with profiles trained on short texts, like the default profiles, rank distances detect them
+	// at least as well.
+	ShortTexts bool
+	// ShortMinimumConfidence is the minimum confidence of DetectShort, 0 means
+	// DefaultShortMinimumConfidence.
+	ShortMinimumConfidence float32
+	// MinInputLength is the number of runes, not counting white space, below which texts are too
+	// short to detect: GetClosestLanguage returns InsufficientInput for them instead of a guess,
+	// unless they are words of the WordSets or symbolic. 0 detects texts of any length.
+	MinInputLength int
+	// MaxAnalyzedRunes is the number of runes of a text above which only a sample of it is
+	// analyzed, selected by the Sampling strategy, so that huge inputs don't build huge n-gram
+	// maps. 0 means DefaultMaxAnalyzedRunes, a negative value analyzes whole texts. The texts of
+	// readers are limited by the limit of the reader methods instead.
+	MaxAnalyzedRunes int
+	// Sampling selects the parts of texts above MaxAnalyzedRunes that are analyzed, their
+	// beginning by default
+	Sampling SamplingStrategy
+	// DedupBatch makes DetectBatch detect repeated texts only once, which saves work on bulk
+	// data with many identical rows at the cost of a map of all texts of a batch.
+	DedupBatch bool
+	// ParallelScoring compares an input with the languages concurrently, with up to GOMAXPROCS
+	// workers, which cuts the latency of single detections with many languages and long inputs.
+	// The results are the same. A custom Distance must then be safe for concurrent use.
+	ParallelScoring bool
+	// Charsets are the candidate charsets of DetectBytes and DecodeBytes for texts that are not
+	// valid UTF-8, e.g. charset.Legacy. Without them, such texts are rejected with ErrInvalidUTF8.
+	Charsets []Charset
+	// VariantClassifiers disambiguate the variants of macro-languages by their primary language
+	// subtag, e.g. "zh" for zh-Hans and zh-Hant, after the closest language was detected by the
+	// profiles of all languages, see DetectVariant and DefaultVariantClassifiers. The confidence
+	// of the detection stays the confidence of the closest language.
+	VariantClassifiers map[string]VariantClassifier
+	// Discriminators tell apart pairs of confusable languages, like Bosnian and Croatian, see
+	// DefaultDiscriminators and TrainDiscriminator. If the confidences of the two closest languages
+	// of a text are within the DiscriminatorMargin and there is a Discriminator of their pair, in
+	// either order, it decides which of them is the closest. The confidences are not changed.
+	Discriminators map[LanguagePair]Discriminator
+	// DiscriminatorMargin is the difference between the confidences, between 0 and 1, of the two
+	// closest languages up to which they are discriminated, 0 means DefaultDiscriminatorMargin
+	DiscriminatorMargin float64
+	// WordWeight is the share, between 0 and 1, of the confidence of languages with a WordProfile
+	// that is the confidence of the words of the input, the rest is the confidence of its n-grams.
+	// Words separate closely related languages like Danish and Norwegian. 0 compares n-grams only,
+	// texts of readers are always compared by their n-grams only.
+	WordWeight float64
+	// ReportUnknown adds an UnknownLanguage result to the results of GetLanguages, whose confidence
+	// is the highest of the Background model and the UnknownBelow of the closest language. It is
+	// ranked before the languages it is at least as confident as, so that texts of untrained
+	// languages are ranked as unknown first.
+	ReportUnknown bool
+	// FoldCase lowercases the texts before they are analyzed and detects them with the case-folded
+	// variants of languages that have a cased and a case-folded variant, see Language.CaseFolded.
+	// Otherwise the cased variants are used, e.g. for German, whose nouns are capitalized.
+	FoldCase bool
+	// Calibration maps the confidences of GetClosestLanguageWithProbability to calibrated
+	// probabilities that the detections are correct, see TrainCalibration
+	Calibration *Calibration
+	// Cache caches the results of GetClosestLanguageWithConfidence, and of the methods based on it,
+	// and of GetLanguages by the normalized texts, nil disables caching. It is purged when the
+	// languages, language options or word sets change, copies of the detector share it.
+	Cache *ResultCache
+	// Collector collects the latency and the result of every call of GetClosestLanguageWithConfidence,
+	// and of the methods based on it, and of GetLanguages, nil disables collecting. See DetectorStats.
+	Collector Collector
+
+	// options are the LanguageOptions by language name, see SetLanguageOptions
+	options map[string]LanguageOptions
+	// wordSets are the WordSets by language name, see SetWordSet
+	wordSets map[string]WordSet
+	// recent records the languages of reliable detections, nil for detectors that are not created
+	// by a constructor
+	recent *recentLanguages
+	// fuzzy caches the indexes of GetLanguagesFuzzy, nil for detectors that are not created by a
+	// constructor
+	fuzzy *fuzzyIndexes
+	// mu guards the Languages, options and wordSets, it is nil for detectors that are not created by a constructor
+	mu *sync.RWMutex
+}
+
+// Snapshot returns the current languages of this detector, which must not be modified. Unlike
+// the Languages, it is safe to use while other goroutines add or remove languages. It includes
+// both variants of languages with cased and case-folded variants, see FoldCase.
+func (d *Detector) Snapshot() []Language {
+	return d.allLanguages()
+}
+
+// snapshot returns the current languages of this detector that are detected, which must not be
+// modified: of languages with cased and case-folded variants, only the variant of FoldCase
+func (d *Detector) snapshot() []Language {
+	return selectVariants(d.allLanguages(), d.FoldCase)
+}
+
+// allLanguages returns the current languages of this detector, which must not be modified
+func (d *Detector) allLanguages() []Language {
+	if d.Languages == &defaultLanguages {
+		loadDefaults()
+	}
+	if d.mu != nil {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+	}
+	if d.Languages == nil {
+		return nil
+	}
+	return *d.Languages
+}
+
+// update replaces the languages of this detector with the result of change. The slice passed to
+// change has no spare capacity, so that appending to it copies the languages instead of modifying
+// the snapshots of concurrent detections.
+func (d *Detector) update(change func(languages []Language) []Language) {
+	if d.Languages == &defaultLanguages {
+		loadDefaults()
+	}
+	if d.mu != nil {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+	}
+	if d.Languages == nil {
+		d.Languages = &[]Language{}
+	}
+	current := *d.Languages
+	*d.Languages = change(current[:len(current):len(current)])
+	d.Cache.Purge()
+	d.fuzzy.purge()
 }
 
 // NewDetector returns a new Detector without any language.
 // It can be used to add languages selectively.
 func NewDetector() Detector {
-	return Detector{&[]Language{}, DefaultMinimumConfidence}
+	return Detector{Languages: &[]Language{}, MinimumConfidence: DefaultMinimumConfidence, mu: &sync.RWMutex{}, recent: newRecentLanguages(), fuzzy: newFuzzyIndexes()}
+}
+
+// NewDetectorWithLanguages returns a new Detector with a copy of languages. With EmbeddedLanguages,
+// it returns a detector of the default languages that doesn't depend on the state of the package:
+//
+//	detector := langdet.NewDetectorWithLanguages(langdet.EmbeddedLanguages()...)
+func NewDetectorWithLanguages(languages ...Language) Detector {
+	d := NewDetector()
+	d.AddLanguage(languages...)
+	return d
 }
 
 // NewDefaultLanguages returns a new Detector with the default languages, if loaded:
-// currently: Arabic, English, French, German, Hebrew, Russian, Turkish
+// currently: Arabic, English, French, German, Hebrew, Russian, Turkish. They are the embedded
+// languages, or the languages of the last LoadDefault.
 func NewDefaultLanguages() Detector {
-	defaultCopy := make([]Language, len(defaultLanguages))
-	copy(defaultCopy, defaultLanguages)
-	return Detector{&defaultCopy, DefaultMinimumConfidence}
+	defaults := DefaultDetector.allLanguages()
+	defaultCopy := make([]Language, len(defaults))
+	copy(defaultCopy, defaults)
+	return Detector{Languages: &defaultCopy, MinimumConfidence: DefaultMinimumConfidence, mu: &sync.RWMutex{}, recent: newRecentLanguages(), fuzzy: newFuzzyIndexes()}
 }
 
-// NewWithLanguagesFromReader returns a new Detector with existing language parsed from a reader
+// NewWithLanguagesFromReader returns a new Detector with existing language parsed from a reader.
+// It panics if the languages cannot be decoded.
+//
+// Deprecated: use NewDetectorFromReader, which returns an error instead.
 func NewWithLanguagesFromReader(reader io.Reader) Detector {
+	d, err := NewDetectorFromReader(reader)
+	if err != nil {
+		panic(err.Error())
+	}
+	return d
+}
+
+// NewDetectorFromReader returns a new Detector with the languages of a provided Reader
+// containing Marshalled array of Languages
+func NewDetectorFromReader(reader io.Reader) (Detector, error) {
+	languages := []Language{}
+	if err := decodeLanguages(reader, &languages); err != nil {
+		return NewDetector(), fmt.Errorf("could not unmarshall languages: %w", err)
+	}
+	return Detector{Languages: &languages, MinimumConfidence: DefaultMinimumConfidence, mu: &sync.RWMutex{}, recent: newRecentLanguages(), fuzzy: newFuzzyIndexes()}, nil
+}
+
+// NewDetectorFromBytes returns a new Detector with the languages of the data of a languages file,
+// like NewDetectorFromReader. It needs no file system, e.g. in the browser.
+func NewDetectorFromBytes(data []byte) (Detector, error) {
+	languages, err := ParseLanguages(data)
+	if err != nil {
+		return NewDetector(), err
+	}
+	return NewDetectorWithLanguages(languages...), nil
+}
+
+// ParseLanguages decodes and validates the languages of the data of a languages file, a JSON array
+// of Languages
+func ParseLanguages(data []byte) ([]Language, error) {
+	languages := []Language{}
+	if err := decodeLanguageBytes(data, &languages); err != nil {
+		return nil, fmt.Errorf("could not unmarshall languages: %w", err)
+	}
+	return languages, nil
+}
+
+// LoadLanguagesFile replaces the languages of this Detector with the languages of a provided file
+// containing Marshalled array of Languages, like InitWithDefault does for the default languages
+func (d *Detector) LoadLanguagesFile(filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("could not open languages file: %w", err)
+	}
+	defer f.Close()
+	return d.LoadLanguagesFromReader(f)
+}
+
+// LoadLanguagesFromReader replaces the languages of this Detector with the languages of a provided
+// Reader containing Marshalled array of Languages. The languages are unchanged on errors.
+func (d *Detector) LoadLanguagesFromReader(reader io.Reader) error {
 	languages := []Language{}
-	analyzedInput, err := ioutil.ReadAll(reader)
+	if err := decodeLanguages(reader, &languages); err != nil {
+		return fmt.Errorf("could not unmarshall languages: %w", err)
+	}
+	d.update(func([]Language) []Language { return languages })
+	return nil
+}
+
+// LoadLanguagesFromBytes replaces the languages of this Detector with the languages of the data of
+// a languages file, e.g. fetched or embedded by the program. The languages are unchanged on errors.
+func (d *Detector) LoadLanguagesFromBytes(data []byte) error {
+	languages, err := ParseLanguages(data)
 	if err != nil {
-		panic(fmt.Sprintf("Could not unmarshall languages: %v", err))
+		return err
 	}
-	parseExistingLanguageMap(&analyzedInput, &languages)
-	return Detector{&languages, DefaultMinimumConfidence}
+	d.update(func([]Language) []Language { return languages })
+	return nil
 }
 
-// LoadLanguagesFromDir initializes the default languages with json
+// LoadLanguagesFromDir initializes the default languages with json or binary
 // files from the specific directory
 func (d *Detector) LoadLanguagesFromDir(dirPath string) error {
-	languages := make([]Language, 0, 0)
+	return d.LoadLanguagesFromFS(os.DirFS(dirPath), ".")
+}
 
-	files, err := ioutil.ReadDir(dirPath)
+// LoadLanguagesFromFS initializes the languages of this Detector with json or binary
+// files from the directory dir of fsys. The files are decoded concurrently by a small
+// pool of workers, the languages keep the order of the files.
+func (d *Detector) LoadLanguagesFromFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		return err
 	}
-	for _, fileInfo := range files {
-		if fileInfo.IsDir() {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
 			continue
 		}
-		fullName := path.Join(dirPath, fileInfo.Name())
-		jsonContent, err := ioutil.ReadFile(fullName)
-		if err != nil {
-			return err
-		}
-		lang := Language{}
-		err = json.Unmarshal(jsonContent, &lang)
+		names = append(names, path.Join(dir, entry.Name()))
+	}
+
+	languages := make([]Language, len(names))
+	errs := make([]error, len(names))
+	jobs := make(chan int)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > maxLoadWorkers {
+		workers = maxLoadWorkers
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				languages[i], errs[i] = decodeLanguageFile(fsys, names[i])
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
 		if err != nil {
 			return err
 		}
-		languages = append(languages, lang)
 	}
-
-	d.Languages = &languages
+	d.update(func([]Language) []Language { return languages })
 	return nil
 }
 
+// decodeLanguageFile decodes a single Language from the named file of fsys, which is either
+// stream-decoded from JSON or in the binary format of Language.MarshalBinary
+func decodeLanguageFile(fsys fs.FS, name string) (Language, error) {
+	lang := Language{}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return lang, err
+	}
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	if magic, _ := reader.Peek(len(binaryMagic)); isBinaryLanguage(magic) {
+		var data []byte
+		if data, err = io.ReadAll(reader); err == nil {
+			err = lang.UnmarshalBinary(data)
+		}
+	} else {
+		err = decodeCheckingKeys(reader, &lang)
+	}
+	if err != nil {
+		return lang, fmt.Errorf("could not unmarshall language %s: %w", name, err)
+	}
+	if err := lang.upgrade(); err != nil {
+		return lang, fmt.Errorf("%s: %w", name, err)
+	}
+	return lang, nil
+}
+
 // AddLanguageFromText adds language analyzes a text and creates a new Language with given name.
 // The new language will be detectable afterwards by this Detector instance.
 func (d *Detector) AddLanguageFromText(textToAnalyze, languageName string) {
-	if d.Languages == nil {
-		newSlice := make([]Language, 0, 0)
-		d.Languages = &newSlice
-	}
 	analyzedLanguage := Analyze(textToAnalyze, languageName)
-	updatedList := append(*d.Languages, analyzedLanguage)
-	*d.Languages = updatedList
+	d.update(func(l []Language) []Language {
+		return append(l, analyzedLanguage)
+	})
 }
 
 // AddLanguage adds language adds a language to the list of detectable languages by this Detector instance.
 func (d *Detector) AddLanguage(languages ...Language) {
-	if d.Languages == nil {
-		s := make([]Language, 0, 0)
-		d.Languages = &s
-	}
-	l := *d.Languages
+	added := make([]Language, len(languages))
 	for i := range languages {
-		l = append(l, languages[i])
+		added[i] = languages[i]
+		added[i].infer()
+	}
+	d.update(func(l []Language) []Language {
+		return append(l, added...)
+	})
+}
+
+// RemoveLanguage removes the languages of a name, of both variants, from this Detector and tells
+// whether there were any. Detections that are already running keep comparing with the removed languages.
+func (d *Detector) RemoveLanguage(name string) bool {
+	removed := false
+	d.update(func(l []Language) []Language {
+		kept := make([]Language, 0, len(l))
+		for _, language := range l {
+			if language.Name == name {
+				removed = true
+				continue
+			}
+			kept = append(kept, language)
+		}
+		return kept
+	})
+	return removed
+}
+
+// ReplaceLanguage replaces the language of the same name and variant of this Detector with a
+// language, e.g. a retrained profile, keeping its position, or adds it if there is none. Concurrent detections
+// compare with either the old or the new language, never with both or none.
+func (d *Detector) ReplaceLanguage(language Language) {
+	language.infer()
+	d.update(func(l []Language) []Language {
+		return swapLanguages(l, []Language{language}, nil)
+	})
+}
+
+// ListLanguages returns the names of the languages of this Detector in their order
+func (d *Detector) ListLanguages() []string {
+	languages := d.snapshot()
+	names := make([]string, len(languages))
+	for i, language := range languages {
+		names[i] = language.Name
 	}
-	*d.Languages = l
+	return names
 }
 
 // GetClosestLanguage returns the name of the language which is closest to the given text if it is confident enough.
-// It returns undefined otherwise. Set detector's MinimumConfidence for customization.
+// It returns undefined otherwise, or InsufficientInput for texts shorter than MinInputLength. Set detector's MinimumConfidence for customization, invalid values
+// (not above 0 or above 1) mean DefaultMinimumConfidence.
+// Texts consisting predominantly of emoji and symbols are detected as SymbolicLanguage, single words
+// are looked up in the WordSets of the languages first, see SetWordSet.
 func (d *Detector) GetClosestLanguage(text string) string {
-	if d.MinimumConfidence <= 0 || d.MinimumConfidence > 1 {
-		d.MinimumConfidence = DefaultMinimumConfidence
-	}
-	if len(*d.Languages) == 0 {
-		fmt.Println("no languages configured for this detector")
+	name, _, reliable := d.GetClosestLanguageWithConfidence(text)
+	if !reliable && name != InsufficientInput {
 		return "undefined"
 	}
-	occ := CreateOccurenceMap(text, nDepth)
+	return name
+}
+
+// GetClosestLanguageWithConfidence returns the name of the language which is closest to the given text,
+// its confidence between 0 and 1 and whether the detection is reliable, i.e. confident enough
+// according to the detector's MinimumConfidence, MinMatchedTokens and MinMatchedRatio, the
+// LanguageOptions of the language and the Background model.
+// It returns undefined, 0 and false if the detector has no languages.
+func (d *Detector) GetClosestLanguageWithConfidence(text string) (string, float64, bool) {
+	if d.Collector != nil {
+		start := time.Now()
+		name, confidence, reliable := d.closestWithVariant(text)
+		d.observe(MethodClosest, name, reliable, start)
+		return name, confidence, reliable
+	}
+	return d.closestWithVariant(text)
+}
+
+// closestWithVariant is GetClosestLanguageWithConfidence without the Collector
+func (d *Detector) closestWithVariant(text string) (string, float64, bool) {
+	name, confidence, reliable := d.closestLanguage(text)
+	if d.VariantClassifiers != nil {
+		name = d.classifyVariant(text, name, confidence, reliable).Variant
+	}
+	return name, confidence, reliable
+}
+
+// closestLanguage is GetClosestLanguageWithConfidence without the VariantClassifiers
+func (d *Detector) closestLanguage(text string) (string, float64, bool) {
+	return d.cachedClosest(d.prepare(text))
+}
+
+// closestPrepared is closestLanguage of a prepared text without the Cache
+func (d *Detector) closestPrepared(text string) (string, float64, bool) {
+	if name, confidence, reliable, ok := d.closestWithoutNGrams(text); ok {
+		return name, confidence, reliable
+	}
+	languages := d.snapshot()
+	if len(languages) == 0 {
+		return "undefined", 0, false
+	}
+	occ := CreateOccurenceMap(text, d.inputDepth(languages))
+	return d.closestFromOccurences(text, occ, d.wordLookup(text))
+}
+
+// closestWithoutNGrams detects the prepared texts that are not compared by their n-grams: single
+// words of the WordSets, texts below MinInputLength, short texts with ShortTexts and symbolic
+// texts. It returns false for other texts.
+func (d *Detector) closestWithoutNGrams(text string) (string, float64, bool, bool) {
+	if name, confidence, ok := d.closestWord(text); ok {
+		return name, confidence, true, true
+	}
+	if d.tooShort(text) && !d.isSymbolic(text) {
+		return InsufficientInput, 0, false, true
+	}
+	if d.ShortTexts && isShort(text) {
+		name, confidence, reliable := d.DetectShort(text)
+		return name, confidence, reliable, true
+	}
+	if d.isSymbolic(text) {
+		return SymbolicLanguage, SymbolRatio(text), true, true
+	}
+	return "", 0, false, false
+}
+
+// closestFromOccurences returns the closest language to a prepared text with the occurrence map occ
+// and the rank lookup map of its words, its confidence between 0 and 1 and whether the detection
+// is reliable. The text is discriminated by the Discriminators, it is empty for readers.
+func (d *Detector) closestFromOccurences(text string, occ, words map[string]int) (string, float64, bool) {
 	lmap := CreateRankLookupMap(occ)
-	c := d.closestFromTable(lmap)
+	c := d.closestFromInput(lmap, words)
+	d.discriminate(text, c)
 
-	if len(c) == 0 || c[0].Confidence < asPercent(d.MinimumConfidence) {
-		return "undefined"
+	if len(c) == 0 {
+		return "undefined", 0, false
 	}
-	return c[0].Name
+	reliable := d.reliable(lmap, c[0])
+	if reliable {
+		d.recent.see(c[0].Name)
+	}
+	return c[0].Name, c[0].Confidence / 100, reliable
+}
+
+// reliable tells whether the result of the closest language to the input lookupMap is confident
+// enough according to the minimum confidence of the language, MinMatchedTokens, MinMatchedRatio,
+// the Background model and the UnknownBelow of the language
+func (d *Detector) reliable(lookupMap map[string]int, result DetectionResult) bool {
+	return result.Confidence >= asPercent(d.minimumConfidence(result.Name)) && d.matchedEnough(result) && !d.closerToBackground(lookupMap, result) && !d.belowUnknown(result)
 }
 
 // GetLanguages analyzes a text and returns the DetectionResult of all languages of this detector.
+// With ReportUnknown, the results include an UnknownLanguage entry.
 func (d *Detector) GetLanguages(text string) []DetectionResult {
-	occ := CreateOccurenceMap(text, nDepth)
+	if d.Collector == nil {
+		return d.cachedLanguages(d.prepare(text))
+	}
+	start := time.Now()
+	results := d.cachedLanguages(d.prepare(text))
+	language := "undefined"
+	if len(results) > 0 {
+		language = results[0].Name
+	}
+	d.observe(MethodLanguages, language, false, start)
+	return results
+}
+
+// languagesPrepared is GetLanguages of a prepared text without the Cache
+func (d *Detector) languagesPrepared(text string) []DetectionResult {
+	occ := CreateOccurenceMap(text, d.inputDepth(d.snapshot()))
 	lmap := CreateRankLookupMap(occ)
-	results := d.closestFromTable(lmap)
+	results := d.closestFromInput(lmap, d.wordLookup(text))
+	d.discriminate(text, results)
+	if d.ReportUnknown {
+		results = withUnknown(results, d.unknownResult(lmap, results))
+	}
 	return results
 }
 
+// prepare returns the text that is analyzed for the detection of a text, a sample of texts above
+// MaxAnalyzedRunes
+func (d *Detector) prepare(text string) string {
+	text = d.sample(text)
+	if d.StripPreamble {
+		text = StripPreamble(text)
+	}
+	if d.FoldCase {
+		text = strings.ToLower(text)
+	}
+	return tokenize(d.Tokenizer, text)
+}
+
 // closestFromTable compares a lookupMap map[token]rank with all languages of this Detector and returns
-// an array containing all DetectionResults
+// an array containing all DetectionResults.
+// Distances are normalized by the number of compared input ranks and the common maxTokenDistance
+// only, so that confidences stay comparable between languages with differently sized profiles.
+// Languages of a smaller n-gram depth than the input are compared with the input tokens of their depth.
 func (d *Detector) closestFromTable(lookupMap map[string]int) []DetectionResult {
-	res := []DetectionResult{}
-	inputSize := len(lookupMap)
-	if inputSize > 300 {
-		inputSize = 300
-	}
-	for _, language := range *d.Languages {
-		lSize := len(language.Profile)
-		maxPossibleDistance := lSize * inputSize
-		dist := GetDistance(lookupMap, language.Profile, lSize)
-		relativeDistance := 1 - float64(dist)/float64(maxPossibleDistance)
-		confidence := int(relativeDistance * 100)
-		res = append(res, DetectionResult{Name: language.Name, Confidence: confidence})
+	return d.closestFromInput(lookupMap, nil)
+}
+
+// closestFromInput is closestFromTable for an input with the rank lookup map of its words, which
+// are compared with the WordProfiles of the languages, see WordWeight
+func (d *Detector) closestFromInput(lookupMap, words map[string]int) []DetectionResult {
+	languages := d.snapshot()
+	res := make([]DetectionResult, len(languages))
+	scorer := d.newCandidateScorer(lookupMap)
+	scorer.words = words
+	if d.ParallelScoring && len(languages) > 1 {
+		// the lookup maps of all depths are derived up front, as the workers must not add them
+		for _, language := range languages {
+			scorer.lookups.forDepth(language.Depth)
+		}
+		concurrently(len(languages), 0, func(i int) {
+			res[i] = scorer.score(languages[i])
+		})
+	} else {
+		for i, language := range languages {
+			res[i] = scorer.score(language)
+		}
 	}
 
-	sort.Sort(ResByConf(res))
+	sortResults(res)
+	groupResults(res)
 	return res
 }
 
+// candidateScorer scores the languages of a detector for an input
+type candidateScorer struct {
+	d       *Detector
+	input   map[string]int
+	words   map[string]int
+	lookups *depthLookups
+	script  string
+	options map[string]LanguageOptions
+}
+
+// newCandidateScorer returns a candidateScorer for the input lookupMap map[token]rank
+func (d *Detector) newCandidateScorer(lookupMap map[string]int) *candidateScorer {
+	return &candidateScorer{
+		d:       d,
+		input:   lookupMap,
+		lookups: newDepthLookups(lookupMap),
+		script:  d.inputScript(lookupMap),
+		options: d.languageOptions(),
+	}
+}
+
+// comparable tells whether a language is written in the script of the input
+func (s *candidateScorer) comparable(language Language) bool {
+	return s.script == "" || language.writtenIn(s.script)
+}
+
+// score compares the input with a language, weighed by the LanguageOptions of the language
+func (s *candidateScorer) score(language Language) DetectionResult {
+	input := s.lookups.forDepth(language.Depth)
+	maxRank := s.d.maxInputRanks(len(input))
+	if !s.comparable(language) {
+		// languages of other scripts are not compared, but still reported without confidence
+		tag := language.LookupTag()
+		return DetectionResult{Name: language.Name, Tag: tag, ComparedTokens: maxRank, InputTokens: len(input), Direction: language.TextDirection(), Group: language.group(tag)}
+	}
+	result := s.d.score(input, maxRank, language)
+	s.d.weighWords(&result, s.words, language)
+	s.options[language.Name].weigh(&result)
+	return result
+}
+
+// scoreLanguage compares a lookupMap map[token]rank with a single language, taking into account
+// the top maxRank ranks of lookupMap and the top profileSize ranks of the profile, all if it is 0
+func scoreLanguage(lookupMap map[string]int, maxRank, profileSize int, language Language) DetectionResult {
+	tag := language.LookupTag()
+	result := DetectionResult{Name: language.Name, Tag: tag, Direction: language.TextDirection(), Group: language.group(tag)}
+	return scoreRanks(lookupMap, maxRank, profileSize, result, rankMap(language.Profile))
+}
+
+// scoreRanks completes the result of the comparison of a lookupMap with the ranks of a profile
+// like scoreLanguage. The distance is normalized by the number of scored input tokens, which is
+// below maxRank if the input has fewer tokens ranked up to maxRank.
+func scoreRanks(lookupMap map[string]int, maxRank, profileSize int, result DetectionResult, profile ranker) DetectionResult {
+	result.ComparedTokens = maxRank
+	result.InputTokens = len(lookupMap)
+	if maxRank > 0 {
+		var scored int
+		var surprisal int64
+		result.Distance, result.MatchedTokens, scored, surprisal = scoreDistance(lookupMap, profile, maxTokenDistance, maxRank, profileSize)
+		result.ComparedTokens = scored
+		if scored > 0 {
+			relativeDistance := 1 - float64(result.Distance)/float64(scored*maxTokenDistance)
+			result.Confidence = relativeDistance * 100
+		}
+		result.GibberishScore = gibberishScore(surprisal, scored, comparedProfileSize(profile.size(), profileSize))
+	}
+	return result
+}
+
+// ranker looks up the ranks of the tokens of a profile
+type ranker interface {
+	// rank returns the rank of a token, or false if the token is not ranked
+	rank(token string) (int, bool)
+	// size returns the number of ranked tokens
+	size() int
+}
+
+// rankMap is the ranker of a map[token]rank
+type rankMap map[string]int
+
+func (m rankMap) rank(token string) (int, bool) {
+	rank, ok := m[token]
+	return rank, ok
+}
+
+func (m rankMap) size() int {
+	return len(m)
+}
+
+// matchedEnough tells whether result matched enough of the compared input tokens,
+// according to MinMatchedTokens and MinMatchedRatio
+func (d *Detector) matchedEnough(result DetectionResult) bool {
+	if result.MatchedTokens < d.MinMatchedTokens {
+		return false
+	}
+	compared := result.ComparedTokens
+	return compared == 0 || float64(result.MatchedTokens)/float64(compared) >= d.MinMatchedRatio
+}
+
+// maxInputRanks returns the number of top ranked tokens of an input with inputSize tokens
+// that are compared with the language profiles
+func (d *Detector) maxInputRanks(inputSize int) int {
+	return inputRanks(d.MaxInputRanks, inputSize)
+}
+
+// comparedRanks returns the configured number of compared input ranks, or a value < 0 if it is unlimited
+func (d *Detector) comparedRanks() int {
+	return configuredRanks(d.MaxInputRanks)
+}
+
+// inputRanks returns the number of top ranked tokens of an input with inputSize tokens that are
+// compared with the language profiles, for a MaxInputRanks setting of maxInputRanks
+func inputRanks(maxInputRanks, inputSize int) int {
+	maxRank := configuredRanks(maxInputRanks)
+	if maxRank < 0 || inputSize < maxRank {
+		return inputSize
+	}
+	return maxRank
+}
+
+// configuredRanks returns the number of compared input ranks for a MaxInputRanks setting,
+// or a value < 0 if it is unlimited
+func configuredRanks(maxInputRanks int) int {
+	if maxInputRanks == 0 {
+		return DefaultMaxInputRanks
+	}
+	return maxInputRanks
+}
+
 // GetDistance calculates the out-of-place distance between two Profiles,
-// taking into account only items of mapA, that have a value bigger then 300
+// taking into account only items of mapA, that have a value not bigger then DefaultMaxInputRanks.
+// Items with negative ranks are skipped in mapA and count as missing from mapB, a negative maxDist
+// counts as 0.
 func GetDistance(mapA, mapB map[string]int, maxDist int) int {
-	var result int
+	return getDistance(mapA, mapB, maxDist, DefaultMaxInputRanks)
+}
+
+// getDistance calculates the out-of-place distance between two Profiles,
+// taking into account only items of mapA, that have a value not bigger then maxRank
+func getDistance(mapA, mapB map[string]int, maxDist, maxRank int) int {
+	dist, _, _, _ := scoreDistance(mapA, rankMap(mapB), maxDist, maxRank, 0)
+	return dist
+}
+
+// scoreDistance calculates the out-of-place distance between two Profiles like getDistance,
+// the number of compared items of mapA that are found in mapB, the number of compared items of
+// mapA and the surprisal of the compared items of mapA according to the language model of mapB,
+// see surprisal. Items of mapB with ranks above profileSize count as missing, unless profileSize is 0.
+func scoreDistance(mapA map[string]int, mapB ranker, maxDist, maxRank, profileSize int) (result, matched, scored int, surprisal int64) {
+	if maxDist < 0 {
+		maxDist = 0
+	}
 	negMaxDist := ((-1) * maxDist)
+	sizeB := comparedProfileSize(mapB.size(), profileSize)
 	for key, rankA := range mapA {
-		if rankA > 300 {
+		// negative ranks of hand-made maps are skipped, so that the differences can't overflow
+		if rankA > maxRank || rankA < 0 {
 			continue
 		}
+		scored++
 		var diff int
-		if rankB, ok := mapB[key]; ok {
+		rankB, ok := mapB.rank(key)
+		if ok && (rankB < 0 || (profileSize > 0 && rankB > profileSize)) {
+			rankB, ok = 0, false
+		}
+		surprisal += tokenSurprisal(rankB, sizeB)
+		if ok {
+			matched++
 			diff = rankB - rankA
 			if diff > maxDist || diff < negMaxDist {
 				diff = maxDist
@@ -204,10 +1024,19 @@ func GetDistance(mapA, mapB map[string]int, maxDist int) int {
 		}
 		result += diff
 	}
-	return result
+	return result, matched, scored, surprisal
+}
+
+// comparedProfileSize returns the number of compared tokens of a profile with size tokens, when
+// the tokens up to the rank profileSize are compared, all if it is 0
+func comparedProfileSize(size, profileSize int) int {
+	if profileSize > 0 && profileSize < size {
+		return profileSize
+	}
+	return size
 }
 
-// asPercentage takes a float and returns its value in percent, rounded to 1%
-func asPercent(input float32) int {
-	return int(input * 100)
+// asPercent takes a float and returns its value in percent
+func asPercent(input float32) float64 {
+	return float64(input) * 100
 }
@@ -1,10 +1,14 @@
 package langdet_test
 
 import (
+	"fmt"
 	"github.com/imankulov/go-lang-detector/langdet"
 	. "github.com/smartystreets/goconvey/convey"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 )
 
 func createMapRanking(tokensInRank ...string) map[string]int {
@@ -38,13 +42,92 @@ func TestNew(t *testing.T) {
 		Convey("Detector should be initialized", func() {
 			So(d.Languages, ShouldNotBeNil)
 		})
+		Convey("The error-returning constructor should initialize the detector", func() {
+			d, err := langdet.NewDetectorFromReader(strings.NewReader(languageMapAsJson))
+			So(err, ShouldBeNil)
+			So(d.Languages, ShouldNotBeNil)
+		})
 	})
 	Convey("Subject: Initialize DefaultLanguage with languages from reader", t, func() {
 		languageMapAsJson := "[{\"Profile\":{\"____t\":1,\"___t\":3,\"___t_\":5,\"__t\":7,\"__t_\":6,\"__t__\":9,\"_t\":15,\"_t_\":12,\"_t__\":2,\"_t___\":11,\"t\":4,\"t_\":8,\"t__\":14,\"t___\":13,\"t____\":10},\"Name\":\"english\"}]"
+		// the embedded defaults are decoded before they are replaced, so that they can be restored
+		defaults := langdet.DefaultDetector.Snapshot()
+		defer func() { *langdet.DefaultDetector.Languages = defaults }()
 		reader := strings.NewReader(languageMapAsJson)
 		langdet.InitWithDefaultFromReader(reader)
 		Convey("Detector should be initialized", func() {
 			So(langdet.DefaultDetector.Languages, ShouldNotBeNil)
+			So(langdet.DefaultDetector.ListLanguages(), ShouldResemble, []string{"english"})
+		})
+	})
+	Convey("Subject: Load languages returning errors", t, func() {
+		Convey("Valid languages should be loaded", func() {
+			d, err := langdet.NewDetectorFromReader(strings.NewReader(`[{"Profile":{"t":1},"Name":"english"}]`))
+			So(err, ShouldBeNil)
+			So((*d.Languages)[0].Name, ShouldEqual, "english")
+			d, err = langdet.NewDetectorFromBytes([]byte(`[{"Profile":{"t":1},"Name":"english"}]`))
+			So(err, ShouldBeNil)
+			So(d.ListLanguages(), ShouldResemble, []string{"english"})
+		})
+		Convey("Invalid languages should be errors instead of panics", func() {
+			_, err := langdet.NewDetectorFromReader(strings.NewReader("not json"))
+			So(err, ShouldNotBeNil)
+			So(langdet.LoadDefaultFromReader(strings.NewReader("not json")), ShouldNotBeNil)
+			So(langdet.LoadDefault("missing.json"), ShouldNotBeNil)
+			So(func() { langdet.InitWithDefault("missing.json") }, ShouldPanic)
+		})
+	})
+	Convey("Subject: Load languages into a detector of its own", t, func() {
+		english := langdet.Analyze("Hello I am english text, what is your language?", "english")
+		d := langdet.NewDetectorWithLanguages(english)
+		other := langdet.NewDetectorWithLanguages(english)
+		Convey("The languages of a file should replace the languages of the detector only", func() {
+			So(d.LoadLanguagesFromReader(strings.NewReader(`[{"Profile":{"t":1},"Name":"french"}]`)), ShouldBeNil)
+			So(d.ListLanguages(), ShouldResemble, []string{"french"})
+			So(other.ListLanguages(), ShouldResemble, []string{"english"})
+			So(d.LoadLanguagesFromBytes([]byte(`[{"Profile":{"t":1},"Name":"german"}]`)), ShouldBeNil)
+			So(d.ListLanguages(), ShouldResemble, []string{"german"})
+		})
+		Convey("Invalid languages should be errors that keep the languages", func() {
+			So(d.LoadLanguagesFromReader(strings.NewReader("not json")), ShouldNotBeNil)
+			So(d.LoadLanguagesFile("missing.json"), ShouldNotBeNil)
+			So(d.LoadLanguagesFromBytes([]byte(`[{"Name":"french"},{"Name":"french"}]`)), ShouldNotBeNil)
+			So(d.ListLanguages(), ShouldResemble, []string{"english"})
+		})
+	})
+}
+
+func TestLoadLanguagesFromFS(t *testing.T) {
+	Convey("Subject: Load languages from a file system", t, func() {
+		fsys := fstest.MapFS{
+			"profiles/en.json":     {Data: []byte(`{"Profile":{"t":1,"_t":2},"Name":"english"}`)},
+			"profiles/fr.json":     {Data: []byte(`{"Profile":{"e":1,"_e":2},"Name":"french"}`)},
+			"profiles/nested/x.js": {Data: []byte(`not a profile`)},
+		}
+		d := langdet.NewDetector()
+		err := d.LoadLanguagesFromFS(fsys, "profiles")
+		Convey("All profiles of the directory should be loaded, subdirectories skipped", func() {
+			So(err, ShouldBeNil)
+			So(len(*d.Languages), ShouldEqual, 2)
+			So((*d.Languages)[0].Name, ShouldEqual, "english")
+			So((*d.Languages)[1].Profile["e"], ShouldEqual, 1)
+		})
+		Convey("The order of many profiles should be kept", func() {
+			for i := 0; i < 50; i++ {
+				name := fmt.Sprintf("lang%02d", i)
+				fsys["many/"+name+".json"] = &fstest.MapFile{Data: []byte(`{"Name":"` + name + `","Profile":{"a":1}}`)}
+			}
+			err := d.LoadLanguagesFromFS(fsys, "many")
+			So(err, ShouldBeNil)
+			So(len(*d.Languages), ShouldEqual, 50)
+			for i, language := range *d.Languages {
+				So(language.Name, ShouldEqual, fmt.Sprintf("lang%02d", i))
+			}
+		})
+		Convey("A broken profile should return an error", func() {
+			fsys["profiles/broken.json"] = &fstest.MapFile{Data: []byte(`{"Profile":`)}
+			err := d.LoadLanguagesFromFS(fsys, "profiles")
+			So(err, ShouldNotBeNil)
 		})
 	})
 }
@@ -77,6 +160,76 @@ func TestAddLanguage(t *testing.T) {
 	})
 }
 
+func TestRemoveAndReplaceLanguage(t *testing.T) {
+	Convey("Subject: Remove and replace languages of a Detector", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language?", "english")
+		d.AddLanguageFromText("Je parles français et toi?", "french")
+		d.AddLanguageFromText("Ich spreche Deutsch und du?", "german")
+		So(d.ListLanguages(), ShouldResemble, []string{"english", "french", "german"})
+
+		Convey("Removed languages should not be detected anymore", func() {
+			So(d.RemoveLanguage("french"), ShouldBeTrue)
+			So(d.RemoveLanguage("french"), ShouldBeFalse)
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "german"})
+			So(d.GetClosestLanguage("Je parles français et toi?"), ShouldNotEqual, "french")
+		})
+		Convey("Replaced languages should keep their position", func() {
+			d.ReplaceLanguage(langdet.Analyze("Je suis un texte français, quelle est ta langue?", "french"))
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french", "german"})
+			So(d.GetClosestLanguage("quelle est ta langue?"), ShouldEqual, "french")
+			d.ReplaceLanguage(langdet.Analyze("Soy un texto en español", "spanish"))
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french", "german", "spanish"})
+		})
+		Convey("Snapshots taken before a change should be unchanged", func() {
+			snapshot := d.Snapshot()
+			d.RemoveLanguage("english")
+			d.ReplaceLanguage(langdet.Language{Name: "german"})
+			So(len(snapshot), ShouldEqual, 3)
+			So(snapshot[0].Name, ShouldEqual, "english")
+			So(len(snapshot[2].Profile), ShouldBeGreaterThan, 0)
+		})
+		Convey("Languages should be replaced while detecting concurrently", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					d.ReplaceLanguage(langdet.Analyze("Je parles français et toi?", "french"))
+				}()
+				go func() {
+					defer wg.Done()
+					d.GetClosestLanguage("what is your language?")
+				}()
+			}
+			wg.Wait()
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french", "german"})
+		})
+	})
+}
+
+func TestConcurrentDetector(t *testing.T) {
+	Convey("Subject: Add languages while detecting concurrently", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language?", "english")
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				d.AddLanguageFromText("Je parles français et toi?", fmt.Sprintf("french%d", i))
+			}(i)
+			go func() {
+				defer wg.Done()
+				d.GetClosestLanguage("what is your language?")
+			}()
+		}
+		wg.Wait()
+		So(len(*d.Languages), ShouldEqual, 5)
+		So(d.GetClosestLanguage("Hello I am english text, what is your language?"), ShouldEqual, "english")
+	})
+}
+
 func TestClosest(t *testing.T) {
 	Convey("Subject: Test GetClosestLanguage", t, func() {
 		Convey("When finding a closest language", func() {
@@ -98,12 +251,43 @@ func TestClosest(t *testing.T) {
 				So(res, ShouldEqual, "undefined")
 			})
 		})
+		Convey("When the detector has no languages", func() {
+			d := langdet.NewDetector()
+			Convey("Should return an unreliable undefined result", func() {
+				name, confidence, reliable := d.GetClosestLanguageWithConfidence("Hello I am english text")
+				So(name, ShouldEqual, "undefined")
+				So(confidence, ShouldEqual, 0)
+				So(reliable, ShouldBeFalse)
+			})
+		})
+		Convey("When asking for the confidence", func() {
+			s := "Hello I am english text, what is your language? I really dont know you say?"
+			d := langdet.NewDetector()
+			d.AddLanguageFromText(s, "english")
+			d.AddLanguageFromText("Je parles français et toi?", "french")
+			Convey("Should return the closest language, its confidence and whether it is reliable", func() {
+				name, confidence, reliable := d.GetClosestLanguageWithConfidence(s)
+				So(name, ShouldEqual, "english")
+				So(confidence, ShouldEqual, 1)
+				So(reliable, ShouldBeTrue)
+			})
+			Convey("Should return the closest language even if it is not reliable", func() {
+				d.MinMatchedTokens = 1000
+				name, confidence, reliable := d.GetClosestLanguageWithConfidence(s)
+				So(name, ShouldEqual, "english")
+				So(confidence, ShouldBeGreaterThan, 0)
+				So(reliable, ShouldBeFalse)
+			})
+		})
 		Convey("When invalid minimum confidence", func() {
 			d := langdet.NewDetector()
+			d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "english")
 			d.MinimumConfidence = -19
-			Convey("Should set confidence level to default", func() {
-				_ = d.GetClosestLanguage("asd")
-				So(d.MinimumConfidence, ShouldEqual, langdet.DefaultMinimumConfidence)
+			Convey("Should detect with the default confidence level without changing the detector", func() {
+				_, confidence, reliable := d.GetClosestLanguageWithConfidence("asd")
+				So(confidence, ShouldBeLessThan, langdet.DefaultMinimumConfidence)
+				So(reliable, ShouldBeFalse)
+				So(d.MinimumConfidence, ShouldEqual, -19)
 			})
 		})
 	})
@@ -120,7 +304,153 @@ func TestClosest(t *testing.T) {
 		})
 	})
 
+	Convey("Subject: Test confidence of differently sized profiles", t, func() {
+		s := "Hello I am english text"
+		en := langdet.Analyze(s, "english")
+		long := langdet.Language{Name: "english-long", Profile: map[string]int{}}
+		for token, rank := range en.Profile {
+			long.Profile[token] = rank
+		}
+		for i := 1; i <= 1000; i++ {
+			long.Profile[fmt.Sprintf("rare%d", i)] = len(en.Profile) + i
+		}
+		d := langdet.NewDetector()
+		d.AddLanguage(en, long)
+		Convey("Rare tokens of a bigger profile should not change the confidence", func() {
+			res := d.GetLanguages(s)
+			So(len(res), ShouldEqual, 2)
+			So(res[0].Confidence, ShouldEqual, res[1].Confidence)
+		})
+		Convey("An empty text should not match any language", func() {
+			res := d.GetLanguages("")
+			So(res[0].Confidence, ShouldEqual, 0)
+		})
+		Convey("Results should carry the numbers the confidence is calculated from", func() {
+			res := d.GetLanguages(s + " Something else entirely")
+			r := res[0]
+			So(r.InputTokens, ShouldBeGreaterThan, 0)
+			So(r.ComparedTokens, ShouldEqual, r.InputTokens)
+			So(r.MatchedTokens, ShouldBeLessThan, r.ComparedTokens)
+			So(r.Distance, ShouldBeGreaterThan, 0)
+			maxTokenDistance := 10000 // the distance of a missing token, the maximum profile size
+			expected := 100 * (1 - float64(r.Distance)/float64(r.ComparedTokens*maxTokenDistance))
+			So(r.Confidence, ShouldAlmostEqual, expected)
+			So(r.Confidence, ShouldBeLessThan, 100)
+		})
+	})
+	Convey("Subject: Test MinMatchedTokens and MinMatchedRatio", t, func() {
+		s := "Hello I am english text, what is your language? I really dont know you say?"
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(s, "english")
+		Convey("Results should report the number of matched tokens", func() {
+			res := d.GetLanguages("xyz")
+			So(res[0].MatchedTokens, ShouldBeLessThan, len(langdet.CreateOccurenceMap("xyz", 4)))
+		})
+		Convey("Gibberish matching few tokens should be undefined", func() {
+			d.MinMatchedRatio = 0.5
+			So(d.GetClosestLanguage("qwxz jvkq"), ShouldEqual, "undefined")
+			So(d.GetClosestLanguage("what is your language"), ShouldEqual, "english")
+		})
+		Convey("Texts matching less than MinMatchedTokens should be undefined", func() {
+			d.MinMatchedTokens = 1000
+			So(d.GetClosestLanguage(s), ShouldEqual, "undefined")
+		})
+	})
+	Convey("Subject: Test MaxInputRanks", t, func() {
+		s := "Hello I am english text, what is your language? I really dont know you say?"
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(s, "english")
+		d.AddLanguageFromText("Je parles français et toi?", "french")
+		Convey("Comparing only few top ranks should still find the closest language", func() {
+			d.MaxInputRanks = 20
+			res := d.GetLanguages(s)
+			So(res[0].Name, ShouldEqual, "english")
+			So(res[0].Confidence, ShouldBeGreaterThan, res[1].Confidence)
+		})
+		Convey("Disabling the cap should compare all input tokens", func() {
+			d.MaxInputRanks = -1
+			res := d.GetLanguages(s)
+			So(res[0].Name, ShouldEqual, "english")
+			So(res[0].Confidence, ShouldBeGreaterThan, res[1].Confidence)
+		})
+	})
 }
+
+func TestHeldOut(t *testing.T) {
+	training := map[string]string{
+		"english": `The village lies in a valley between two rivers and has about three thousand inhabitants.
+			Most of the people work in the nearby town, where the factory and the hospital are the largest
+			employers. The old church was built in the twelfth century and was restored after the war. Every
+			summer there is a market on the square in front of the town hall, and visitors come from all over
+			the region to buy cheese, bread and wine from the local farmers.`,
+		"french": `Le village se trouve dans une vallée entre deux rivières et compte environ trois mille
+			habitants. La plupart des gens travaillent dans la ville voisine, où l'usine et l'hôpital sont les
+			plus grands employeurs. La vieille église a été construite au douzième siècle et restaurée après la
+			guerre. Chaque été, il y a un marché sur la place devant la mairie, et les visiteurs viennent de
+			toute la région pour acheter du fromage, du pain et du vin aux agriculteurs.`,
+		"german": `Das Dorf liegt in einem Tal zwischen zwei Flüssen und hat etwa dreitausend Einwohner. Die
+			meisten Leute arbeiten in der nahen Stadt, wo die Fabrik und das Krankenhaus die größten
+			Arbeitgeber sind. Die alte Kirche wurde im zwölften Jahrhundert gebaut und nach dem Krieg
+			restauriert. Jeden Sommer gibt es einen Markt auf dem Platz vor dem Rathaus, und Besucher kommen
+			aus der ganzen Region, um Käse, Brot und Wein von den Bauern zu kaufen.`,
+	}
+	heldOut := map[string]string{
+		"english": "The children walked to school along the river every morning",
+		"french":  "Les enfants marchaient vers l'école le long de la rivière chaque matin",
+		"german":  "Die Kinder gingen jeden Morgen am Fluss entlang zur Schule",
+	}
+	Convey("Subject: Detect texts that are not part of the training texts", t, func() {
+		d := langdet.NewDetector()
+		for name, text := range training {
+			d.AddLanguageFromText(text, name)
+		}
+		Convey("Held-out sentences should be clearly closer to their language than to any other", func() {
+			for name, text := range heldOut {
+				res := d.GetLanguages(text)
+				So(res[0].Name, ShouldEqual, name)
+				So(res[0].Confidence-res[1].Confidence, ShouldBeGreaterThan, 10)
+			}
+		})
+		Convey("Confidences should be on the scale of the minimum confidence", func() {
+			for _, text := range heldOut {
+				res := d.GetLanguages(text)
+				So(res[0].Confidence, ShouldBeBetween, 40, 100)
+				So(res[len(res)-1].Confidence, ShouldBeLessThan, res[0].Confidence)
+			}
+		})
+	})
+}
+
+func TestResultOrder(t *testing.T) {
+	Convey("Subject: Deterministic order of the detection results", t, func() {
+		text := "Je ne sais pas ce que tu dis."
+		d := langdet.NewDetector()
+		for _, name := range []string{"zz", "aa", "mm"} {
+			d.AddLanguageFromText(text, name)
+		}
+		d.AddLanguageFromText("What is your language?", "en")
+		names := func(results []langdet.DetectionResult) []string {
+			sorted := []string{}
+			for _, result := range results {
+				sorted = append(sorted, result.Name)
+			}
+			return sorted
+		}
+
+		Convey("Results of the same confidence should be ordered by their language", func() {
+			So(names(d.GetLanguages(text)), ShouldResemble, []string{"aa", "mm", "zz", "en"})
+			So(names(d.TopK(text, 2).Results), ShouldResemble, []string{"aa", "mm"})
+			d.ParallelScoring = true
+			So(names(d.GetLanguages(text)), ShouldResemble, []string{"aa", "mm", "zz", "en"})
+		})
+		Convey("ResByConf should order ties by their language", func() {
+			results := []langdet.DetectionResult{{Name: "b", Confidence: 50}, {Name: "c", Confidence: 70}, {Name: "a", Confidence: 50}}
+			sort.Sort(langdet.ResByConf(results))
+			So(names(results), ShouldResemble, []string{"c", "a", "b"})
+		})
+	})
+}
+
 func TestGetDistance(t *testing.T) {
 	Convey("Subject: Test getDistance", t, func() {
 		Convey("same profiles should return distance 0", func() {
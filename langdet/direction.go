@@ -0,0 +1,129 @@
+package langdet
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Text directions of languages
+const (
+	LeftToRight = "ltr"
+	RightToLeft = "rtl"
+)
+
+// rtlScripts are the unicode scripts written from right to left
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko, unicode.Samaritan, unicode.Mandaic, unicode.Adlam,
+}
+
+// directionTokens is the number of top ranked tokens of a profile that are used to infer its direction
+const directionTokens = 100
+
+// TextDirection returns the text direction of the language, LeftToRight or RightToLeft.
+// If the Direction of the language is not set, it is inferred from the letters of the
+// top ranked tokens of its profile. Analyze, AddLanguage and the loaders of this package
+// set the Direction of the languages, so that detections don't need to infer it.
+func (l Language) TextDirection() string {
+	if l.Direction != "" {
+		return l.Direction
+	}
+	rtl, ltr := 0, 0
+	for token, rank := range l.Profile {
+		if rank > directionTokens {
+			continue
+		}
+		for _, r := range token {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			if isRTL(r) {
+				rtl++
+			} else {
+				ltr++
+			}
+		}
+	}
+	if rtl > ltr {
+		return RightToLeft
+	}
+	return LeftToRight
+}
+
+// infer sets the Direction, the Scripts, the Tag and the Depth of the language, if they are not set
+func (l *Language) infer() {
+	l.Direction = l.TextDirection()
+	l.Scripts = l.TextScripts()
+	l.Tag = l.LookupTag()
+	if l.Depth == 0 && len(l.Profile) > 0 {
+		l.Depth, _, _ = inspectProfile(l.Profile)
+	}
+}
+
+// isRTL tells whether r is a letter of a script written from right to left
+func isRTL(r rune) bool {
+	for _, script := range rtlScripts {
+		if unicode.Is(script, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// DirectionRun is a part of a text whose letters are written in a single direction
+type DirectionRun struct {
+	// Direction is the direction of the letters of the run, LeftToRight or RightToLeft
+	Direction string
+	// Start and End are the byte offsets of the first letter of the run and after its last letter
+	Start, End int
+	// Letters is the number of letters of the run
+	Letters int
+}
+
+// Bidi describes how a text mixes letters written from right to left and from left to right, e.g.
+// when foreign phrases are embedded in an Arabic or Hebrew text
+type Bidi struct {
+	// Mixed tells whether the text has letters of both directions
+	Mixed bool
+	// Dominant is the direction of most letters, or empty for texts without letters
+	Dominant string
+	// RTLRatio is the share of the letters of the text written from right to left, between 0 and 1
+	RTLRatio float64
+	// Runs are the runs of letters of a single direction in the order of the text. Digits,
+	// punctuation and white space between letters of the same direction belong to their run.
+	Runs []DirectionRun
+}
+
+// DetectBidi returns how a text mixes letters of both directions. The direction of a letter is
+// the direction of its script, the unicode bidi algorithm is not applied.
+func DetectBidi(text string) Bidi {
+	bidi := Bidi{}
+	rtl, letters := 0, 0
+	for i, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		direction := LeftToRight
+		if isRTL(r) {
+			direction = RightToLeft
+			rtl++
+		}
+		letters++
+		end := i + utf8.RuneLen(r)
+		if last := len(bidi.Runs) - 1; last >= 0 && bidi.Runs[last].Direction == direction {
+			bidi.Runs[last].End = end
+			bidi.Runs[last].Letters++
+			continue
+		}
+		bidi.Runs = append(bidi.Runs, DirectionRun{Direction: direction, Start: i, End: end, Letters: 1})
+	}
+	if letters == 0 {
+		return bidi
+	}
+	bidi.RTLRatio = float64(rtl) / float64(letters)
+	bidi.Mixed = rtl > 0 && rtl < letters
+	bidi.Dominant = LeftToRight
+	if 2*rtl > letters {
+		bidi.Dominant = RightToLeft
+	}
+	return bidi
+}
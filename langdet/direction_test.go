@@ -0,0 +1,55 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestTextDirection(t *testing.T) {
+	Convey("Subject: Text direction of languages", t, func() {
+		english := langdet.Analyze("Hello I am english text", "english")
+		hebrew := langdet.Analyze("שלום, אני טקסט בעברית", "hebrew")
+		arabic := langdet.Analyze("مرحبا، أنا نص باللغة العربية", "arabic")
+		Convey("The direction should be inferred from the profile", func() {
+			So(english.TextDirection(), ShouldEqual, langdet.LeftToRight)
+			So(hebrew.TextDirection(), ShouldEqual, langdet.RightToLeft)
+			So(arabic.TextDirection(), ShouldEqual, langdet.RightToLeft)
+		})
+		Convey("An explicit direction should be used", func() {
+			english.Direction = langdet.RightToLeft
+			So(english.TextDirection(), ShouldEqual, langdet.RightToLeft)
+		})
+		Convey("Detection results should contain the direction", func() {
+			d := langdet.NewDetector()
+			d.AddLanguage(english, hebrew)
+			res := d.GetLanguages("שלום, אני טקסט")
+			So(res[0].Name, ShouldEqual, "hebrew")
+			So(res[0].Direction, ShouldEqual, langdet.RightToLeft)
+		})
+	})
+}
+
+func TestDetectBidi(t *testing.T) {
+	Convey("Subject: Texts mixing both text directions", t, func() {
+		Convey("Embedded phrases of the other direction should be runs of their own", func() {
+			text := "שלום, I am fine תודה"
+			bidi := langdet.DetectBidi(text)
+			So(bidi.Mixed, ShouldBeTrue)
+			So(bidi.Dominant, ShouldEqual, langdet.RightToLeft)
+			So(bidi.RTLRatio, ShouldEqual, 8.0/15)
+			So(bidi.Runs, ShouldHaveLength, 3)
+			So(bidi.Runs[1].Direction, ShouldEqual, langdet.LeftToRight)
+			So(text[bidi.Runs[1].Start:bidi.Runs[1].End], ShouldEqual, "I am fine")
+			So(bidi.Runs[2].Letters, ShouldEqual, 4)
+		})
+		Convey("Texts of a single direction should not be mixed", func() {
+			bidi := langdet.DetectBidi("Hello, world 42")
+			So(bidi.Mixed, ShouldBeFalse)
+			So(bidi.Dominant, ShouldEqual, langdet.LeftToRight)
+			So(bidi.RTLRatio, ShouldEqual, 0)
+			So(bidi.Runs, ShouldHaveLength, 1)
+			So(langdet.DetectBidi("42 !"), ShouldResemble, langdet.Bidi{})
+		})
+	})
+}
@@ -0,0 +1,324 @@
+package langdet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultDiscriminatorMargin is the DiscriminatorMargin of detectors whose margin is 0
+const DefaultDiscriminatorMargin = 0.05
+
+// DefaultDiscriminatorFeatures is the number of n-grams of a LogisticDiscriminator trained with
+// a MaxFeatures of 0
+const DefaultDiscriminatorFeatures = 500
+
+// DefaultDiscriminatorEpochs is the number of passes over the training texts of TrainDiscriminator
+// with Epochs of 0
+const DefaultDiscriminatorEpochs = 100
+
+// discriminatorLearningRate is the step size of the gradient descent of TrainDiscriminator
+const discriminatorLearningRate = 0.5
+
+// discriminatorL2 is the weight of the L2 penalty of the weights of TrainDiscriminator, which
+// keeps the weights of n-grams of few training texts small
+const discriminatorL2 = 0.001
+
+// LanguagePair is a pair of confusable languages by their names
+type LanguagePair struct {
+	First  string
+	Second string
+}
+
+// Discriminator tells apart the two languages of a LanguagePair, like Bosnian and Croatian, whose
+// profiles share too many n-grams to separate their texts reliably, see Detector.Discriminators
+type Discriminator interface {
+	// Discriminate returns the probability, between 0 and 1, that a text is of the first language
+	// of its pair rather than the second, or false if the text has nothing to tell them apart
+	Discriminate(text string) (float64, bool)
+}
+
+// WordDiscriminator is a Discriminator by lists of words that are common in one language of a pair
+// and rare in the other, like "tko" in Croatian and "ko" in Serbian
+type WordDiscriminator struct {
+	First  Words
+	Second Words
+}
+
+// Discriminate implements Discriminator. The probability is the smoothed share of the words of
+// the text that are in First among those in either list.
+func (w WordDiscriminator) Discriminate(text string) (float64, bool) {
+	first, second := 0, 0
+	for _, field := range strings.Fields(text) {
+		word := strings.ToLower(strings.TrimFunc(field, unicode.IsPunct))
+		if w.First.Contains(word) {
+			first++
+		}
+		if w.Second.Contains(word) {
+			second++
+		}
+	}
+	if first+second == 0 {
+		return 0.5, false
+	}
+	return (float64(first) + 0.5) / (float64(first+second) + 1), true
+}
+
+// LogisticDiscriminator is a Discriminator by a logistic regression over the discriminative n-grams
+// of a pair, learned from labeled texts by TrainDiscriminator
+type LogisticDiscriminator struct {
+	// First and Second are the names of the languages of the pair
+	First  string `json:"first"`
+	Second string `json:"second"`
+	// Depth is the n-gram depth of the features
+	Depth int `json:"depth"`
+	// Bias is the log-odds of the first language of a text without features
+	Bias float64 `json:"bias"`
+	// Weights are the log-odds of the first language added by the n-grams of a text, positive
+	// weights are evidence for the first language, negative ones for the second
+	Weights map[string]float64 `json:"weights"`
+}
+
+// Pair returns the pair of languages of the discriminator
+func (l *LogisticDiscriminator) Pair() LanguagePair {
+	return LanguagePair{First: l.First, Second: l.Second}
+}
+
+// Discriminate implements Discriminator with the n-grams of the text that have a weight
+func (l *LogisticDiscriminator) Discriminate(text string) (float64, bool) {
+	logOdds, matched := l.Bias, 0
+	for token := range CreateOccurenceMap(text, l.depth()) {
+		if weight, ok := l.Weights[token]; ok {
+			logOdds += weight
+			matched++
+		}
+	}
+	if matched == 0 {
+		return 0.5, false
+	}
+	return sigmoid(logOdds), true
+}
+
+// depth returns the Depth, or the default depth if it is not set
+func (l *LogisticDiscriminator) depth() int {
+	if l.Depth < 1 {
+		return nDepth
+	}
+	return l.Depth
+}
+
+// sigmoid returns the probability of log-odds
+func sigmoid(logOdds float64) float64 {
+	return 1 / (1 + math.Exp(-logOdds))
+}
+
+// DiscriminatorOptions configure TrainDiscriminator
+type DiscriminatorOptions struct {
+	// Depth is the n-gram depth of the features, 0 means the depth of the profiles of Analyze
+	Depth int
+	// MaxFeatures is the number of most discriminative n-grams that get a weight, 0 means
+	// DefaultDiscriminatorFeatures
+	MaxFeatures int
+	// Epochs is the number of passes over the texts, 0 means DefaultDiscriminatorEpochs
+	Epochs int
+}
+
+// TrainDiscriminator learns a LogisticDiscriminator of a pair of languages from labeled texts of
+// both. The features are the n-grams that occur in the texts of one language much more often than
+// in the other, their weights are learned by gradient descent on the log-loss. Texts of the two
+// languages are weighted alike, so that the pair doesn't favor the language with more texts.
+func TrainDiscriminator(pair LanguagePair, first, second []string, options DiscriminatorOptions) (*LogisticDiscriminator, error) {
+	if len(first) == 0 || len(second) == 0 {
+		return nil, fmt.Errorf("discriminator of %s and %s needs texts of both languages", pair.First, pair.Second)
+	}
+	model := &LogisticDiscriminator{First: pair.First, Second: pair.Second, Depth: options.Depth, Weights: map[string]float64{}}
+	if model.Depth < 1 {
+		model.Depth = nDepth
+	}
+	maxFeatures := options.MaxFeatures
+	if maxFeatures <= 0 {
+		maxFeatures = DefaultDiscriminatorFeatures
+	}
+	epochs := options.Epochs
+	if epochs <= 0 {
+		epochs = DefaultDiscriminatorEpochs
+	}
+
+	// the n-grams of every text, first the texts of the first language
+	samples := make([]map[string]int, 0, len(first)+len(second))
+	counts := [2]map[string]int{{}, {}}
+	for label, texts := range [2][]string{first, second} {
+		for _, text := range texts {
+			tokens := CreateOccurenceMap(text, model.Depth)
+			for token := range tokens {
+				counts[label][token]++
+			}
+			samples = append(samples, tokens)
+		}
+	}
+	features := discriminativeTokens(counts, len(first), len(second), maxFeatures)
+	for _, feature := range features {
+		model.Weights[feature] = 0
+	}
+
+	// full batch gradient descent, the texts of both languages weighted alike
+	weights := [2]float64{0.5 / float64(len(first)), 0.5 / float64(len(second))}
+	for epoch := 0; epoch < epochs; epoch++ {
+		gradients := make(map[string]float64, len(features))
+		biasGradient := 0.0
+		for i, tokens := range samples {
+			label, target := 1, 0.0
+			if i < len(first) {
+				label, target = 0, 1
+			}
+			logOdds := model.Bias
+			for token := range tokens {
+				logOdds += model.Weights[token]
+			}
+			residual := (sigmoid(logOdds) - target) * weights[label]
+			biasGradient += residual
+			for token := range tokens {
+				if _, ok := model.Weights[token]; ok {
+					gradients[token] += residual
+				}
+			}
+		}
+		model.Bias -= discriminatorLearningRate * biasGradient
+		for _, feature := range features {
+			model.Weights[feature] -= discriminatorLearningRate * (gradients[feature] + discriminatorL2*model.Weights[feature])
+		}
+	}
+	for feature, weight := range model.Weights {
+		if weight == 0 {
+			delete(model.Weights, feature)
+		}
+	}
+	return model, nil
+}
+
+// discriminativeTokens returns the up to maxFeatures tokens whose smoothed document frequencies in
+// the texts of the two languages differ most, by the absolute log ratio of the frequencies
+func discriminativeTokens(counts [2]map[string]int, firstTexts, secondTexts, maxFeatures int) []string {
+	type scored struct {
+		token string
+		score float64
+	}
+	candidates := []scored{}
+	seen := make(map[string]bool)
+	for _, languageCounts := range counts {
+		for token := range languageCounts {
+			if seen[token] {
+				continue
+			}
+			seen[token] = true
+			first, second := counts[0][token], counts[1][token]
+			if first+second < 2 {
+				continue
+			}
+			ratio := (float64(first) + 0.5) / (float64(firstTexts) + 1) / ((float64(second) + 0.5) / (float64(secondTexts) + 1))
+			candidates = append(candidates, scored{token: token, score: math.Abs(math.Log(ratio))})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].token < candidates[j].token
+	})
+	if len(candidates) > maxFeatures {
+		candidates = candidates[:maxFeatures]
+	}
+	tokens := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		tokens[i] = candidate.token
+	}
+	return tokens
+}
+
+// ReadDiscriminator decodes a JSON LogisticDiscriminator, like the files of the discriminate command
+func ReadDiscriminator(r io.Reader) (*LogisticDiscriminator, error) {
+	model := &LogisticDiscriminator{}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(model); err != nil {
+		return nil, err
+	}
+	if model.First == "" || model.Second == "" {
+		return nil, fmt.Errorf("discriminator has no pair of languages")
+	}
+	return model, nil
+}
+
+// LoadDiscriminator reads a JSON LogisticDiscriminator file
+func LoadDiscriminator(path string) (*LogisticDiscriminator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	model, err := ReadDiscriminator(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse discriminator %s: %w", path, err)
+	}
+	return model, nil
+}
+
+// DefaultDiscriminators returns the built-in WordDiscriminators of notoriously confusable pairs of
+// languages by their ISO 639-1 codes: Bosnian, Croatian and Serbian in Latin script, Norwegian
+// Bokmål and Nynorsk, and Indonesian and Malay
+func DefaultDiscriminators() map[LanguagePair]Discriminator {
+	// Croatian and Bosnian are written in ijekavian, Serbian mostly in ekavian. Words of both lists
+	// of a pair are no evidence for either language.
+	croatian := NewWords("tko", "što", "tisuća", "tisuću", "kruh", "kolodvor", "vlak", "zrakoplov", "glazba", "sveučilište", "povijest", "kava", "siječanj", "veljača", "ožujak", "travanj", "svibanj", "lipanj", "srpanj", "kolovoz", "rujan", "listopad", "studeni", "prosinac", "mlijeko", "lijepo", "rijeka", "dijete", "vrijeme", "mjesto", "tjedan", "općina", "obitelj", "glede")
+	serbian := NewWords("ko", "šta", "hiljada", "hiljadu", "hleb", "stanica", "voz", "avion", "muzika", "univerzitet", "istorija", "kafa", "januar", "februar", "mart", "april", "maj", "jun", "jul", "avgust", "septembar", "oktobar", "novembar", "decembar", "mleko", "lepo", "reka", "dete", "vreme", "mesto", "nedelja", "opština", "porodica")
+	bosnian := NewWords("ko", "šta", "hiljada", "hiljadu", "hljeb", "stanica", "voz", "avion", "muzika", "univerzitet", "historija", "kahva", "januar", "februar", "mart", "april", "maj", "juni", "juli", "august", "septembar", "oktobar", "novembar", "decembar", "mlijeko", "lijepo", "rijeka", "dijete", "vrijeme", "mjesto", "sedmica", "općina", "porodica", "lahko")
+	bokmal := NewWords("ikke", "jeg", "hva", "hvem", "hvor", "hvordan", "hvorfor", "hun", "noen", "noe", "fra", "mye", "bare", "hvis", "også", "være", "ble", "blir", "kirke", "uke", "hjem", "boken", "sammen")
+	nynorsk := NewWords("ikkje", "eg", "kva", "kven", "kvar", "korleis", "kvifor", "ho", "nokon", "noko", "frå", "mykje", "berre", "dersom", "òg", "vere", "vart", "vert", "kyrkje", "veke", "heim", "boka", "saman")
+	indonesian := NewWords("bisa", "karena", "uang", "kantor", "sepeda", "mobil", "kamar", "mengerti", "gratis", "nomor", "polisi", "kapan", "sih", "dong", "kok", "banget", "nggak", "gak", "aja", "apotek", "handuk", "kulkas", "pemerintah", "sepatu", "jeruk", "ponsel")
+	malay := NewWords("boleh", "kerana", "wang", "pejabat", "basikal", "bilik", "hospital", "faham", "percuma", "nombor", "polis", "bila", "awak", "sahaja", "farmasi", "tuala", "kerajaan", "kasut", "oren", "telefon", "bimbit")
+	return map[LanguagePair]Discriminator{
+		{First: "hr", Second: "sr"}: WordDiscriminator{First: croatian, Second: serbian},
+		{First: "bs", Second: "hr"}: WordDiscriminator{First: bosnian, Second: croatian},
+		{First: "bs", Second: "sr"}: WordDiscriminator{First: bosnian, Second: serbian},
+		{First: "nb", Second: "nn"}: WordDiscriminator{First: bokmal, Second: nynorsk},
+		{First: "id", Second: "ms"}: WordDiscriminator{First: indonesian, Second: malay},
+	}
+}
+
+// discriminate reorders the two closest of sorted results of a prepared text if their confidences
+// are within the DiscriminatorMargin and the Discriminator of their pair prefers the second. An
+// empty text, like the input of a reader, is not discriminated.
+func (d *Detector) discriminate(text string, results []DetectionResult) {
+	if len(d.Discriminators) == 0 || len(results) < 2 || text == "" {
+		return
+	}
+	margin := d.DiscriminatorMargin
+	if margin == 0 {
+		margin = DefaultDiscriminatorMargin
+	}
+	if (results[0].Confidence-results[1].Confidence)/100 >= margin {
+		return
+	}
+	if d.prefersSecond(text, results[0].Name, results[1].Name) {
+		results[0], results[1] = results[1], results[0]
+	}
+}
+
+// prefersSecond tells whether the Discriminator of the pair of the closest and the second closest
+// language of a text prefers the second
+func (d *Detector) prefersSecond(text, closest, second string) bool {
+	if discriminator, ok := d.Discriminators[LanguagePair{First: closest, Second: second}]; ok {
+		probability, ok := discriminator.Discriminate(text)
+		return ok && probability < 0.5
+	}
+	if discriminator, ok := d.Discriminators[LanguagePair{First: second, Second: closest}]; ok {
+		probability, ok := discriminator.Discriminate(text)
+		return ok && probability > 0.5
+	}
+	return false
+}
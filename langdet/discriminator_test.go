@@ -0,0 +1,77 @@
+package langdet_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDiscriminators(t *testing.T) {
+	Convey("Subject: Discriminate pairs of confusable languages", t, func() {
+		// the same profile for both languages, whose confidences are always alike
+		corpus := "jedan dva tri četiri pet šest sedam osam devet deset danas je lijep dan u gradu"
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(corpus, "hr")
+		d.AddLanguageFromText(corpus, "sr")
+		d.MinimumConfidence = 0.1
+
+		Convey("The discriminator of the pair should decide between the closest languages", func() {
+			d.Discriminators = langdet.DefaultDiscriminators()
+			So(d.GetClosestLanguage("tko je to danas u gradu"), ShouldEqual, "hr")
+			So(d.GetClosestLanguage("ko je to danas u gradu"), ShouldEqual, "sr")
+			So(d.GetLanguages("šta je to danas u gradu")[0].Name, ShouldEqual, "sr")
+		})
+		Convey("Pairs should be discriminated in either order", func() {
+			d.Discriminators = map[langdet.LanguagePair]langdet.Discriminator{
+				{First: "sr", Second: "hr"}: langdet.WordDiscriminator{First: langdet.NewWords("ko"), Second: langdet.NewWords("tko")},
+			}
+			So(d.GetClosestLanguage("tko je to danas u gradu"), ShouldEqual, "hr")
+			So(d.GetClosestLanguage("ko je to danas u gradu"), ShouldEqual, "sr")
+		})
+		Convey("Languages beyond the margin should not be discriminated", func() {
+			d.AddLanguageFromText("the quick brown fox jumps over the lazy dog", "en")
+			d.Discriminators = map[langdet.LanguagePair]langdet.Discriminator{
+				{First: "sr", Second: "en"}: langdet.WordDiscriminator{First: langdet.NewWords("ko"), Second: langdet.NewWords("the")},
+			}
+			So(d.GetClosestLanguage("the quick brown fox jumps over ko"), ShouldEqual, "en")
+		})
+		Convey("Texts without features should not be discriminated", func() {
+			probability, ok := langdet.WordDiscriminator{First: langdet.NewWords("tko")}.Discriminate("nothing here")
+			So(ok, ShouldBeFalse)
+			So(probability, ShouldEqual, 0.5)
+		})
+	})
+
+	Convey("Subject: Train a discriminator from labeled texts", t, func() {
+		pair := langdet.LanguagePair{First: "nb", Second: "nn"}
+		bokmal := []string{"jeg vet ikke hva hun vil", "hvorfor kommer hun ikke hjem", "jeg vil bare hjem", "hva gjør du i dag", "hun er ikke her"}
+		nynorsk := []string{"eg veit ikkje kva ho vil", "kvifor kjem ho ikkje heim", "eg vil berre heim", "kva gjer du i dag", "ho er ikkje her"}
+		discriminator, err := langdet.TrainDiscriminator(pair, bokmal, nynorsk, langdet.DiscriminatorOptions{})
+
+		Convey("The n-grams of the languages should be discriminated", func() {
+			So(err, ShouldBeNil)
+			So(discriminator.Pair(), ShouldResemble, pair)
+			probability, ok := discriminator.Discriminate("jeg kommer ikke i dag")
+			So(ok, ShouldBeTrue)
+			So(probability, ShouldBeGreaterThan, 0.5)
+			probability, ok = discriminator.Discriminate("eg kjem ikkje i dag")
+			So(ok, ShouldBeTrue)
+			So(probability, ShouldBeLessThan, 0.5)
+		})
+		Convey("Discriminators should be read back from JSON", func() {
+			data, err := json.Marshal(discriminator)
+			So(err, ShouldBeNil)
+			read, err := langdet.ReadDiscriminator(bytes.NewReader(data))
+			So(err, ShouldBeNil)
+			So(read, ShouldResemble, discriminator)
+			_, err = langdet.ReadDiscriminator(bytes.NewReader([]byte(`{"weights": {}}`)))
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Pairs without texts of both languages should be errors", func() {
+			_, err := langdet.TrainDiscriminator(pair, bokmal, nil, langdet.DiscriminatorOptions{})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
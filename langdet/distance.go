@@ -0,0 +1,200 @@
+package langdet
+
+import (
+	"fmt"
+	"math"
+)
+
+// DistanceFunc compares the input tokens of a text with the profile of a language. It replaces
+// the out-of-place rank distance of a Detector, see Detector.Distance.
+type DistanceFunc interface {
+	// Similarity returns the similarity between 0 and 1 of the tokens of input, a map[token]rank,
+	// with ranks up to maxRank to a profile, and the number of these tokens that are found in
+	// the profile
+	Similarity(input map[string]int, maxRank int, profile map[string]int) (similarity float64, matched int)
+}
+
+// OutOfPlace is the out-of-place rank distance of the n-gram based text categorization, the
+// default DistanceFunc. Every compared token adds the difference of its ranks to the distance, or
+// maxTokenDistance if it is missing in the profile, and the similarity is one minus the distance
+// relative to the maximum possible distance of the compared tokens.
+type OutOfPlace struct{}
+
+// Similarity implements DistanceFunc
+func (OutOfPlace) Similarity(input map[string]int, maxRank int, profile map[string]int) (float64, int) {
+	if maxRank <= 0 {
+		return 0, 0
+	}
+	distance, matched, scored, _ := scoreDistance(input, rankMap(profile), maxTokenDistance, maxRank, 0)
+	if scored == 0 {
+		return 0, 0
+	}
+	return 1 - float64(distance)/float64(scored*maxTokenDistance), matched
+}
+
+// RankWeightFunc returns the weight of the difference of ranks of an input token of a rank,
+// starting at 1, see RankWeighted
+type RankWeightFunc func(rank int) float64
+
+// InverseRankWeight weighs a rank by 1/rank, the frequencies of Zipf's law
+func InverseRankWeight(rank int) float64 {
+	return 1 / float64(rank)
+}
+
+// SqrtRankWeight weighs a rank by 1/√rank, which decays slower than InverseRankWeight
+func SqrtRankWeight(rank int) float64 {
+	return 1 / math.Sqrt(float64(rank))
+}
+
+// LogRankWeight weighs a rank by the logarithm of its inverse, see rankWeight, which decays slowest
+func LogRankWeight(rank int) float64 {
+	return rankWeight(rank)
+}
+
+// RankWeighted is the out-of-place rank distance with the difference of the ranks of every
+// compared token weighted by the rank of the token in the input, so that disagreements on the most
+// frequent n-grams of a text count more than disagreements on its rare ones, which OutOfPlace
+// counts alike. The similarity is one minus the weighted distance relative to the maximum weighted
+// distance of the compared tokens.
+type RankWeighted struct {
+	// Weight returns the weight of an input rank, nil means SqrtRankWeight
+	Weight RankWeightFunc
+}
+
+// Similarity implements DistanceFunc
+func (r RankWeighted) Similarity(input map[string]int, maxRank int, profile map[string]int) (float64, int) {
+	weight := r.Weight
+	if weight == nil {
+		weight = SqrtRankWeight
+	}
+	distance, total, matched := 0.0, 0.0, 0
+	for token, rank := range input {
+		if rank > maxRank || rank < 1 {
+			continue
+		}
+		w := weight(rank)
+		total += w * maxTokenDistance
+		profileRank, ok := profile[token]
+		if !ok || profileRank < 0 {
+			distance += w * maxTokenDistance
+			continue
+		}
+		matched++
+		diff := profileRank - rank
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > maxTokenDistance {
+			diff = maxTokenDistance
+		}
+		distance += w * float64(diff)
+	}
+	if total == 0 {
+		return 0, matched
+	}
+	return 1 - distance/total, matched
+}
+
+// Cosine is the cosine similarity of the frequency vectors of the input and the profile. The
+// frequencies are estimated from the ranks, see rankWeight. Unlike rank distances, it weighs
+// frequent tokens more than rare ones, but it compares every token of the profile, which makes
+// it slower.
+type Cosine struct{}
+
+// Similarity implements DistanceFunc
+func (Cosine) Similarity(input map[string]int, maxRank int, profile map[string]int) (float64, int) {
+	dot, inputNorm, profileNorm, matched := 0.0, 0.0, 0.0, 0
+	for token, rank := range input {
+		if rank > maxRank {
+			continue
+		}
+		weight := rankWeight(rank)
+		inputNorm += weight * weight
+		if profileRank, ok := profile[token]; ok {
+			dot += weight * rankWeight(profileRank)
+			matched++
+		}
+	}
+	for _, rank := range profile {
+		weight := rankWeight(rank)
+		profileNorm += weight * weight
+	}
+	if inputNorm == 0 || profileNorm == 0 {
+		return 0, matched
+	}
+	return dot / math.Sqrt(inputNorm*profileNorm), matched
+}
+
+// Jaccard is the Jaccard index of the sets of the compared input tokens and of the same number
+// of top ranked tokens of the profile. It ignores the ranks within the sets, which makes it fast
+// and robust against noisy rankings of short texts.
+type Jaccard struct{}
+
+// Similarity implements DistanceFunc
+func (Jaccard) Similarity(input map[string]int, maxRank int, profile map[string]int) (float64, int) {
+	compared := 0
+	matched := 0
+	for token, rank := range input {
+		if rank > maxRank {
+			continue
+		}
+		compared++
+		if profileRank, ok := profile[token]; ok && profileRank <= maxRank {
+			matched++
+		}
+	}
+	top := 0
+	for _, rank := range profile {
+		if rank <= maxRank {
+			top++
+		}
+	}
+	if union := compared + top - matched; union > 0 {
+		return float64(matched) / float64(union), matched
+	}
+	return 0, matched
+}
+
+// ParseDistanceFunc returns the DistanceFunc of a name: out-of-place, rank-weighted, cosine or
+// jaccard
+func ParseDistanceFunc(name string) (DistanceFunc, error) {
+	switch name {
+	case "out-of-place":
+		return OutOfPlace{}, nil
+	case "rank-weighted":
+		return RankWeighted{}, nil
+	case "cosine":
+		return Cosine{}, nil
+	case "jaccard":
+		return Jaccard{}, nil
+	}
+	return nil, fmt.Errorf("unknown distance %q, expected out-of-place, rank-weighted, cosine or jaccard", name)
+}
+
+// rankWeight returns an estimated frequency of a token of a rank, the logarithm of the inverse
+// of its rank relative to maxSampleSize. Weights of 1/rank, the frequencies of Zipf's law, let the
+// few top ranked unigrams dominate similarities.
+func rankWeight(rank int) float64 {
+	return math.Log(float64(maxSampleSize+1) / float64(rank))
+}
+
+// score compares an input map[token]rank with a language, taking into account the top maxRank
+// ranks of the input, by the Distance of the detector
+func (d *Detector) score(input map[string]int, maxRank int, language Language) DetectionResult {
+	if _, outOfPlace := d.Distance.(OutOfPlace); d.Distance == nil || outOfPlace {
+		return scoreLanguage(input, maxRank, d.ProfileSize, language)
+	}
+	tag := language.LookupTag()
+	result := DetectionResult{
+		Name:           language.Name,
+		Tag:            tag,
+		ComparedTokens: maxRank,
+		InputTokens:    len(input),
+		Direction:      language.TextDirection(),
+		Group:          language.group(tag),
+	}
+	similarity, matched := d.Distance.Similarity(input, maxRank, language.Profile)
+	result.Confidence = similarity * 100
+	result.MatchedTokens = matched
+	return result
+}
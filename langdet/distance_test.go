@@ -0,0 +1,133 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+// distanceFuncs are the DistanceFuncs of this package by name
+var distanceFuncs = map[string]langdet.DistanceFunc{
+	"OutOfPlace":   langdet.OutOfPlace{},
+	"RankWeighted": langdet.RankWeighted{},
+	"Cosine":       langdet.Cosine{},
+	"Jaccard":      langdet.Jaccard{},
+}
+
+func TestDistanceFuncs(t *testing.T) {
+	Convey("Subject: Pluggable distance functions", t, func() {
+		profile := map[string]int{"a": 1, "b": 2, "c": 3}
+		for name, distance := range distanceFuncs {
+			Convey(name+" should be 1 for the same tokens and 0 for other tokens", func() {
+				similarity, matched := distance.Similarity(profile, 3, profile)
+				So(similarity, ShouldAlmostEqual, 1)
+				So(matched, ShouldEqual, 3)
+				similarity, matched = distance.Similarity(map[string]int{"x": 1, "y": 2}, 2, profile)
+				So(similarity, ShouldAlmostEqual, 0)
+				So(matched, ShouldEqual, 0)
+				similarity, _ = distance.Similarity(map[string]int{"a": 1, "x": 2}, 2, profile)
+				So(similarity, ShouldBeBetween, 0, 1)
+			})
+			Convey(name+" should detect the closest language", func() {
+				s := "Hello I am english text, what is your language? I really dont know you say?"
+				d := langdet.NewDetector()
+				d.AddLanguageFromText(s, "english")
+				d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+				d.Distance = distance
+				results := d.GetLanguages("what is your language")
+				So(results[0].Name, ShouldEqual, "english")
+				So(results[0].Confidence, ShouldBeGreaterThan, results[1].Confidence)
+				So(results[0].MatchedTokens, ShouldBeGreaterThan, 0)
+			})
+		}
+	})
+	Convey("Subject: Rank weighted distance", t, func() {
+		profile := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+		Convey("Disagreements on top ranks should count more", func() {
+			top, _ := langdet.RankWeighted{}.Similarity(map[string]int{"x": 1, "b": 2, "c": 3, "d": 4}, 4, profile)
+			bottom, _ := langdet.RankWeighted{}.Similarity(map[string]int{"a": 1, "b": 2, "c": 3, "x": 4}, 4, profile)
+			So(top, ShouldBeLessThan, bottom)
+			outOfPlaceTop, _ := langdet.OutOfPlace{}.Similarity(map[string]int{"x": 1, "b": 2, "c": 3, "d": 4}, 4, profile)
+			outOfPlaceBottom, _ := langdet.OutOfPlace{}.Similarity(map[string]int{"a": 1, "b": 2, "c": 3, "x": 4}, 4, profile)
+			So(outOfPlaceTop, ShouldEqual, outOfPlaceBottom)
+		})
+		Convey("Weights should be configurable", func() {
+			input := map[string]int{"x": 1, "b": 2, "c": 3, "d": 4}
+			inverse, _ := langdet.RankWeighted{Weight: langdet.InverseRankWeight}.Similarity(input, 4, profile)
+			log, _ := langdet.RankWeighted{Weight: langdet.LogRankWeight}.Similarity(input, 4, profile)
+			So(inverse, ShouldBeLessThan, log)
+			flat, _ := langdet.RankWeighted{Weight: func(int) float64 { return 1 }}.Similarity(input, 4, profile)
+			outOfPlace, _ := langdet.OutOfPlace{}.Similarity(input, 4, profile)
+			So(flat, ShouldAlmostEqual, outOfPlace)
+		})
+		Convey("Distances should be parsed by name", func() {
+			distance, err := langdet.ParseDistanceFunc("rank-weighted")
+			So(err, ShouldBeNil)
+			So(distance, ShouldResemble, langdet.RankWeighted{})
+			_, err = langdet.ParseDistanceFunc("euclidean")
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Subject: Compared profile size", t, func() {
+		s := "Hello I am english text, what is your language? I really dont know you say?"
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(s, "english")
+		full := d.GetLanguages("what is your language")[0]
+		Convey("Tokens beyond the profile size should count as missing", func() {
+			d.ProfileSize = 20
+			small := d.GetLanguages("what is your language")[0]
+			So(small.MatchedTokens, ShouldBeLessThan, full.MatchedTokens)
+			So(small.Confidence, ShouldBeLessThan, full.Confidence)
+		})
+		Convey("Profile sizes beyond the profile should change nothing", func() {
+			d.ProfileSize = langdet.DefaultProfileSize
+			So(d.GetLanguages("what is your language")[0], ShouldResemble, full)
+		})
+	})
+}
+
+func TestDistanceNormalization(t *testing.T) {
+	Convey("Subject: Normalize distances by the scored input tokens", t, func() {
+		Convey("Ranks beyond the input should not inflate the similarity", func() {
+			profile := map[string]int{"a": 1, "b": 2, "c": 3}
+			input := map[string]int{"a": 1, "x": 2}
+			exact, _ := langdet.OutOfPlace{}.Similarity(input, 2, profile)
+			So(exact, ShouldAlmostEqual, 0.5)
+			similarity, _ := langdet.OutOfPlace{}.Similarity(input, langdet.DefaultMaxInputRanks, profile)
+			So(similarity, ShouldAlmostEqual, exact)
+			similarity, matched := langdet.OutOfPlace{}.Similarity(map[string]int{"a": 2}, 1, profile)
+			So(similarity, ShouldEqual, 0)
+			So(matched, ShouldEqual, 0)
+		})
+		Convey("Short inputs should compare all of their tokens in all languages", func() {
+			d := langdet.NewDefaultLanguages()
+			for _, text := range []string{"the house", "das Haus", "la maison", "дом", "البيت", "הבית", "ev"} {
+				for _, result := range d.GetLanguages(text) {
+					So(result.ComparedTokens, ShouldEqual, result.InputTokens)
+					if result.ComparedTokens > 0 && result.Distance > 0 {
+						So(result.Confidence, ShouldBeLessThan, 100)
+					}
+				}
+				wide := langdet.NewDefaultLanguages()
+				wide.MaxInputRanks = langdet.DefaultMaxInputRanks * 10
+				So(wide.GetLanguages(text), ShouldResemble, d.GetLanguages(text))
+			}
+		})
+	})
+}
+
+func BenchmarkDistanceFuncs(b *testing.B) {
+	s := "Hello I am english text, what is your language? I really dont know you say?"
+	d := langdet.NewDetector()
+	d.AddLanguageFromText(s, "english")
+	d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+	d.AddLanguageFromText("Das ist ein deutscher Satz, ich weiss nicht was du sagst", "german")
+	for name, distance := range distanceFuncs {
+		d.Distance = distance
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				d.GetLanguages(s)
+			}
+		})
+	}
+}
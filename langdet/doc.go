@@ -0,0 +1,8 @@
+// Package langdet detects the language of texts by comparing the ranks of their character n-grams
+// with the n-gram profiles of languages.
+//
+// Training and detection are deterministic: they use no randomness, profiles rank tokens of the
+// same count by the token, and the same text detected with the same profiles and options gives
+// the same results. Features that sample texts take a rand.Source, so that callers can fix the
+// seed for reproducible tests and pipelines.
+package langdet
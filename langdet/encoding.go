@@ -0,0 +1,98 @@
+package langdet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is the error of DetectBytes and DecodeBytes for texts that are not valid UTF-8,
+// if the detector has no Charsets
+var ErrInvalidUTF8 = errors.New("text is not valid UTF-8")
+
+// Charset decodes texts of a legacy character set, like ISO-8859-5 or Windows-1251, to UTF-8.
+// The charset package has the common ones.
+type Charset interface {
+	// Name returns the name of the charset, e.g. "windows-1251"
+	Name() string
+	// Decode returns the UTF-8 text of data
+	Decode(data []byte) (string, error)
+}
+
+// DetectBytes returns the closest language of a text like GetClosestLanguage. Texts that are not
+// valid UTF-8 are decoded with the Charsets of the detector first, see DecodeBytes, and are
+// rejected with ErrInvalidUTF8 without them, instead of detecting garbage n-grams.
+func (d *Detector) DetectBytes(data []byte) (string, error) {
+	text, _, err := d.DecodeBytes(data)
+	if err != nil {
+		return "undefined", err
+	}
+	return d.GetClosestLanguage(text), nil
+}
+
+// DecodeBytes returns the UTF-8 text of data and the name of its charset, "utf-8" for valid UTF-8
+// without its byte order mark. A rune that is cut off at the end, e.g. by a size limit, is
+// dropped. Other texts are decoded with every charset of the Charsets of the detector and the
+// decoding whose closest language is the most confident, weighed by its fraction of letters, wins:
+// wrong charsets of single-byte encodings produce rare n-grams and undefined characters. Without Charsets, the error is ErrInvalidUTF8.
+func (d *Detector) DecodeBytes(data []byte) (string, string, error) {
+	if valid := trimPartialRune(data); utf8.Valid(valid) {
+		// some editors start UTF-8 files with a byte order mark
+		return strings.TrimPrefix(string(valid), "\uFEFF"), "utf-8", nil
+	}
+	if len(d.Charsets) == 0 {
+		return "", "", ErrInvalidUTF8
+	}
+	best, bestCharset, bestConfidence := "", "", -1.0
+	var err error
+	for _, charset := range d.Charsets {
+		text, decodeErr := charset.Decode(data)
+		if decodeErr != nil {
+			err = fmt.Errorf("could not decode %s: %w", charset.Name(), decodeErr)
+			continue
+		}
+		// undefined bytes of a charset decode to replacement characters, which are no letters
+		_, confidence, _ := d.GetClosestLanguageWithConfidence(text)
+		if confidence *= letterRatio(text); confidence > bestConfidence {
+			best, bestCharset, bestConfidence = text, charset.Name(), confidence
+		}
+	}
+	if bestCharset == "" {
+		return "", "", err
+	}
+	return best, bestCharset, nil
+}
+
+// letterRatio returns the fraction of letters of the characters of a text that are no spaces or
+// punctuation, 0 if there are none
+func letterRatio(text string) float64 {
+	total, letters := 0, 0
+	for _, r := range text {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			continue
+		}
+		total++
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(letters) / float64(total)
+}
+
+// trimPartialRune returns data without an incomplete UTF-8 sequence at its end
+func trimPartialRune(data []byte) []byte {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax+1; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return data[:i]
+			}
+			break
+		}
+	}
+	return data
+}
@@ -0,0 +1,60 @@
+package langdet_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// latin1 is a langdet.Charset of ISO-8859-1, whose bytes are the code points of their runes
+type latin1 struct{}
+
+func (latin1) Name() string { return "iso-8859-1" }
+
+func (latin1) Decode(data []byte) (string, error) {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes), nil
+}
+
+func TestDetectBytes(t *testing.T) {
+	Convey("Subject: Detect texts of bytes", t, func() {
+		d := langdet.NewDefaultLanguages()
+		german := "Wir sind am Wochenende ans Meer gefahren und das Wetter war sehr sch\xf6n"
+
+		Convey("Valid UTF-8 should be detected like strings", func() {
+			language, err := d.DetectBytes([]byte("\uFEFFWir sind am Wochenende ans Meer gefahren"))
+			So(err, ShouldBeNil)
+			So(language, ShouldEqual, "de")
+			text, charset, err := d.DecodeBytes([]byte("\uFEFFschön"))
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, "schön")
+			So(charset, ShouldEqual, "utf-8")
+		})
+		Convey("A rune cut off at the end should be dropped", func() {
+			text, charset, err := d.DecodeBytes([]byte("schön")[:4])
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, "sch")
+			So(charset, ShouldEqual, "utf-8")
+		})
+		Convey("Invalid UTF-8 should be rejected without charsets", func() {
+			language, err := d.DetectBytes([]byte(german))
+			So(errors.Is(err, langdet.ErrInvalidUTF8), ShouldBeTrue)
+			So(language, ShouldEqual, "undefined")
+		})
+		Convey("Invalid UTF-8 should be decoded with the charsets", func() {
+			d.Charsets = []langdet.Charset{latin1{}}
+			language, err := d.DetectBytes([]byte(german))
+			So(err, ShouldBeNil)
+			So(language, ShouldEqual, "de")
+			text, charset, err := d.DecodeBytes([]byte(german))
+			So(err, ShouldBeNil)
+			So(text, ShouldEndWith, "sehr schön")
+			So(charset, ShouldEqual, "iso-8859-1")
+		})
+	})
+}
@@ -0,0 +1,98 @@
+package langdet
+
+// Scorer scores the languages of a text like Detector.GetLanguages, most confident first,
+// with confidences between 0 and 100
+type Scorer interface {
+	GetLanguages(text string) []DetectionResult
+}
+
+// ScorerFunc adapts a function to the Scorer interface, e.g. for word lists or script rules
+type ScorerFunc func(text string) []DetectionResult
+
+// GetLanguages returns f(text)
+func (f ScorerFunc) GetLanguages(text string) []DetectionResult {
+	return f(text)
+}
+
+// EnsembleMember is a Scorer of an EnsembleDetector with its weight
+type EnsembleMember struct {
+	Scorer Scorer
+	Weight float64
+}
+
+// EnsembleDetector combines the results of several scorers, e.g. detectors of n-grams and word
+// lists, into the weighted mean of their confidences. Ensembles of different methods often beat
+// every single one of them. An EnsembleDetector is a Scorer itself.
+type EnsembleDetector struct {
+	Members           []EnsembleMember
+	MinimumConfidence float32
+}
+
+// NewEnsembleDetector returns an EnsembleDetector of the scorers with equal weights
+func NewEnsembleDetector(scorers ...Scorer) *EnsembleDetector {
+	e := &EnsembleDetector{MinimumConfidence: DefaultMinimumConfidence}
+	for _, scorer := range scorers {
+		e.Members = append(e.Members, EnsembleMember{Scorer: scorer, Weight: 1})
+	}
+	return e
+}
+
+// GetLanguages returns the results of all languages of the members, with the weighted mean of
+// their confidences. Other fields are those of the first member that reports the language.
+func (e *EnsembleDetector) GetLanguages(text string) []DetectionResult {
+	res := []DetectionResult{}
+	index := make(map[string]int)
+	confidences := []float64{}
+	totalWeight := 0.0
+	for _, member := range e.Members {
+		if member.Weight <= 0 {
+			continue
+		}
+		totalWeight += member.Weight
+		for _, result := range member.Scorer.GetLanguages(text) {
+			i, ok := index[result.Name]
+			if !ok {
+				i = len(res)
+				index[result.Name] = i
+				res = append(res, result)
+				confidences = append(confidences, 0)
+			}
+			confidences[i] += member.Weight * result.Confidence
+		}
+	}
+	for i := range res {
+		res[i].Confidence = confidences[i] / totalWeight
+	}
+	sortResults(res)
+	groupResults(res)
+	return res
+}
+
+// GetClosestLanguage returns the name of the most confident language of the ensemble, if its
+// confidence is at least MinimumConfidence, or undefined otherwise
+func (e *EnsembleDetector) GetClosestLanguage(text string) string {
+	results := e.GetLanguages(text)
+	if len(results) == 0 || results[0].Confidence < asPercent(validMinimumConfidence(e.MinimumConfidence)) {
+		return "undefined"
+	}
+	return results[0].Name
+}
+
+// LearnWeights sets the weight of every member to its accuracy on labeled sample texts, given by
+// language name, so that members that detect the samples better get more weight
+func (e *EnsembleDetector) LearnWeights(samples map[string][]string) {
+	for i, member := range e.Members {
+		correct, total := 0, 0
+		for name, texts := range samples {
+			for _, text := range texts {
+				total++
+				if results := member.Scorer.GetLanguages(text); len(results) > 0 && results[0].Name == name {
+					correct++
+				}
+			}
+		}
+		if total > 0 {
+			e.Members[i].Weight = float64(correct) / float64(total)
+		}
+	}
+}
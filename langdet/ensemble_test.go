@@ -0,0 +1,48 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestEnsembleDetector(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Combine several scorers", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+		alwaysFrench := langdet.ScorerFunc(func(string) []langdet.DetectionResult {
+			return []langdet.DetectionResult{{Name: "french", Confidence: 100}}
+		})
+		e := langdet.NewEnsembleDetector(&d, alwaysFrench)
+
+		Convey("Confidences should be the weighted means of the members", func() {
+			text := "what is your language"
+			single := d.GetLanguages(text)
+			results := e.GetLanguages(text)
+			So(len(results), ShouldEqual, 2)
+			for _, result := range results {
+				if result.Name == "english" {
+					So(result.Confidence, ShouldAlmostEqual, single[0].Confidence/2)
+				}
+			}
+			e.Members[1].Weight = 0
+			So(e.GetLanguages(text), ShouldResemble, single)
+		})
+		Convey("Learned weights should follow the accuracy of the members", func() {
+			e.LearnWeights(map[string][]string{"english": {en, "what is your language"}, "french": {fr}})
+			So(e.Members[0].Weight, ShouldEqual, 1)
+			So(e.Members[1].Weight, ShouldAlmostEqual, 1.0/3)
+			So(e.GetLanguages(en)[0].Name, ShouldEqual, "english")
+		})
+		Convey("The closest language should respect the minimum confidence", func() {
+			e.MinimumConfidence = 0.01
+			So(e.GetClosestLanguage(fr), ShouldEqual, "french")
+			e.MinimumConfidence = 1
+			So(e.GetClosestLanguage(en), ShouldEqual, "undefined")
+			So(langdet.NewEnsembleDetector().GetClosestLanguage(en), ShouldEqual, "undefined")
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package langdet
+
+// DetectOptions restrict the candidate languages of a single detection
+type DetectOptions struct {
+	// Allow are the names of the candidate languages, all languages of the detector if empty
+	Allow []string
+	// Deny are the names of languages that are never detected
+	Deny []string
+}
+
+// DetectExcluding returns the name of the closest language to the text like GetClosestLanguage,
+// but never one of the excluded languages. This detects the source language of a text that is
+// contaminated with known target language, e.g. UI strings around user content for translation.
+func (d *Detector) DetectExcluding(text string, exclude ...string) string {
+	rest := d.restricted(DetectOptions{Deny: exclude})
+	return rest.GetClosestLanguage(text)
+}
+
+// GetClosestLanguageIn returns the name of the closest of the allowed languages to the text like
+// GetClosestLanguage, for callers that know the few languages a text can be written in
+func (d *Detector) GetClosestLanguageIn(text string, allowed ...string) string {
+	rest := d.restricted(DetectOptions{Allow: allowed})
+	return rest.GetClosestLanguage(text)
+}
+
+// DetectWithOptions returns the closest candidate language of the options to the text, its
+// confidence and whether the detection is reliable like GetClosestLanguageWithConfidence. Only
+// the candidates are scored, so it is cheaper than a detection with all languages.
+func (d *Detector) DetectWithOptions(text string, options DetectOptions) (string, float64, bool) {
+	rest := d.restricted(options)
+	return rest.GetClosestLanguageWithConfidence(text)
+}
+
+// restricted returns a copy of this detector with the candidate languages of options only
+func (d *Detector) restricted(options DetectOptions) Detector {
+	allowed := make(map[string]bool, len(options.Allow))
+	for _, name := range options.Allow {
+		allowed[name] = true
+	}
+	denied := make(map[string]bool, len(options.Deny))
+	for _, name := range options.Deny {
+		denied[name] = true
+	}
+	languages := []Language{}
+	for _, language := range d.snapshot() {
+		if (len(allowed) == 0 || allowed[language.Name]) && !denied[language.Name] {
+			languages = append(languages, language)
+		}
+	}
+	rest := *d
+	rest.Languages = &languages
+	rest.mu = nil
+	rest.Cache = nil
+	return rest
+}
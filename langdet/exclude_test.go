@@ -0,0 +1,40 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDetectExcluding(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	de := "Ich spreche deutsch und du? Ich weiss nicht was du sagst."
+	Convey("Subject: Detect a language excluding others", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+		d.AddLanguageFromText(de, "german")
+		d.MinimumConfidence = 0.01
+
+		Convey("Excluded languages should never be detected", func() {
+			So(d.GetClosestLanguage(en+fr), ShouldEqual, "english")
+			So(d.DetectExcluding(en+fr, "english"), ShouldEqual, "french")
+			So(d.DetectExcluding(fr, "english", "unknown"), ShouldEqual, "french")
+		})
+		Convey("Only allowed languages should be detected", func() {
+			So(d.GetClosestLanguageIn(en+fr, "french", "german"), ShouldEqual, "french")
+			So(d.GetClosestLanguageIn(en, "english"), ShouldEqual, "english")
+			So(d.GetClosestLanguageIn(en, "unknown"), ShouldEqual, "undefined")
+			name, confidence, reliable := d.DetectWithOptions(en+fr, langdet.DetectOptions{Allow: []string{"english", "french"}, Deny: []string{"english"}})
+			So(name, ShouldEqual, "french")
+			So(confidence, ShouldBeGreaterThan, 0)
+			So(reliable, ShouldBeTrue)
+		})
+		Convey("The detector itself should keep all languages", func() {
+			d.DetectExcluding(en, "english")
+			d.GetClosestLanguageIn(en, "french")
+			So(len(*d.Languages), ShouldEqual, 3)
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package langdet
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NGramContribution is the contribution of an input n-gram to the distance of a text to a language,
+// see Explain
+type NGramContribution struct {
+	NGram string
+	// InputRank is the rank of the n-gram in the text
+	InputRank int
+	// ProfileRank is the rank of the n-gram in the profile of the language, 0 if it is missing
+	ProfileRank int
+	// Diff is the out-of-place distance of the n-gram, the maximum distance if it is missing or
+	// ranked beyond the ProfileSize of the detector
+	Diff int
+}
+
+// Explanation explains the comparison of a text with a language, see Explain
+type Explanation struct {
+	// Result is the result of the language like in GetLanguages, without the GroupConfidence,
+	// which depends on the results of the other languages
+	Result DetectionResult
+	// Compared is false if the text is written in another script than the language, which
+	// detections don't compare with it
+	Compared bool
+	// Distance is the out-of-place distance of the text, the sum of the Diffs of the NGrams
+	Distance int
+	// MaxDistance is the distance of a text whose n-grams are all missing from the profile
+	MaxDistance int
+	// NGrams are the compared n-grams of the text, highest Diff first, then by InputRank
+	NGrams []NGramContribution
+	// Missing are the compared n-grams of the text that are missing from the profile, by InputRank
+	Missing []string
+}
+
+// Explain returns the contributions of the n-grams of a text to its distance to the named language,
+// for debugging misdetections: the rank of every compared input n-gram in the text and in the
+// profile and its out-of-place distance. The Result is the result of the language like in
+// GetLanguages, whose confidence may differ from the distance with a custom Distance or WordWeight.
+func (d *Detector) Explain(text, lang string) (Explanation, error) {
+	language := d.language(lang)
+	if language == nil {
+		return Explanation{}, fmt.Errorf("unknown language %q", lang)
+	}
+	lookupMap := d.singleLookupMap(text, *language)
+	script := d.inputScript(lookupMap)
+	explanation := Explanation{Compared: script == "" || language.writtenIn(script)}
+	maxRank := d.maxInputRanks(len(lookupMap))
+	explanation.Result = d.score(lookupMap, maxRank, *language)
+	d.LanguageOptions(language.Name).weigh(&explanation.Result)
+	if !explanation.Compared {
+		explanation.Result.Confidence = 0
+	}
+
+	profile := rankMap(language.Profile)
+	for token, inputRank := range lookupMap {
+		if inputRank > maxRank {
+			continue
+		}
+		contribution := NGramContribution{NGram: token, InputRank: inputRank, Diff: maxTokenDistance}
+		if rank, ok := profile.rank(token); ok && (d.ProfileSize <= 0 || rank <= d.ProfileSize) {
+			contribution.ProfileRank = rank
+			if diff := rank - inputRank; diff < 0 && diff > -maxTokenDistance {
+				contribution.Diff = -diff
+			} else if diff >= 0 && diff < maxTokenDistance {
+				contribution.Diff = diff
+			}
+		} else {
+			explanation.Missing = append(explanation.Missing, token)
+		}
+		explanation.Distance += contribution.Diff
+		explanation.NGrams = append(explanation.NGrams, contribution)
+	}
+	explanation.MaxDistance = len(explanation.NGrams) * maxTokenDistance
+	sort.Slice(explanation.NGrams, func(i, j int) bool {
+		a, b := explanation.NGrams[i], explanation.NGrams[j]
+		if a.Diff != b.Diff {
+			return a.Diff > b.Diff
+		}
+		if a.InputRank != b.InputRank {
+			return a.InputRank < b.InputRank
+		}
+		return a.NGram < b.NGram
+	})
+	sort.Slice(explanation.Missing, func(i, j int) bool {
+		a, b := explanation.Missing[i], explanation.Missing[j]
+		if lookupMap[a] != lookupMap[b] {
+			return lookupMap[a] < lookupMap[b]
+		}
+		return a < b
+	})
+	return explanation, nil
+}
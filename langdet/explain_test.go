@@ -0,0 +1,47 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExplain(t *testing.T) {
+	Convey("Subject: Explain the distance of a text to a language", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "english")
+		d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+		text := "what is your language, I dont know"
+
+		Convey("The contributions should add up to the distance of the result", func() {
+			explanation, err := d.Explain(text, "english")
+			So(err, ShouldBeNil)
+			So(explanation.Compared, ShouldBeTrue)
+			result := d.GetLanguages(text)[0]
+			result.GroupConfidence = 0
+			So(explanation.Result, ShouldResemble, result)
+			So(explanation.Distance, ShouldEqual, explanation.Result.Distance)
+			So(explanation.NGrams, ShouldHaveLength, explanation.Result.ComparedTokens)
+			sum := 0
+			for i, ngram := range explanation.NGrams {
+				sum += ngram.Diff
+				if i > 0 {
+					So(ngram.Diff, ShouldBeLessThanOrEqualTo, explanation.NGrams[i-1].Diff)
+				}
+			}
+			So(sum, ShouldEqual, explanation.Distance)
+		})
+		Convey("N-grams missing from the profile should be listed", func() {
+			explanation, err := d.Explain("zzyzx", "english")
+			So(err, ShouldBeNil)
+			So(explanation.Missing, ShouldContain, "zz")
+			So(explanation.NGrams[0].ProfileRank, ShouldEqual, 0)
+			So(explanation.NGrams[0].Diff, ShouldEqual, explanation.MaxDistance/len(explanation.NGrams))
+		})
+		Convey("Unknown languages should be errors", func() {
+			_, err := d.Explain(text, "german")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
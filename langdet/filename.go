@@ -0,0 +1,94 @@
+package langdet
+
+import (
+	"path"
+	"strings"
+	"unicode"
+)
+
+// filenameScripts map scripts to the only default language written in them
+var filenameScripts = []struct {
+	script   *unicode.RangeTable
+	language LanguageCode
+}{
+	{unicode.Arabic, Arabic},
+	{unicode.Hebrew, Hebrew},
+	{unicode.Cyrillic, Russian},
+}
+
+// filenameLetters are the letters that set a latin language apart from English
+var filenameLetters = map[LanguageCode]string{
+	French:  "àâæçéèêëîïôœùûüÿ",
+	German:  "äöüß",
+	Turkish: "çğıöşü",
+}
+
+// filenameWords are frequent words of filenames
+var filenameWords = map[LanguageCode][]string{
+	English: {"the", "and", "of", "for", "to", "with", "report", "notes", "meeting", "invoice", "final", "draft", "copy", "new", "photos", "holiday"},
+	French:  {"le", "la", "les", "de", "des", "du", "et", "pour", "avec", "rapport", "réunion", "facture", "copie", "nouveau", "vacances"},
+	German:  {"der", "die", "das", "und", "von", "für", "mit", "bericht", "besprechung", "rechnung", "kopie", "neu", "urlaub", "entwurf"},
+	Turkish: {"ve", "bir", "ile", "için", "rapor", "toplantı", "fatura", "kopya", "yeni", "tatil", "taslak", "notlar"},
+}
+
+// DetectLanguageOfFilename guesses the language of a filename, which is usually too short for
+// the n-gram detection. It combines the script of the name, letters specific to a language and
+// lists of frequent words, and only knows the default languages. The extension is ignored.
+// It returns undefined if the name gives no or contradicting evidence, e.g. for numbers or
+// a name of a single word.
+func DetectLanguageOfFilename(filename string) string {
+	name := strings.ToLower(path.Base(strings.ReplaceAll(filename, "\\", "/")))
+	name = strings.TrimSuffix(name, path.Ext(name))
+
+	letters := 0
+	inScript := make(map[LanguageCode]int)
+	scores := make(map[LanguageCode]int)
+	for _, r := range name {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range filenameScripts {
+			if unicode.Is(s.script, r) {
+				inScript[s.language]++
+			}
+		}
+		for language, specific := range filenameLetters {
+			if strings.ContainsRune(specific, r) {
+				scores[language]++
+			}
+		}
+	}
+	if letters == 0 {
+		return "undefined"
+	}
+	for language, count := range inScript {
+		if count*2 > letters {
+			return language.String()
+		}
+	}
+
+	words := strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, word := range words {
+		for language, frequent := range filenameWords {
+			for _, w := range frequent {
+				if w == word {
+					scores[language] += 2
+				}
+			}
+		}
+	}
+
+	best, bestScore, tie := LanguageCode(""), 0, false
+	for language, score := range scores {
+		if score > bestScore {
+			best, bestScore, tie = language, score, false
+		} else if score == bestScore {
+			tie = true
+		}
+	}
+	if bestScore < 2 || tie {
+		return "undefined"
+	}
+	return best.String()
+}
@@ -0,0 +1,28 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDetectLanguageOfFilename(t *testing.T) {
+	Convey("Subject: Detect the language of filenames", t, func() {
+		Convey("Names in a non-latin script should be detected by the script", func() {
+			So(langdet.DetectLanguageOfFilename("Отчёт за март.docx"), ShouldEqual, "ru")
+			So(langdet.DetectLanguageOfFilename("/home/user/דוח שנתי.pdf"), ShouldEqual, "he")
+			So(langdet.DetectLanguageOfFilename("تقرير 2020.txt"), ShouldEqual, "ar")
+		})
+		Convey("Latin names should be detected by specific letters and frequent words", func() {
+			So(langdet.DetectLanguageOfFilename("Rechnung für März.pdf"), ShouldEqual, "de")
+			So(langdet.DetectLanguageOfFilename("rapport de la réunion.odt"), ShouldEqual, "fr")
+			So(langdet.DetectLanguageOfFilename("toplantı notları.txt"), ShouldEqual, "tr")
+			So(langdet.DetectLanguageOfFilename("notes_of_the_meeting.md"), ShouldEqual, "en")
+		})
+		Convey("Names without evidence should be undefined", func() {
+			So(langdet.DetectLanguageOfFilename("IMG_20200101.jpg"), ShouldEqual, "undefined")
+			So(langdet.DetectLanguageOfFilename("2020-01-01.csv"), ShouldEqual, "undefined")
+			So(langdet.DetectLanguageOfFilename("schedule.xlsx"), ShouldEqual, "undefined")
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package langdet
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/imankulov/go-lang-detector/langdet/ngram"
+)
+
+// fingerprintDepth is the n-gram depth of Fingerprint. It is fixed, unlike the depths of profiles,
+// so that fingerprints stay the same when profiles are retrained.
+const fingerprintDepth = 2
+
+// Fingerprint returns a stable 64 bit hash of the normalized token profile of a text: the counts
+// of the n-grams of its lowercased words, see WordTokenizer. Texts that only differ in whitespace,
+// case, punctuation, numbers, URLs or format controls have the same fingerprint, which makes it a
+// key for caches and deduplication of texts by their linguistic content. Texts without words
+// have the fingerprint 0. Fingerprints don't depend on profiles or detector options.
+func Fingerprint(text string) uint64 {
+	words := WordTokenizer{Lowercase: true}.Tokenize(strings.Map(ngram.StripFormatControl, text))
+	if len(words) == 0 {
+		return 0
+	}
+	occurences := make(map[string]int)
+	updateOccurences(occurences, strings.Join(words, " "), fingerprintDepth, false)
+	tokens := make([]string, 0, len(occurences))
+	for token := range occurences {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	hash := fnv.New64a()
+	count := make([]byte, binary.MaxVarintLen64)
+	for _, token := range tokens {
+		hash.Write([]byte(token))
+		// the count also terminates the token, n-grams never contain the bytes of small varints
+		hash.Write(count[:binary.PutUvarint(count, uint64(occurences[token]))])
+	}
+	return hash.Sum64()
+}
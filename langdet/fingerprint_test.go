@@ -0,0 +1,32 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFingerprint(t *testing.T) {
+	Convey("Subject: Fingerprints of the linguistic content of texts", t, func() {
+		text := "Hello, how are you doing today?"
+
+		Convey("Texts with the same words should have the same fingerprint", func() {
+			fingerprint := langdet.Fingerprint(text)
+			So(fingerprint, ShouldNotEqual, 0)
+			So(langdet.Fingerprint("  hello how ARE you\n\tdoing today!!"), ShouldEqual, fingerprint)
+			So(langdet.Fingerprint("Hello, how are you doing today? 42 https://example.com"), ShouldEqual, fingerprint)
+			So(langdet.Fingerprint("Hello, how are\u200Byou doing to\u2060day?"), ShouldEqual, fingerprint)
+		})
+		Convey("Texts with other words should have other fingerprints", func() {
+			So(langdet.Fingerprint("Hello, how are you doing tomorrow?"), ShouldNotEqual, langdet.Fingerprint(text))
+			So(langdet.Fingerprint("Hello, how are you you doing today?"), ShouldNotEqual, langdet.Fingerprint(text))
+			So(langdet.Fingerprint("Привет, как дела?"), ShouldNotEqual, langdet.Fingerprint(text))
+		})
+		Convey("Fingerprints should be stable across processes and releases", func() {
+			So(langdet.Fingerprint(text), ShouldEqual, uint64(11820178511955833015))
+			So(langdet.Fingerprint(""), ShouldEqual, 0)
+			So(langdet.Fingerprint("... 123 !!!"), ShouldEqual, 0)
+		})
+	})
+}
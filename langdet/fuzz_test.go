@@ -0,0 +1,74 @@
+package langdet_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"unicode/utf8"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// FuzzParseLanguage checks that parsed profiles are valid and can be detected with
+func FuzzParseLanguage(f *testing.F) {
+	f.Add([]byte(`{"Name":"xx","Profile":{"_a":1,"ab":2,"b":3}}`))
+	f.Add([]byte(`{"Name":"xx","Profile":{"a":1,"b":9223372036854775807},"Size":-1}`))
+	f.Add([]byte(`{"Name":"xx","Profile":{"a":1,"a":2},"Depth":1,"FormatVersion":2}`))
+	f.Add([]byte("{\"Name\":\"xx\",\"Profile\":{\"a\xff\":1},\"WordProfile\":{\"a\":0}}"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		language, err := langdet.ParseLanguage(data)
+		if err != nil {
+			return
+		}
+		if err := language.Validate(); err != nil {
+			t.Fatalf("parsed profile is invalid: %v", err)
+		}
+		d := langdet.NewDetector()
+		d.AddLanguage(language)
+		d.GetLanguages("the quick brown fox jumps over the lazy dog")
+		d.GetClosestLanguage("a")
+	})
+}
+
+// FuzzLoadLanguages checks that profile files either load as valid languages or fail to load
+func FuzzLoadLanguages(f *testing.F) {
+	f.Add([]byte(`{"Name":"xx","Profile":{"_a":1,"ab":2,"b":3}}`))
+	f.Add([]byte(`{"Name":"xx","Profile":{"a":1,"b":2,"a":3}}`))
+	f.Add([]byte(`{"Name":"xx","Profile":{"a":1},"FormatVersion":1,"Size":0}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		d := langdet.NewDetector()
+		if err := d.LoadLanguagesFromFS(fstest.MapFS{"profiles/xx.json": {Data: data}}, "profiles"); err != nil {
+			return
+		}
+		for _, language := range d.Snapshot() {
+			if err := language.Validate(); err != nil {
+				t.Fatalf("loaded profile is invalid: %v", err)
+			}
+		}
+		d.GetLanguages("the quick brown fox jumps over the lazy dog")
+	})
+}
+
+// FuzzCreateOccurenceMap checks that the n-grams of any text are valid UTF-8 and ranked from 1
+func FuzzCreateOccurenceMap(f *testing.F) {
+	f.Add("the quick brown fox", 3)
+	f.Add("日本語のテキスト", 2)
+	f.Add("\xff\xfe a‍b", 5)
+	f.Fuzz(func(t *testing.T, text string, depth int) {
+		depth = depth%8 + 1
+		if depth < 1 {
+			depth += 8
+		}
+		occurences := langdet.CreateOccurenceMap(text, depth)
+		ranks := langdet.CreateRankLookupMap(occurences)
+		seen := make(map[int]bool, len(ranks))
+		for token, rank := range ranks {
+			if !utf8.ValidString(token) || token == "" {
+				t.Fatalf("invalid n-gram %q", token)
+			}
+			if rank < 1 || rank > len(ranks) || seen[rank] {
+				t.Fatalf("invalid rank %d of %q", rank, token)
+			}
+			seen[rank] = true
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package langdet
+
+import (
+	"reflect"
+	"sync"
+	"unicode/utf8"
+)
+
+// minFuzzyLength is the minimum number of runes of the n-grams that are matched with a substituted
+// character by the fuzzy detection, shorter n-grams would match too many n-grams of any language
+const minFuzzyLength = 4
+
+// fuzzyPenalty is added to the rank of a profile n-gram matched with a substituted character, so
+// that such a match counts less than an exact match of the same rank
+const fuzzyPenalty = maxTokenDistance / 2
+
+// fuzzyWildcard replaces the substituted character in the keys of fuzzyNeighbors
+const fuzzyWildcard = "\x00"
+
+// fuzzyNeighbors maps the n-grams of a profile with a wildcard at one of their letters to the
+// lowest rank of the n-grams they match
+type fuzzyNeighbors map[string]int
+
+// newFuzzyNeighbors returns the fuzzyNeighbors of a profile
+func newFuzzyNeighbors(profile map[string]int) fuzzyNeighbors {
+	neighbors := make(fuzzyNeighbors, len(profile)*3)
+	for token, rank := range profile {
+		forEachWildcard(token, func(key string) {
+			if known, ok := neighbors[key]; !ok || rank < known {
+				neighbors[key] = rank
+			}
+		})
+	}
+	return neighbors
+}
+
+// forEachWildcard calls f with the variants of a token of at least minFuzzyLength runes with one
+// of its letters replaced by fuzzyWildcard. The padding of tokens is not replaced.
+func forEachWildcard(token string, f func(key string)) {
+	if utf8.RuneCountInString(token) < minFuzzyLength {
+		return
+	}
+	for i, r := range token {
+		if r == '_' {
+			continue
+		}
+		f(token[:i] + fuzzyWildcard + token[i+utf8.RuneLen(r):])
+	}
+}
+
+// fuzzyRanker is the ranker of a profile that ranks n-grams missing from the profile like the
+// closest n-gram of the profile that differs in a single letter, plus the fuzzyPenalty. Like
+// scoreDistance, it compares the tokens up to the rank profileSize only, all if it is 0.
+type fuzzyRanker struct {
+	profile     rankMap
+	neighbors   fuzzyNeighbors
+	profileSize int
+}
+
+func (r fuzzyRanker) rank(token string) (int, bool) {
+	if rank, ok := r.profile[token]; ok && r.compared(rank) {
+		return rank, true
+	}
+	best, found := 0, false
+	forEachWildcard(token, func(key string) {
+		if rank, ok := r.neighbors[key]; ok && r.compared(rank) && (!found || rank < best) {
+			best, found = rank, true
+		}
+	})
+	if !found {
+		return 0, false
+	}
+	return best + fuzzyPenalty, true
+}
+
+func (r fuzzyRanker) size() int {
+	return comparedProfileSize(len(r.profile), r.profileSize)
+}
+
+// compared tells whether a rank of the profile is compared
+func (r fuzzyRanker) compared(rank int) bool {
+	return r.profileSize <= 0 || rank <= r.profileSize
+}
+
+// fuzzyIndexes caches the fuzzyNeighbors of the profiles of a detector by the identity of their
+// maps, it is purged when the languages change
+type fuzzyIndexes struct {
+	mu        sync.Mutex
+	neighbors map[uintptr]fuzzyNeighbors
+}
+
+func newFuzzyIndexes() *fuzzyIndexes {
+	return &fuzzyIndexes{neighbors: make(map[uintptr]fuzzyNeighbors)}
+}
+
+// get returns the fuzzyNeighbors of a profile, which are built on first use. Without indexes, as
+// for detectors that are not created by a constructor, they are built every time.
+func (f *fuzzyIndexes) get(profile map[string]int) fuzzyNeighbors {
+	if f == nil {
+		return newFuzzyNeighbors(profile)
+	}
+	key := reflect.ValueOf(profile).Pointer()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	neighbors, ok := f.neighbors[key]
+	if !ok {
+		neighbors = newFuzzyNeighbors(profile)
+		f.neighbors[key] = neighbors
+	}
+	return neighbors
+}
+
+// purge removes all fuzzyNeighbors, it is safe to call on nil
+func (f *fuzzyIndexes) purge() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	f.neighbors = make(map[uintptr]fuzzyNeighbors)
+	f.mu.Unlock()
+}
+
+// GetLanguagesFuzzy returns the DetectionResult of all languages like GetLanguages, but tolerates
+// single character substitutions in the n-grams of the text, as they are common in the output of
+// OCR and speech recognition: input n-grams of at least four characters that are missing from a
+// profile are ranked like the closest profile n-gram that differs in one letter, with a penalty.
+// The neighbors of a profile are indexed on first use, which takes some time and memory.
+// The results of languages compared with a custom Distance are those of GetLanguages.
+func (d *Detector) GetLanguagesFuzzy(text string) []DetectionResult {
+	lookupMap := CreateRankLookupMap(CreateOccurenceMap(d.prepare(text), d.inputDepth(d.snapshot())))
+	scorer := d.newCandidateScorer(lookupMap)
+	res := []DetectionResult{}
+	for _, language := range d.snapshot() {
+		if _, outOfPlace := d.Distance.(OutOfPlace); (d.Distance != nil && !outOfPlace) || !scorer.comparable(language) {
+			res = append(res, scorer.score(language))
+			continue
+		}
+		input := scorer.lookups.forDepth(language.Depth)
+		tag := language.LookupTag()
+		result := DetectionResult{Name: language.Name, Tag: tag, Direction: language.TextDirection(), Group: language.group(tag)}
+		ranker := fuzzyRanker{profile: rankMap(language.Profile), neighbors: d.fuzzy.get(language.Profile), profileSize: d.ProfileSize}
+		// the ranker compares the ranks up to the ProfileSize, with the penalty they may exceed it
+		result = scoreRanks(input, d.maxInputRanks(len(input)), 0, result, ranker)
+		scorer.options[language.Name].weigh(&result)
+		res = append(res, result)
+	}
+	sortResults(res)
+	groupResults(res)
+	return res
+}
+
+// DetectFuzzy returns the closest language of a noisy text like GetClosestLanguageWithConfidence,
+// with the results of GetLanguagesFuzzy. The detection is reliable if the confidence reaches the
+// minimum confidence of the language and enough input tokens match, see MinMatchedTokens.
+func (d *Detector) DetectFuzzy(text string) (string, float64, bool) {
+	results := d.GetLanguagesFuzzy(text)
+	if len(results) == 0 {
+		return "undefined", 0, false
+	}
+	closest := results[0]
+	reliable := closest.Confidence >= asPercent(d.minimumConfidence(closest.Name)) && d.matchedEnough(closest)
+	return closest.Name, closest.Confidence / 100, reliable
+}
@@ -0,0 +1,98 @@
+package langdet_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// noisyTexts are texts of the default languages for the fuzzy detection tests
+var noisyTexts = map[string][]string{
+	"en": {
+		"The weather was cold and the children stayed inside to read their books.",
+		"Please send me the report before the meeting on Thursday afternoon.",
+		"We walked along the river until the sun went down behind the hills.",
+		"The company announced that it will open a new office next year.",
+		"She said that the train would arrive later than expected tonight.",
+	},
+	"fr": {
+		"Le temps était froid et les enfants sont restés à la maison pour lire.",
+		"Merci de m'envoyer le rapport avant la réunion de jeudi après-midi.",
+		"Nous avons marché le long de la rivière jusqu'au coucher du soleil.",
+		"L'entreprise a annoncé qu'elle ouvrira un nouveau bureau l'année prochaine.",
+		"Elle a dit que le train arriverait plus tard que prévu ce soir.",
+	},
+	"de": {
+		"Das Wetter war kalt und die Kinder blieben drinnen, um ihre Bücher zu lesen.",
+		"Bitte schicken Sie mir den Bericht vor der Besprechung am Donnerstag.",
+		"Wir sind am Fluss entlang gegangen, bis die Sonne hinter den Hügeln unterging.",
+		"Das Unternehmen hat angekündigt, im nächsten Jahr ein neues Büro zu eröffnen.",
+		"Sie sagte, dass der Zug heute Abend später als erwartet ankommen würde.",
+	},
+	"tr": {
+		"Hava soğuktu ve çocuklar kitaplarını okumak için evde kaldılar.",
+		"Lütfen raporu perşembe öğleden sonraki toplantıdan önce bana gönderin.",
+		"Güneş tepelerin arkasında batana kadar nehir boyunca yürüdük.",
+		"Şirket gelecek yıl yeni bir ofis açacağını duyurdu.",
+		"Trenin bu akşam beklenenden daha geç geleceğini söyledi.",
+	},
+}
+
+// corrupt replaces a fraction of the letters of a text with random lowercase letters, like the
+// character errors of OCR
+func corrupt(text string, rate float64, random *rand.Rand) string {
+	runes := []rune(text)
+	for i, r := range runes {
+		if unicode.IsLetter(r) && random.Float64() < rate {
+			runes[i] = rune('a' + random.Intn(26))
+		}
+	}
+	return string(runes)
+}
+
+func TestDetectFuzzy(t *testing.T) {
+	Convey("Subject: Detect noisy texts", t, func() {
+		d := langdet.NewDefaultLanguages()
+
+		Convey("Fuzzy detections should be more accurate on corrupted texts", func() {
+			random := rand.New(rand.NewSource(1))
+			exact, fuzzy, exactReliable, fuzzyReliable := 0, 0, 0, 0
+			for round := 0; round < 5; round++ {
+				for _, lang := range []string{"de", "en", "fr", "tr"} {
+					for _, text := range noisyTexts[lang] {
+						// the first words of the text, with 30% of the letters substituted
+						words := strings.Fields(text)[:4]
+						noisy := corrupt(strings.Join(words, " "), 0.3, random)
+						if d.GetLanguages(noisy)[0].Name == lang {
+							exact++
+						}
+						if name, _, reliable := d.GetClosestLanguageWithConfidence(noisy); name == lang && reliable {
+							exactReliable++
+						}
+						if d.GetLanguagesFuzzy(noisy)[0].Name == lang {
+							fuzzy++
+						}
+						if name, _, reliable := d.DetectFuzzy(noisy); name == lang && reliable {
+							fuzzyReliable++
+						}
+					}
+				}
+			}
+			So(fuzzy, ShouldBeGreaterThanOrEqualTo, exact)
+			So(fuzzyReliable, ShouldBeGreaterThan, exactReliable)
+		})
+		Convey("N-grams with a substituted letter should match", func() {
+			en := langdet.NewDetector()
+			en.AddLanguage(langdet.Language{Name: "en", Profile: map[string]int{"_the": 1, "the_": 2}})
+			So(en.GetLanguages("thx")[0].MatchedTokens, ShouldEqual, 0)
+			So(en.GetLanguagesFuzzy("thx")[0].MatchedTokens, ShouldEqual, 2)
+			So(en.GetLanguagesFuzzy("thx")[0].Confidence, ShouldBeGreaterThan, en.GetLanguages("thx")[0].Confidence)
+			en.ProfileSize = 1
+			So(en.GetLanguagesFuzzy("thx")[0].MatchedTokens, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,83 @@
+//go:build ignore
+
+// gen_defaults trains the embedded default languages from the texts of the corpus directory, one
+// <code>.txt file of real text per language. The corpus is not part of the repository: the embedded
+// profiles are trained from 600 KB of translated user interface strings and manual pages per
+// language, and Wikipedia abstracts extracted with the langdet command work as well.
+// Run it with go generate after changing the texts or the analysis.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+func main() {
+	languages := []langdet.Language{}
+	for _, code := range langdet.DefaultLanguageCodes {
+		text, err := os.ReadFile(filepath.Join("corpus", code.String()+".txt"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		languages = append(languages, langdet.Analyze(string(text), code.String()))
+	}
+	languagesJSON, err := json.Marshal(languages)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("default_languages.json", languagesJSON, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	// the profile sets of the profiles subpackages are subsets of the default languages
+	for set, codes := range profileSets {
+		subset := []langdet.Language{}
+		for _, language := range languages {
+			for _, code := range codes {
+				if language.Name == code {
+					subset = append(subset, language)
+				}
+			}
+		}
+		subsetJSON, err := json.Marshal(subset)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join("profiles", set, "languages.json"), subsetJSON, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// the transliterated variants are trained from the transliterated texts of their language
+	transliterated := []langdet.Language{}
+	for _, name := range transliteratedNames {
+		code, _, _ := strings.Cut(name, "-")
+		text, err := os.ReadFile(filepath.Join("corpus", code+".txt"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		transliterated = append(transliterated, langdet.Analyze(langdet.Transliterations[name].Transliterate(string(text)), name))
+	}
+	transliteratedJSON, err := json.Marshal(transliterated)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("transliterated_languages.json", transliteratedJSON, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// profileSets are the codes of the default languages of the profiles subpackages by their name
+var profileSets = map[string][]string{
+	"european":      {"de", "en", "fr", "ru", "tr"},
+	"middleeastern": {"ar", "he"},
+}
+
+// transliteratedNames are the embedded transliterated variants of default languages, see
+// langdet.TransliteratedLanguages
+var transliteratedNames = []string{"ru-Latn"}
@@ -0,0 +1,70 @@
+package langdet
+
+import "math"
+
+// The ranks of a profile are used as a language model following Zipf's law: the probability
+// of the token of rank r of a profile with n tokens is 1/(r*H(n)), with the harmonic number H(n).
+// Tokens that are not part of the profile are treated as tokens of rank 2n+2.
+// Surprisals are summed up as integers of micro nats, so that sums don't depend on the order
+// of the tokens.
+
+// surprisalScale is the number of surprisal units per nat
+const surprisalScale = 1e6
+
+// tokenSurprisal returns the surprisal of a token of rank, or of an unknown token if rank is 0,
+// in a profile with profileSize tokens, without the constant ln(H(n))
+func tokenSurprisal(rank, profileSize int) int64 {
+	if rank <= 0 {
+		return unknownSurprisal(profileSize)
+	}
+	return int64(math.Log(float64(rank)) * surprisalScale)
+}
+
+// unknownSurprisal returns the surprisal of a token unknown to a profile with profileSize tokens,
+// without the constant ln(H(n))
+func unknownSurprisal(profileSize int) int64 {
+	return int64(math.Log(float64(2*profileSize+2)) * surprisalScale)
+}
+
+// gibberishScore returns the mean surprisal of compared tokens relative to the surprisal of
+// unknown tokens, so that 0 means only tokens of rank 1 and 1 means only unknown tokens
+func gibberishScore(surprisal int64, compared, profileSize int) float64 {
+	if compared == 0 {
+		return 0
+	}
+	return float64(surprisal) / float64(compared) / float64(unknownSurprisal(profileSize))
+}
+
+// Perplexity returns the perplexity of the text under the profile of the language, used as a
+// language model of n-grams. It is the exponential of the mean surprisal of the n-grams of the
+// text, weighted by their occurrences, so that texts that fit the language well have a low
+// perplexity. It returns 0 for texts without n-grams and +Inf for languages without a profile.
+func (l Language) Perplexity(text string) float64 {
+	size := len(l.Profile)
+	if size == 0 {
+		return math.Inf(1)
+	}
+	var surprisal int64
+	tokens := 0
+	depth := l.Depth
+	if depth == 0 {
+		depth = nDepth
+	}
+	for token, count := range CreateOccurenceMap(text, depth) {
+		surprisal += int64(count) * tokenSurprisal(l.Profile[token], size)
+		tokens += count
+	}
+	if tokens == 0 {
+		return 0
+	}
+	return math.Exp(float64(surprisal)/float64(tokens)/surprisalScale + math.Log(harmonic(size)))
+}
+
+// harmonic returns the n-th harmonic number
+func harmonic(n int) float64 {
+	h := 0.0
+	for i := n; i > 0; i-- {
+		h += 1 / float64(i)
+	}
+	return h
+}
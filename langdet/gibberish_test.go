@@ -0,0 +1,51 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestGibberishScore(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	Convey("Subject: Score how badly texts fit the closest language", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+
+		Convey("Keyboard mash should score higher than text of the language", func() {
+			text := d.GetLanguages("what is your language, you say")[0]
+			mash := d.GetLanguages("asdkjh qwpoeiru zxmcnv lkjhasd")[0]
+			So(text.GibberishScore, ShouldBeLessThan, mash.GibberishScore)
+			So(text.GibberishScore, ShouldBeGreaterThan, 0)
+			So(mash.GibberishScore, ShouldBeLessThanOrEqualTo, 1)
+		})
+		Convey("Tokens unknown to the language should score 1", func() {
+			So(d.GetLanguages("zzqqvv")[0].GibberishScore, ShouldEqual, 1)
+			So(d.GetLanguages("")[0].GibberishScore, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestPerplexity(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Perplexity of texts under a language model", t, func() {
+		english := langdet.Analyze(en, "english")
+		french := langdet.Analyze(fr, "french")
+
+		Convey("Texts should fit their own language better", func() {
+			text := "what is your language, you say"
+			So(english.Perplexity(text), ShouldBeLessThan, french.Perplexity(text))
+			So(english.Perplexity(text), ShouldBeLessThan, english.Perplexity("zzqqvv xxkkjj"))
+			So(english.Perplexity(text), ShouldBeGreaterThan, 1)
+		})
+		Convey("Perplexity should not depend on the order of the n-grams", func() {
+			So(english.Perplexity(fr), ShouldEqual, english.Perplexity(fr))
+		})
+		Convey("Empty texts and profiles should be handled", func() {
+			So(english.Perplexity(""), ShouldEqual, 0)
+			So(math.IsInf(langdet.Language{}.Perplexity(en), 1), ShouldBeTrue)
+		})
+	})
+}
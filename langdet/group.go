@@ -0,0 +1,100 @@
+package langdet
+
+// languageGroups are the groups of common languages by their ISO 639-1 codes, see LanguageGroup
+var languageGroups = map[string]string{
+	"ca": "Romance", "es": "Romance", "fr": "Romance", "gl": "Romance", "it": "Romance", "pt": "Romance", "ro": "Romance",
+	"af": "Germanic", "da": "Germanic", "de": "Germanic", "en": "Germanic", "is": "Germanic", "nb": "Germanic",
+	"nl": "Germanic", "nn": "Germanic", "no": "Germanic", "sv": "Germanic", "yi": "Germanic",
+	"be": "Slavic", "bg": "Slavic", "bs": "Slavic", "cs": "Slavic", "hr": "Slavic", "mk": "Slavic", "pl": "Slavic",
+	"ru": "Slavic", "sk": "Slavic", "sl": "Slavic", "sr": "Slavic", "uk": "Slavic",
+	"lt": "Baltic", "lv": "Baltic",
+	"cy": "Celtic", "ga": "Celtic", "gd": "Celtic", "br": "Celtic",
+	"et": "Uralic", "fi": "Uralic", "hu": "Uralic",
+	"az": "Turkic", "kk": "Turkic", "ky": "Turkic", "tk": "Turkic", "tr": "Turkic", "tt": "Turkic", "uz": "Turkic",
+	"am": "Semitic", "ar": "Semitic", "he": "Semitic", "mt": "Semitic",
+	"bn": "Indo-Aryan", "gu": "Indo-Aryan", "hi": "Indo-Aryan", "mr": "Indo-Aryan", "ne": "Indo-Aryan", "pa": "Indo-Aryan", "ur": "Indo-Aryan",
+	"fa": "Iranian", "ku": "Iranian", "ps": "Iranian", "tg": "Iranian",
+	"kn": "Dravidian", "ml": "Dravidian", "ta": "Dravidian", "te": "Dravidian",
+	"id": "Malayic", "ms": "Malayic",
+}
+
+// LanguageGroup returns the Group of the language, or looks up the group of common languages by
+// its Tag or Name if it is not set. It returns an empty string for languages of no known group.
+func (l Language) LanguageGroup() string {
+	return l.group(l.LookupTag())
+}
+
+// group is LanguageGroup with the looked up tag of the language
+func (l Language) group(tag LanguageTag) string {
+	if l.Group != "" {
+		return l.Group
+	}
+	return languageGroups[tag.ISO6391]
+}
+
+// GroupResult is the result of GetClosestGroup
+type GroupResult struct {
+	// Group is the group of the closest language, the closest language itself if it has no group,
+	// or undefined if the detector has no languages
+	Group string
+	// Languages are the languages of the group whose confidence is within the minimum margin of
+	// the closest language, closest first, see MinMargin
+	Languages []string
+	// Confidence is the GroupConfidence of the closest language, between 0 and 1
+	Confidence float64
+	// Reliable tells whether the closest language is confident enough, like the result of
+	// GetClosestLanguageWithConfidence, and no language of another group is within the margin
+	Reliable bool
+}
+
+// GetClosestGroup returns the group of the closest language to the text, with its languages that
+// are too close to tell apart, so that callers can fall back to a decision at the level of the
+// group when closely related languages score alike, e.g. "Romance" with "es" and "pt". Languages
+// declare their group by their Group, see LanguageGroup.
+func (d *Detector) GetClosestGroup(text string) GroupResult {
+	text = d.prepare(text)
+	lookupMap := CreateRankLookupMap(CreateOccurenceMap(text, d.inputDepth(d.snapshot())))
+	results := d.closestFromInput(lookupMap, d.wordLookup(text))
+	if len(results) == 0 {
+		return GroupResult{Group: "undefined"}
+	}
+	closest := results[0]
+	group := GroupResult{Group: closest.Group, Confidence: closest.GroupConfidence / 100}
+	if group.Group == "" {
+		group.Group = closest.Name
+	}
+	minimum := d.MinMargin
+	if minimum == 0 {
+		minimum = DefaultMinMargin
+	}
+	group.Reliable = d.reliable(lookupMap, closest)
+	for i, result := range results {
+		if i > 0 && (closest.Confidence-result.Confidence)/100 >= minimum {
+			break
+		}
+		if i == 0 || (closest.Group != "" && result.Group == closest.Group) {
+			group.Languages = append(group.Languages, result.Name)
+		} else {
+			group.Reliable = false
+		}
+	}
+	return group
+}
+
+// groupResults sets the GroupConfidence of sorted results, the confidence of the closest language
+// of their Group
+func groupResults(results []DetectionResult) {
+	confidences := make(map[string]float64)
+	for i := range results {
+		result := &results[i]
+		if result.Group == "" {
+			result.GroupConfidence = result.Confidence
+			continue
+		}
+		// the results are sorted, the first result of a group is its closest language
+		if _, ok := confidences[result.Group]; !ok {
+			confidences[result.Group] = result.Confidence
+		}
+		result.GroupConfidence = confidences[result.Group]
+	}
+}
@@ -0,0 +1,58 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLanguageGroups(t *testing.T) {
+	Convey("Subject: Aggregate the results of languages by their group", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hola, me llamo Juan y no sé qué idioma hablas. ¿Dónde está la estación de tren?", "es")
+		d.AddLanguageFromText("Olá, meu nome é João e não sei que língua você fala. Onde fica a estação de trem?", "pt")
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "en")
+
+		Convey("Common languages should have a group unless they declare one", func() {
+			So(langdet.Language{Name: "es"}.LanguageGroup(), ShouldEqual, "Romance")
+			So(langdet.Language{Name: "Portuguese"}.LanguageGroup(), ShouldEqual, "Romance")
+			So(langdet.Language{Name: "es", Group: "Ibero-Romance"}.LanguageGroup(), ShouldEqual, "Ibero-Romance")
+			So(langdet.Language{Name: "xx"}.LanguageGroup(), ShouldEqual, "")
+		})
+		Convey("Results should have the confidence of the closest language of their group", func() {
+			results := d.GetLanguages("não sei qué idioma")
+			So(results[0].Group, ShouldEqual, "Romance")
+			for _, result := range results {
+				switch result.Group {
+				case "Romance":
+					So(result.GroupConfidence, ShouldEqual, results[0].Confidence)
+				default:
+					So(result.GroupConfidence, ShouldEqual, result.Confidence)
+				}
+			}
+		})
+		Convey("Languages of the closest group within the margin should be returned together", func() {
+			d.MinMargin = 1
+			group := d.GetClosestGroup("não sei qué idioma")
+			So(group.Group, ShouldEqual, "Romance")
+			So(group.Languages, ShouldHaveLength, 2)
+			So(group.Languages, ShouldContain, "es")
+			So(group.Languages, ShouldContain, "pt")
+			// english is within the margin as well
+			So(group.Reliable, ShouldBeFalse)
+
+			d.MinMargin = -1
+			group = d.GetClosestGroup("Onde fica a estação de trem?")
+			So(group, ShouldResemble, langdet.GroupResult{Group: "Romance", Languages: []string{"pt"}, Confidence: group.Confidence, Reliable: true})
+			So(group.Confidence, ShouldBeGreaterThan, 0)
+		})
+		Convey("Languages without a group should be their own group", func() {
+			d.AddLanguageFromText("Zzyx qwop blarg frobnicate wibble zzyx qwop", "xx")
+			d.MinMargin = -1
+			So(d.GetClosestGroup("zzyx qwop blarg").Group, ShouldEqual, "xx")
+			empty := langdet.NewDetector()
+			So(empty.GetClosestGroup("anything").Group, ShouldEqual, "undefined")
+		})
+	})
+}
@@ -0,0 +1,88 @@
+// Package grpcserver serves a langdet.Detector as the langdet.v1.Detector gRPC service of the
+// langdetpb package.
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/langdetpb"
+	"google.golang.org/grpc"
+)
+
+// Server implements the langdet.v1.Detector service with a shared detector, which is safe for
+// concurrent use
+type Server struct {
+	langdetpb.UnimplementedDetectorServer
+	Detector *langdet.Detector
+}
+
+// Register registers the service of a detector with a gRPC server
+func Register(server grpc.ServiceRegistrar, detector *langdet.Detector) {
+	langdetpb.RegisterDetectorServer(server, &Server{Detector: detector})
+}
+
+// Detect implements langdetpb.DetectorServer
+func (s *Server) Detect(ctx context.Context, request *langdetpb.DetectRequest) (*langdetpb.DetectResponse, error) {
+	text := request.GetText()
+	name, confidence, reliable := s.Detector.GetClosestLanguageWithConfidence(text)
+	if !reliable {
+		name = "undefined"
+	}
+	response := &langdetpb.DetectResponse{
+		Language:    name,
+		DisplayName: langdet.DefaultMeta.DisplayName(name, request.GetLocale()),
+		Confidence:  confidence,
+		Reliable:    reliable,
+	}
+	if k := request.GetTopK(); k != 0 {
+		if k < 0 {
+			k = 0
+		}
+		top := s.Detector.TopK(text, int(k))
+		response.Ambiguous = top.Ambiguous
+		for _, result := range top.Results {
+			response.Results = append(response.Results, detectionResult(result))
+		}
+	}
+	if request.GetSegments() {
+		for _, segment := range s.Detector.DetectSegments(text) {
+			response.Segments = append(response.Segments, &langdetpb.Segment{
+				Text:       segment.Text,
+				Start:      int32(segment.Start),
+				End:        int32(segment.End),
+				Language:   segment.Language,
+				Confidence: segment.Confidence,
+			})
+		}
+	}
+	return response, nil
+}
+
+// ListLanguages implements langdetpb.DetectorServer
+func (s *Server) ListLanguages(ctx context.Context, request *langdetpb.ListLanguagesRequest) (*langdetpb.ListLanguagesResponse, error) {
+	response := &langdetpb.ListLanguagesResponse{}
+	for _, language := range s.Detector.Snapshot() {
+		response.Languages = append(response.Languages, &langdetpb.Language{
+			Name:        language.Name,
+			Tag:         language.LookupTag().String(),
+			DisplayName: langdet.DefaultMeta.DisplayName(language.Name, request.GetLocale()),
+			Direction:   language.TextDirection(),
+		})
+	}
+	return response, nil
+}
+
+// detectionResult returns the message of a DetectionResult
+func detectionResult(result langdet.DetectionResult) *langdetpb.DetectionResult {
+	return &langdetpb.DetectionResult{
+		Name:           result.Name,
+		Tag:            result.Tag.String(),
+		Confidence:     result.Confidence,
+		Distance:       int32(result.Distance),
+		MatchedTokens:  int32(result.MatchedTokens),
+		ComparedTokens: int32(result.ComparedTokens),
+		InputTokens:    int32(result.InputTokens),
+		Direction:      result.Direction,
+	}
+}
@@ -0,0 +1,60 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/grpcserver"
+	"github.com/imankulov/go-lang-detector/langdet/langdetpb"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServer(t *testing.T) {
+	Convey("Subject: Serve a detector as a gRPC service", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say? ", "en")
+		d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "fr")
+
+		listener := bufconn.Listen(1 << 20)
+		server := grpc.NewServer()
+		grpcserver.Register(server, &d)
+		go server.Serve(listener)
+		defer server.Stop()
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		So(err, ShouldBeNil)
+		defer conn.Close()
+		client := langdetpb.NewDetectorClient(conn)
+
+		Convey("Detect should return the closest language with the top results and segments", func() {
+			response, err := client.Detect(context.Background(), &langdetpb.DetectRequest{
+				Text: "what is your language? Je ne sais pas ce que tu dis.", TopK: 1, Segments: true, Locale: "de",
+			})
+			So(err, ShouldBeNil)
+			So(response.GetResults(), ShouldHaveLength, 1)
+			So(response.GetSegments(), ShouldHaveLength, 2)
+			So(response.GetSegments()[0].GetLanguage(), ShouldEqual, "en")
+			So(response.GetSegments()[1].GetLanguage(), ShouldEqual, "fr")
+
+			response, err = client.Detect(context.Background(), &langdetpb.DetectRequest{Text: "what is your language", TopK: -1, Locale: "de"})
+			So(err, ShouldBeNil)
+			So(response.GetLanguage(), ShouldEqual, "en")
+			So(response.GetDisplayName(), ShouldEqual, "Englisch")
+			So(response.GetReliable(), ShouldBeTrue)
+			So(response.GetResults(), ShouldHaveLength, 2)
+			So(response.GetResults()[0].GetTag(), ShouldEqual, "en")
+		})
+		Convey("ListLanguages should list the languages", func() {
+			response, err := client.ListLanguages(context.Background(), &langdetpb.ListLanguagesRequest{})
+			So(err, ShouldBeNil)
+			So(response.GetLanguages(), ShouldHaveLength, 2)
+			So(response.GetLanguages()[1].GetDisplayName(), ShouldEqual, "French")
+		})
+	})
+}
@@ -0,0 +1,100 @@
+package langdet
+
+import (
+	"context"
+	"io/fs"
+	"path"
+)
+
+// LanguageSource lists and loads the profiles of languages one by one, e.g. from network storage,
+// see LoadLanguagesIncrementally
+type LanguageSource interface {
+	// Profiles returns the names of the profiles of the source
+	Profiles(ctx context.Context) ([]string, error)
+	// Load returns the language of a profile of the source
+	Load(ctx context.Context, profile string) (Language, error)
+}
+
+// FSSource is the LanguageSource of the json or binary profile files of the directory Dir of FS,
+// whose profiles are the paths of the files
+type FSSource struct {
+	FS  fs.FS
+	Dir string
+}
+
+// Profiles implements LanguageSource
+func (s FSSource) Profiles(ctx context.Context) ([]string, error) {
+	entries, err := fs.ReadDir(s.FS, s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, path.Join(s.Dir, entry.Name()))
+		}
+	}
+	return names, nil
+}
+
+// Load implements LanguageSource
+func (s FSSource) Load(ctx context.Context, profile string) (Language, error) {
+	if err := ctx.Err(); err != nil {
+		return Language{}, err
+	}
+	return decodeLanguageFile(s.FS, profile)
+}
+
+// LoadProgress reports the progress of LoadLanguagesIncrementally after every batch
+type LoadProgress struct {
+	// Loaded is the number of profiles loaded so far, Total the number of profiles of the source
+	Loaded, Total int
+	// Languages are the names of the languages of the batch, which are detected from now on
+	Languages []string
+}
+
+// LoadLanguagesIncrementally loads the languages of a source in batches of batchSize profiles, for
+// detectors backed by slow storage. The languages of every batch are added to this detector as
+// soon as the batch is loaded, replacing the languages of their names, so that detections use them
+// while the rest is still loading, and progress, if not nil, is called. It stops at the first
+// error or when ctx is done, the languages of the batches loaded so far are kept.
+func (d *Detector) LoadLanguagesIncrementally(ctx context.Context, source LanguageSource, batchSize int, progress func(LoadProgress)) error {
+	profiles, err := source.Profiles(ctx)
+	if err != nil {
+		return err
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	for start := 0; start < len(profiles); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := start + batchSize
+		if end > len(profiles) {
+			end = len(profiles)
+		}
+		batch := make([]Language, end-start)
+		errs := make([]error, len(batch))
+		concurrently(len(batch), maxLoadWorkers, func(i int) {
+			batch[i], errs[i] = source.Load(ctx, profiles[start+i])
+		})
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+		names := make([]string, len(batch))
+		for i := range batch {
+			batch[i].infer()
+			names[i] = batch[i].Name
+		}
+		d.update(func(languages []Language) []Language {
+			return swapLanguages(languages, batch, nil)
+		})
+		if progress != nil {
+			progress(LoadProgress{Loaded: end, Total: len(profiles), Languages: names})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package langdet_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// blockingSource is a LanguageSource whose profiles of blocked names are only loaded once release
+// is closed
+type blockingSource struct {
+	languages map[string]langdet.Language
+	order     []string
+	blocked   string
+	release   chan struct{}
+}
+
+func (s blockingSource) Profiles(ctx context.Context) ([]string, error) {
+	return s.order, nil
+}
+
+func (s blockingSource) Load(ctx context.Context, profile string) (langdet.Language, error) {
+	if profile == s.blocked {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			return langdet.Language{}, ctx.Err()
+		}
+	}
+	language, ok := s.languages[profile]
+	if !ok {
+		return language, errors.New("no profile " + profile)
+	}
+	return language, nil
+}
+
+func TestLoadLanguagesIncrementally(t *testing.T) {
+	Convey("Subject: Load the languages of slow storage incrementally", t, func() {
+		source := blockingSource{
+			languages: map[string]langdet.Language{
+				"en": langdet.Analyze("Hello I am english text, what is your language?", "english"),
+				"fr": langdet.Analyze("Je parles français et toi? Je ne sais pas ce que tu dis.", "french"),
+				"de": langdet.Analyze("Ich spreche Deutsch und du? Ich weiss nicht was du sagst.", "german"),
+			},
+			order:   []string{"en", "fr", "de"},
+			blocked: "de",
+			release: make(chan struct{}),
+		}
+
+		Convey("Loaded batches should be detected while the rest is loading", func() {
+			d := langdet.NewDetector()
+			progress := []langdet.LoadProgress{}
+			err := d.LoadLanguagesIncrementally(context.Background(), source, 2, func(p langdet.LoadProgress) {
+				progress = append(progress, p)
+				if p.Loaded == 2 {
+					So(d.ListLanguages(), ShouldResemble, []string{"english", "french"})
+					So(d.GetClosestLanguage("Je ne sais pas ce que tu dis"), ShouldEqual, "french")
+					close(source.release)
+				}
+			})
+			So(err, ShouldBeNil)
+			So(progress, ShouldResemble, []langdet.LoadProgress{
+				{Loaded: 2, Total: 3, Languages: []string{"english", "french"}},
+				{Loaded: 3, Total: 3, Languages: []string{"german"}},
+			})
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french", "german"})
+		})
+		Convey("Canceled loads should keep the loaded batches", func() {
+			d := langdet.NewDetector()
+			ctx, cancel := context.WithCancel(context.Background())
+			err := d.LoadLanguagesIncrementally(ctx, source, 1, func(p langdet.LoadProgress) {
+				if p.Loaded == 2 {
+					cancel()
+				}
+			})
+			So(err, ShouldEqual, context.Canceled)
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french"})
+		})
+		Convey("Files of a directory should be a source", func() {
+			data, err := json.Marshal(source.languages["en"])
+			So(err, ShouldBeNil)
+			fsys := fstest.MapFS{"profiles/en.json": {Data: data}, "profiles/broken.json": {Data: []byte("{")}}
+			d := langdet.NewDetector()
+			err = d.LoadLanguagesIncrementally(context.Background(), langdet.FSSource{FS: fsys, Dir: "profiles"}, 1, nil)
+			So(err, ShouldNotBeNil)
+			So(d.ListLanguages(), ShouldBeEmpty)
+			delete(fsys, "profiles/broken.json")
+			So(d.LoadLanguagesIncrementally(context.Background(), langdet.FSSource{FS: fsys, Dir: "profiles"}, 1, nil), ShouldBeNil)
+			So(d.ListLanguages(), ShouldResemble, []string{"english"})
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package langdet
+
+import (
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// Prune removes the tokens ranked above maxRank from the profile of the language, and their Counts,
+// and returns the number of removed tokens. Trained profiles keep rare tokens beyond the size they
+// are compared with, which only bloat the profile files. A maxRank of 0 prunes to the Size of the
+// language. Counts of unranked tokens are removed as well, so that Update ranks them as unseen.
+func (l *Language) Prune(maxRank int) int {
+	if maxRank <= 0 {
+		maxRank = l.Size
+	}
+	if maxRank <= 0 {
+		return 0
+	}
+	removed := 0
+	for token, rank := range l.Profile {
+		if rank > maxRank {
+			delete(l.Profile, token)
+			removed++
+		}
+	}
+	for token := range l.Counts {
+		if _, ok := l.Profile[token]; !ok {
+			delete(l.Counts, token)
+		}
+	}
+	if l.Size == 0 || l.Size > maxRank {
+		l.Size = maxRank
+	}
+	return removed
+}
+
+// ProfileStats are statistics of the profile of a language, to diagnose profiles, see Stats
+type ProfileStats struct {
+	// Tokens is the number of ranked tokens
+	Tokens int
+	// MaxRank is the highest rank of the profile
+	MaxRank int
+	// Lengths are the numbers of tokens by their number of letters
+	Lengths map[int]int
+	// Top are the top ranked tokens with their ranks as Occurrence, best first
+	Top []Token
+	// Entropy is the entropy in bits of the frequencies of the tokens, from the Counts of the
+	// language or estimated from the ranks. Profiles of low entropy are dominated by few tokens.
+	Entropy float64
+}
+
+// Stats returns the statistics of the profile of the language with its top ranked tokens
+func (l Language) Stats(top int) ProfileStats {
+	stats := ProfileStats{Tokens: len(l.Profile), Lengths: make(map[int]int)}
+	tokens := make([]Token, 0, len(l.Profile))
+	weights := make([]float64, 0, len(l.Profile))
+	for token, rank := range l.Profile {
+		tokens = append(tokens, Token{Key: token, Occurrence: rank})
+		stats.Lengths[utf8.RuneCountInString(token)]++
+		if rank > stats.MaxRank {
+			stats.MaxRank = rank
+		}
+		if count, ok := l.Counts[token]; ok {
+			weights = append(weights, float64(count))
+		} else {
+			weights = append(weights, rankWeight(rank))
+		}
+	}
+	sort.Sort(ByOccurrence(tokens))
+	if top > len(tokens) {
+		top = len(tokens)
+	}
+	if top > 0 {
+		stats.Top = tokens[:top]
+	}
+	stats.Entropy = entropy(weights)
+	return stats
+}
+
+// entropy returns the entropy in bits of the distribution of the weights
+func entropy(weights []float64) float64 {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	result := 0.0
+	for _, weight := range weights {
+		if weight > 0 {
+			p := weight / total
+			result -= p * math.Log2(p)
+		}
+	}
+	return result
+}
+
+// ProfileOverlap returns the fraction of the tokens of the top ranks of a that are ranked within
+// the top ranks of b, all ranks if ranks is 0. Confusable languages have profiles of a high overlap.
+func ProfileOverlap(a, b Language, ranks int) float64 {
+	compared, shared := 0, 0
+	for token, rank := range a.Profile {
+		if ranks > 0 && rank > ranks {
+			continue
+		}
+		compared++
+		if other, ok := b.Profile[token]; ok && (ranks <= 0 || other <= ranks) {
+			shared++
+		}
+	}
+	if compared == 0 {
+		return 0
+	}
+	return float64(shared) / float64(compared)
+}
@@ -0,0 +1,49 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInspect(t *testing.T) {
+	Convey("Subject: Prune and inspect profiles", t, func() {
+		language := langdet.Language{
+			Name:    "xx",
+			Profile: map[string]int{"a": 1, "b": 2, "ab": 3, "c": 4},
+			Counts:  map[string]int{"a": 8, "b": 4, "ab": 2, "c": 1, "d": 1},
+			Size:    3,
+		}
+
+		Convey("Pruning should remove the tokens beyond the rank and unranked counts", func() {
+			So(language.Prune(2), ShouldEqual, 2)
+			So(language.Profile, ShouldResemble, map[string]int{"a": 1, "b": 2})
+			So(language.Counts, ShouldResemble, map[string]int{"a": 8, "b": 4})
+			So(language.Size, ShouldEqual, 2)
+		})
+		Convey("Pruning to 0 should keep the trained size", func() {
+			So(language.Prune(0), ShouldEqual, 1)
+			So(language.Profile, ShouldHaveLength, 3)
+			So(language.Counts, ShouldNotContainKey, "d")
+		})
+		Convey("Stats should count the tokens and list the top ones", func() {
+			stats := language.Stats(2)
+			So(stats.Tokens, ShouldEqual, 4)
+			So(stats.MaxRank, ShouldEqual, 4)
+			So(stats.Lengths, ShouldResemble, map[int]int{1: 3, 2: 1})
+			So(stats.Top, ShouldResemble, []langdet.Token{{Key: "a", Occurrence: 1}, {Key: "b", Occurrence: 2}})
+			So(stats.Entropy, ShouldBeBetween, 0, 2)
+			uniform := langdet.Language{Profile: language.Profile, Counts: map[string]int{"a": 1, "b": 1, "ab": 1, "c": 1}}
+			So(uniform.Stats(0).Entropy, ShouldAlmostEqual, 2)
+			So(uniform.Stats(0).Top, ShouldBeEmpty)
+		})
+		Convey("Overlaps should be the shared fraction of the top ranks", func() {
+			other := langdet.Language{Profile: map[string]int{"b": 1, "a": 5, "z": 2}}
+			So(langdet.ProfileOverlap(language, other, 2), ShouldAlmostEqual, 0.5)
+			So(langdet.ProfileOverlap(language, other, 0), ShouldAlmostEqual, 0.5)
+			So(langdet.ProfileOverlap(language, language, 0), ShouldAlmostEqual, 1)
+			So(langdet.ProfileOverlap(langdet.Language{}, language, 0), ShouldEqual, 0)
+		})
+	})
+}
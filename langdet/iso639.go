@@ -0,0 +1,176 @@
+package langdet
+
+import "strings"
+
+// LanguageTag identifies a language by its ISO 639 codes, so that results are interoperable with
+// BCP 47 consumers, whatever the names of the profiles are
+type LanguageTag struct {
+	// ISO6391 is the two letter ISO 639-1 code, if the language has one
+	ISO6391 string `json:",omitempty"`
+	// ISO6393 is the three letter ISO 639-3 code
+	ISO6393 string `json:",omitempty"`
+	// DisplayName is the english name of the language
+	DisplayName string `json:",omitempty"`
+}
+
+// String returns the BCP 47 primary language subtag of the tag, the ISO 639-1 code if there is
+// one, the ISO 639-3 code otherwise, or "und" for an unknown language
+func (t LanguageTag) String() string {
+	switch {
+	case t.ISO6391 != "":
+		return t.ISO6391
+	case t.ISO6393 != "":
+		return t.ISO6393
+	}
+	return "und"
+}
+
+// LookupTag returns the Tag of the language, or looks it up by the Name of the language
+// if it is not set
+func (l Language) LookupTag() LanguageTag {
+	if l.Tag != (LanguageTag{}) {
+		return l.Tag
+	}
+	tag, _ := LookupLanguageTag(l.Name)
+	return tag
+}
+
+// LookupLanguageTag returns the tag of a language by its ISO 639-1, ISO 639-2 or ISO 639-3 code,
+// its english name or a BCP 47 tag like "en-US", case-insensitively. Languages of DefaultMeta
+// take precedence over the built-in list of common languages.
+func LookupLanguageTag(name string) (LanguageTag, bool) {
+	name = strings.TrimSpace(name)
+	if meta, ok := DefaultMeta.Get(name); ok && (meta.ISO6391 != "" || meta.ISO6393 != "") {
+		return LanguageTag{ISO6391: meta.ISO6391, ISO6393: meta.ISO6393, DisplayName: meta.DisplayName}, true
+	}
+	key := strings.ToLower(name)
+	if alias, ok := iso639Aliases[key]; ok {
+		key = alias
+	}
+	if i, ok := iso639Index[key]; ok {
+		return iso639Tags[i], true
+	}
+	if i := strings.IndexAny(name, "-_"); i > 0 {
+		return LookupLanguageTag(name[:i])
+	}
+	return LanguageTag{}, false
+}
+
+// NormalizeLanguageName returns the BCP 47 primary language subtag of a language name like
+// "eng" or "English", or the name itself if the language is unknown
+func NormalizeLanguageName(name string) string {
+	if tag, ok := LookupLanguageTag(name); ok {
+		return tag.String()
+	}
+	return name
+}
+
+// iso639Tags are the tags of common languages
+var iso639Tags = []LanguageTag{
+	{ISO6391: "af", ISO6393: "afr", DisplayName: "Afrikaans"},
+	{ISO6391: "am", ISO6393: "amh", DisplayName: "Amharic"},
+	{ISO6391: "ar", ISO6393: "ara", DisplayName: "Arabic"},
+	{ISO6391: "az", ISO6393: "aze", DisplayName: "Azerbaijani"},
+	{ISO6391: "be", ISO6393: "bel", DisplayName: "Belarusian"},
+	{ISO6391: "bg", ISO6393: "bul", DisplayName: "Bulgarian"},
+	{ISO6391: "bn", ISO6393: "ben", DisplayName: "Bengali"},
+	{ISO6391: "bs", ISO6393: "bos", DisplayName: "Bosnian"},
+	{ISO6391: "ca", ISO6393: "cat", DisplayName: "Catalan"},
+	{ISO6391: "cs", ISO6393: "ces", DisplayName: "Czech"},
+	{ISO6391: "cy", ISO6393: "cym", DisplayName: "Welsh"},
+	{ISO6391: "da", ISO6393: "dan", DisplayName: "Danish"},
+	{ISO6391: "de", ISO6393: "deu", DisplayName: "German"},
+	{ISO6391: "el", ISO6393: "ell", DisplayName: "Greek"},
+	{ISO6391: "en", ISO6393: "eng", DisplayName: "English"},
+	{ISO6391: "eo", ISO6393: "epo", DisplayName: "Esperanto"},
+	{ISO6391: "es", ISO6393: "spa", DisplayName: "Spanish"},
+	{ISO6391: "et", ISO6393: "est", DisplayName: "Estonian"},
+	{ISO6391: "eu", ISO6393: "eus", DisplayName: "Basque"},
+	{ISO6391: "fa", ISO6393: "fas", DisplayName: "Persian"},
+	{ISO6391: "fi", ISO6393: "fin", DisplayName: "Finnish"},
+	{ISO6391: "fr", ISO6393: "fra", DisplayName: "French"},
+	{ISO6391: "ga", ISO6393: "gle", DisplayName: "Irish"},
+	{ISO6391: "gl", ISO6393: "glg", DisplayName: "Galician"},
+	{ISO6391: "gu", ISO6393: "guj", DisplayName: "Gujarati"},
+	{ISO6391: "he", ISO6393: "heb", DisplayName: "Hebrew"},
+	{ISO6391: "hi", ISO6393: "hin", DisplayName: "Hindi"},
+	{ISO6391: "hr", ISO6393: "hrv", DisplayName: "Croatian"},
+	{ISO6391: "hu", ISO6393: "hun", DisplayName: "Hungarian"},
+	{ISO6391: "hy", ISO6393: "hye", DisplayName: "Armenian"},
+	{ISO6391: "id", ISO6393: "ind", DisplayName: "Indonesian"},
+	{ISO6391: "is", ISO6393: "isl", DisplayName: "Icelandic"},
+	{ISO6391: "it", ISO6393: "ita", DisplayName: "Italian"},
+	{ISO6391: "ja", ISO6393: "jpn", DisplayName: "Japanese"},
+	{ISO6391: "ka", ISO6393: "kat", DisplayName: "Georgian"},
+	{ISO6391: "kk", ISO6393: "kaz", DisplayName: "Kazakh"},
+	{ISO6391: "km", ISO6393: "khm", DisplayName: "Khmer"},
+	{ISO6391: "kn", ISO6393: "kan", DisplayName: "Kannada"},
+	{ISO6391: "ko", ISO6393: "kor", DisplayName: "Korean"},
+	{ISO6391: "ky", ISO6393: "kir", DisplayName: "Kyrgyz"},
+	{ISO6391: "la", ISO6393: "lat", DisplayName: "Latin"},
+	{ISO6391: "lt", ISO6393: "lit", DisplayName: "Lithuanian"},
+	{ISO6391: "lv", ISO6393: "lav", DisplayName: "Latvian"},
+	{ISO6391: "mk", ISO6393: "mkd", DisplayName: "Macedonian"},
+	{ISO6391: "ml", ISO6393: "mal", DisplayName: "Malayalam"},
+	{ISO6391: "mn", ISO6393: "mon", DisplayName: "Mongolian"},
+	{ISO6391: "mr", ISO6393: "mar", DisplayName: "Marathi"},
+	{ISO6391: "ms", ISO6393: "msa", DisplayName: "Malay"},
+	{ISO6391: "my", ISO6393: "mya", DisplayName: "Burmese"},
+	{ISO6391: "ne", ISO6393: "nep", DisplayName: "Nepali"},
+	{ISO6391: "nl", ISO6393: "nld", DisplayName: "Dutch"},
+	{ISO6391: "no", ISO6393: "nor", DisplayName: "Norwegian"},
+	{ISO6391: "nb", ISO6393: "nob", DisplayName: "Norwegian Bokmål"},
+	{ISO6391: "nn", ISO6393: "nno", DisplayName: "Norwegian Nynorsk"},
+	{ISO6391: "pa", ISO6393: "pan", DisplayName: "Punjabi"},
+	{ISO6391: "pl", ISO6393: "pol", DisplayName: "Polish"},
+	{ISO6391: "ps", ISO6393: "pus", DisplayName: "Pashto"},
+	{ISO6391: "pt", ISO6393: "por", DisplayName: "Portuguese"},
+	{ISO6391: "ro", ISO6393: "ron", DisplayName: "Romanian"},
+	{ISO6391: "ru", ISO6393: "rus", DisplayName: "Russian"},
+	{ISO6391: "si", ISO6393: "sin", DisplayName: "Sinhala"},
+	{ISO6391: "sk", ISO6393: "slk", DisplayName: "Slovak"},
+	{ISO6391: "sl", ISO6393: "slv", DisplayName: "Slovenian"},
+	{ISO6391: "so", ISO6393: "som", DisplayName: "Somali"},
+	{ISO6391: "sq", ISO6393: "sqi", DisplayName: "Albanian"},
+	{ISO6391: "sr", ISO6393: "srp", DisplayName: "Serbian"},
+	{ISO6391: "sv", ISO6393: "swe", DisplayName: "Swedish"},
+	{ISO6391: "sw", ISO6393: "swa", DisplayName: "Swahili"},
+	{ISO6391: "ta", ISO6393: "tam", DisplayName: "Tamil"},
+	{ISO6391: "te", ISO6393: "tel", DisplayName: "Telugu"},
+	{ISO6391: "tg", ISO6393: "tgk", DisplayName: "Tajik"},
+	{ISO6391: "th", ISO6393: "tha", DisplayName: "Thai"},
+	{ISO6391: "tl", ISO6393: "tgl", DisplayName: "Tagalog"},
+	{ISO6391: "tr", ISO6393: "tur", DisplayName: "Turkish"},
+	{ISO6391: "uk", ISO6393: "ukr", DisplayName: "Ukrainian"},
+	{ISO6391: "ur", ISO6393: "urd", DisplayName: "Urdu"},
+	{ISO6391: "uz", ISO6393: "uzb", DisplayName: "Uzbek"},
+	{ISO6391: "vi", ISO6393: "vie", DisplayName: "Vietnamese"},
+	{ISO6391: "yi", ISO6393: "yid", DisplayName: "Yiddish"},
+	{ISO6391: "zh", ISO6393: "zho", DisplayName: "Chinese"},
+	{ISO6391: "zu", ISO6393: "zul", DisplayName: "Zulu"},
+	{ISO6393: "ceb", DisplayName: "Cebuano"},
+	{ISO6393: "fil", DisplayName: "Filipino"},
+	{ISO6393: "haw", DisplayName: "Hawaiian"},
+	{ISO6393: "yue", DisplayName: "Cantonese"},
+}
+
+// iso639Aliases map ISO 639-2/B codes and deprecated ISO 639-1 codes to ISO 639-3 codes
+var iso639Aliases = map[string]string{
+	"alb": "sqi", "arm": "hye", "baq": "eus", "bur": "mya", "chi": "zho", "cze": "ces",
+	"dut": "nld", "fre": "fra", "geo": "kat", "ger": "deu", "gre": "ell", "ice": "isl",
+	"mac": "mkd", "may": "msa", "per": "fas", "rum": "ron", "slo": "slk", "wel": "cym",
+	"iw": "heb", "in": "ind", "ji": "yid",
+}
+
+// iso639Index maps the lower case codes and display names of iso639Tags to their index
+var iso639Index = func() map[string]int {
+	index := make(map[string]int, 3*len(iso639Tags))
+	for i, tag := range iso639Tags {
+		for _, key := range []string{tag.ISO6391, tag.ISO6393, tag.DisplayName} {
+			if key != "" {
+				index[strings.ToLower(key)] = i
+			}
+		}
+	}
+	return index
+}()
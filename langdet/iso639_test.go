@@ -0,0 +1,43 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLanguageTags(t *testing.T) {
+	Convey("Subject: ISO 639 tags of languages", t, func() {
+		Convey("Codes, names and BCP 47 tags should be looked up", func() {
+			english := langdet.LanguageTag{ISO6391: "en", ISO6393: "eng", DisplayName: "English"}
+			for _, name := range []string{"en", "eng", "English", " ENGLISH ", "en-US", "en_GB"} {
+				tag, ok := langdet.LookupLanguageTag(name)
+				So(ok, ShouldBeTrue)
+				So(tag, ShouldResemble, english)
+			}
+			tag, _ := langdet.LookupLanguageTag("ger")
+			So(tag.String(), ShouldEqual, "de")
+			tag, _ = langdet.LookupLanguageTag("Cantonese")
+			So(tag.String(), ShouldEqual, "yue")
+			_, ok := langdet.LookupLanguageTag("klingon")
+			So(ok, ShouldBeFalse)
+			So(langdet.LanguageTag{}.String(), ShouldEqual, "und")
+		})
+		Convey("Names should be normalized to BCP 47 subtags", func() {
+			So(langdet.NormalizeLanguageName("fre"), ShouldEqual, "fr")
+			So(langdet.NormalizeLanguageName("iw"), ShouldEqual, "he")
+			So(langdet.NormalizeLanguageName("hi-en-mixed"), ShouldEqual, "hi")
+			So(langdet.NormalizeLanguageName("klingon"), ShouldEqual, "klingon")
+		})
+		Convey("Languages and results should carry their tags", func() {
+			d := langdet.NewDetector()
+			d.AddLanguageFromText("Hello I am english text, what is your language?", "English")
+			d.AddLanguage(langdet.Language{Name: "custom", Profile: map[string]int{"a": 1}})
+			So((*d.Languages)[0].Tag.ISO6393, ShouldEqual, "eng")
+			So((*d.Languages)[1].Tag, ShouldResemble, langdet.LanguageTag{})
+			results := d.GetLanguages("what is your language")
+			So(results[0].Tag.String(), ShouldEqual, "en")
+			So(d.Compiled()[0].Tag().String(), ShouldEqual, "en")
+		})
+	})
+}
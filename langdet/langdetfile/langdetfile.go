@@ -0,0 +1,226 @@
+// Package langdetfile detects the languages of files and documents: it extracts the text of plain
+// text, HTML, Markdown, JSON and CSV documents by their MIME type and detects its language, so
+// that indexing pipelines don't have to assemble the extraction themselves.
+package langdetfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// DefaultMaxBytes is the number of bytes read from a file by a Detector whose MaxBytes is 0
+const DefaultMaxBytes = 1 << 20
+
+// ErrUnsupportedType is the error of documents of MIME types whose text can't be extracted
+var ErrUnsupportedType = errors.New("unsupported MIME type")
+
+// extensionTypes are the MIME types of the extensions of text documents that mime.TypeByExtension
+// may not know
+var extensionTypes = map[string]string{
+	".txt":      "text/plain",
+	".text":     "text/plain",
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+	".csv":      "text/csv",
+	".tsv":      "text/tab-separated-values",
+	".json":     "application/json",
+	".html":     "text/html",
+	".htm":      "text/html",
+}
+
+// Result is the language of a document
+type Result struct {
+	// Language is the closest language of the text of the document, see GetClosestLanguage
+	Language string
+	// Confidence is the confidence of the language between 0 and 1
+	Confidence float64
+	// Reliable tells whether the detection is reliable like GetClosestLanguageWithConfidence
+	Reliable bool
+	// MIMEType is the MIME type the text was extracted as, without parameters
+	MIMEType string
+	// Charset is the charset the document was decoded from, see Detector.DecodeBytes
+	Charset string
+}
+
+// Detector detects the languages of documents with a langdet.Detector
+type Detector struct {
+	// Detector detects the extracted texts, nil means langdet.Default()
+	Detector *langdet.Detector
+	// MaxBytes is the number of bytes read from a file, 0 means DefaultMaxBytes. Documents are
+	// cut off after it, the text extracted up to there is detected.
+	MaxBytes int64
+}
+
+// Detect returns the language of the file at path with the default languages, see Detector.Detect
+func Detect(path string) (Result, error) {
+	return Detector{}.Detect(path)
+}
+
+// DetectBytes returns the language of a document of a MIME type with the default languages, see
+// Detector.DetectBytes
+func DetectBytes(data []byte, mimeType string) (Result, error) {
+	return Detector{}.DetectBytes(data, mimeType)
+}
+
+// Detect returns the language of the file at path, whose MIME type is looked up by its extension
+// or, for unknown extensions, sniffed from its content
+func (d Detector) Detect(path string) (Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+	maxBytes := d.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	data, err := io.ReadAll(io.LimitReader(f, maxBytes))
+	if err != nil {
+		return Result{}, fmt.Errorf("%s: %w", path, err)
+	}
+	result, err := d.DetectBytes(data, TypeOf(path, data))
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", path, err)
+	}
+	return result, nil
+}
+
+// DetectBytes returns the language of the text extracted from a document of a MIME type, like
+// "text/html; charset=utf-8". Documents that are not valid UTF-8 are decoded with the Charsets of
+// the detector first.
+func (d Detector) DetectBytes(data []byte, mimeType string) (Result, error) {
+	detector := d.Detector
+	if detector == nil {
+		detector = langdet.Default()
+	}
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid MIME type %q: %w", mimeType, err)
+	}
+	result := Result{Language: "undefined", MIMEType: mediaType}
+	decoded, charset, err := detector.DecodeBytes(data)
+	if err != nil {
+		return result, err
+	}
+	result.Charset = charset
+	text, err := Extract(decoded, mediaType)
+	if err != nil {
+		return result, err
+	}
+	result.Language, result.Confidence, result.Reliable = detector.GetClosestLanguageWithConfidence(text)
+	if !result.Reliable && result.Language != langdet.InsufficientInput {
+		result.Language = "undefined"
+	}
+	return result, nil
+}
+
+// TypeOf returns the MIME type of a file by its extension or, for unknown extensions, by the
+// content of the file, see http.DetectContentType
+func TypeOf(path string, data []byte) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if mimeType, ok := extensionTypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(data)
+}
+
+// Extract returns the text of a document of a media type, without parameters: the visible text
+// of HTML and Markdown, the string values of JSON, the cells of CSV and TSV with letters and
+// plain text without its preamble. Documents that are cut off are extracted up to the cut.
+func Extract(document, mediaType string) (string, error) {
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return langdet.HTMLText([]byte(document)), nil
+	case mediaType == "text/markdown" || mediaType == "text/x-markdown":
+		return langdet.MarkdownText(document), nil
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return jsonText(document), nil
+	case mediaType == "text/csv":
+		return csvText(document, ','), nil
+	case mediaType == "text/tab-separated-values":
+		return csvText(document, '\t'), nil
+	case strings.HasPrefix(mediaType, "text/"):
+		return langdet.StripPreamble(document), nil
+	}
+	return "", fmt.Errorf("%w %q", ErrUnsupportedType, mediaType)
+}
+
+// jsonText returns the string values of a JSON document, one per line, without the keys of objects
+func jsonText(document string) string {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	// levels are the open objects and arrays, with whether the next token of an object is a key
+	type level struct{ object, key bool }
+	levels := []level{}
+	texts := []string{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// the end of the document, or the cut of a document that was cut off
+			break
+		}
+		isKey := false
+		if top := len(levels) - 1; top >= 0 && levels[top].object {
+			isKey = levels[top].key
+			levels[top].key = !levels[top].key
+		}
+		switch t := token.(type) {
+		case json.Delim:
+			switch t {
+			case '{':
+				levels = append(levels, level{object: true, key: true})
+			case '[':
+				levels = append(levels, level{})
+			default:
+				levels = levels[:len(levels)-1]
+			}
+		case string:
+			if !isKey && hasLetters(t) {
+				texts = append(texts, t)
+			}
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// csvText returns the cells with letters of a CSV document with the separator comma, one per line
+func csvText(document string, comma rune) string {
+	reader := csv.NewReader(strings.NewReader(document))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+	var texts strings.Builder
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			break
+		}
+		for _, cell := range record {
+			if hasLetters(cell) {
+				texts.WriteString(cell)
+				texts.WriteByte('\n')
+			}
+		}
+	}
+	return texts.String()
+}
+
+// hasLetters tells whether a text has letters, unlike numbers and identifiers of digits
+func hasLetters(text string) bool {
+	return strings.IndexFunc(text, unicode.IsLetter) >= 0
+}
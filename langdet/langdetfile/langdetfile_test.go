@@ -0,0 +1,63 @@
+package langdetfile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet/langdetfile"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDetect(t *testing.T) {
+	Convey("Subject: Detect the languages of documents", t, func() {
+		fr := "Je ne sais pas ce que tu dis, mais je pense que nous devrions partir demain matin."
+
+		Convey("The text should be extracted by the MIME type", func() {
+			text, err := langdetfile.Extract(`{"title": "Bonjour", "id": 12, "tags": ["1234", "monde"], "nested": {"key": "valeur"}}`, "application/json")
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, "Bonjour\nmonde\nvaleur")
+			text, err = langdetfile.Extract("id,text\n1,\"Bonjour, monde\"\n2,salut\n", "text/csv")
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, "id\ntext\nBonjour, monde\nsalut\n")
+			text, err = langdetfile.Extract("<p>Bonjour <b>monde</b></p><script>var x</script>", "text/html")
+			So(err, ShouldBeNil)
+			So(text, ShouldNotContainSubstring, "var x")
+			_, err = langdetfile.Extract("\x89PNG", "image/png")
+			So(errors.Is(err, langdetfile.ErrUnsupportedType), ShouldBeTrue)
+		})
+		Convey("Documents that are cut off should be extracted up to the cut", func() {
+			text, err := langdetfile.Extract(`[{"text": "Bonjour"}, {"text": "mon`, "application/json")
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, "Bonjour")
+		})
+		Convey("Files should be detected by their extension or content", func() {
+			dir := t.TempDir()
+			files := map[string]string{
+				"page.html": "<html><body><p>" + fr + "</p></body></html>",
+				"data.json": `{"messages": [{"id": 1, "body": "` + fr + `"}]}`,
+				"table.csv": "id,comment\n1,\"" + fr + "\"\n",
+				"notes":     fr,
+				"readme.md": "# Notes\n\n" + fr + "\n\n```\ncode\n```\n",
+			}
+			for name, content := range files {
+				path := filepath.Join(dir, name)
+				So(os.WriteFile(path, []byte(content), 0644), ShouldBeNil)
+				result, err := langdetfile.Detect(path)
+				So(err, ShouldBeNil)
+				So(result.Language, ShouldEqual, "fr")
+				So(result.Charset, ShouldEqual, "utf-8")
+			}
+			So(langdetfile.TypeOf(filepath.Join(dir, "notes"), []byte(fr)), ShouldStartWith, "text/plain")
+		})
+		Convey("Bytes should be detected with their MIME type", func() {
+			result, err := langdetfile.DetectBytes([]byte(`{"text": "`+fr+`"}`), "application/json; charset=utf-8")
+			So(err, ShouldBeNil)
+			So(result.Language, ShouldEqual, "fr")
+			So(result.MIMEType, ShouldEqual, "application/json")
+			_, err = langdetfile.DetectBytes([]byte(fr), "not a type")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,5 @@
+// Package langdetpb has the protobuf messages and the gRPC service of the langdet.v1.Detector API,
+// generated from langdet.proto. Clients in other languages generate theirs from the same file.
+package langdetpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative langdet.proto
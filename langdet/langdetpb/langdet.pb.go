@@ -0,0 +1,641 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: langdet.proto
+
+package langdetpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DetectRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Number of the closest languages returned as results, 0 for none and -1
+	// for all languages.
+	TopK int32 `protobuf:"varint,2,opt,name=top_k,json=topK,proto3" json:"top_k,omitempty"`
+	// Split the text into segments of a single language.
+	Segments bool `protobuf:"varint,3,opt,name=segments,proto3" json:"segments,omitempty"`
+	// Locale of the display names, e.g. "de".
+	Locale        string `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DetectRequest) Reset() {
+	*x = DetectRequest{}
+	mi := &file_langdet_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectRequest) ProtoMessage() {}
+
+func (x *DetectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_langdet_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectRequest.ProtoReflect.Descriptor instead.
+func (*DetectRequest) Descriptor() ([]byte, []int) {
+	return file_langdet_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DetectRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *DetectRequest) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *DetectRequest) GetSegments() bool {
+	if x != nil {
+		return x.Segments
+	}
+	return false
+}
+
+func (x *DetectRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type DetectResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Closest language, or "undefined" if the detection is not reliable.
+	Language string `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`
+	// Name of the language in the language of the locale of the request.
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// Confidence of the closest language between 0 and 1.
+	Confidence float64 `protobuf:"fixed64,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Reliable   bool    `protobuf:"varint,4,opt,name=reliable,proto3" json:"reliable,omitempty"`
+	// Whether the closest language doesn't clearly win over the second closest,
+	// with top_k only.
+	Ambiguous     bool               `protobuf:"varint,5,opt,name=ambiguous,proto3" json:"ambiguous,omitempty"`
+	Results       []*DetectionResult `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	Segments      []*Segment         `protobuf:"bytes,7,rep,name=segments,proto3" json:"segments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DetectResponse) Reset() {
+	*x = DetectResponse{}
+	mi := &file_langdet_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectResponse) ProtoMessage() {}
+
+func (x *DetectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_langdet_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectResponse.ProtoReflect.Descriptor instead.
+func (*DetectResponse) Descriptor() ([]byte, []int) {
+	return file_langdet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DetectResponse) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *DetectResponse) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *DetectResponse) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *DetectResponse) GetReliable() bool {
+	if x != nil {
+		return x.Reliable
+	}
+	return false
+}
+
+func (x *DetectResponse) GetAmbiguous() bool {
+	if x != nil {
+		return x.Ambiguous
+	}
+	return false
+}
+
+func (x *DetectResponse) GetResults() []*DetectionResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *DetectResponse) GetSegments() []*Segment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+type DetectionResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// BCP 47 primary language subtag, or "und" for unknown languages.
+	Tag string `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Confidence in percent, between 0 and 100.
+	Confidence     float64 `protobuf:"fixed64,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Distance       int32   `protobuf:"varint,4,opt,name=distance,proto3" json:"distance,omitempty"`
+	MatchedTokens  int32   `protobuf:"varint,5,opt,name=matched_tokens,json=matchedTokens,proto3" json:"matched_tokens,omitempty"`
+	ComparedTokens int32   `protobuf:"varint,6,opt,name=compared_tokens,json=comparedTokens,proto3" json:"compared_tokens,omitempty"`
+	InputTokens    int32   `protobuf:"varint,7,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	Direction      string  `protobuf:"bytes,8,opt,name=direction,proto3" json:"direction,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DetectionResult) Reset() {
+	*x = DetectionResult{}
+	mi := &file_langdet_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetectionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectionResult) ProtoMessage() {}
+
+func (x *DetectionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_langdet_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectionResult.ProtoReflect.Descriptor instead.
+func (*DetectionResult) Descriptor() ([]byte, []int) {
+	return file_langdet_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DetectionResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DetectionResult) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *DetectionResult) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *DetectionResult) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *DetectionResult) GetMatchedTokens() int32 {
+	if x != nil {
+		return x.MatchedTokens
+	}
+	return 0
+}
+
+func (x *DetectionResult) GetComparedTokens() int32 {
+	if x != nil {
+		return x.ComparedTokens
+	}
+	return 0
+}
+
+func (x *DetectionResult) GetInputTokens() int32 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *DetectionResult) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+type Segment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Byte offsets of the segment in the text.
+	Start         int32   `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32   `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	Language      string  `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	Confidence    float64 `protobuf:"fixed64,5,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Segment) Reset() {
+	*x = Segment{}
+	mi := &file_langdet_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Segment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Segment) ProtoMessage() {}
+
+func (x *Segment) ProtoReflect() protoreflect.Message {
+	mi := &file_langdet_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Segment.ProtoReflect.Descriptor instead.
+func (*Segment) Descriptor() ([]byte, []int) {
+	return file_langdet_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Segment) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Segment) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Segment) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *Segment) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Segment) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+type ListLanguagesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Locale of the display names, e.g. "de".
+	Locale        string `protobuf:"bytes,1,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLanguagesRequest) Reset() {
+	*x = ListLanguagesRequest{}
+	mi := &file_langdet_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLanguagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLanguagesRequest) ProtoMessage() {}
+
+func (x *ListLanguagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_langdet_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLanguagesRequest.ProtoReflect.Descriptor instead.
+func (*ListLanguagesRequest) Descriptor() ([]byte, []int) {
+	return file_langdet_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListLanguagesRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ListLanguagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Languages     []*Language            `protobuf:"bytes,1,rep,name=languages,proto3" json:"languages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLanguagesResponse) Reset() {
+	*x = ListLanguagesResponse{}
+	mi := &file_langdet_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLanguagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLanguagesResponse) ProtoMessage() {}
+
+func (x *ListLanguagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_langdet_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLanguagesResponse.ProtoReflect.Descriptor instead.
+func (*ListLanguagesResponse) Descriptor() ([]byte, []int) {
+	return file_langdet_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListLanguagesResponse) GetLanguages() []*Language {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+type Language struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Direction     string                 `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Language) Reset() {
+	*x = Language{}
+	mi := &file_langdet_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Language) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Language) ProtoMessage() {}
+
+func (x *Language) ProtoReflect() protoreflect.Message {
+	mi := &file_langdet_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Language.ProtoReflect.Descriptor instead.
+func (*Language) Descriptor() ([]byte, []int) {
+	return file_langdet_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Language) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Language) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *Language) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Language) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+var File_langdet_proto protoreflect.FileDescriptor
+
+const file_langdet_proto_rawDesc = "" +
+	"\n" +
+	"\rlangdet.proto\x12\n" +
+	"langdet.v1\"l\n" +
+	"\rDetectRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x13\n" +
+	"\x05top_k\x18\x02 \x01(\x05R\x04topK\x12\x1a\n" +
+	"\bsegments\x18\x03 \x01(\bR\bsegments\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06locale\"\x91\x02\n" +
+	"\x0eDetectResponse\x12\x1a\n" +
+	"\blanguage\x18\x01 \x01(\tR\blanguage\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x01R\n" +
+	"confidence\x12\x1a\n" +
+	"\breliable\x18\x04 \x01(\bR\breliable\x12\x1c\n" +
+	"\tambiguous\x18\x05 \x01(\bR\tambiguous\x125\n" +
+	"\aresults\x18\x06 \x03(\v2\x1b.langdet.v1.DetectionResultR\aresults\x12/\n" +
+	"\bsegments\x18\a \x03(\v2\x13.langdet.v1.SegmentR\bsegments\"\x84\x02\n" +
+	"\x0fDetectionResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x01R\n" +
+	"confidence\x12\x1a\n" +
+	"\bdistance\x18\x04 \x01(\x05R\bdistance\x12%\n" +
+	"\x0ematched_tokens\x18\x05 \x01(\x05R\rmatchedTokens\x12'\n" +
+	"\x0fcompared_tokens\x18\x06 \x01(\x05R\x0ecomparedTokens\x12!\n" +
+	"\finput_tokens\x18\a \x01(\x05R\vinputTokens\x12\x1c\n" +
+	"\tdirection\x18\b \x01(\tR\tdirection\"\x81\x01\n" +
+	"\aSegment\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x05R\x03end\x12\x1a\n" +
+	"\blanguage\x18\x04 \x01(\tR\blanguage\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x05 \x01(\x01R\n" +
+	"confidence\".\n" +
+	"\x14ListLanguagesRequest\x12\x16\n" +
+	"\x06locale\x18\x01 \x01(\tR\x06locale\"K\n" +
+	"\x15ListLanguagesResponse\x122\n" +
+	"\tlanguages\x18\x01 \x03(\v2\x14.langdet.v1.LanguageR\tlanguages\"q\n" +
+	"\bLanguage\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1c\n" +
+	"\tdirection\x18\x04 \x01(\tR\tdirection2\xa1\x01\n" +
+	"\bDetector\x12?\n" +
+	"\x06Detect\x12\x19.langdet.v1.DetectRequest\x1a\x1a.langdet.v1.DetectResponse\x12T\n" +
+	"\rListLanguages\x12 .langdet.v1.ListLanguagesRequest\x1a!.langdet.v1.ListLanguagesResponseB9Z7github.com/imankulov/go-lang-detector/langdet/langdetpbb\x06proto3"
+
+var (
+	file_langdet_proto_rawDescOnce sync.Once
+	file_langdet_proto_rawDescData []byte
+)
+
+func file_langdet_proto_rawDescGZIP() []byte {
+	file_langdet_proto_rawDescOnce.Do(func() {
+		file_langdet_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_langdet_proto_rawDesc), len(file_langdet_proto_rawDesc)))
+	})
+	return file_langdet_proto_rawDescData
+}
+
+var file_langdet_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_langdet_proto_goTypes = []any{
+	(*DetectRequest)(nil),         // 0: langdet.v1.DetectRequest
+	(*DetectResponse)(nil),        // 1: langdet.v1.DetectResponse
+	(*DetectionResult)(nil),       // 2: langdet.v1.DetectionResult
+	(*Segment)(nil),               // 3: langdet.v1.Segment
+	(*ListLanguagesRequest)(nil),  // 4: langdet.v1.ListLanguagesRequest
+	(*ListLanguagesResponse)(nil), // 5: langdet.v1.ListLanguagesResponse
+	(*Language)(nil),              // 6: langdet.v1.Language
+}
+var file_langdet_proto_depIdxs = []int32{
+	2, // 0: langdet.v1.DetectResponse.results:type_name -> langdet.v1.DetectionResult
+	3, // 1: langdet.v1.DetectResponse.segments:type_name -> langdet.v1.Segment
+	6, // 2: langdet.v1.ListLanguagesResponse.languages:type_name -> langdet.v1.Language
+	0, // 3: langdet.v1.Detector.Detect:input_type -> langdet.v1.DetectRequest
+	4, // 4: langdet.v1.Detector.ListLanguages:input_type -> langdet.v1.ListLanguagesRequest
+	1, // 5: langdet.v1.Detector.Detect:output_type -> langdet.v1.DetectResponse
+	5, // 6: langdet.v1.Detector.ListLanguages:output_type -> langdet.v1.ListLanguagesResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_langdet_proto_init() }
+func file_langdet_proto_init() {
+	if File_langdet_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_langdet_proto_rawDesc), len(file_langdet_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_langdet_proto_goTypes,
+		DependencyIndexes: file_langdet_proto_depIdxs,
+		MessageInfos:      file_langdet_proto_msgTypes,
+	}.Build()
+	File_langdet_proto = out.File
+	file_langdet_proto_goTypes = nil
+	file_langdet_proto_depIdxs = nil
+}
@@ -0,0 +1,164 @@
+package langdet
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// hiddenElements are the HTML elements whose content is not visible text
+var hiddenElements = map[string]bool{"script": true, "style": true, "noscript": true, "template": true, "svg": true}
+
+// inlineElements are the HTML elements that don't separate the words around them
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "code": true, "em": true, "i": true, "mark": true, "s": true,
+	"small": true, "span": true, "strong": true, "sub": true, "sup": true, "u": true,
+}
+
+// Inline markdown syntax removed by MarkdownText
+var (
+	markdownCode  = regexp.MustCompile("`[^`]*`")
+	markdownImage = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	markdownLink  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownBlock = regexp.MustCompile(`^(\s*(>|#{1,6}\s|[-*+]\s|\d+[.)]\s))+`)
+	markdownRule  = regexp.MustCompile(`^[\s=\-*_|:]*$`)
+	markdownRef   = regexp.MustCompile(`^\s*\[[^\]]+\]:\s`)
+)
+
+// HTMLText extracts the visible text of an HTML document: it strips tags, comments and the content
+// of scripts and styles, and decodes entities. Tags and their attributes would otherwise dominate
+// the n-grams of a page.
+func HTMLText(data []byte) string {
+	var text strings.Builder
+	s := string(data)
+	hidden := ""
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			lt = len(s)
+		}
+		if hidden == "" {
+			text.WriteString(s[:lt])
+		}
+		s = s[lt:]
+		if len(s) < 2 {
+			break
+		}
+		if strings.HasPrefix(s, "<!--") {
+			s = skipPast(s, "-->")
+			continue
+		}
+		if !isTagStart(s[1]) {
+			// a literal "<", like in "a < b"
+			if hidden == "" {
+				text.WriteByte('<')
+			}
+			s = s[1:]
+			continue
+		}
+		end := tagEnd(s)
+		if end < 0 {
+			break
+		}
+		name, selfClosing := tagName(s[1:end])
+		s = s[end+1:]
+		switch {
+		case hidden != "":
+			if name == "/"+hidden {
+				hidden = ""
+			}
+		case hiddenElements[name] && !selfClosing:
+			hidden = name
+		case !inlineElements[strings.TrimPrefix(name, "/")]:
+			text.WriteByte(' ')
+		}
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(text.String())), " ")
+}
+
+// isTagStart tells whether c, the byte after a "<", starts a tag, a closing tag or a declaration
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || c == '?' || (c|0x20 >= 'a' && c|0x20 <= 'z')
+}
+
+// tagEnd returns the index of the ">" that closes the tag at the start of s, skipping quoted
+// attribute values, or -1 if the tag is not closed
+func tagEnd(s string) int {
+	quote := byte(0)
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// tagName returns the lowercased name of a tag without its brackets, with a leading "/" for
+// closing tags, and whether the tag closes itself
+func tagName(tag string) (string, bool) {
+	selfClosing := strings.HasSuffix(tag, "/")
+	if i := strings.IndexAny(tag, " \t\n\r/>"); i > 0 {
+		tag = tag[:i]
+	} else if i == 0 && strings.HasPrefix(tag, "/") {
+		if j := strings.IndexAny(tag[1:], " \t\n\r>"); j >= 0 {
+			tag = tag[:j+1]
+		}
+	}
+	return strings.ToLower(tag), selfClosing
+}
+
+// MarkdownText extracts the visible text of a Markdown document: it removes code blocks, inline
+// code, images, link targets and definitions, block markers like headings, quotes and list items,
+// table rules and inline HTML.
+func MarkdownText(text string) string {
+	var visible strings.Builder
+	fence := ""
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		indented := strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+		if indented && !markdownBlock.MatchString(trimmed) {
+			// an indented code block, unlike nested list items
+			continue
+		}
+		if markdownRule.MatchString(line) || markdownRef.MatchString(line) {
+			continue
+		}
+		line = markdownBlock.ReplaceAllString(trimmed, "")
+		line = markdownCode.ReplaceAllString(line, " ")
+		line = markdownImage.ReplaceAllString(line, " ")
+		line = markdownLink.ReplaceAllString(line, "$1")
+		line = strings.NewReplacer("|", " ", "*", "", "~~", "").Replace(line)
+		visible.WriteString(line)
+		visible.WriteByte('\n')
+	}
+	return HTMLText([]byte(visible.String()))
+}
+
+// DetectHTML returns the name of the closest language of the visible text of an HTML document,
+// or undefined like GetClosestLanguage, see HTMLText
+func (d *Detector) DetectHTML(data []byte) string {
+	return d.GetClosestLanguage(HTMLText(data))
+}
+
+// DetectMarkdown returns the name of the closest language of the visible text of a Markdown
+// document, or undefined like GetClosestLanguage, see MarkdownText
+func (d *Detector) DetectMarkdown(text string) string {
+	return d.GetClosestLanguage(MarkdownText(text))
+}
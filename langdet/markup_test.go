@@ -0,0 +1,31 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMarkup(t *testing.T) {
+	Convey("Subject: HTML and Markdown input", t, func() {
+		Convey("HTML should be reduced to its visible text", func() {
+			page := `<!DOCTYPE html><html><head><title>Caf&eacute;</title><style>p { color: red }</style>
+				<script>if (a < b) { document.write("<p>x</p>") }</script></head>
+				<body><p class="intro" data-x='a > b'>Hello <b>wor</b>ld &amp; a < b</p><!-- hidden --><br/>bye</body></html>`
+			So(langdet.HTMLText([]byte(page)), ShouldEqual, "Café Hello world & a < b bye")
+		})
+		Convey("Markdown should be reduced to its visible text", func() {
+			doc := "# Title\n\n> quoted *text* with `code`\n\n- item [link](http://example.com)\n" +
+				"    - nested\n\n```go\nfunc main() {}\n```\n\n    indented code\n\n" +
+				"| a | b |\n|---|---|\n![image](x.png) <span>inline</span>\n\n[ref]: http://example.com\n"
+			So(langdet.MarkdownText(doc), ShouldEqual, "Title quoted text with item link nested a b inline")
+		})
+		Convey("Markup should not count for the detection", func() {
+			d := langdet.NewDefaultLanguages()
+			page := `<div class="container"><p>Das ist ein kleiner Text in deutscher Sprache, den wir erkennen wollen.</p></div>`
+			So(d.DetectHTML([]byte(page)), ShouldEqual, "de")
+			So(d.DetectMarkdown("## Überschrift\n\nDas ist ein kleiner Text in [deutscher Sprache](https://example.com/english-page)."), ShouldEqual, "de")
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package langdet
+
+import (
+	"fmt"
+	"math"
+)
+
+// Merge adds the Counts of other, multiplied by weight, to the Counts of the language and ranks
+// its profile again, e.g. to combine profiles of the same language trained on different corpora.
+// A weight of 1 gives the profile of training on both corpora, weights balance corpora of
+// different sizes. Both languages must have been trained with KeepCounts and with the same depth.
+// The WordProfile of the language is kept, as word profiles have no counts to merge.
+func (l *Language) Merge(other Language, weight float64) error {
+	if weight <= 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return fmt.Errorf("invalid weight %v to merge %s, it must be positive", weight, other.Name)
+	}
+	if l.Counts == nil || other.Counts == nil {
+		return fmt.Errorf("cannot merge %s and %s, both need counts, train them with KeepCounts", l.Name, other.Name)
+	}
+	if depth, otherDepth := profileDepth(*l), profileDepth(other); depth != otherDepth {
+		return fmt.Errorf("cannot merge %s of depth %d and %s of depth %d", l.Name, depth, other.Name, otherDepth)
+	}
+	counts := make(map[string]int, len(l.Counts)+len(other.Counts))
+	for token, count := range l.Counts {
+		counts[token] = count
+	}
+	for token, count := range other.Counts {
+		weighted := int(math.Round(float64(count) * weight))
+		counts[token] += weighted
+		l.TokenCount += weighted
+	}
+	l.Counts = counts
+	if other.Size > l.Size {
+		l.Size = other.Size
+	}
+	switch {
+	case other.SourceDescription == "":
+	case l.SourceDescription == "":
+		l.SourceDescription = other.SourceDescription
+	default:
+		l.SourceDescription += ", " + other.SourceDescription
+	}
+	l.Samples = append(l.Samples[:len(l.Samples):len(l.Samples)], other.Samples...)
+	l.FormatVersion = ProfileFormatVersion
+	l.Rerank()
+	return nil
+}
+
+// profileDepth returns the Depth of a language, or the depth inferred from its profile if it is 0
+func profileDepth(language Language) int {
+	if language.Depth != 0 {
+		return language.Depth
+	}
+	depth, _, _ := inspectProfile(language.Profile)
+	return depth
+}
@@ -0,0 +1,42 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMerge(t *testing.T) {
+	Convey("Subject: Merge profiles trained on different corpora", t, func() {
+		wiki, chat := "The city is the capital of the region.", "lol see you tomorrow then"
+		train := func(depth int, texts ...string) langdet.Language {
+			trainer := &langdet.Trainer{Depth: depth, KeepCounts: true}
+			for _, text := range texts {
+				trainer.Feed(text)
+			}
+			return trainer.Build("en")
+		}
+
+		Convey("Merging with weight 1 should equal training on both corpora", func() {
+			merged := train(3, wiki)
+			So(merged.Merge(train(3, chat), 1), ShouldBeNil)
+			both := train(3, wiki, chat)
+			So(merged.Profile, ShouldResemble, both.Profile)
+			So(merged.Counts, ShouldResemble, both.Counts)
+			So(merged.TokenCount, ShouldEqual, both.TokenCount)
+		})
+		Convey("Weights should multiply the counts of the merged profile", func() {
+			merged := train(3, wiki)
+			So(merged.Merge(train(3, chat), 2), ShouldBeNil)
+			So(merged.Counts, ShouldResemble, train(3, wiki, chat, chat).Counts)
+		})
+		Convey("Profiles of different depths or without counts should not be merged", func() {
+			merged := train(3, wiki)
+			So(merged.Merge(train(2, chat), 1), ShouldNotBeNil)
+			So(merged.Merge(langdet.Analyze(chat, "en"), 1), ShouldNotBeNil)
+			So(merged.Merge(train(3, chat), 0), ShouldNotBeNil)
+			So(merged.Counts, ShouldResemble, train(3, wiki).Counts)
+		})
+	})
+}
@@ -0,0 +1,163 @@
+package langdet
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LanguageMeta is metadata of a language that is commonly needed right after the detection
+type LanguageMeta struct {
+	// Name is the name of the language profile, e.g. "en"
+	Name string
+	// DisplayName is the english name of the language, e.g. "English"
+	DisplayName string
+	// ISO6391 and ISO6393 are the two and three letter ISO 639 codes of the language
+	ISO6391 string
+	ISO6393 string
+	// DefaultLocale is the most common locale of the language, e.g. "en-US"
+	DefaultLocale string
+	// Direction is the text direction of the language, LeftToRight or RightToLeft
+	Direction string
+	// Script is the ISO 15924 code of the usual script of the language, e.g. "Latn"
+	Script string
+	// PluralCategories are the CLDR plural categories of the language, e.g. "one" and "other"
+	PluralCategories []string
+	// LocalizedNames are the display names of the language in other languages by locale,
+	// e.g. "Französisch" for "de"
+	LocalizedNames map[string]string `json:",omitempty"`
+}
+
+// MetaRegistry maps language names to their LanguageMeta. It is safe for concurrent use.
+// Languages are looked up case-insensitively by their Name, display name and ISO 639 codes.
+type MetaRegistry struct {
+	mu    sync.RWMutex
+	metas map[string]LanguageMeta
+	keys  map[string]string
+}
+
+// DefaultMeta is the registry with the metadata of the default languages. It can be edited by users.
+var DefaultMeta = NewMetaRegistry(localize(defaultMetas, defaultLocalizedNames)...)
+
+var defaultMetas = []LanguageMeta{
+	{Name: Arabic.String(), DisplayName: "Arabic", ISO6391: "ar", ISO6393: "ara", DefaultLocale: "ar-EG", Direction: RightToLeft, Script: "Arab", PluralCategories: []string{"zero", "one", "two", "few", "many", "other"}},
+	{Name: German.String(), DisplayName: "German", ISO6391: "de", ISO6393: "deu", DefaultLocale: "de-DE", Direction: LeftToRight, Script: "Latn", PluralCategories: []string{"one", "other"}},
+	{Name: English.String(), DisplayName: "English", ISO6391: "en", ISO6393: "eng", DefaultLocale: "en-US", Direction: LeftToRight, Script: "Latn", PluralCategories: []string{"one", "other"}},
+	{Name: French.String(), DisplayName: "French", ISO6391: "fr", ISO6393: "fra", DefaultLocale: "fr-FR", Direction: LeftToRight, Script: "Latn", PluralCategories: []string{"one", "many", "other"}},
+	{Name: Hebrew.String(), DisplayName: "Hebrew", ISO6391: "he", ISO6393: "heb", DefaultLocale: "he-IL", Direction: RightToLeft, Script: "Hebr", PluralCategories: []string{"one", "two", "other"}},
+	{Name: Russian.String(), DisplayName: "Russian", ISO6391: "ru", ISO6393: "rus", DefaultLocale: "ru-RU", Direction: LeftToRight, Script: "Cyrl", PluralCategories: []string{"one", "few", "many", "other"}},
+	{Name: Turkish.String(), DisplayName: "Turkish", ISO6391: "tr", ISO6393: "tur", DefaultLocale: "tr-TR", Direction: LeftToRight, Script: "Latn", PluralCategories: []string{"one", "other"}},
+}
+
+// defaultLocalizedNames are the display names of the default languages in the default languages
+var defaultLocalizedNames = map[string]map[string]string{
+	Arabic.String():  {"ar": "العربية", "de": "Arabisch", "en": "Arabic", "fr": "arabe", "he": "ערבית", "ru": "арабский", "tr": "Arapça"},
+	German.String():  {"ar": "الألمانية", "de": "Deutsch", "en": "German", "fr": "allemand", "he": "גרמנית", "ru": "немецкий", "tr": "Almanca"},
+	English.String(): {"ar": "الإنجليزية", "de": "Englisch", "en": "English", "fr": "anglais", "he": "אנגלית", "ru": "английский", "tr": "İngilizce"},
+	French.String():  {"ar": "الفرنسية", "de": "Französisch", "en": "French", "fr": "français", "he": "צרפתית", "ru": "французский", "tr": "Fransızca"},
+	Hebrew.String():  {"ar": "العبرية", "de": "Hebräisch", "en": "Hebrew", "fr": "hébreu", "he": "עברית", "ru": "иврит", "tr": "İbranice"},
+	Russian.String(): {"ar": "الروسية", "de": "Russisch", "en": "Russian", "fr": "russe", "he": "רוסית", "ru": "русский", "tr": "Rusça"},
+	Turkish.String(): {"ar": "التركية", "de": "Türkisch", "en": "Turkish", "fr": "turc", "he": "טורקית", "ru": "турецкий", "tr": "Türkçe"},
+}
+
+// localize returns the metas with the localized names of names
+func localize(metas []LanguageMeta, names map[string]map[string]string) []LanguageMeta {
+	localized := make([]LanguageMeta, len(metas))
+	for i, meta := range metas {
+		meta.LocalizedNames = names[meta.Name]
+		localized[i] = meta
+	}
+	return localized
+}
+
+// NewMetaRegistry returns a new MetaRegistry with the given metadata
+func NewMetaRegistry(metas ...LanguageMeta) *MetaRegistry {
+	r := &MetaRegistry{metas: make(map[string]LanguageMeta), keys: make(map[string]string)}
+	for _, meta := range metas {
+		r.Set(meta)
+	}
+	return r
+}
+
+// Set adds or replaces the metadata of the language meta.Name
+func (r *MetaRegistry) Set(meta LanguageMeta) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metas[meta.Name] = meta
+	for _, key := range []string{meta.DisplayName, meta.ISO6391, meta.ISO6393, meta.Name} {
+		if key != "" {
+			r.keys[strings.ToLower(key)] = meta.Name
+		}
+	}
+}
+
+// Get returns the metadata of a language by its name, display name or ISO 639 code
+func (r *MetaRegistry) Get(name string) (LanguageMeta, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	meta, ok := r.metas[r.keys[strings.ToLower(name)]]
+	return meta, ok
+}
+
+// DisplayName returns the display name of a language in the language of a locale like "de" or
+// "de-AT", so that front-ends don't need their own translation tables. It falls back to the english
+// display name, and to the name itself for unknown languages.
+func (r *MetaRegistry) DisplayName(name, locale string) string {
+	meta, ok := r.Get(name)
+	if !ok {
+		return name
+	}
+	locale = strings.ToLower(strings.Replace(locale, "_", "-", -1))
+	for locale != "" {
+		for key, localized := range meta.LocalizedNames {
+			if strings.ToLower(key) == locale {
+				return localized
+			}
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	if meta.DisplayName != "" {
+		return meta.DisplayName
+	}
+	return name
+}
+
+// Names returns the sorted names of all languages of the registry
+func (r *MetaRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.metas))
+	for name := range r.metas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load adds or replaces the metadata of a JSON array of LanguageMeta read from reader
+func (r *MetaRegistry) Load(reader io.Reader) error {
+	metas := []LanguageMeta{}
+	if err := json.NewDecoder(reader).Decode(&metas); err != nil {
+		return err
+	}
+	for _, meta := range metas {
+		r.Set(meta)
+	}
+	return nil
+}
+
+// MarshalJSON returns the metadata of all languages as JSON array, which can be edited and loaded with Load
+func (r *MetaRegistry) MarshalJSON() ([]byte, error) {
+	metas := []LanguageMeta{}
+	for _, name := range r.Names() {
+		meta, _ := r.Get(name)
+		metas = append(metas, meta)
+	}
+	return json.Marshal(metas)
+}
@@ -0,0 +1,49 @@
+package langdet_test
+
+import (
+	"encoding/json"
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+)
+
+func TestMetaRegistry(t *testing.T) {
+	Convey("Subject: Language metadata registry", t, func() {
+		Convey("Default languages should be found by name, display name and ISO codes", func() {
+			for _, key := range []string{"ru", "rus", "Russian", "russian"} {
+				meta, ok := langdet.DefaultMeta.Get(key)
+				So(ok, ShouldBeTrue)
+				So(meta.DefaultLocale, ShouldEqual, "ru-RU")
+				So(meta.Script, ShouldEqual, "Cyrl")
+			}
+			meta, _ := langdet.DefaultMeta.Get("he")
+			So(meta.Direction, ShouldEqual, langdet.RightToLeft)
+		})
+		Convey("Display names should be localized by locale", func() {
+			So(langdet.DefaultMeta.DisplayName("fr", "de"), ShouldEqual, "Französisch")
+			So(langdet.DefaultMeta.DisplayName("French", "de_AT"), ShouldEqual, "Französisch")
+			So(langdet.DefaultMeta.DisplayName("tr", "tr-TR"), ShouldEqual, "Türkçe")
+			So(langdet.DefaultMeta.DisplayName("ru", "pl"), ShouldEqual, "Russian")
+			So(langdet.DefaultMeta.DisplayName("klingon", "de"), ShouldEqual, "klingon")
+		})
+		Convey("Unknown languages should not be found", func() {
+			_, ok := langdet.DefaultMeta.Get("klingon")
+			So(ok, ShouldBeFalse)
+		})
+		Convey("The registry should be editable and round trip as JSON", func() {
+			r := langdet.NewMetaRegistry()
+			err := r.Load(strings.NewReader(`[{"Name":"pl","DisplayName":"Polish","ISO6391":"pl","DefaultLocale":"pl-PL"}]`))
+			So(err, ShouldBeNil)
+			meta, ok := r.Get("Polish")
+			So(ok, ShouldBeTrue)
+			So(meta.DefaultLocale, ShouldEqual, "pl-PL")
+
+			data, err := json.Marshal(r)
+			So(err, ShouldBeNil)
+			copied := langdet.NewMetaRegistry()
+			So(copied.Load(strings.NewReader(string(data))), ShouldBeNil)
+			So(copied.Names(), ShouldResemble, []string{"pl"})
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package langdet
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ProfileFormatVersion is the FormatVersion of the profiles built by Trainer. Version 1 is the
+// rank-only format, version 2 added the training metadata TokenCount, SourceDescription and
+// CreatedAt. Profiles without a FormatVersion are read as version 1.
+const ProfileFormatVersion = 2
+
+// FormatVersionError is the error of a profile of a newer FormatVersion than ProfileFormatVersion,
+// whose new fields this version would silently ignore
+type FormatVersionError struct {
+	Name    string
+	Version int
+}
+
+func (e *FormatVersionError) Error() string {
+	return fmt.Sprintf("language %q has the format version %d, newer than the supported version %d, upgrade the detector to load it", e.Name, e.Version, ProfileFormatVersion)
+}
+
+// profileUpgrades upgrade loaded profiles to the next FormatVersion: the upgrade at index i upgrades
+// profiles of version i+1
+var profileUpgrades = []func(l *Language){
+	// version 1, the rank-only format, has no Size: its profiles were all trained with the
+	// default size
+	func(l *Language) {
+		if l.Size != 0 {
+			return
+		}
+		l.Size = DefaultProfileSize
+		if _, _, maxRank := inspectProfile(l.Profile); maxRank > l.Size {
+			l.Size = maxRank
+		}
+	},
+}
+
+// upgrade validates a loaded profile with Validate, infers the fields it doesn't have and
+// upgrades it from its FormatVersion to ProfileFormatVersion, so that profiles of older formats
+// load like current ones
+func (l *Language) upgrade() error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	inferDepth := l.Depth == 0
+	l.infer()
+	if inferDepth {
+		// the inferred depth ignores hand-made tokens, the upgraded profile must fit them
+		for token := range l.Profile {
+			if length := utf8.RuneCountInString(token); length > l.Depth+1 {
+				l.Depth = length - 1
+			}
+		}
+	}
+	version := l.FormatVersion
+	if version == 0 {
+		version = 1
+	}
+	for ; version < ProfileFormatVersion; version++ {
+		profileUpgrades[version-1](l)
+	}
+	l.FormatVersion = ProfileFormatVersion
+	return nil
+}
+
+// checkMetadata validates the metadata of a loaded profile: profiles of newer formats are
+// rejected, and the tokens of profiles with a FormatVersion must fit their Depth. Profiles of the
+// rank-only format are accepted as they are, their Depth is inferred.
+func (l Language) checkMetadata() error {
+	switch {
+	case l.FormatVersion > ProfileFormatVersion:
+		return &FormatVersionError{Name: l.Name, Version: l.FormatVersion}
+	case l.FormatVersion < 0 || l.Depth < 0 || l.TokenCount < 0:
+		return fmt.Errorf("language %q has invalid metadata", l.Name)
+	case l.FormatVersion == 0 || l.Depth == 0:
+		return nil
+	}
+	for token := range l.Profile {
+		// a depth of n creates tokens of up to n+1 letters
+		if utf8.RuneCountInString(token) > l.Depth+1 {
+			return fmt.Errorf("language %q has the token %q, deeper than its depth %d", l.Name, token, l.Depth)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package langdet_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProfileMetadata(t *testing.T) {
+	Convey("Subject: Metadata of language profiles", t, func() {
+		Convey("Metadata should survive the JSON format", func() {
+			trainer := &langdet.Trainer{Depth: 2, Source: "test corpus"}
+			trainer.Feed("the quick brown fox")
+			built := trainer.Build("english")
+			data, err := json.Marshal(built)
+			So(err, ShouldBeNil)
+			d := langdet.NewDetector()
+			So(d.LoadLanguagesFromFS(fstest.MapFS{"english.json": {Data: data}}, "."), ShouldBeNil)
+			loaded := d.Snapshot()[0]
+			So(loaded.TokenCount, ShouldEqual, built.TokenCount)
+			So(loaded.SourceDescription, ShouldEqual, "test corpus")
+			So(loaded.CreatedAt.Equal(built.CreatedAt), ShouldBeTrue)
+			So(loaded.FormatVersion, ShouldEqual, langdet.ProfileFormatVersion)
+		})
+		Convey("Profiles of the rank-only format should load upgraded, without metadata", func() {
+			d, err := langdet.NewDetectorFromReader(strings.NewReader(`[{"Name": "english", "Profile": {"e": 1, "th": 2, "_th": 3}}]`))
+			So(err, ShouldBeNil)
+			loaded := d.Snapshot()[0]
+			So(loaded.Depth, ShouldEqual, 2)
+			So(loaded.Size, ShouldEqual, langdet.DefaultProfileSize)
+			So(loaded.FormatVersion, ShouldEqual, langdet.ProfileFormatVersion)
+			So(loaded.CreatedAt, ShouldResemble, time.Time{})
+
+			data, err := json.Marshal(loaded)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldNotContainSubstring, "CreatedAt")
+		})
+		Convey("Profiles of newer formats should be rejected", func() {
+			_, err := langdet.NewDetectorFromReader(strings.NewReader(`[{"Name": "english", "Profile": {"e": 1}, "FormatVersion": 99}]`))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "format version 99")
+			var versionErr *langdet.FormatVersionError
+			So(errors.As(err, &versionErr), ShouldBeTrue)
+			So(versionErr.Version, ShouldEqual, 99)
+		})
+		Convey("Profiles with tokens deeper than their depth should be rejected", func() {
+			fsys := fstest.MapFS{"english.json": {Data: []byte(`{"Name": "english", "Profile": {"e": 1, "_the_": 2}, "Depth": 2, "FormatVersion": 2}`)}}
+			d := langdet.NewDetector()
+			err := d.LoadLanguagesFromFS(fsys, ".")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "deeper than its depth 2")
+		})
+	})
+}
@@ -0,0 +1,194 @@
+package langdet
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Detection methods of DetectionEvent.Method
+const (
+	MethodClosest   = "closest"
+	MethodLanguages = "languages"
+)
+
+// DetectionEvent is a detection observed by a Collector
+type DetectionEvent struct {
+	// Method is MethodClosest for GetClosestLanguage and the methods based on it, and
+	// MethodLanguages for GetLanguages
+	Method string
+	// Language is the closest language, undefined if the detection is not reliable. For
+	// GetLanguages, which doesn't decide on reliability, it is the first result.
+	Language string
+	// Reliable tells whether a detection of MethodClosest is reliable, it is false for GetLanguages
+	Reliable bool
+	// Latency is the duration of the detection
+	Latency time.Duration
+}
+
+// Collector collects the detections of a Detector, e.g. to export them as metrics of a service,
+// see Detector.Collector. It is called by concurrent detections and must be safe for concurrent use.
+type Collector interface {
+	ObserveDetection(event DetectionEvent)
+}
+
+// observe reports a detection that started at start to the Collector of the detector
+func (d *Detector) observe(method, language string, reliable bool, start time.Time) {
+	if !reliable && method == MethodClosest && language != InsufficientInput {
+		language = "undefined"
+	}
+	d.Collector.ObserveDetection(DetectionEvent{Method: method, Language: language, Reliable: reliable, Latency: time.Since(start)})
+}
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram of DetectorStats
+var DefaultLatencyBuckets = []time.Duration{
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+// DetectorStats is a Collector that counts the detections by method and by language and records a
+// histogram of their latencies, for services that scrape them with Snapshot or export them in the
+// Prometheus text format with WritePrometheus. Its zero value is ready to use.
+type DetectorStats struct {
+	// Buckets are the upper bounds of the latency histogram in increasing order, nil means
+	// DefaultLatencyBuckets. They must not be changed once detections are observed.
+	Buckets []time.Duration
+
+	mu        sync.Mutex
+	methods   map[string]uint64
+	languages map[string]uint64
+	latencies []uint64
+	sum       time.Duration
+}
+
+// StatsSnapshot is the state of a DetectorStats at a point in time
+type StatsSnapshot struct {
+	// Detections is the number of detections by method, see DetectionEvent.Method
+	Detections map[string]uint64
+	// Languages is the number of detections by language, including undefined
+	Languages map[string]uint64
+	// Buckets are the upper bounds of the latency histogram
+	Buckets []time.Duration
+	// Latencies are the cumulative numbers of detections up to the latencies of the Buckets
+	Latencies []uint64
+	// Count is the number of detections, Sum is their total latency
+	Count uint64
+	Sum   time.Duration
+}
+
+// UndefinedRate returns the fraction of the detections of MethodClosest that were undefined, 0 if
+// there were none
+func (s StatsSnapshot) UndefinedRate() float64 {
+	closest := s.Detections[MethodClosest]
+	if closest == 0 {
+		return 0
+	}
+	return float64(s.Languages["undefined"]) / float64(closest)
+}
+
+// ObserveDetection counts a detection
+func (s *DetectorStats) ObserveDetection(event DetectionEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.methods == nil {
+		s.methods = make(map[string]uint64)
+		s.languages = make(map[string]uint64)
+		s.latencies = make([]uint64, len(s.buckets()))
+	}
+	s.methods[event.Method]++
+	s.languages[event.Language]++
+	for i, bound := range s.buckets() {
+		if event.Latency <= bound {
+			s.latencies[i]++
+		}
+	}
+	s.sum += event.Latency
+}
+
+// buckets returns the Buckets or DefaultLatencyBuckets
+func (s *DetectorStats) buckets() []time.Duration {
+	if s.Buckets == nil {
+		return DefaultLatencyBuckets
+	}
+	return s.Buckets
+}
+
+// Snapshot returns a copy of the counters
+func (s *DetectorStats) Snapshot() StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := StatsSnapshot{
+		Detections: make(map[string]uint64, len(s.methods)),
+		Languages:  make(map[string]uint64, len(s.languages)),
+		Buckets:    append([]time.Duration(nil), s.buckets()...),
+		Latencies:  make([]uint64, len(s.buckets())),
+		Sum:        s.sum,
+	}
+	for method, count := range s.methods {
+		snapshot.Detections[method] = count
+		snapshot.Count += count
+	}
+	for language, count := range s.languages {
+		snapshot.Languages[language] = count
+	}
+	copy(snapshot.Latencies, s.latencies)
+	return snapshot
+}
+
+// WritePrometheus writes the counters in the Prometheus text format: the counters
+// langdet_detector_detections_total by method, langdet_detector_languages_total by language and
+// langdet_detector_undefined_total, and the histogram langdet_detector_latency_seconds
+func (s *DetectorStats) WritePrometheus(w io.Writer) error {
+	snapshot := s.Snapshot()
+	ew := &errWriter{w: w}
+	ew.printf("# HELP langdet_detector_detections_total Number of detections by method.\n# TYPE langdet_detector_detections_total counter\n")
+	for _, method := range sortedKeys(snapshot.Detections) {
+		ew.printf("langdet_detector_detections_total{method=%q} %d\n", method, snapshot.Detections[method])
+	}
+	ew.printf("# HELP langdet_detector_languages_total Number of detections by detected language.\n# TYPE langdet_detector_languages_total counter\n")
+	for _, language := range sortedKeys(snapshot.Languages) {
+		ew.printf("langdet_detector_languages_total{language=%q} %d\n", language, snapshot.Languages[language])
+	}
+	ew.printf("# HELP langdet_detector_undefined_total Number of detections that were not reliable.\n# TYPE langdet_detector_undefined_total counter\n")
+	ew.printf("langdet_detector_undefined_total %d\n", snapshot.Languages["undefined"])
+	ew.printf("# HELP langdet_detector_latency_seconds Latency of the detections.\n# TYPE langdet_detector_latency_seconds histogram\n")
+	for i, bound := range snapshot.Buckets {
+		ew.printf("langdet_detector_latency_seconds_bucket{le=\"%g\"} %d\n", bound.Seconds(), snapshot.Latencies[i])
+	}
+	ew.printf("langdet_detector_latency_seconds_bucket{le=\"+Inf\"} %d\n", snapshot.Count)
+	ew.printf("langdet_detector_latency_seconds_sum %g\n", snapshot.Sum.Seconds())
+	ew.printf("langdet_detector_latency_seconds_count %d\n", snapshot.Count)
+	return ew.err
+}
+
+// sortedKeys returns the keys of a map of counters in increasing order
+func sortedKeys(counters map[string]uint64) []string {
+	keys := make([]string, 0, len(counters))
+	for key := range counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// errWriter writes formatted text until the first error, which it keeps
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
@@ -0,0 +1,53 @@
+package langdet_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDetectorStats(t *testing.T) {
+	Convey("Subject: Collect the detections of a detector", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "english")
+		d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+		d.MinimumConfidence = 0.2
+		stats := &langdet.DetectorStats{}
+		d.Collector = stats
+
+		Convey("The detections should be counted by method and language", func() {
+			So(d.GetClosestLanguage("what is your language, I dont know"), ShouldEqual, "english")
+			So(d.GetClosestLanguage("je ne sais pas ce que tu dis"), ShouldEqual, "french")
+			So(d.GetClosestLanguage("zzzz qqqq xxxx"), ShouldEqual, "undefined")
+			d.GetLanguages("je ne sais pas")
+
+			snapshot := stats.Snapshot()
+			So(snapshot.Count, ShouldEqual, 4)
+			So(snapshot.Detections, ShouldResemble, map[string]uint64{langdet.MethodClosest: 3, langdet.MethodLanguages: 1})
+			So(snapshot.Languages, ShouldResemble, map[string]uint64{"english": 1, "french": 2, "undefined": 1})
+			So(snapshot.UndefinedRate(), ShouldAlmostEqual, 1.0/3)
+			So(snapshot.Latencies[len(snapshot.Latencies)-1], ShouldBeLessThanOrEqualTo, snapshot.Count)
+			So(snapshot.Sum, ShouldBeGreaterThan, 0)
+		})
+		Convey("The latencies should be counted in cumulative buckets", func() {
+			stats := &langdet.DetectorStats{Buckets: []time.Duration{time.Millisecond, time.Second}}
+			stats.ObserveDetection(langdet.DetectionEvent{Method: langdet.MethodClosest, Language: "english", Latency: 2 * time.Millisecond})
+			stats.ObserveDetection(langdet.DetectionEvent{Method: langdet.MethodClosest, Language: "english", Latency: 2 * time.Second})
+			So(stats.Snapshot().Latencies, ShouldResemble, []uint64{0, 1})
+		})
+		Convey("The counters should be written in the Prometheus text format", func() {
+			d.GetClosestLanguage("what is your language, I dont know")
+			var buf bytes.Buffer
+			So(stats.WritePrometheus(&buf), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, "# TYPE langdet_detector_latency_seconds histogram\n")
+			So(buf.String(), ShouldContainSubstring, "langdet_detector_detections_total{method=\"closest\"} 1\n")
+			So(buf.String(), ShouldContainSubstring, "langdet_detector_languages_total{language=\"english\"} 1\n")
+			So(buf.String(), ShouldContainSubstring, "langdet_detector_undefined_total 0\n")
+			So(buf.String(), ShouldContainSubstring, "langdet_detector_latency_seconds_bucket{le=\"+Inf\"} 1\n")
+			So(buf.String(), ShouldContainSubstring, "langdet_detector_latency_seconds_count 1\n")
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package langdet
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// MigrateLanguage upgrades a profile of the rank-only JSON format, that only has a Name and a
+// Profile, to the current format: it validates the ranks and the depth of the profile and sets
+// the Depth, Tag, Direction, Scripts and Size that are missing, and the current FormatVersion.
+func MigrateLanguage(language Language) (Language, error) {
+	if len(language.Profile) == 0 {
+		return language, fmt.Errorf("language %q has an empty profile", language.Name)
+	}
+	depth, _, _ := inspectProfile(language.Profile)
+	for token, rank := range language.Profile {
+		if rank <= 0 {
+			return language, fmt.Errorf("language %q: token %q has the invalid rank %d", language.Name, token, rank)
+		}
+	}
+	if language.Depth > 0 && depth > language.Depth {
+		return language, fmt.Errorf("language %q has tokens of depth %d, deeper than its depth %d", language.Name, depth, language.Depth)
+	}
+	if depth <= 0 {
+		return language, fmt.Errorf("language %q has no tokens of a valid depth", language.Name)
+	}
+	if err := language.upgrade(); err != nil {
+		return language, err
+	}
+	return language, nil
+}
+
+// Migrate upgrades the JSON or binary profiles of the directory dir of fsys with MigrateLanguage
+// and writes them to w as a compiled bundle. It returns the migrated languages.
+func Migrate(fsys fs.FS, dir string, w io.Writer) ([]Language, error) {
+	d := NewDetector()
+	if err := d.LoadLanguagesFromFS(fsys, dir); err != nil {
+		return nil, err
+	}
+	languages := d.allLanguages()
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("no profiles found in %s", dir)
+	}
+	migrated := make([]Language, len(languages))
+	for i, language := range languages {
+		var err error
+		if migrated[i], err = MigrateLanguage(language); err != nil {
+			return nil, err
+		}
+	}
+	return migrated, WriteBundle(w, migrated)
+}
@@ -0,0 +1,60 @@
+package langdet_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMigrate(t *testing.T) {
+	Convey("Subject: Migration of legacy profiles to compiled bundles", t, func() {
+		fsys := fstest.MapFS{
+			"legacy/english.json": {Data: []byte(`{"Name": "english", "Profile": {"e": 1, "t": 2, "th": 3, "_th": 4, "the_": 5}}`)},
+			"legacy/russian.json": {Data: []byte(`{"Name": "russian", "Profile": {"о": 1, "е": 2, "_и_": 3}}`)},
+		}
+		Convey("Legacy profiles should get their metadata", func() {
+			var buf bytes.Buffer
+			languages, err := langdet.Migrate(fsys, "legacy", &buf)
+			So(err, ShouldBeNil)
+			So(languages, ShouldHaveLength, 2)
+			So(languages[0].Depth, ShouldEqual, 3)
+			So(languages[0].Tag.ISO6391, ShouldEqual, "en")
+			So(languages[0].Size, ShouldEqual, langdet.DefaultProfileSize)
+			So(languages[1].Depth, ShouldEqual, 2)
+			So(languages[1].Scripts, ShouldResemble, []string{"Cyrillic"})
+
+			Convey("and load from the bundle", func() {
+				read, err := langdet.ReadBundle(bytes.NewReader(buf.Bytes()))
+				So(err, ShouldBeNil)
+				So(read, ShouldResemble, languages)
+
+				d := langdet.NewDetector()
+				So(d.LoadBundleFromReader(bytes.NewReader(buf.Bytes())), ShouldBeNil)
+				So(d.GetLanguages("the the")[0].Name, ShouldEqual, "english")
+
+				name := filepath.Join(t.TempDir(), "bundle.bin")
+				So(os.WriteFile(name, buf.Bytes(), 0644), ShouldBeNil)
+				d, err = langdet.Config{Profiles: []string{name}}.NewDetector()
+				So(err, ShouldBeNil)
+				So(d.Snapshot(), ShouldHaveLength, 2)
+			})
+		})
+		Convey("Invalid profiles should not be migrated", func() {
+			_, err := langdet.MigrateLanguage(langdet.Language{Name: "empty"})
+			So(err, ShouldNotBeNil)
+			_, err = langdet.MigrateLanguage(langdet.Language{Name: "negative", Profile: map[string]int{"a": -1}})
+			So(err, ShouldNotBeNil)
+			_, err = langdet.MigrateLanguage(langdet.Language{Name: "deep", Depth: 1, Profile: map[string]int{"_ab": 1}})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Other data and newer bundles should be rejected", func() {
+			_, err := langdet.ReadBundle(bytes.NewReader([]byte(`{"Name": "english"}`)))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -1,5 +1,10 @@
 package langdet
 
+import (
+	"sort"
+	"time"
+)
+
 // Token represents a text token and its occurence in an analyzed text
 type Token struct {
 	Occurrence int
@@ -13,7 +18,7 @@ func (a ByOccurrence) Len() int      { return len(a) }
 func (a ByOccurrence) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 func (a ByOccurrence) Less(i, j int) bool {
 	if a[i].Occurrence == a[j].Occurrence {
-		return a[i].Key < a[i].Key
+		return a[i].Key < a[j].Key
 	}
 	return a[i].Occurrence < a[j].Occurrence
 }
@@ -22,18 +27,124 @@ func (a ByOccurrence) Less(i, j int) bool {
 type Language struct {
 	Profile map[string]int
 	Name    string
+	// Tag identifies the language by its ISO 639 codes. If it is empty, LookupTag looks it up
+	// by the Name of the language.
+	Tag LanguageTag
+	// Depth is the n-gram depth the profile was created with. If it is 0, the loaders of this
+	// package infer it from the longest tokens of the profile.
+	Depth int `json:",omitempty"`
+	// Direction is the text direction of the language, LeftToRight or RightToLeft.
+	// If it is empty, TextDirection infers it from the profile.
+	Direction string `json:",omitempty"`
+	// Scripts are the unicode scripts of the language, most frequent first.
+	// If it is empty, TextScripts infers them from the profile.
+	Scripts []string `json:",omitempty"`
+	// Mix lists the languages of a code-mixed variety (e.g. "hi" and "en" for a "hi-en-mixed" profile),
+	// it is empty for pure languages
+	Mix []string `json:",omitempty"`
+	// Samples are held-out sentences of the language, that are used by Detector.SelfTest
+	Samples []string `json:",omitempty"`
+	// Size is the number of ranks the profile was trained with, 0 if it is unknown. Profiles can be
+	// compared with fewer ranks, see Detector.ProfileSize.
+	Size int `json:",omitempty"`
+	// Counts are the raw numbers of occurrences of the n-grams the profile was built from, if the
+	// Trainer kept them. They allow Update to extend the profile exactly.
+	Counts map[string]int `json:",omitempty"`
+	// TokenCount is the number of n-grams the profile was trained from, 0 if it is unknown
+	TokenCount int `json:",omitempty"`
+	// SourceDescription describes the training corpus, e.g. the dumps the profile was trained from
+	SourceDescription string `json:",omitempty"`
+	// CreatedAt is the time the profile was built, zero if it is unknown
+	CreatedAt time.Time `json:",omitzero"`
+	// WordProfile ranks the most frequent lowercase words of the language like Profile ranks its
+	// n-grams, if the Trainer counted words. It separates closely related languages that share
+	// most n-grams, see Detector.WordWeight.
+	WordProfile map[string]int `json:",omitempty"`
+	// UnknownBelow is the confidence, between 0 and 1, up to which texts are more likely of an
+	// untrained language than of this one, see FitUnknown. 0 means no cutoff.
+	UnknownBelow float64 `json:",omitempty"`
+	// CaseFolded tells that the profile was trained from lowercased text. A Detector may have a
+	// cased and a case-folded variant of a language of the same name, see Detector.FoldCase.
+	CaseFolded bool `json:",omitempty"`
+	// FormatVersion is the ProfileFormatVersion the profile was written with, 0 for profiles of
+	// the rank-only format that predates it. The loaders of this package upgrade profiles of older
+	// versions to ProfileFormatVersion and reject profiles of newer versions, see FormatVersionError.
+	FormatVersion int `json:",omitempty"`
+	// Group is the family or group of the language, e.g. "Romance", whose languages are easily
+	// confused. If it is empty, LanguageGroup looks up the group of common languages.
+	Group string `json:",omitempty"`
+}
+
+// Rerank rebuilds consistent ranks of the profile after its Counts or ranks were edited, e.g. to
+// boost domain tokens or after merging profiles. Languages with Counts get the profile of their
+// counts, like a trained profile. Otherwise the edited ranks become 1..n, tokens keep their order
+// by rank and tokens of the same rank are ordered by the token, so that the result is deterministic.
+// Compiled forms of the language are not changed, they must be compiled again.
+func (l *Language) Rerank() {
+	if l.Counts != nil {
+		size := l.Size
+		if size <= 0 {
+			size = DefaultProfileSize
+		}
+		l.Profile = rankLookupMap(l.Counts, size)
+		return
+	}
+	tokens := make([]Token, 0, len(l.Profile))
+	for key, rank := range l.Profile {
+		tokens = append(tokens, Token{Key: key, Occurrence: rank})
+	}
+	sort.Sort(ByOccurrence(tokens))
+	for i, token := range tokens {
+		l.Profile[token.Key] = i + 1
+	}
 }
 
 // DetectionResult represents the result from comparing 2 Profiles. It includes the confidence which is basically the
-// the relative distance between the two profiles.
+// the relative distance between the two profiles, and the numbers it was calculated from, so that callers can
+// implement their own acceptance logic.
 type DetectionResult struct {
-	Name       string
-	Confidence int
+	Name string
+	// Tag identifies the language by its ISO 639 codes, it is empty for unknown languages
+	Tag LanguageTag
+	// Confidence is the relative distance between the profiles in percent, between 0 and 100
+	Confidence float64
+	// Distance is the raw out-of-place distance between the compared input tokens and the profile
+	Distance int
+	// MatchedTokens is the number of compared input tokens found in the profile of the language
+	MatchedTokens int
+	// ComparedTokens is the number of top ranked input tokens that were compared with the profile
+	ComparedTokens int
+	// InputTokens is the number of distinct tokens of the input
+	InputTokens int
+	// Direction is the text direction of the language, LeftToRight or RightToLeft
+	Direction string
+	// GibberishScore tells how badly the compared input tokens fit the language model of the
+	// profile, between 0 for the most frequent tokens only and 1 for tokens unknown to the
+	// language. The GibberishScore of the closest language flags keyboard mash and spam.
+	GibberishScore float64
+	// Group is the group of the language, see Language.LanguageGroup, empty if it has none
+	Group string
+	// GroupConfidence is the highest confidence of the languages of the Group in percent, the
+	// Confidence if the language has no group. It is set by GetLanguages and the methods based on it.
+	GroupConfidence float64
 }
 
-//ResByConf represents an array of DetectionResult and can be sorted by Confidence.
+// ResByConf represents an array of DetectionResult and can be sorted by Confidence, most confident
+// first. Results of the same confidence are ordered by the Name of their language, so that the order
+// of the results of a detection is deterministic.
 type ResByConf []DetectionResult
 
-func (a ResByConf) Len() int           { return len(a) }
-func (a ResByConf) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a ResByConf) Less(i, j int) bool { return a[i].Confidence > a[j].Confidence }
+func (a ResByConf) Len() int      { return len(a) }
+func (a ResByConf) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ResByConf) Less(i, j int) bool {
+	if a[i].Confidence != a[j].Confidence {
+		return a[i].Confidence > a[j].Confidence
+	}
+	return a[i].Name < a[j].Name
+}
+
+// sortResults sorts results in the order of ResByConf. The sort is stable, results of the same
+// confidence and name keep their order.
+func sortResults(results []DetectionResult) {
+	sort.SliceStable(results, ResByConf(results).Less)
+}
@@ -0,0 +1,140 @@
+// Package ngram extracts the n-grams that langdet profiles are made of. Texts are split into
+// words and every word is padded with underscores, so that its first and last letters make their
+// own n-grams: with depth 2, "abc" is padded as "__abc__" and has the n-grams "a", "b", "c",
+// "_a", "ab", "bc", "c_", "__a", "_ab", "abc", "bc_" and "c__".
+//
+// Words are separated by spaces and the characters of Separators. Digits are dropped without
+// separating words, every other character is a letter of a word. The langdet package extracts the
+// n-grams of texts with the zero Options.
+package ngram
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Padding is the character that pads words
+const Padding = '_'
+
+// Separators are the characters besides the space that separate words
+const Separators = "\n,#/\\.!?:;-'\"_*"
+
+// Options customize Extract, the zero value extracts n-grams like the langdet package
+type Options struct {
+	// NoPadding extracts the n-grams of the bare words, without the n-grams that mark the
+	// beginnings and ends of words
+	NoPadding bool
+	// Lowercase maps letters to lower case before they are extracted
+	Lowercase bool
+	// KeepFormatControls keeps zero-width and bidi control characters, see StripFormatControl
+	KeepFormatControls bool
+}
+
+// Extract returns the occurrences of the n-grams of 1 to depth+1 letters of a text. It returns an
+// empty map for negative depths.
+func Extract(text string, depth int, opts Options) map[string]int {
+	counts := make(map[string]int)
+	Update(counts, text, depth, opts)
+	return counts
+}
+
+// Update adds the occurrences of the n-grams of a text to counts like Extract, so that the n-grams
+// of many texts can be counted together
+func Update(counts map[string]int, text string, depth int, opts Options) {
+	if depth < 0 {
+		return
+	}
+	b := builders.Get().(*builder)
+	defer builders.Put(b)
+	b.word = b.word[:0]
+	pad := depth
+	if opts.NoPadding {
+		pad = 0
+	}
+	for _, r := range text {
+		if !opts.KeepFormatControls {
+			if r = StripFormatControl(r); r < 0 {
+				continue
+			}
+		}
+		switch {
+		case r >= '0' && r <= '9':
+			// digits are removed, they don't separate words
+		case r == ' ' || strings.ContainsRune(Separators, r):
+			b.count(counts, depth, pad)
+		default:
+			if opts.Lowercase {
+				r = unicode.ToLower(r)
+			}
+			b.word = utf8.AppendRune(b.word, r)
+		}
+	}
+	b.count(counts, depth, pad)
+}
+
+// StripFormatControl maps zero-width and bidi control characters for strings.Map. The zero width
+// space separates words in some scripts, so it is replaced by a space, the others are removed.
+func StripFormatControl(r rune) rune {
+	switch {
+	case r == '\u200B':
+		return ' '
+	case r >= '\u200C' && r <= '\u200F', // zero width (non-)joiner, left-to-right and right-to-left marks
+		r >= '\u202A' && r <= '\u202E', // bidi embeddings and overrides
+		r >= '\u2060' && r <= '\u2064', // word joiner and invisible operators
+		r >= '\u2066' && r <= '\u2069', // bidi isolates
+		r == '\u061C', r == '\uFEFF':   // arabic letter mark, zero width no-break space
+		return -1
+	}
+	return r
+}
+
+// builders are the reusable buffers of Update
+var builders = sync.Pool{New: func() interface{} { return &builder{} }}
+
+// builder holds the buffers of the word that Update counts the n-grams of. The n-grams of a word
+// are substrings of a single padded copy of the word, so that counting allocates once per word
+// instead of once per n-gram.
+type builder struct {
+	// word is the current word
+	word []byte
+	// offsets are the byte offsets of the runes of the padded word and its length
+	offsets []int
+}
+
+// count adds the n-grams of up to depth+1 letters of the current word, padded with up to pad
+// underscores, to counts and starts a new word
+func (b *builder) count(counts map[string]int, depth, pad int) {
+	if len(b.word) == 0 {
+		return
+	}
+	padded := make([]byte, 0, len(b.word)+2*pad)
+	for i := 0; i < pad; i++ {
+		padded = append(padded, Padding)
+	}
+	padded = append(padded, b.word...)
+	for i := 0; i < pad; i++ {
+		padded = append(padded, Padding)
+	}
+	word := string(padded)
+	b.word = b.word[:0]
+
+	b.offsets = b.offsets[:0]
+	for i := range word {
+		b.offsets = append(b.offsets, i)
+	}
+	b.offsets = append(b.offsets, len(word))
+	letters := len(b.offsets) - 1 - 2*pad
+	for n := 1; n <= depth+1; n++ {
+		// the n-grams of the word padded with n-1 underscores, or with all of the padding if it is shorter
+		padding := n - 1
+		if padding > pad {
+			padding = pad
+		}
+		first := pad - padding
+		for p := first; p+n <= first+letters+2*padding; p++ {
+			counts[word[b.offsets[p]:b.offsets[p+n]]]++
+		}
+	}
+}
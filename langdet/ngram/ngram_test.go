@@ -0,0 +1,45 @@
+package ngram_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/ngram"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExtract(t *testing.T) {
+	Convey("Subject: Extract the n-grams of a text", t, func() {
+		Convey("Words should be padded with underscores", func() {
+			So(ngram.Extract("abc", 2, ngram.Options{}), ShouldResemble, map[string]int{
+				"a": 1, "b": 1, "c": 1,
+				"_a": 1, "ab": 1, "bc": 1, "c_": 1,
+				"__a": 1, "_ab": 1, "abc": 1, "bc_": 1, "c__": 1,
+			})
+		})
+		Convey("Bare words should only have their own n-grams without padding", func() {
+			So(ngram.Extract("abc ab", 2, ngram.Options{NoPadding: true}), ShouldResemble, map[string]int{
+				"a": 2, "b": 2, "c": 1, "ab": 2, "bc": 1, "abc": 1,
+			})
+		})
+		Convey("Separators should split words and digits should be dropped", func() {
+			So(ngram.Extract("a1b,c", 0, ngram.Options{}), ShouldResemble, map[string]int{"a": 1, "b": 1, "c": 1})
+			So(ngram.Extract("a1b,c", 1, ngram.Options{NoPadding: true}), ShouldResemble, map[string]int{"a": 1, "b": 1, "c": 1, "ab": 1})
+		})
+		Convey("Letters should be lowercased on request", func() {
+			So(ngram.Extract("AbÇ", 0, ngram.Options{}), ShouldResemble, map[string]int{"A": 1, "b": 1, "Ç": 1})
+			So(ngram.Extract("AbÇ", 0, ngram.Options{Lowercase: true}), ShouldResemble, map[string]int{"a": 1, "b": 1, "ç": 1})
+		})
+		Convey("Format controls should be stripped unless they are kept", func() {
+			So(ngram.Extract("a\u200Db\u200Bc", 0, ngram.Options{}), ShouldResemble, map[string]int{"a": 1, "b": 1, "c": 1})
+			So(ngram.Extract("a\u200Db", 0, ngram.Options{KeepFormatControls: true}), ShouldContainKey, "\u200D")
+		})
+		Convey("Negative depths should have no n-grams", func() {
+			So(ngram.Extract("abc", -1, ngram.Options{}), ShouldBeEmpty)
+		})
+		Convey("The zero Options should extract the n-grams of langdet", func() {
+			text := "Über den Wolken muss die Freiheit wohl grenzenlos sein, 42 mal!"
+			So(ngram.Extract(text, 3, ngram.Options{}), ShouldResemble, langdet.CreateOccurenceMap(text, 3))
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package langdet
+
+// LanguageOptions tune the detection of a single language of a Detector
+type LanguageOptions struct {
+	// MinConfidence replaces the MinimumConfidence of the detector for this language, if not 0
+	MinConfidence float32 `json:"minConfidence" yaml:"minConfidence"`
+	// Prior weights the confidence of this language, e.g. 1.2 favors it and 0.8 penalizes it.
+	// Confidences are capped at 100 percent, 0 means no weight.
+	Prior float64 `json:"prior" yaml:"prior"`
+}
+
+// SetLanguageOptions sets the options of the named language, which are applied when texts are
+// compared with it. Profiles trained on much more data than others can get a higher threshold
+// or a lower prior, so that they don't win the detection of texts they merely resemble. The Cache
+// is purged.
+func (d *Detector) SetLanguageOptions(name string, options LanguageOptions) {
+	if d.mu != nil {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+	}
+	updated := make(map[string]LanguageOptions, len(d.options)+1)
+	for language, current := range d.options {
+		updated[language] = current
+	}
+	updated[name] = options
+	d.options = updated
+	d.Cache.Purge()
+}
+
+// LanguageOptions returns the options of the named language
+func (d *Detector) LanguageOptions(name string) LanguageOptions {
+	return d.languageOptions()[name]
+}
+
+// languageOptions returns the current options of all languages, which must not be modified
+func (d *Detector) languageOptions() map[string]LanguageOptions {
+	if d.mu != nil {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+	}
+	return d.options
+}
+
+// minimumConfidence returns the minimum confidence of the named language between 0 and 1
+func (d *Detector) minimumConfidence(name string) float32 {
+	if minimum := d.LanguageOptions(name).MinConfidence; minimum > 0 {
+		return minimum
+	}
+	return validMinimumConfidence(d.MinimumConfidence)
+}
+
+// validMinimumConfidence returns a minimum confidence, or DefaultMinimumConfidence if it is not
+// above 0 or above 1. The detection methods resolve invalid values without changing the
+// detector, as they may run concurrently.
+func validMinimumConfidence(minimum float32) float32 {
+	if minimum <= 0 || minimum > 1 {
+		return DefaultMinimumConfidence
+	}
+	return minimum
+}
+
+// weigh applies the prior of the options to the confidence of result
+func (o LanguageOptions) weigh(result *DetectionResult) {
+	if o.Prior <= 0 {
+		return
+	}
+	result.Confidence *= o.Prior
+	if result.Confidence > 100 {
+		result.Confidence = 100
+	}
+}
@@ -0,0 +1,42 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLanguageOptions(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Per-language thresholds and priors", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+		text := "what is your language"
+		name, confidence, reliable := d.GetClosestLanguageWithConfidence(text)
+		So(name, ShouldEqual, "english")
+
+		Convey("A minimum confidence of a language should replace the one of the detector", func() {
+			d.SetLanguageOptions("english", langdet.LanguageOptions{MinConfidence: float32(confidence) + 0.01})
+			_, _, reliable = d.GetClosestLanguageWithConfidence(text)
+			So(reliable, ShouldBeFalse)
+			d.SetLanguageOptions("english", langdet.LanguageOptions{MinConfidence: float32(confidence) - 0.01})
+			_, _, reliable = d.GetClosestLanguageWithConfidence(text)
+			So(reliable, ShouldBeTrue)
+		})
+		Convey("Priors should weigh the confidences of languages", func() {
+			results := d.GetLanguages(text)
+			d.SetLanguageOptions("french", langdet.LanguageOptions{Prior: 100})
+			weighted := d.GetLanguages(text)
+			So(weighted[0].Name, ShouldEqual, "french")
+			So(weighted[0].Confidence, ShouldEqual, 100)
+			So(weighted[1], ShouldResemble, results[0])
+		})
+		Convey("Languages without options should use the defaults", func() {
+			So(d.LanguageOptions("english"), ShouldResemble, langdet.LanguageOptions{})
+			_, weighted, _ := d.GetClosestLanguageWithConfidence(text)
+			So(weighted, ShouldEqual, confidence)
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package langdet_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var parallelTexts = []string{
+	"The village lies in a valley between two rivers and has about three thousand inhabitants.",
+	"Le village se trouve dans une vallée entre deux rivières et compte environ trois mille habitants.",
+	"Das Dorf liegt in einem Tal zwischen zwei Flüssen und hat etwa dreitausend Einwohner.",
+	"Il villaggio si trova in una valle tra due fiumi e ha circa tremila abitanti.",
+	"El pueblo está en un valle entre dos ríos y tiene unos tres mil habitantes.",
+}
+
+// manyLanguages returns a detector with n languages of varying texts and depths
+func manyLanguages(n int) langdet.Detector {
+	d := langdet.NewDetector()
+	for i := 0; i < n; i++ {
+		trainer := &langdet.Trainer{Depth: 2 + i%3}
+		trainer.Feed(strings.Repeat(parallelTexts[i%len(parallelTexts)]+" ", 1+i%4) + parallelTexts[(i+1)%len(parallelTexts)])
+		d.AddLanguage(trainer.Build(fmt.Sprintf("lang%03d", i)))
+	}
+	return d
+}
+
+func TestParallelScoring(t *testing.T) {
+	Convey("Subject: Scoring the languages concurrently", t, func() {
+		d := manyLanguages(40)
+		text := strings.Join(parallelTexts, " ")
+		serial := d.GetLanguages(text)
+		d.ParallelScoring = true
+		Convey("Results should be the same as of serial scoring", func() {
+			for i := 0; i < 5; i++ {
+				So(d.GetLanguages(text), ShouldResemble, serial)
+			}
+		})
+	})
+}
+
+func BenchmarkParallelScoring(b *testing.B) {
+	d := manyLanguages(150)
+	text := strings.Repeat(strings.Join(parallelTexts, " "), 20)
+	for _, parallel := range []bool{false, true} {
+		d.ParallelScoring = parallel
+		b.Run(fmt.Sprintf("parallel=%v", parallel), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				d.GetLanguages(text)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package langdet
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// StripPreamble removes a byte order mark, a shebang line, XML declarations, processing instructions,
+// DOCTYPE declarations and leading white space from the start of a text, as they pollute the n-grams
+// of short or sampled texts without telling anything about its language.
+func StripPreamble(text string) string {
+	for {
+		stripped := strings.TrimLeftFunc(strings.TrimPrefix(text, "\uFEFF"), unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(stripped, "#!"):
+			stripped = skipPast(stripped, "\n")
+		case strings.HasPrefix(stripped, "<?"):
+			stripped = skipPast(stripped, "?>")
+		case len(stripped) >= 9 && strings.EqualFold(stripped[:9], "<!DOCTYPE"):
+			stripped = skipPast(stripped, ">")
+		}
+		if stripped == text {
+			return text
+		}
+		text = stripped
+	}
+}
+
+// skipPast returns the text after the first occurrence of sep, or nothing if sep does not occur
+func skipPast(text, sep string) string {
+	i := strings.Index(text, sep)
+	if i < 0 {
+		return ""
+	}
+	return text[i+len(sep):]
+}
+
+// DetectFromReader returns the name of the closest language of the first DefaultMaxDetectBytes of
+// a reader, or undefined like GetClosestLanguage. The preamble of the text is stripped, see StripPreamble.
+func (d *Detector) DetectFromReader(reader io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, DefaultMaxDetectBytes))
+	if err != nil {
+		return "undefined", err
+	}
+	return d.GetClosestLanguage(StripPreamble(string(data))), nil
+}
+
+// DetectFile returns the name of the closest language of the beginning of a file, see DetectFromReader
+func (d *Detector) DetectFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "undefined", err
+	}
+	defer f.Close()
+	return d.DetectFromReader(f)
+}
@@ -0,0 +1,59 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStripPreamble(t *testing.T) {
+	Convey("Subject: Strip file preambles", t, func() {
+		Convey("BOMs, shebangs and declarations should be stripped", func() {
+			So(langdet.StripPreamble("\uFEFFhello"), ShouldEqual, "hello")
+			So(langdet.StripPreamble("#!/usr/bin/env python\nhello"), ShouldEqual, "hello")
+			So(langdet.StripPreamble("\uFEFF<?xml version=\"1.0\"?>\n<!DOCTYPE html>\n<p>hello</p>"), ShouldEqual, "<p>hello</p>")
+			So(langdet.StripPreamble("<!doctype html>hello"), ShouldEqual, "hello")
+		})
+		Convey("Other texts should be unchanged", func() {
+			So(langdet.StripPreamble("hello #! <?xml?>"), ShouldEqual, "hello #! <?xml?>")
+			So(langdet.StripPreamble(""), ShouldEqual, "")
+		})
+	})
+}
+
+func TestDetectFile(t *testing.T) {
+	s := "Hello I am english text, what is your language? I really dont know you say? "
+	Convey("Subject: Detect the language of files", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(s, "english")
+		d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+
+		Convey("The preamble of a reader should not change the result", func() {
+			res, err := d.DetectFromReader(strings.NewReader("\uFEFF<?xml version=\"1.0\" encoding=\"UTF-8\"?>" + s))
+			So(err, ShouldBeNil)
+			So(res, ShouldEqual, "english")
+		})
+		Convey("StripPreamble should strip the preamble of texts for all detection methods", func() {
+			text := "<?xml version=\"1.0\"?>" + s
+			d.StripPreamble = true
+			So(d.GetLanguages(text), ShouldResemble, d.GetLanguages(s))
+			verified, confidence := d.Verify(text, "english")
+			_, expected := d.Verify(s, "english")
+			So(verified, ShouldBeTrue)
+			So(confidence, ShouldEqual, expected)
+		})
+		Convey("Files should be detected and missing files should be errors", func() {
+			path := filepath.Join(t.TempDir(), "script.sh")
+			os.WriteFile(path, []byte("#!/bin/sh\n"+s), 0644)
+			res, err := d.DetectFile(path)
+			So(err, ShouldBeNil)
+			So(res, ShouldEqual, "english")
+			res, err = d.DetectFile(path + ".missing")
+			So(err, ShouldNotBeNil)
+			So(res, ShouldEqual, "undefined")
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPresets(t *testing.T) {
+	Convey("Subject: Use-case presets", t, func() {
+		d := langdet.NewDefaultLanguages()
+		text := "Das ist ein kleiner Text in deutscher Sprache, den wir erkennen wollen."
+		Convey("Presets should set the compared ranks", func() {
+			So(d.ApplyPreset(langdet.PresetFast), ShouldBeNil)
+			So(d.MaxInputRanks, ShouldEqual, 100)
+			So(d.ProfileSize, ShouldEqual, 500)
+			So(d.GetClosestLanguage(text), ShouldEqual, "de")
+			So(d.ApplyPreset(langdet.PresetDefault), ShouldBeNil)
+			So(d.ProfileSize, ShouldEqual, 0)
+			So(d.ApplyPreset("slow"), ShouldNotBeNil)
+		})
+		Convey("Presets should apply to copies sharing the profiles", func() {
+			fast, err := d.WithPreset(langdet.PresetFast)
+			So(err, ShouldBeNil)
+			So(fast.ProfileSize, ShouldEqual, 500)
+			So(d.ProfileSize, ShouldEqual, 0)
+			So(fast.GetLanguages(text)[0].MatchedTokens, ShouldBeLessThan, d.GetLanguages(text)[0].MatchedTokens)
+			d.AddLanguage(langdet.Language{Name: "new", Profile: map[string]int{"a": 1}})
+			So(fast.Snapshot(), ShouldHaveLength, len(d.Snapshot()))
+		})
+		Convey("Configs should apply presets before the other options", func() {
+			d, err := langdet.Config{Preset: langdet.PresetFast, MaxInputRanks: 200}.NewDetector()
+			So(err, ShouldBeNil)
+			So(d.MaxInputRanks, ShouldEqual, 200)
+			So(d.ProfileSize, ShouldEqual, 500)
+			_, err = langdet.Config{Preset: "slow"}.NewDetector()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package langdet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// PIISafe guarantees that this package and the langdet command never log, cache or store input
+// texts, e.g. for GDPR-sensitive deployments. Features that would keep texts beyond a single call,
+// like caches, logs and feedback stores, keep their hashes instead or are disabled while it is set.
+// It should be set before the package is used.
+var PIISafe = false
+
+// hashedTextLength is the number of hex digits of the hashes of HashText
+const hashedTextLength = 16
+
+// HashText returns a short hex encoded SHA-256 hash of a text, which identifies the text without
+// revealing it. Short texts can still be guessed by hashing candidates, so hashes of texts are
+// pseudonymous rather than anonymous.
+func HashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])[:hashedTextLength]
+}
+
+// RedactText returns the representation of an input text for logs and traces: the text itself,
+// or its length and hash if PIISafe is set
+func RedactText(text string) string {
+	if !PIISafe {
+		return text
+	}
+	return fmt.Sprintf("[redacted %d bytes sha256:%s]", len(text), HashText(text))
+}
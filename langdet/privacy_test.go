@@ -0,0 +1,26 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestRedactText(t *testing.T) {
+	Convey("Subject: Redact input texts", t, func() {
+		text := "My name is John Smith"
+		Convey("Texts should be kept unless PIISafe is set", func() {
+			So(langdet.RedactText(text), ShouldEqual, text)
+		})
+		Convey("Texts should be replaced by their length and hash if PIISafe is set", func() {
+			langdet.PIISafe = true
+			defer func() { langdet.PIISafe = false }()
+			redacted := langdet.RedactText(text)
+			So(redacted, ShouldNotContainSubstring, "John")
+			So(redacted, ShouldContainSubstring, "21 bytes")
+			So(redacted, ShouldContainSubstring, langdet.HashText(text))
+			So(langdet.HashText(text), ShouldNotEqual, langdet.HashText(text+"."))
+			So(len(langdet.HashText(text)), ShouldEqual, 16)
+		})
+	})
+}
@@ -0,0 +1,22 @@
+// Package middleeastern embeds the profiles of the Middle Eastern default languages: Arabic and
+// Hebrew, see the profiles package
+package middleeastern
+
+import (
+	_ "embed"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/profiles"
+)
+
+// data are the profiles of the languages, written by the generator of the default languages
+//
+//go:embed languages.json
+var data []byte
+
+var set = profiles.NewSet(data)
+
+// Load returns the languages of this package, named by their ISO 639-1 codes
+func Load() []langdet.Language {
+	return set.Load()
+}
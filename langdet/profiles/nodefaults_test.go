@@ -0,0 +1,20 @@
+//go:build langdet_nodefaults
+
+package profiles_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/profiles/european"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProfilesWithoutDefaults(t *testing.T) {
+	Convey("Subject: Profiles of a language set without the embedded default languages", t, func() {
+		So(langdet.EmbeddedLanguages(), ShouldBeEmpty)
+		detector := langdet.NewDetectorWithLanguages(european.Load()...)
+		So(detector.ListLanguages(), ShouldHaveLength, 5)
+		So(detector.GetClosestLanguage("Wir sind am Wochenende ans Meer gefahren"), ShouldEqual, "de")
+	})
+}
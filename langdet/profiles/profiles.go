@@ -0,0 +1,48 @@
+// Package profiles has the embedded profiles of the default languages in subpackages of language
+// sets, so that applications import the profiles of the languages they need only:
+//
+//	import "github.com/imankulov/go-lang-detector/langdet/profiles/european"
+//
+//	detector := langdet.NewDetectorWithLanguages(european.Load()...)
+//
+// The profiles of the sets that are not imported are not linked into the binary. The langdet
+// package itself embeds all default languages, though, so the sets only make the binary smaller
+// when it is built with the langdet_nodefaults tag:
+//
+//	go build -tags langdet_nodefaults
+//
+// The subpackages are european, with German, English, French, Russian and Turkish, and
+// middleeastern, with Arabic and Hebrew.
+package profiles
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// Set is a set of embedded profiles, which are decoded on first use
+type Set struct {
+	data      []byte
+	once      sync.Once
+	languages []langdet.Language
+}
+
+// NewSet returns the set of the profiles of data, a JSON array of Languages
+func NewSet(data []byte) *Set {
+	return &Set{data: data}
+}
+
+// Load returns the languages of the set. The profiles are decoded once, each call returns a copy of
+// its own. It panics if they cannot be decoded.
+func (s *Set) Load() []langdet.Language {
+	s.once.Do(func() {
+		languages, err := langdet.ParseLanguages(s.data)
+		if err != nil {
+			panic(fmt.Sprintf("Could not unmarshall embedded languages: %v", err))
+		}
+		s.languages = languages
+	})
+	return append([]langdet.Language{}, s.languages...)
+}
@@ -0,0 +1,52 @@
+package profiles_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/profiles"
+	"github.com/imankulov/go-lang-detector/langdet/profiles/european"
+	"github.com/imankulov/go-lang-detector/langdet/profiles/middleeastern"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProfiles(t *testing.T) {
+	Convey("Subject: Profiles of language sets", t, func() {
+		Convey("The sets should have the profiles of the default languages", func() {
+			names := []string{}
+			for _, set := range [][]langdet.Language{european.Load(), middleeastern.Load()} {
+				for _, language := range set {
+					names = append(names, language.Name)
+				}
+			}
+			sort.Strings(names)
+			So(names, ShouldResemble, []string{"ar", "de", "en", "fr", "he", "ru", "tr"})
+		})
+		Convey("The profiles should be those of the embedded default languages", func() {
+			// the default languages are not embedded with the langdet_nodefaults tag
+			embedded := map[string]langdet.Language{}
+			for _, language := range langdet.EmbeddedLanguages() {
+				embedded[language.Name] = language
+			}
+			for _, language := range append(european.Load(), middleeastern.Load()...) {
+				if expected, ok := embedded[language.Name]; ok {
+					So(language.Profile, ShouldResemble, expected.Profile)
+				}
+			}
+		})
+		Convey("Detectors of a set should detect its languages", func() {
+			d := langdet.NewDetectorWithLanguages(european.Load()...)
+			So(d.GetClosestLanguage("Der schnelle braune Fuchs springt über den faulen Hund."), ShouldEqual, "de")
+			So(d.ListLanguages(), ShouldHaveLength, 5)
+		})
+		Convey("Every load should return a copy of its own", func() {
+			languages := european.Load()
+			languages[0].Name = "changed"
+			So(european.Load()[0].Name, ShouldNotEqual, "changed")
+		})
+		Convey("Broken sets should panic", func() {
+			So(func() { profiles.NewSet([]byte("not json")).Load() }, ShouldPanic)
+		})
+	})
+}
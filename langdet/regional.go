@@ -0,0 +1,193 @@
+package langdet
+
+import "strings"
+
+// VariantClassifier disambiguates the regional or script variants of a macro-language, like pt-BR
+// and pt-PT or zh-Hans and zh-Hant, which share too many n-grams to be separated by their profiles.
+type VariantClassifier interface {
+	// ClassifyVariant returns the name of the variant of a text among variants, the languages of
+	// a macro-language, and its confidence between 0 and 1, or false if it can't tell them apart
+	ClassifyVariant(text string, variants []Language) (string, float64, bool)
+}
+
+// VariantResult is the result of the two-stage detection of DetectVariant
+type VariantResult struct {
+	// MacroLanguage is the primary language subtag of the closest language, e.g. pt for pt-BR
+	MacroLanguage string
+	// Variant is the name of the closest variant of the macro-language, or of the closest language
+	// if it has no variants
+	Variant string
+	// Confidence is the confidence of the macro-language, between 0 and 1
+	Confidence float64
+	// VariantConfidence is the confidence of the variant classifier, between 0 and 1, or the
+	// confidence of the closest language if the variants were not classified
+	VariantConfidence float64
+	// Reliable tells whether the macro-language was detected reliably, like the result of
+	// GetClosestLanguageWithConfidence
+	Reliable bool
+}
+
+// DefaultVariantClassifiers returns the built-in classifiers of the variants of macro-languages:
+// Chinese by its simplified and traditional characters, Portuguese by the WordProfiles of its
+// variants
+func DefaultVariantClassifiers() map[string]VariantClassifier {
+	return map[string]VariantClassifier{
+		"zh": HanScriptClassifier{},
+		"pt": WordVariantClassifier{},
+	}
+}
+
+// MacroLanguage returns the primary language subtag of the name of a language, e.g. pt for pt-BR
+// and zh for zh_Hant. Names of macro-languages are returned lowercased.
+func MacroLanguage(name string) string {
+	if i := strings.IndexAny(name, "-_"); i > 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(name)
+}
+
+// DetectVariant detects the macro-language of a text first, with the profiles of all languages,
+// and then the variant of the macro-language with the VariantClassifiers of the detector, or with
+// the profiles of the variants if there is no classifier for it or it can't tell them apart
+func (d *Detector) DetectVariant(text string) VariantResult {
+	name, confidence, reliable := d.closestLanguage(text)
+	return d.classifyVariant(text, name, confidence, reliable)
+}
+
+// classifyVariant completes the result of the closest language of a text by its variant
+func (d *Detector) classifyVariant(text, name string, confidence float64, reliable bool) VariantResult {
+	result := VariantResult{MacroLanguage: MacroLanguage(name), Variant: name, Confidence: confidence, VariantConfidence: confidence, Reliable: reliable}
+	classifier, ok := d.VariantClassifiers[result.MacroLanguage]
+	if !ok {
+		return result
+	}
+	variants := d.variantsOf(result.MacroLanguage)
+	if len(variants) < 2 {
+		return result
+	}
+	if variant, variantConfidence, ok := classifier.ClassifyVariant(d.prepare(text), variants); ok {
+		result.Variant, result.VariantConfidence = variant, variantConfidence
+	}
+	return result
+}
+
+// variantsOf returns the languages of a macro-language
+func (d *Detector) variantsOf(macro string) []Language {
+	variants := []Language{}
+	for _, language := range d.snapshot() {
+		if MacroLanguage(language.Name) == macro {
+			variants = append(variants, language)
+		}
+	}
+	return variants
+}
+
+// WordVariantClassifier classifies variants by comparing the words of a text with the WordProfiles
+// of the variants, see Trainer.WordRanks. Variants without a WordProfile are not compared.
+type WordVariantClassifier struct{}
+
+// ClassifyVariant implements VariantClassifier
+func (WordVariantClassifier) ClassifyVariant(text string, variants []Language) (string, float64, bool) {
+	counts := map[string]int{}
+	countWords(counts, text)
+	if len(counts) == 0 {
+		return "", 0, false
+	}
+	words := CreateRankLookupMap(counts)
+	results := []DetectionResult{}
+	for _, variant := range variants {
+		if len(variant.WordProfile) == 0 {
+			continue
+		}
+		results = append(results, scoreRanks(words, len(words), 0, DetectionResult{Name: variant.Name}, rankMap(variant.WordProfile)))
+	}
+	if len(results) < 2 {
+		return "", 0, false
+	}
+	sortResults(results)
+	if results[0].Confidence == results[1].Confidence {
+		return "", 0, false
+	}
+	return results[0].Name, results[0].Confidence / 100, true
+}
+
+// HanScriptClassifier classifies the simplified and traditional variants of Chinese by the
+// characters of the text that only one of the scripts uses. The variants are told apart by their
+// names: the Hans script subtag or the regions CN, SG and MY for simplified Chinese, and the Hant
+// script subtag or the regions TW, HK and MO for traditional Chinese, e.g. zh-Hans and zh-TW.
+type HanScriptClassifier struct{}
+
+// ClassifyVariant implements VariantClassifier
+func (HanScriptClassifier) ClassifyVariant(text string, variants []Language) (string, float64, bool) {
+	simplified, traditional := 0, 0
+	for _, r := range text {
+		switch hanScripts[r] {
+		case simplifiedHan:
+			simplified++
+		case traditionalHan:
+			traditional++
+		}
+	}
+	if simplified == traditional {
+		return "", 0, false
+	}
+	script, count := simplifiedHan, simplified
+	if traditional > simplified {
+		script, count = traditionalHan, traditional
+	}
+	for _, variant := range variants {
+		if hanVariantScript(variant.Name) == script {
+			return variant.Name, float64(count) / float64(simplified+traditional), true
+		}
+	}
+	return "", 0, false
+}
+
+// Han scripts of the variants of Chinese
+const (
+	simplifiedHan  = "Hans"
+	traditionalHan = "Hant"
+)
+
+// hanVariantScript returns the Han script of a variant of Chinese by the subtags of its name, or
+// nothing if the name doesn't tell it
+func hanVariantScript(name string) string {
+	subtags := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r == '-' || r == '_' })
+	if len(subtags) < 2 {
+		return ""
+	}
+	for _, subtag := range subtags[1:] {
+		switch subtag {
+		case "hans", "cn", "sg", "my":
+			return simplifiedHan
+		case "hant", "tw", "hk", "mo":
+			return traditionalHan
+		}
+	}
+	return ""
+}
+
+// hanPairs are frequent characters in their simplified and traditional form, which only one of
+// the scripts uses
+var hanPairs = []string{
+	"这這", "个個", "们們", "来來", "时時", "说說", "国國", "会會", "对對", "学學",
+	"发發", "经經", "还還", "没沒", "过過", "现現", "为為", "开開", "问問", "见見",
+	"长長", "东東", "车車", "书書", "门門", "马馬", "话話", "语語", "电電", "气氣",
+	"点點", "万萬", "与與", "业業", "两兩", "亲親", "从從", "实實", "动動", "头頭",
+	"关關", "体體", "边邊", "听聽", "让讓", "认認", "觉覺", "机機", "样樣", "间間",
+	"进進", "远遠", "运運", "爱愛", "钱錢", "买買", "卖賣", "写寫", "读讀", "饭飯",
+	"鱼魚", "鸟鳥", "龙龍", "飞飛", "员員", "应應", "带帶", "难難", "处處", "总總",
+	"张張", "报報", "场場", "统統", "题題", "华華", "区區", "历歷", "号號", "节節",
+	"给給", "无無", "义義", "乐樂", "岁歲", "脑腦", "网網", "页頁", "丽麗", "务務",
+}
+
+// hanScripts maps the characters of hanPairs to their script
+var hanScripts = func() map[rune]string {
+	scripts := make(map[rune]string, 2*len(hanPairs))
+	for _, pair := range hanPairs {
+		runes := []rune(pair)
+		scripts[runes[0]] = simplifiedHan
+		scripts[runes[1]] = traditionalHan
+	}
+	return scripts
+}()
@@ -0,0 +1,48 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRegionalVariants(t *testing.T) {
+	Convey("Subject: Regional and script variants of macro-languages", t, func() {
+		So(langdet.MacroLanguage("pt-BR"), ShouldEqual, "pt")
+		So(langdet.MacroLanguage("zh_Hant"), ShouldEqual, "zh")
+		So(langdet.MacroLanguage("english"), ShouldEqual, "english")
+
+		Convey("Chinese variants should be told apart by their characters", func() {
+			d := langdet.NewDetector()
+			d.AddLanguageFromText("这是我们的学校。我们每天都在这里学习中文。他说这个问题很难。", "zh-Hans")
+			d.AddLanguageFromText("這是我們的學校。我們每天都在這裡學習中文。他說這個問題很難。", "zh-Hant")
+			d.AddLanguageFromText("The dog and the cat play in the garden every day.", "english")
+			d.VariantClassifiers = langdet.DefaultVariantClassifiers()
+
+			result := d.DetectVariant("我們的學校很大。")
+			So(result.MacroLanguage, ShouldEqual, "zh")
+			So(result.Variant, ShouldEqual, "zh-Hant")
+			So(result.VariantConfidence, ShouldEqual, 1)
+			So(d.DetectVariant("我们的学校很大。").Variant, ShouldEqual, "zh-Hans")
+			So(d.DetectVariant("The cat plays in the garden.").Variant, ShouldEqual, "english")
+		})
+		Convey("Portuguese variants should be told apart by their words", func() {
+			train := func(text, name string) langdet.Language {
+				trainer := &langdet.Trainer{Depth: 3, WordRanks: 100}
+				trainer.Feed(text)
+				return trainer.Build(name)
+			}
+			d := langdet.NewDetector()
+			d.AddLanguage(
+				train("Você está usando o celular no ônibus. A gente vai pegar o trem para a fazenda. O time ganhou o jogo de futebol.", "pt-BR"),
+				train("Tu estás a usar o telemóvel no autocarro. Nós vamos apanhar o comboio para a quinta. A equipa ganhou o jogo de futebol.", "pt-PT"),
+			)
+			d.VariantClassifiers = langdet.DefaultVariantClassifiers()
+			So(d.DetectVariant("Vou apanhar o autocarro com o telemóvel.").Variant, ShouldEqual, "pt-PT")
+			So(d.DetectVariant("Vou pegar o ônibus com o celular.").Variant, ShouldEqual, "pt-BR")
+			name, _, _ := d.GetClosestLanguageWithConfidence("Vou pegar o ônibus com o celular.")
+			So(name, ShouldEqual, "pt-BR")
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package langdet
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Item is a text to detect together with typed metadata of the caller, like a document ID or a
+// tenant, that is passed through to its Result, so that callers need no bookkeeping of their own
+// to match results to their requests
+type Item[T any] struct {
+	Text string
+	Meta T
+}
+
+// Result is the detection of an Item: the closest language of its text, the confidence between
+// 0 and 1 and whether the detection is reliable like GetClosestLanguageWithConfidence, and the
+// Meta of the item with its type
+type Result[T any] struct {
+	Meta       T
+	Name       string
+	Confidence float64
+	Reliable   bool
+}
+
+// DetectItems returns the Results of items in their order. The items are detected concurrently
+// by the detector like the texts of DetectBatch.
+func DetectItems[T any](d *Detector, items []Item[T], workers int) []Result[T] {
+	results := make([]Result[T], len(items))
+	concurrently(len(items), workers, func(i int) {
+		results[i] = detectItem(d, items[i])
+	})
+	return results
+}
+
+// DetectItemsFunc detects items concurrently like DetectItems and calls f with the Result of every
+// item as soon as its detection finishes, e.g. to write it out without holding all results. f is
+// called by one goroutine at a time, and DetectItemsFunc returns after the last call.
+func DetectItemsFunc[T any](d *Detector, items []Item[T], workers int, f func(Result[T])) {
+	var mu sync.Mutex
+	concurrently(len(items), workers, func(i int) {
+		result := detectItem(d, items[i])
+		mu.Lock()
+		defer mu.Unlock()
+		f(result)
+	})
+}
+
+// DetectItemStream detects the items received from in by workers goroutines, or GOMAXPROCS
+// goroutines if workers is not positive, and sends their Results to the returned channel, which
+// is closed once in is closed and all its items are detected. Results are sent in the order
+// their detection finishes, their Meta tells which item they belong to.
+func DetectItemStream[T any](d *Detector, in <-chan Item[T], workers int) <-chan Result[T] {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	out := make(chan Result[T])
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range in {
+				out <- detectItem(d, item)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// detectItem returns the Result of an item
+func detectItem[T any](d *Detector, item Item[T]) Result[T] {
+	name, confidence, reliable := d.GetClosestLanguageWithConfidence(item.Text)
+	return Result[T]{Meta: item.Meta, Name: name, Confidence: confidence, Reliable: reliable}
+}
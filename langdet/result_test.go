@@ -0,0 +1,56 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// document is caller metadata that flows through the detection
+type document struct {
+	ID     int
+	Tenant string
+}
+
+func TestDetectItems(t *testing.T) {
+	Convey("Subject: Detection results with caller metadata", t, func() {
+		d := langdet.NewDefaultLanguages()
+		items := []langdet.Item[document]{
+			{Text: "Das ist ein kleiner Text in deutscher Sprache.", Meta: document{ID: 1, Tenant: "a"}},
+			{Text: "This is a small text in the english language.", Meta: document{ID: 2, Tenant: "b"}},
+			{Text: "Ceci est un petit texte en langue française.", Meta: document{ID: 3, Tenant: "a"}},
+		}
+		expected := map[int]string{1: "de", 2: "en", 3: "fr"}
+		Convey("Batches should keep the order and the metadata of the items", func() {
+			results := langdet.DetectItems(&d, items, 2)
+			So(results, ShouldHaveLength, 3)
+			for i, result := range results {
+				So(result.Meta, ShouldResemble, items[i].Meta)
+				So(result.Name, ShouldEqual, expected[result.Meta.ID])
+				So(result.Reliable, ShouldBeTrue)
+			}
+		})
+		Convey("Callbacks should get the result of every item", func() {
+			seen := map[int]string{}
+			langdet.DetectItemsFunc(&d, items, 2, func(result langdet.Result[document]) {
+				seen[result.Meta.ID] = result.Name
+			})
+			So(seen, ShouldResemble, expected)
+		})
+		Convey("Streams should pass the metadata through", func() {
+			in := make(chan langdet.Item[document])
+			go func() {
+				for _, item := range items {
+					in <- item
+				}
+				close(in)
+			}()
+			seen := map[int]string{}
+			for result := range langdet.DetectItemStream(&d, in, 0) {
+				seen[result.Meta.ID] = result.Name
+			}
+			So(seen, ShouldResemble, expected)
+		})
+	})
+}
@@ -0,0 +1,184 @@
+package langdet
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// ResultCache is a least recently used cache of the detection results of a Detector, for workloads
+// with many repeated texts like usernames or product titles. Results are keyed by a SHA-256 hash
+// of the normalized text, i.e. after StripPreamble, FoldCase and the Tokenizer, so that the cache
+// never keeps input texts, even without PIISafe. It is safe for concurrent use.
+type ResultCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[resultKey]*list.Element
+	order   *list.List // of *resultEntry, most recently used first
+	stats   ResultCacheStats
+	// generation is incremented by every Purge, so that results of detections that started
+	// before a purge are not cached after it
+	generation uint64
+}
+
+// ResultCacheStats are the counters of a ResultCache
+type ResultCacheStats struct {
+	// Hits is the number of detections answered by the cache
+	Hits uint64
+	// Misses is the number of detections that were not cached
+	Misses uint64
+	// Evictions is the number of results that were evicted to make room for newer ones
+	Evictions uint64
+	// Entries is the current number of cached results
+	Entries int
+}
+
+// HitRate returns the fraction of the detections answered by the cache, 0 before any detection
+func (s ResultCacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// resultKey is the hash of the kind of a detection and its normalized text
+type resultKey [sha256.Size]byte
+
+// The kinds of cached detections
+const (
+	closestKind   = 'c'
+	languagesKind = 'l'
+)
+
+// resultEntry is a cached result of a detection
+type resultEntry struct {
+	key resultKey
+	// generation is the generation of the cache when the detection started
+	generation uint64
+	// closest is the result of closestLanguage
+	closest DetectionResult
+	// reliable tells whether closest is reliable
+	reliable bool
+	// results are the results of GetLanguages
+	results []DetectionResult
+}
+
+// NewResultCache returns a cache of the results of up to size detections, sizes below 1 are
+// raised to 1
+func NewResultCache(size int) *ResultCache {
+	if size < 1 {
+		size = 1
+	}
+	return &ResultCache{size: size, entries: make(map[resultKey]*list.Element), order: list.New()}
+}
+
+// Stats returns the current counters of the cache
+func (c *ResultCache) Stats() ResultCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+// Purge removes all cached results and keeps the counters. The cache of a detector is purged when
+// its languages, language options or word sets change; it must also be purged after its other
+// settings are changed. Detections that started before the purge are not cached.
+func (c *ResultCache) Purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[resultKey]*list.Element)
+	c.order.Init()
+	c.generation++
+}
+
+// currentGeneration returns the generation of the cache, which must be taken before the
+// languages and settings of a detection are read
+func (c *ResultCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// key returns the cache key of a detection of a kind of a normalized text
+func (c *ResultCache) key(kind byte, text string) resultKey {
+	data := make([]byte, 0, len(text)+1)
+	data = append(data, kind)
+	data = append(data, text...)
+	return sha256.Sum256(data)
+}
+
+// get returns the cached entry of a key and counts the hit or miss
+func (c *ResultCache) get(key resultKey) (resultEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return resultEntry{}, false
+	}
+	c.stats.Hits++
+	c.order.MoveToFront(element)
+	return *element.Value.(*resultEntry), true
+}
+
+// add caches an entry, evicting the least recently used entry if the cache is full. Entries of
+// detections that started before the last Purge are dropped.
+func (c *ResultCache) add(entry resultEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry.generation != c.generation {
+		return
+	}
+	if element, ok := c.entries[entry.key]; ok {
+		*element.Value.(*resultEntry) = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(&entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// cachedClosest returns the closest language of a prepared text like closestLanguage, from the
+// Cache if the detector has one
+func (d *Detector) cachedClosest(text string) (string, float64, bool) {
+	if d.Cache == nil {
+		return d.closestPrepared(text)
+	}
+	key := d.Cache.key(closestKind, text)
+	generation := d.Cache.currentGeneration()
+	if entry, ok := d.Cache.get(key); ok {
+		if entry.reliable {
+			d.recent.see(entry.closest.Name)
+		}
+		return entry.closest.Name, entry.closest.Confidence, entry.reliable
+	}
+	name, confidence, reliable := d.closestPrepared(text)
+	d.Cache.add(resultEntry{key: key, generation: generation, closest: DetectionResult{Name: name, Confidence: confidence}, reliable: reliable})
+	return name, confidence, reliable
+}
+
+// cachedLanguages returns the results of all languages for a prepared text like GetLanguages, from
+// the Cache if the detector has one
+func (d *Detector) cachedLanguages(text string) []DetectionResult {
+	if d.Cache == nil {
+		return d.languagesPrepared(text)
+	}
+	key := d.Cache.key(languagesKind, text)
+	generation := d.Cache.currentGeneration()
+	if entry, ok := d.Cache.get(key); ok {
+		return append([]DetectionResult(nil), entry.results...)
+	}
+	results := d.languagesPrepared(text)
+	d.Cache.add(resultEntry{key: key, generation: generation, results: append([]DetectionResult(nil), results...)})
+	return results
+}
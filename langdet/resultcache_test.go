@@ -0,0 +1,73 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResultCache(t *testing.T) {
+	Convey("Subject: Cache the results of repeated texts", t, func() {
+		d := langdet.NewDefaultLanguages()
+		uncached := langdet.NewDefaultLanguages()
+		d.Cache = langdet.NewResultCache(2)
+		text := "Wir sind am Wochenende ans Meer gefahren"
+
+		Convey("Cached results should equal the detected results", func() {
+			name, confidence, reliable := uncached.GetClosestLanguageWithConfidence(text)
+			for i := 0; i < 3; i++ {
+				cachedName, cachedConfidence, cachedReliable := d.GetClosestLanguageWithConfidence(text)
+				So(cachedName, ShouldEqual, name)
+				So(cachedConfidence, ShouldEqual, confidence)
+				So(cachedReliable, ShouldEqual, reliable)
+				So(d.GetLanguages(text), ShouldResemble, uncached.GetLanguages(text))
+			}
+			stats := d.Cache.Stats()
+			So(stats.Misses, ShouldEqual, 2)
+			So(stats.Hits, ShouldEqual, 4)
+			So(stats.Entries, ShouldEqual, 2)
+			So(stats.HitRate(), ShouldAlmostEqual, 4.0/6)
+		})
+		Convey("Texts should be cached after their normalization", func() {
+			d.FoldCase = true
+			d.GetClosestLanguageWithConfidence(text)
+			d.GetClosestLanguageWithConfidence("WIR SIND AM WOCHENENDE ANS MEER GEFAHREN")
+			So(d.Cache.Stats().Hits, ShouldEqual, 1)
+		})
+		Convey("The least recently used results should be evicted", func() {
+			d.GetClosestLanguageWithConfidence("one text")
+			d.GetClosestLanguageWithConfidence("another text")
+			d.GetClosestLanguageWithConfidence("one text")
+			d.GetClosestLanguageWithConfidence("a third text")
+			d.GetClosestLanguageWithConfidence("one text")
+			stats := d.Cache.Stats()
+			So(stats.Hits, ShouldEqual, 2)
+			So(stats.Evictions, ShouldEqual, 1)
+		})
+		Convey("The cache should be purged when the languages change", func() {
+			d.GetClosestLanguageWithConfidence(text)
+			d.RemoveLanguage("de")
+			name, _, _ := d.GetClosestLanguageWithConfidence(text)
+			So(name, ShouldNotEqual, "de")
+			So(d.Cache.Stats().Hits, ShouldEqual, 0)
+		})
+		Convey("The cache should be purged when the options of a language change", func() {
+			_, _, reliable := d.GetClosestLanguageWithConfidence(text)
+			So(reliable, ShouldBeTrue)
+			d.SetLanguageOptions("de", langdet.LanguageOptions{MinConfidence: 0.999})
+			_, _, reliable = d.GetClosestLanguageWithConfidence(text)
+			So(reliable, ShouldBeFalse)
+			d.GetLanguages(text)
+			d.SetWordSet("de", langdet.NewWords("wir"))
+			d.GetLanguages(text)
+			So(d.Cache.Stats().Hits, ShouldEqual, 0)
+		})
+		Convey("A config should enable the cache", func() {
+			c := langdet.Config{ResultCache: 10}
+			configured, err := c.NewDetector()
+			So(err, ShouldBeNil)
+			So(configured.Cache, ShouldNotBeNil)
+		})
+	})
+}
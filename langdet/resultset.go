@@ -0,0 +1,110 @@
+package langdet
+
+// ResultSet is the detection of a text with lookups of the results of its languages by their names
+// and ISO 639 codes, so that callers don't have to search and sort the results themselves:
+//
+//	set := detector.GetResultSet(text)
+//	if set.IsLanguage("en", 0.8) {
+//		// the text is English with a confidence of at least 80%
+//	}
+type ResultSet struct {
+	// Results are the DetectionResults of all languages, most confident first
+	Results []DetectionResult
+	// closest is the index of the result of the closest language, -1 if there is none
+	closest  int
+	reliable bool
+	// index are the indexes of the results by the names and codes of their languages
+	index map[string]int
+}
+
+// NewResultSet returns the ResultSet of sorted results, like those of GetLanguages, whose first
+// result is the closest language unless no language has any confidence, and whether it is reliable
+func NewResultSet(results []DetectionResult, reliable bool) ResultSet {
+	s := ResultSet{Results: results, closest: -1, reliable: reliable, index: make(map[string]int, 2*len(results))}
+	if len(results) > 0 && results[0].Confidence > 0 {
+		s.closest = 0
+	}
+	for i, result := range results {
+		// the most confident result of a code wins
+		for _, key := range []string{result.Name, result.Tag.ISO6391, result.Tag.ISO6393} {
+			if _, ok := s.index[key]; !ok && key != "" {
+				s.index[key] = i
+			}
+		}
+	}
+	return s
+}
+
+// ResultSet returns the ResultSet of the detection of a batch. Its closest language is the
+// Language of the set or, if it is undefined, the first of the Results like NewResultSet.
+func (s DetectionResultSet) ResultSet() ResultSet {
+	set := NewResultSet(s.Results, s.Reliable)
+	if i, ok := set.index[s.Language]; ok {
+		set.closest = i
+	} else if s.Language != "undefined" {
+		// the language was not detected by the results, like InsufficientInput
+		set.closest = -1
+	}
+	return set
+}
+
+// GetResultSet returns the ResultSet of a text, detected like the texts of DetectBatch
+func (d *Detector) GetResultSet(text string) ResultSet {
+	return d.newBatch().detect(text).ResultSet()
+}
+
+// ByLanguage returns the result of a language by its name, its ISO 639-1 or ISO 639-3 code, or a
+// name of the language known to LookupLanguageTag, like "English", and false if it has no result
+func (s ResultSet) ByLanguage(code string) (DetectionResult, bool) {
+	i, ok := s.lookup(code)
+	if !ok {
+		return DetectionResult{}, false
+	}
+	return s.Results[i], true
+}
+
+// lookup returns the index of the result of a language
+func (s ResultSet) lookup(code string) (int, bool) {
+	if i, ok := s.index[code]; ok {
+		return i, true
+	}
+	tag, ok := LookupLanguageTag(code)
+	if !ok {
+		return 0, false
+	}
+	for _, key := range []string{tag.ISO6391, tag.ISO6393} {
+		if i, ok := s.index[key]; ok && key != "" {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Best returns the result of the closest language, and false if there is none, e.g. for texts
+// that are too short to detect. Check IsReliable before trusting it.
+func (s ResultSet) Best() (DetectionResult, bool) {
+	if s.closest < 0 {
+		return DetectionResult{}, false
+	}
+	return s.Results[s.closest], true
+}
+
+// IsReliable tells whether the closest language is confident enough, like the result of
+// GetClosestLanguageWithConfidence
+func (s ResultSet) IsReliable() bool {
+	return s.reliable
+}
+
+// Confidence returns the confidence of a language, looked up like ByLanguage, between 0 and 1, or
+// 0 if it has no result
+func (s ResultSet) Confidence(code string) float64 {
+	result, _ := s.ByLanguage(code)
+	return result.Confidence / 100
+}
+
+// IsLanguage tells whether a language, looked up like ByLanguage, is the closest language with a
+// confidence of at least minConfidence, between 0 and 1
+func (s ResultSet) IsLanguage(code string, minConfidence float64) bool {
+	i, ok := s.lookup(code)
+	return ok && i == s.closest && s.Results[i].Confidence/100 >= minConfidence
+}
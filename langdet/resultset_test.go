@@ -0,0 +1,55 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestResultSet(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Look up the results of a text by language", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "en")
+		d.AddLanguageFromText(fr, "fr")
+		set := d.GetResultSet(en)
+
+		Convey("The best result should be the closest language", func() {
+			best, ok := set.Best()
+			So(ok, ShouldBeTrue)
+			So(best.Name, ShouldEqual, d.GetClosestLanguage(en))
+			So(set.IsReliable(), ShouldBeTrue)
+			So(set.Results, ShouldResemble, d.GetLanguages(en))
+		})
+		Convey("Results should be looked up by name, code and language name", func() {
+			for _, code := range []string{"en", "eng", "English"} {
+				result, ok := set.ByLanguage(code)
+				So(ok, ShouldBeTrue)
+				So(result.Name, ShouldEqual, "en")
+				So(set.Confidence(code), ShouldEqual, result.Confidence/100)
+			}
+			_, ok := set.ByLanguage("de")
+			So(ok, ShouldBeFalse)
+			So(set.Confidence("de"), ShouldEqual, 0)
+		})
+		Convey("Only the closest language should be the language of the text", func() {
+			So(set.IsLanguage("English", 0.5), ShouldBeTrue)
+			So(set.IsLanguage("en", 1.1), ShouldBeFalse)
+			So(set.IsLanguage("fr", 0), ShouldBeFalse)
+		})
+		Convey("Texts without results should have no best result", func() {
+			set := d.GetResultSet("")
+			_, ok := set.Best()
+			So(ok, ShouldBeFalse)
+			So(set.IsReliable(), ShouldBeFalse)
+			So(set.IsLanguage("en", 0), ShouldBeFalse)
+		})
+		Convey("Result sets should be built from results", func() {
+			set := langdet.NewResultSet(d.GetLanguages(fr), false)
+			best, _ := set.Best()
+			So(best.Name, ShouldEqual, "fr")
+			So(set.IsReliable(), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,100 @@
+package langdet
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxAnalyzedRunes is the MaxAnalyzedRunes of detectors whose limit is 0. The n-grams of
+// a text are stable long before 50000 runes.
+const DefaultMaxAnalyzedRunes = 50000
+
+// sampleWindows is the number of windows of SampleWindows
+const sampleWindows = 8
+
+// SamplingStrategy selects the parts of texts longer than the MaxAnalyzedRunes of a detector that
+// are analyzed
+type SamplingStrategy int
+
+const (
+	// SamplePrefix analyzes the beginning of long texts
+	SamplePrefix SamplingStrategy = iota
+	// SampleWindows analyzes evenly spaced windows of long texts, of which the first is their
+	// beginning, for documents whose beginning is not representative, like mails with long
+	// quotes or documents with a foreign title page
+	SampleWindows
+)
+
+// String returns the name of the strategy, as in config files
+func (s SamplingStrategy) String() string {
+	switch s {
+	case SamplePrefix:
+		return "prefix"
+	case SampleWindows:
+		return "windows"
+	}
+	return fmt.Sprintf("SamplingStrategy(%d)", int(s))
+}
+
+// ParseSamplingStrategy returns the strategy of a name, prefix or windows
+func ParseSamplingStrategy(name string) (SamplingStrategy, error) {
+	switch name {
+	case "prefix":
+		return SamplePrefix, nil
+	case "windows":
+		return SampleWindows, nil
+	}
+	return SamplePrefix, fmt.Errorf("unknown sampling strategy %q, expected prefix or windows", name)
+}
+
+// sample returns the part of a text that is analyzed, the whole text if it has up to
+// MaxAnalyzedRunes runes
+func (d *Detector) sample(text string) string {
+	maxRunes := d.MaxAnalyzedRunes
+	if maxRunes == 0 {
+		maxRunes = DefaultMaxAnalyzedRunes
+	}
+	// a text has at least as many bytes as runes and at most UTFMax times as many, most texts are
+	// decided by their length alone
+	if maxRunes < 0 || len(text) <= maxRunes || (len(text) <= maxRunes*utf8.UTFMax && utf8.RuneCountInString(text) <= maxRunes) {
+		return text
+	}
+	if d.Sampling != SampleWindows || maxRunes < sampleWindows {
+		return runePrefix(text, maxRunes)
+	}
+	windowRunes := maxRunes / sampleWindows
+	var b strings.Builder
+	for i := 0; i < sampleWindows; i++ {
+		start := i * len(text) / sampleWindows
+		for start < len(text) && !utf8.RuneStart(text[start]) {
+			start++
+		}
+		window := runePrefix(text[start:], windowRunes)
+		cut := start+len(window) < len(text)
+		if i > 0 {
+			// windows start and end at word boundaries, so that they don't add n-grams of cut words
+			if space := strings.IndexFunc(window, unicode.IsSpace); space >= 0 {
+				window = window[space:]
+			}
+			b.WriteByte('\n')
+		}
+		if end := strings.LastIndexFunc(window, unicode.IsSpace); end > 0 && cut {
+			window = window[:end]
+		}
+		b.WriteString(window)
+	}
+	return b.String()
+}
+
+// runePrefix returns the first n runes of a text
+func runePrefix(text string, n int) string {
+	for i := range text {
+		if n == 0 {
+			return text[:i]
+		}
+		n--
+	}
+	return text
+}
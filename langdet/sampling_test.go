@@ -0,0 +1,42 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+)
+
+func TestSampling(t *testing.T) {
+	Convey("Subject: Sample the texts above MaxAnalyzedRunes", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "english")
+		d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+		// an english beginning of 60000 runes followed by 600000 runes of french
+		text := strings.Repeat("what is your language, I really dont know. ", 1400) + strings.Repeat("je ne sais pas ce que tu dis, et toi? ", 16000)
+
+		Convey("Long texts should be analyzed by their beginning by default", func() {
+			So(d.GetLanguages(text)[0].Name, ShouldEqual, "english")
+		})
+		Convey("Windows should sample the whole text", func() {
+			d.Sampling = langdet.SampleWindows
+			So(d.GetLanguages(text)[0].Name, ShouldEqual, "french")
+		})
+		Convey("A negative limit should analyze whole texts", func() {
+			d.MaxAnalyzedRunes = -1
+			So(d.GetLanguages(text)[0].Name, ShouldEqual, "french")
+		})
+		Convey("Texts up to the limit should be analyzed whole", func() {
+			d.MaxAnalyzedRunes = 60000 + 600000
+			So(d.GetLanguages(text)[0].Name, ShouldEqual, "french")
+		})
+		Convey("Strategies should be parsed by their names", func() {
+			strategy, err := langdet.ParseSamplingStrategy("windows")
+			So(err, ShouldBeNil)
+			So(strategy, ShouldEqual, langdet.SampleWindows)
+			So(strategy.String(), ShouldEqual, "windows")
+			_, err = langdet.ParseSamplingStrategy("middle")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
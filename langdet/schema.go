@@ -0,0 +1,205 @@
+package langdet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// The JSON field names of profiles, i.e. of the fields of Language. Fields of zero values other than
+// Profile, Name and Tag may be omitted.
+const (
+	FieldProfile           = "Profile"
+	FieldName              = "Name"
+	FieldTag               = "Tag"
+	FieldDepth             = "Depth"
+	FieldDirection         = "Direction"
+	FieldScripts           = "Scripts"
+	FieldMix               = "Mix"
+	FieldSamples           = "Samples"
+	FieldSize              = "Size"
+	FieldCounts            = "Counts"
+	FieldTokenCount        = "TokenCount"
+	FieldSourceDescription = "SourceDescription"
+	FieldCreatedAt         = "CreatedAt"
+	FieldWordProfile       = "WordProfile"
+	FieldUnknownBelow      = "UnknownBelow"
+	FieldCaseFolded        = "CaseFolded"
+	FieldFormatVersion     = "FormatVersion"
+	FieldGroup             = "Group"
+)
+
+// The JSON field names of the Tag of profiles, i.e. of the fields of LanguageTag
+const (
+	FieldISO6391     = "ISO6391"
+	FieldISO6393     = "ISO6393"
+	FieldDisplayName = "DisplayName"
+)
+
+// ProfileFields returns the JSON field names of profiles in the order of the fields of Language
+func ProfileFields() []string {
+	return []string{
+		FieldProfile, FieldName, FieldTag, FieldDepth, FieldDirection, FieldScripts, FieldMix, FieldSamples,
+		FieldSize, FieldCounts, FieldTokenCount, FieldSourceDescription, FieldCreatedAt, FieldWordProfile,
+		FieldUnknownBelow, FieldCaseFolded, FieldFormatVersion, FieldGroup,
+	}
+}
+
+// MaxProfileRank is the highest rank of the tokens of valid profiles, see Language.Validate. Trained
+// profiles rank some thousand tokens, much higher ranks only come from corrupted files.
+const MaxProfileRank = 1 << 24
+
+// ParseOptions are the settings of ParseLanguageWithOptions
+type ParseOptions struct {
+	// DisallowUnknownFields rejects profiles with fields that are not ProfileFields, e.g. to verify
+	// that the profiles of a third-party tool don't depend on fields this version ignores
+	DisallowUnknownFields bool
+}
+
+// ParseLanguage decodes and validates the JSON profile of a single language, so that third-party
+// tools that generate profiles can verify that they are compatible, see ParseLanguageWithOptions
+func ParseLanguage(data []byte) (Language, error) {
+	return ParseLanguageWithOptions(data, ParseOptions{})
+}
+
+// ParseLanguageWithOptions decodes and validates the JSON profile of a single language like the
+// loaders of this package, see Language.Validate, and the JSON must not have trailing data. The
+// direction, scripts, tag and depth of the language are inferred if the profile doesn't have them.
+func ParseLanguageWithOptions(data []byte, options ParseOptions) (Language, error) {
+	language := Language{}
+	if err := checkDuplicateKeys(bytes.NewReader(data)); err != nil {
+		return language, fmt.Errorf("could not parse profile: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if options.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&language); err != nil {
+		return language, fmt.Errorf("could not parse profile: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return language, fmt.Errorf("profile %q has trailing data", language.Name)
+	}
+	if err := language.Validate(); err != nil {
+		return language, err
+	}
+	language.infer()
+	return language, nil
+}
+
+// Validate checks that a language can be detected safely: the profile must have a name and ranked
+// tokens, the tokens must be valid UTF-8, ranks must be unique and between 1 and MaxProfileRank,
+// counts must not be negative and the metadata must be valid and fit the tokens. The loaders of
+// this package validate all profiles, so that corrupted or adversarial files are rejected instead
+// of poisoning the scores.
+func (l Language) Validate() error {
+	switch {
+	case l.Name == "":
+		return fmt.Errorf("profile has no %s", FieldName)
+	case len(l.Profile) == 0:
+		return fmt.Errorf("profile %q has no ranked tokens", l.Name)
+	case l.Direction != "" && l.Direction != LeftToRight && l.Direction != RightToLeft:
+		return fmt.Errorf("profile %q has the invalid %s %q", l.Name, FieldDirection, l.Direction)
+	case l.Size < 0 || l.Size > MaxProfileRank || !(l.UnknownBelow >= 0 && l.UnknownBelow <= 1):
+		return fmt.Errorf("profile %q has invalid metadata", l.Name)
+	}
+	if err := validRanks(l.Name, FieldProfile, l.Profile); err != nil {
+		return err
+	}
+	if err := validRanks(l.Name, FieldWordProfile, l.WordProfile); err != nil {
+		return err
+	}
+	for token, count := range l.Counts {
+		if count < 0 {
+			return fmt.Errorf("profile %q has the negative count %d of %q", l.Name, count, token)
+		}
+	}
+	return l.checkMetadata()
+}
+
+// validRanks checks that the ranks of a field of a profile are unique and between 1 and
+// MaxProfileRank, and that the tokens are not empty and valid UTF-8
+func validRanks(name, field string, ranks map[string]int) error {
+	tokens := make(map[int]string, len(ranks))
+	for token, rank := range ranks {
+		if token == "" || !utf8.ValidString(token) {
+			return fmt.Errorf("profile %q has the invalid token %q in its %s", name, token, field)
+		}
+		if rank < 1 || rank > MaxProfileRank {
+			return fmt.Errorf("profile %q has the invalid rank %d of %q in its %s", name, rank, token, field)
+		}
+		if other, ok := tokens[rank]; ok {
+			return fmt.Errorf("profile %q has the rank %d twice in its %s, for %q and %q", name, rank, field, other, token)
+		}
+		tokens[rank] = token
+	}
+	return nil
+}
+
+// errDuplicateKey is the error of JSON objects with a key twice
+var errDuplicateKey = errors.New("duplicate key")
+
+// decodeCheckingKeys stream-decodes the JSON value of reader into target like json.Decoder, and
+// rejects objects with duplicate keys like checkDuplicateKeys. The keys are checked concurrently on
+// the bytes read by the decoder, so that documents are not read into memory twice.
+func decodeCheckingKeys(reader io.Reader, target interface{}) error {
+	pipeReader, pipeWriter := io.Pipe()
+	checked := make(chan error, 1)
+	go func() {
+		err := checkDuplicateKeys(pipeReader)
+		// a failed check stops the decoder, a passed one has read all bytes
+		pipeReader.CloseWithError(err)
+		checked <- err
+	}()
+	err := json.NewDecoder(io.TeeReader(reader, pipeWriter)).Decode(target)
+	pipeWriter.Close()
+	checkErr := <-checked
+	if errors.Is(checkErr, errDuplicateKey) || err == nil {
+		return checkErr
+	}
+	return err
+}
+
+// checkDuplicateKeys returns an error if an object of a JSON document has a key twice, e.g. a token
+// of a profile with two ranks, which the decoder would silently resolve to the last one
+func checkDuplicateKeys(reader io.Reader) error {
+	decoder := json.NewDecoder(reader)
+	// objects are the keys of the open objects, nil for open arrays
+	objects := []map[string]bool{}
+	// key tells whether the next token of the innermost object is a key
+	key := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		inObject := len(objects) > 0 && objects[len(objects)-1] != nil
+		if name, ok := token.(string); ok && inObject && key {
+			keys := objects[len(objects)-1]
+			if keys[name] {
+				return fmt.Errorf("%w %q", errDuplicateKey, name)
+			}
+			keys[name] = true
+			key = false
+			continue
+		}
+		switch token {
+		case json.Delim('{'):
+			objects = append(objects, make(map[string]bool))
+			key = true
+			continue
+		case json.Delim('['):
+			objects = append(objects, nil)
+		case json.Delim('}'), json.Delim(']'):
+			objects = objects[:len(objects)-1]
+		}
+		// after a value, the next token of an object is a key
+		key = len(objects) > 0 && objects[len(objects)-1] != nil
+	}
+}
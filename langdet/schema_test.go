@@ -0,0 +1,108 @@
+package langdet_test
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProfileSchema(t *testing.T) {
+	Convey("Subject: The JSON schema of profiles", t, func() {
+		Convey("The field names should be the fields of encoded profiles", func() {
+			language := langdet.Language{
+				Profile: map[string]int{"a": 1}, Name: "xx", Tag: langdet.LanguageTag{ISO6391: "xx", ISO6393: "xxx", DisplayName: "X"},
+				Depth: 1, Direction: langdet.LeftToRight, Scripts: []string{"Latin"}, Mix: []string{"en"}, Samples: []string{"a"},
+				Size: 1, Counts: map[string]int{"a": 1}, TokenCount: 1, SourceDescription: "test", CreatedAt: time.Now(),
+				WordProfile: map[string]int{"a": 1}, UnknownBelow: 0.5, CaseFolded: true, FormatVersion: langdet.ProfileFormatVersion,
+				Group: "Test",
+			}
+			data, err := json.Marshal(language)
+			So(err, ShouldBeNil)
+			fields := map[string]json.RawMessage{}
+			So(json.Unmarshal(data, &fields), ShouldBeNil)
+			names := []string{}
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			expected := langdet.ProfileFields()
+			sort.Strings(expected)
+			So(names, ShouldResemble, expected)
+
+			tag := map[string]string{}
+			So(json.Unmarshal(fields[langdet.FieldTag], &tag), ShouldBeNil)
+			So(tag, ShouldContainKey, langdet.FieldISO6391)
+			So(tag, ShouldContainKey, langdet.FieldISO6393)
+			So(tag, ShouldContainKey, langdet.FieldDisplayName)
+		})
+		Convey("Valid profiles should be parsed with inferred metadata", func() {
+			language, err := langdet.ParseLanguage([]byte(`{"Name":"ru","Profile":{"_д":1,"да":2,"д":3}}`))
+			So(err, ShouldBeNil)
+			So(language.Depth, ShouldEqual, 1)
+			So(language.Scripts, ShouldResemble, []string{"Cyrillic"})
+			trained := langdet.Analyze("the quick brown fox", "en")
+			data, err := json.Marshal(trained)
+			So(err, ShouldBeNil)
+			parsed, err := langdet.ParseLanguageWithOptions(data, langdet.ParseOptions{DisallowUnknownFields: true})
+			So(err, ShouldBeNil)
+			So(parsed.Profile, ShouldResemble, trained.Profile)
+		})
+		Convey("Invalid profiles should be rejected", func() {
+			for _, profile := range []string{
+				`{"Name":"xx","Profile":{"a":1}} {}`,
+				`{"Profile":{"a":1}}`,
+				`{"Name":"xx","Profile":{}}`,
+				`{"Name":"xx","Profile":{"a":0}}`,
+				`{"Name":"xx","Profile":{"a":1,"b":1}}`,
+				`{"Name":"xx","Profile":{"a":1},"Counts":{"a":-1}}`,
+				`{"Name":"xx","Profile":{"a":1},"Direction":"up"}`,
+				`{"Name":"xx","Profile":{"a":1},"UnknownBelow":2}`,
+				`{"Name":"xx","Profile":{"abc":1},"Depth":1,"FormatVersion":2}`,
+				`{"Name":"xx","Profile":{"a":1},"FormatVersion":99}`,
+				`{"Name":"xx","Profile":{"a":1},"WordProfile":{"":1}}`,
+				`{"Name":"xx","Profile":{"a":1,"b":16777217}}`,
+				`{"Name":"xx","Profile":{"a":1,"b":2,"a":3}}`,
+				`{"Name":"xx","Profile":{"a":1},"Size":9223372036854775807}`,
+			} {
+				_, err := langdet.ParseLanguage([]byte(profile))
+				So(err, ShouldNotBeNil)
+			}
+		})
+		Convey("Loaders should reject the profiles that Validate rejects", func() {
+			So(langdet.Language{Name: "xx", Profile: map[string]int{"a": -1}}.Validate(), ShouldNotBeNil)
+			// JSON decodes invalid UTF-8 as replacement characters, binary profiles keep it
+			So(langdet.Language{Name: "xx", Profile: map[string]int{"a\xff": 1}}.Validate(), ShouldNotBeNil)
+			So(langdet.Language{Name: "xx", Profile: map[string]int{"a": 1}}.Validate(), ShouldBeNil)
+			fsys := fstest.MapFS{
+				"huge/xx.json":      {Data: []byte(`{"Name":"xx","Profile":{"a":1,"b":9223372036854775807}}`)},
+				"duplicate/xx.json": {Data: []byte(`{"Name":"xx","Profile":{"a":1,"a":2}}`)},
+			}
+			d := langdet.NewDetector()
+			So(d.LoadLanguagesFromFS(fsys, "huge"), ShouldNotBeNil)
+			So(d.LoadLanguagesFromFS(fsys, "duplicate"), ShouldNotBeNil)
+			So(d.Snapshot(), ShouldBeEmpty)
+		})
+		Convey("Streamed profiles should be checked for duplicate keys", func() {
+			d := langdet.NewDetector()
+			err := d.LoadLanguagesFromReader(strings.NewReader(`[{"Name":"xx","Profile":{"a":1}},{"Name":"yy","Profile":{"b":1,"b":2}}]`))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, `duplicate key "b"`)
+			So(d.LoadLanguagesFromReader(strings.NewReader(`[{"Name":"xx","Profile":{"a":1}},{"Name":"yy","Profile":{"b":1}}]`)), ShouldBeNil)
+			So(d.ListLanguages(), ShouldResemble, []string{"xx", "yy"})
+			So(d.LoadLanguagesFromReader(strings.NewReader(`[{"Name":"xx","Profile":{"a":"one"}}]`)), ShouldNotBeNil)
+		})
+		Convey("Unknown fields should only be rejected on request", func() {
+			profile := []byte(`{"Name":"xx","Profile":{"a":1},"Extra":true}`)
+			_, err := langdet.ParseLanguage(profile)
+			So(err, ShouldBeNil)
+			_, err = langdet.ParseLanguageWithOptions(profile, langdet.ParseOptions{DisallowUnknownFields: true})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,127 @@
+package langdet
+
+import (
+	"sort"
+	"unicode"
+)
+
+// commonScripts are checked first when looking up the script of a letter
+var commonScripts = []string{"Latin", "Cyrillic", "Arabic", "Hebrew", "Greek", "Han", "Hiragana", "Katakana", "Hangul", "Devanagari", "Thai"}
+
+// scriptTokens is the number of top ranked tokens of a profile that are used to infer its scripts
+const scriptTokens = 300
+
+// minScriptShare is the minimum fraction of the letters of a profile's top ranked tokens,
+// for a script to be one of the scripts of the language
+const minScriptShare = 0.05
+
+// ScriptOf returns the name of the unicode script of r, as in unicode.Scripts,
+// or "Unknown" if r is not part of any script
+func ScriptOf(r rune) string {
+	for _, name := range commonScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return "Unknown"
+}
+
+// DetectScript returns the name of the unicode script most letters of the text are written in,
+// e.g. "Latin", "Cyrillic" or "Han", or an empty string if the text has no letters
+func DetectScript(text string) string {
+	counts := make(map[string]int)
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			counts[ScriptOf(r)]++
+		}
+	}
+	return mostFrequentScript(counts)
+}
+
+// TextScripts returns the unicode scripts of the language, most frequent first. If the Scripts of
+// the language are not set, they are inferred from the letters of the top ranked tokens of its
+// profile; scripts of less than 5% of the letters are ignored.
+func (l Language) TextScripts() []string {
+	if l.Scripts != nil {
+		return l.Scripts
+	}
+	counts := make(map[string]int)
+	letters := 0
+	for token, rank := range l.Profile {
+		if rank > scriptTokens {
+			continue
+		}
+		for _, r := range token {
+			if unicode.IsLetter(r) {
+				counts[ScriptOf(r)]++
+				letters++
+			}
+		}
+	}
+	scripts := []string{}
+	for script, count := range counts {
+		if float64(count) >= minScriptShare*float64(letters) {
+			scripts = append(scripts, script)
+		}
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		if counts[scripts[i]] == counts[scripts[j]] {
+			return scripts[i] < scripts[j]
+		}
+		return counts[scripts[i]] > counts[scripts[j]]
+	})
+	return scripts
+}
+
+// writtenIn tells whether the language is written in script. Languages without known scripts
+// are assumed to be written in any script.
+func (l Language) writtenIn(script string) bool {
+	scripts := l.TextScripts()
+	if len(scripts) == 0 {
+		return true
+	}
+	for _, s := range scripts {
+		if s == script {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupScript returns the script of most letters of the input of lookupMap, estimating the
+// frequency of its single letter tokens from their ranks
+func lookupScript(lookupMap map[string]int) string {
+	counts := make(map[string]int)
+	for token, rank := range lookupMap {
+		letters := []rune(token)
+		if len(letters) == 1 && unicode.IsLetter(letters[0]) {
+			counts[ScriptOf(letters[0])] += len(lookupMap) - rank + 1
+		}
+	}
+	return mostFrequentScript(counts)
+}
+
+// mostFrequentScript returns the script with the highest count, or an empty string if there is none
+func mostFrequentScript(counts map[string]int) string {
+	best := ""
+	for script, count := range counts {
+		if best == "" || count > counts[best] || count == counts[best] && script < best {
+			best = script
+		}
+	}
+	return best
+}
+
+// inputScript returns the script of the input of lookupMap, or an empty string if the
+// script filter is disabled
+func (d *Detector) inputScript(lookupMap map[string]int) string {
+	if d.DisableScriptFilter {
+		return ""
+	}
+	return lookupScript(lookupMap)
+}
@@ -0,0 +1,47 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDetectScript(t *testing.T) {
+	Convey("Subject: Detect the script of texts", t, func() {
+		Convey("The script of most letters should be detected", func() {
+			So(langdet.DetectScript("Hello world"), ShouldEqual, "Latin")
+			So(langdet.DetectScript("Привет, как дела? OK"), ShouldEqual, "Cyrillic")
+			So(langdet.DetectScript("这是中文"), ShouldEqual, "Han")
+			So(langdet.DetectScript("שלום עולם"), ShouldEqual, "Hebrew")
+			So(langdet.DetectScript("नमस्ते दुनिया"), ShouldEqual, "Devanagari")
+			So(langdet.DetectScript("123 !?"), ShouldEqual, "")
+		})
+		Convey("The scripts of languages should be inferred from their profiles", func() {
+			So(langdet.Analyze("Hello world", "en").Scripts, ShouldResemble, []string{"Latin"})
+			So(langdet.Analyze("Привет мир", "ru").Scripts, ShouldResemble, []string{"Cyrillic"})
+			So(langdet.Analyze("mera naam राहुल है", "hi-en").Scripts, ShouldResemble, []string{"Latin", "Devanagari"})
+		})
+	})
+}
+
+func TestScriptFilter(t *testing.T) {
+	Convey("Subject: Compare texts only with languages of their script", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "english")
+		d.AddLanguageFromText("Привет, я русский текст. Какой у тебя язык? Я правда не знаю, что ты говоришь.", "russian")
+		text := "Я правда не знаю, какой у тебя язык, what is your language"
+
+		Convey("Languages of other scripts should get no confidence", func() {
+			res := d.GetLanguages(text)
+			So(res[0].Name, ShouldEqual, "russian")
+			So(res[1].Name, ShouldEqual, "english")
+			So(res[1].Confidence, ShouldEqual, 0)
+		})
+		Convey("All languages should be compared if the filter is disabled", func() {
+			d.DisableScriptFilter = true
+			res := d.GetLanguages(text)
+			So(len(res), ShouldEqual, 2)
+			So(res[1].Confidence, ShouldBeGreaterThan, 0)
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package langdet
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Segment is a part of a text in a single language
+type Segment struct {
+	Text string
+	// Start and End are the byte offsets of the segment in the text
+	Start, End int
+	// Language is the name of the language of the segment, or undefined
+	Language string
+	// Confidence is the confidence of the language for the whole segment, between 0 and 1
+	Confidence float64
+}
+
+// DetectSegments splits a text of several languages, e.g. an email with a quoted reply, into
+// segments of a single language. Every sentence is detected separately and adjacent sentences of
+// the same language are merged. Sentences without letters belong to the segment before them.
+// The confidence of a segment is the confidence of its language for the merged text.
+func (d *Detector) DetectSegments(text string) []Segment {
+	segments := []Segment{}
+	if len(d.snapshot()) == 0 {
+		return segments
+	}
+	for _, sentence := range splitSentences(text) {
+		if strings.IndexFunc(sentence.Text, unicode.IsLetter) >= 0 {
+			if results := d.GetLanguages(sentence.Text); len(results) > 0 {
+				sentence.Language = results[0].Name
+			}
+		}
+		last := len(segments) - 1
+		switch {
+		case last >= 0 && (sentence.Language == "" || sentence.Language == segments[last].Language):
+		case last >= 0 && segments[last].Language == "":
+			segments[last].Language = sentence.Language
+		default:
+			segments = append(segments, sentence)
+			continue
+		}
+		segments[last].End = sentence.End
+		segments[last].Text = text[segments[last].Start:segments[last].End]
+	}
+
+	for i := range segments {
+		if segments[i].Language == "" {
+			segments[i].Language = "undefined"
+			continue
+		}
+		for _, result := range d.GetLanguages(segments[i].Text) {
+			if result.Name == segments[i].Language {
+				segments[i].Confidence = result.Confidence / 100
+				break
+			}
+		}
+	}
+	return segments
+}
+
+// isSentenceEnd tells whether r ends a sentence, in latin, CJK, arabic, indic, armenian or
+// ethiopic punctuation
+func isSentenceEnd(r rune) bool {
+	return strings.ContainsRune(".!?…。！？؟۔।॥։።", r)
+}
+
+// endsWithoutSpace tells whether r ends a sentence also if no white space follows, like the full
+// width punctuation of Chinese and Japanese, which are written without spaces
+func endsWithoutSpace(r rune) bool {
+	return strings.ContainsRune("。！？", r)
+}
+
+// isClosing tells whether r is a closing quote or bracket, which belongs to the sentence before it
+func isClosing(r rune) bool {
+	return r == '"' || r == '\'' || unicode.In(r, unicode.Pe, unicode.Pf)
+}
+
+// splitSentences splits a text into sentences without surrounding white space, which end with
+// punctuation followed by white space, with full width punctuation or with a line break. Closing
+// quotes and brackets after the punctuation belong to the sentence.
+func splitSentences(text string) []Segment {
+	sentences := []Segment{}
+	start, end := -1, 0
+	ended, spaceless := false, false
+	add := func() {
+		if start >= 0 {
+			sentences = append(sentences, Segment{Text: text[start:end], Start: start, End: end})
+		}
+		start, ended, spaceless = -1, false, false
+	}
+	for i, r := range text {
+		switch {
+		case r == '\n' || (ended && unicode.IsSpace(r)):
+			add()
+		case unicode.IsSpace(r):
+		default:
+			closing := ended && isClosing(r)
+			if spaceless && !closing {
+				add()
+			}
+			if start < 0 {
+				start = i
+			}
+			// an invalid byte is ranged as utf8.RuneError, whose encoding is 3 bytes wide
+			_, size := utf8.DecodeRuneInString(text[i:])
+			end = i + size
+			if !closing {
+				ended = isSentenceEnd(r)
+				spaceless = endsWithoutSpace(r)
+			}
+		}
+	}
+	add()
+	return sentences
+}
@@ -0,0 +1,47 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDetectSegments(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Detect the languages of the parts of mixed texts", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+
+		Convey("Adjacent sentences of a language should be merged", func() {
+			text := "What is your language? I really dont know. 42!\n\n  Je ne sais pas ce que tu dis. Je parles français.  "
+			segments := d.DetectSegments(text)
+			So(len(segments), ShouldEqual, 2)
+			So(segments[0].Language, ShouldEqual, "english")
+			So(segments[0].Text, ShouldEqual, "What is your language? I really dont know. 42!")
+			So(segments[1].Language, ShouldEqual, "french")
+			So(segments[1].Text, ShouldEqual, "Je ne sais pas ce que tu dis. Je parles français.")
+			So(text[segments[1].Start:segments[1].End], ShouldEqual, segments[1].Text)
+			So(segments[1].Confidence, ShouldBeGreaterThan, 0)
+			So(segments[1].Confidence, ShouldBeLessThanOrEqualTo, 1)
+		})
+		Convey("Texts without letters should be a single undefined segment", func() {
+			segments := d.DetectSegments("12. 34!")
+			So(len(segments), ShouldEqual, 1)
+			So(segments[0].Language, ShouldEqual, "undefined")
+			So(d.DetectSegments("  "), ShouldBeEmpty)
+		})
+		Convey("Texts ending in truncated UTF-8 should be split within their bounds", func() {
+			text := "Hello I am english text\xff"
+			segments := d.DetectSegments(text)
+			So(len(segments), ShouldEqual, 1)
+			So(segments[0].End, ShouldEqual, len(text))
+			So(segments[0].Text, ShouldEqual, text)
+		})
+		Convey("Detectors without languages should return no segments", func() {
+			empty := langdet.NewDetector()
+			So(empty.DetectSegments(en), ShouldBeEmpty)
+		})
+	})
+}
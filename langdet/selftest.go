@@ -0,0 +1,24 @@
+package langdet
+
+// SelfTestFailure describes a sample sentence that was not detected as the language it belongs to
+type SelfTestFailure struct {
+	Language string
+	Sample   string
+	Detected string
+}
+
+// SelfTest detects the sample sentences stored with the languages of this Detector and returns
+// every sample that is not detected as its own language. No failures means that the detector works
+// as expected, which makes it a fast smoke test after deploying new profiles.
+func (d *Detector) SelfTest() []SelfTestFailure {
+	failures := []SelfTestFailure{}
+	for _, language := range d.snapshot() {
+		for _, sample := range language.Samples {
+			detected := d.GetClosestLanguage(sample)
+			if detected != language.Name {
+				failures = append(failures, SelfTestFailure{Language: language.Name, Sample: sample, Detected: detected})
+			}
+		}
+	}
+	return failures
+}
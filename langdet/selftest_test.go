@@ -0,0 +1,28 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	Convey("Subject: Self test of a detector", t, func() {
+		en := langdet.Analyze("Hello I am english text, what is your language? I really dont know you say?", "english")
+		fr := langdet.Analyze("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+		d := langdet.NewDetector()
+		Convey("Samples detected as their language should pass", func() {
+			en.Samples = []string{"what is your language"}
+			d.AddLanguage(en, fr)
+			So(d.SelfTest(), ShouldBeEmpty)
+		})
+		Convey("Samples detected as another language should be reported", func() {
+			fr.Samples = []string{"what is your language"}
+			d.AddLanguage(en, fr)
+			failures := d.SelfTest()
+			So(len(failures), ShouldEqual, 1)
+			So(failures[0].Language, ShouldEqual, "french")
+			So(failures[0].Detected, ShouldEqual, "english")
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package langdet
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SentenceLanguage is the language of a sentence of a text, see AnnotateSentences
+type SentenceLanguage struct {
+	Text string
+	// Start and End are the byte offsets of the sentence in the text
+	Start, End int
+	// Language is the name of the closest language of the sentence, or undefined for sentences
+	// without letters
+	Language string
+	// Confidence is the confidence of the language between 0 and 1
+	Confidence float64
+	// Reliable tells whether the detection is reliable like GetClosestLanguageWithConfidence
+	Reliable bool
+}
+
+// AnnotateSentences splits a text into sentences and detects the language of every sentence, e.g.
+// to highlight foreign-language sentences in a UI or to route them to the right translation
+// engine. Sentences end with punctuation followed by white space, with the full width
+// punctuation of Chinese and Japanese or with line breaks; closing quotes and brackets belong to
+// the sentence before them. Unlike DetectSegments, sentences of the same language are not merged.
+func (d *Detector) AnnotateSentences(text string) []SentenceLanguage {
+	sentences := splitSentences(text)
+	annotated := make([]SentenceLanguage, len(sentences))
+	for i, sentence := range sentences {
+		annotated[i] = SentenceLanguage{Text: sentence.Text, Start: sentence.Start, End: sentence.End, Language: "undefined"}
+		if strings.IndexFunc(sentence.Text, unicode.IsLetter) < 0 {
+			continue
+		}
+		annotated[i].Language, annotated[i].Confidence, annotated[i].Reliable = d.GetClosestLanguageWithConfidence(sentence.Text)
+	}
+	return annotated
+}
@@ -0,0 +1,56 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAnnotateSentences(t *testing.T) {
+	Convey("Subject: Annotate the sentences of a text with their languages", t, func() {
+		d := langdet.NewDefaultLanguages()
+		texts := func(sentences []langdet.SentenceLanguage) []string {
+			result := []string{}
+			for _, sentence := range sentences {
+				result = append(result, sentence.Text)
+			}
+			return result
+		}
+
+		Convey("Every sentence should be detected with its offsets", func() {
+			text := "Wir sind am Wochenende ans Meer gefahren. Большинство жителей деревни работают в соседнем городе!  42."
+			sentences := d.AnnotateSentences(text)
+			So(sentences, ShouldHaveLength, 3)
+			So(sentences[0].Language, ShouldEqual, "de")
+			So(sentences[1].Language, ShouldEqual, "ru")
+			So(sentences[1].Reliable, ShouldBeTrue)
+			So(sentences[2].Language, ShouldEqual, "undefined")
+			for _, sentence := range sentences {
+				So(text[sentence.Start:sentence.End], ShouldEqual, sentence.Text)
+			}
+		})
+		Convey("Full width punctuation should end sentences without spaces", func() {
+			So(texts(d.AnnotateSentences("今天天气很好。我们去公园吧！好吗？")), ShouldResemble, []string{"今天天气很好。", "我们去公园吧！", "好吗？"})
+			So(texts(d.AnnotateSentences("「行こう。」と言った。")), ShouldResemble, []string{"「行こう。」", "と言った。"})
+		})
+		Convey("Closing quotes should belong to their sentence", func() {
+			So(texts(d.AnnotateSentences(`He said "stop." Then he left (quickly.) The end`)), ShouldResemble,
+				[]string{`He said "stop."`, "Then he left (quickly.)", "The end"})
+		})
+		Convey("Punctuation of other scripts should end sentences", func() {
+			So(texts(d.AnnotateSentences("هل أنت بخير؟ نعم")), ShouldHaveLength, 2)
+			So(texts(d.AnnotateSentences("यह एक वाक्य है। यह दूसरा है।")), ShouldHaveLength, 2)
+		})
+		Convey("Invalid UTF-8 should not cut sentences beyond the text", func() {
+			text := "Wir sind am Wochenende ans Meer gefahren. Hello\xe2\x82"
+			sentences := d.AnnotateSentences(text)
+			So(sentences, ShouldHaveLength, 2)
+			So(sentences[1].Text, ShouldEqual, "Hello\xe2\x82")
+			So(sentences[1].End, ShouldEqual, len(text))
+		})
+		Convey("Empty texts should have no sentences", func() {
+			So(d.AnnotateSentences(" \n "), ShouldBeEmpty)
+		})
+	})
+}
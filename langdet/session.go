@@ -0,0 +1,149 @@
+package langdet
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultSessionDecay is the decay of a SessionDetector whose Decay is 0
+const DefaultSessionDecay = 0.8
+
+// DefaultSessionHysteresis is the hysteresis of a SessionDetector whose Hysteresis is 0
+const DefaultSessionHysteresis = 0.05
+
+// SessionDetector detects the language of a conversation, like a chat session, from its messages,
+// which are mostly too short to be detected reliably on their own. Every message adds its
+// confidences of all languages to the evidence of the session, weighed by its length, and the
+// evidence of earlier messages decays, so that the estimate follows a conversation that switches
+// its language. Use one SessionDetector per user or conversation. It is safe for concurrent use.
+type SessionDetector struct {
+	// Decay is the factor between 0 and 1 the evidence of earlier messages is multiplied with for
+	// every new message, 0 means DefaultSessionDecay. Low values forget earlier messages quickly.
+	Decay float64
+	// Hysteresis is the margin between 0 and 1 by which the confidence of another language must
+	// exceed the confidence of the language of the session to switch to it, 0 means
+	// DefaultSessionHysteresis. It keeps single ambiguous messages from flipping the language.
+	Hysteresis float64
+	// OnChange is called with the previous and the new language of the session, when its language
+	// changes, starting with the first reliable estimate, whose previous language is undefined.
+	// It is called after Add released the session, so it may call the session's methods.
+	OnChange func(from, to string)
+
+	mu       sync.Mutex
+	detector *Detector
+	evidence map[string]float64
+	weight   float64
+	language string
+}
+
+// NewSessionDetector returns a SessionDetector without evidence, detecting with detector
+func NewSessionDetector(detector *Detector) *SessionDetector {
+	return &SessionDetector{detector: detector, evidence: make(map[string]float64)}
+}
+
+// Add adds the evidence of a message to the session and returns the current estimate like Current.
+// Messages consisting predominantly of emoji and symbols add no evidence.
+func (s *SessionDetector) Add(message string) (string, float64, bool) {
+	text := s.detector.prepare(message)
+	if s.detector.isSymbolic(text) {
+		return s.Current()
+	}
+	results := s.detector.GetLanguages(message)
+	length := float64(utf8.RuneCountInString(text))
+
+	s.mu.Lock()
+	s.add(results, length)
+	name, confidence, reliable := s.current()
+	from := s.language
+	changed := s.switchTo(name, confidence, reliable)
+	onChange := s.OnChange
+	s.mu.Unlock()
+	if changed && onChange != nil {
+		if from == "" {
+			from = "undefined"
+		}
+		onChange(from, name)
+	}
+	return name, confidence, reliable
+}
+
+// add adds the results of a message of the given length to the evidence, s.mu must be held
+func (s *SessionDetector) add(results []DetectionResult, length float64) {
+	decay := s.Decay
+	if decay <= 0 || decay > 1 {
+		decay = DefaultSessionDecay
+	}
+	for name := range s.evidence {
+		s.evidence[name] *= decay
+	}
+	for _, result := range results {
+		s.evidence[result.Name] += result.Confidence / 100 * length
+	}
+	s.weight = s.weight*decay + length
+}
+
+// switchTo changes the language of the session to the current estimate, if it is reliable and
+// its confidence exceeds the confidence of the language by the hysteresis. s.mu must be held.
+func (s *SessionDetector) switchTo(name string, confidence float64, reliable bool) bool {
+	if !reliable || name == s.language {
+		return false
+	}
+	hysteresis := s.Hysteresis
+	if hysteresis <= 0 || hysteresis > 1 {
+		hysteresis = DefaultSessionHysteresis
+	}
+	if s.language != "" && confidence-s.evidence[s.language]/s.weight < hysteresis {
+		return false
+	}
+	s.language = name
+	return true
+}
+
+// Language returns the language of the session, the last estimate that was reliable and beat the
+// previous language by the hysteresis, or undefined
+func (s *SessionDetector) Language() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.language == "" {
+		return "undefined"
+	}
+	return s.language
+}
+
+// Current returns the best estimate of the language of the session so far, its confidence between
+// 0 and 1, the weighted average of the confidences of the messages, and whether it is reliable
+// according to the minimum confidence of the language. It returns undefined, 0 and false before
+// any message.
+func (s *SessionDetector) Current() (string, float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current()
+}
+
+// current returns the current estimate, s.mu must be held
+func (s *SessionDetector) current() (string, float64, bool) {
+	best, confidence := "undefined", 0.0
+	if s.weight <= 0 {
+		return best, confidence, false
+	}
+	for name, evidence := range s.evidence {
+		if c := evidence / s.weight; c > confidence || (c == confidence && c > 0 && name < best) {
+			best, confidence = name, c
+		}
+	}
+	reliable := confidence*100 >= asPercent(s.detector.minimumConfidence(best))
+	if reliable {
+		s.detector.recent.see(best)
+	}
+	return best, confidence, reliable
+}
+
+// Reset forgets the evidence of all messages and the language of the session, e.g. when a new
+// conversation starts. OnChange is not called.
+func (s *SessionDetector) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evidence = make(map[string]float64)
+	s.weight = 0
+	s.language = ""
+}
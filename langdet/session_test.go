@@ -0,0 +1,64 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSessionDetector(t *testing.T) {
+	Convey("Subject: Rolling detection of chat sessions", t, func() {
+		d := langdet.NewDefaultLanguages()
+		s := langdet.NewSessionDetector(&d)
+		Convey("Sessions without messages should be undefined", func() {
+			name, confidence, reliable := s.Current()
+			So(name, ShouldEqual, "undefined")
+			So(confidence, ShouldEqual, 0)
+			So(reliable, ShouldBeFalse)
+		})
+		Convey("The evidence of short messages should add up", func() {
+			for _, message := range []string{"hallo", "wie geht's?", "gut, danke", "bis morgen"} {
+				s.Add(message)
+			}
+			name, confidence, reliable := s.Current()
+			So(name, ShouldEqual, "de")
+			So(reliable, ShouldBeTrue)
+			name, sameConfidence, _ := s.Add("😀😀😀")
+			So(name, ShouldEqual, "de")
+			So(sameConfidence, ShouldEqual, confidence)
+
+			Convey("and decay when the conversation switches its language", func() {
+				s.Decay = 0.3
+				for _, message := range []string{"ok, let's switch", "to english now", "see you tomorrow"} {
+					s.Add(message)
+				}
+				name, _, _ := s.Current()
+				So(name, ShouldEqual, "en")
+				s.Reset()
+				name, _, _ = s.Current()
+				So(name, ShouldEqual, "undefined")
+			})
+		})
+		Convey("Language changes should be reported with hysteresis", func() {
+			changes := []string{}
+			s.OnChange = func(from, to string) {
+				changes = append(changes, from+">"+to+"="+s.Language())
+			}
+			for _, message := range []string{"wie geht's?", "gut, danke", "bis morgen"} {
+				s.Add(message)
+			}
+			So(changes, ShouldResemble, []string{"undefined>de=de"})
+			s.Hysteresis = 1
+			for _, message := range []string{"ok, let's switch", "to english now", "see you tomorrow"} {
+				s.Add(message)
+			}
+			So(s.Language(), ShouldEqual, "de")
+			s.Hysteresis = 0
+			s.Add("and now in english for real")
+			So(changes, ShouldResemble, []string{"undefined>de=de", "de>en=en"})
+			s.Reset()
+			So(s.Language(), ShouldEqual, "undefined")
+		})
+	})
+}
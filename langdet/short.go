@@ -0,0 +1,124 @@
+package langdet
+
+import (
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ShortTextLength is the number of runes below which texts are short, like search queries, tweets
+// and product titles. Their few n-grams make rank distances unstable.
+const ShortTextLength = 30
+
+// DefaultShortMinimumConfidence is the minimum confidence of DetectShort, when the detector's
+// ShortMinimumConfidence is 0. Cosine similarities of unigram and bigram vectors are lower than
+// the confidences of rank distances, so the cutoff is lower than DefaultMinimumConfidence.
+var DefaultShortMinimumConfidence float32 = 0.25
+
+// shortDepth is the n-gram depth of the frequency vectors of short texts
+const shortDepth = 2
+
+// GetLanguagesShort returns the DetectionResult of all languages for a short text like GetLanguages,
+// but compares the character unigram and bigram frequencies of the text and the profiles by their
+// cosine similarity, which doesn't depend on the ranks of a handful of n-grams. The frequencies of
+// a profile are its Counts, or weights derived from its ranks otherwise, see shortWeight.
+// The Confidence is the similarity in percent, weighed by the LanguageOptions of the language.
+func (d *Detector) GetLanguagesShort(text string) []DetectionResult {
+	occ := CreateOccurenceMap(d.prepare(text), shortDepth)
+	scorer := d.newCandidateScorer(CreateRankLookupMap(occ))
+	inputNorm := 0.0
+	for _, count := range occ {
+		inputNorm += float64(count * count)
+	}
+	inputNorm = math.Sqrt(inputNorm)
+
+	res := []DetectionResult{}
+	for _, language := range d.snapshot() {
+		tag := language.LookupTag()
+		result := DetectionResult{
+			Name:           language.Name,
+			Tag:            tag,
+			ComparedTokens: len(occ),
+			InputTokens:    len(occ),
+			Direction:      language.TextDirection(),
+			Group:          language.group(tag),
+		}
+		if scorer.comparable(language) && inputNorm > 0 {
+			dot, norm := 0.0, 0.0
+			for token := range language.Profile {
+				if utf8.RuneCountInString(token) > shortDepth {
+					continue
+				}
+				weight := shortWeight(language, token)
+				norm += weight * weight
+				if count, ok := occ[token]; ok {
+					dot += weight * float64(count)
+					result.MatchedTokens++
+				}
+			}
+			if norm > 0 {
+				result.Confidence = dot / (inputNorm * math.Sqrt(norm)) * 100
+			}
+			scorer.options[language.Name].weigh(&result)
+		}
+		res = append(res, result)
+	}
+	sortResults(res)
+	groupResults(res)
+	return res
+}
+
+// DetectShort returns the closest language of a short text like GetClosestLanguageWithConfidence,
+// with the results of GetLanguagesShort. The detection is reliable if the confidence reaches the
+// ShortMinimumConfidence of the detector and enough input tokens match, see MinMatchedTokens.
+func (d *Detector) DetectShort(text string) (string, float64, bool) {
+	text = d.prepare(text)
+	if name, confidence, ok := d.closestWord(text); ok {
+		return name, confidence, true
+	}
+	if d.isSymbolic(text) {
+		return SymbolicLanguage, SymbolRatio(text), true
+	}
+	results := d.GetLanguagesShort(text)
+	if len(results) == 0 {
+		return "undefined", 0, false
+	}
+	minimum := d.ShortMinimumConfidence
+	if minimum <= 0 || minimum > 1 {
+		minimum = DefaultShortMinimumConfidence
+	}
+	reliable := results[0].Confidence >= asPercent(minimum) && d.matchedEnough(results[0])
+	if reliable {
+		d.recent.see(results[0].Name)
+	}
+	return results[0].Name, results[0].Confidence / 100, reliable
+}
+
+// isShort tells whether a text is short enough for DetectShort
+func isShort(text string) bool {
+	return utf8.RuneCountInString(text) < ShortTextLength
+}
+
+// shortWeight returns the weight of a token of a language in its frequency vector: its count, or
+// the rankWeight of its rank
+func shortWeight(language Language, token string) float64 {
+	if count, ok := language.Counts[token]; ok {
+		return float64(count)
+	}
+	return rankWeight(language.Profile[token])
+}
+
+// tooShort tells whether a text has fewer runes than the MinInputLength of the detector, not
+// counting white space
+func (d *Detector) tooShort(text string) bool {
+	if d.MinInputLength <= 0 {
+		return false
+	}
+	runes := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			runes++
+		}
+	}
+	return runes < d.MinInputLength
+}
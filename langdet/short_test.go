@@ -0,0 +1,109 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+// shortTexts are short texts of the detectors of the short text tests
+var shortTexts = map[string]string{
+	"what is your language": "english",
+	"je ne sais pas":        "french",
+	"was du sagst":          "german",
+}
+
+// newShortDetector returns a detector with a few latin and a cyrillic language
+func newShortDetector() langdet.Detector {
+	d := langdet.NewDetector()
+	d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know what you say?", "english")
+	d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+	d.AddLanguageFromText("Das ist ein deutscher Satz, ich weiss nicht was du sagst", "german")
+	d.AddLanguageFromText("Это русское предложение, я не знаю, что ты говоришь", "russian")
+	return d
+}
+
+func TestDetectShort(t *testing.T) {
+	Convey("Subject: Detect short texts", t, func() {
+		d := newShortDetector()
+		Convey("Short texts should be detected by their unigram and bigram frequencies", func() {
+			for text, expected := range shortTexts {
+				name, confidence, reliable := d.DetectShort(text)
+				So(name, ShouldEqual, expected)
+				So(confidence, ShouldBeBetweenOrEqual, 0, 1)
+				So(reliable, ShouldBeTrue)
+			}
+		})
+		Convey("Languages of other scripts should get no confidence", func() {
+			results := d.GetLanguagesShort("what is your language")
+			So(results, ShouldHaveLength, 4)
+			So(results[3].Name, ShouldEqual, "russian")
+			So(results[3].Confidence, ShouldEqual, 0)
+		})
+		Convey("Texts without n-grams of any language should not be reliable", func() {
+			_, _, reliable := d.DetectShort("ðþ")
+			So(reliable, ShouldBeFalse)
+		})
+		Convey("ShortTexts should detect short texts only with DetectShort", func() {
+			d.ShortTexts = true
+			name, confidence, reliable := d.GetClosestLanguageWithConfidence("je ne sais pas")
+			shortName, shortConfidence, shortReliable := d.DetectShort("je ne sais pas")
+			So(name, ShouldEqual, shortName)
+			So(confidence, ShouldAlmostEqual, shortConfidence)
+			So(reliable, ShouldEqual, shortReliable)
+			long := "Hello I am english text, what is your language?"
+			_, confidence, _ = d.GetClosestLanguageWithConfidence(long)
+			_, shortConfidence, _ = d.DetectShort(long)
+			So(confidence, ShouldNotEqual, shortConfidence)
+		})
+	})
+}
+
+func BenchmarkDetectShort(b *testing.B) {
+	d := newShortDetector()
+	for i := 0; i < b.N; i++ {
+		for text := range shortTexts {
+			d.DetectShort(text)
+		}
+	}
+}
+
+func BenchmarkGetClosestLanguageShort(b *testing.B) {
+	d := newShortDetector()
+	for i := 0; i < b.N; i++ {
+		for text := range shortTexts {
+			d.GetClosestLanguageWithConfidence(text)
+		}
+	}
+}
+
+func TestMinInputLength(t *testing.T) {
+	Convey("Subject: Texts shorter than MinInputLength", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "english")
+		d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+		d.MinInputLength = 10
+		Convey("Should be insufficient input instead of undefined", func() {
+			So(d.GetClosestLanguage("ok"), ShouldEqual, langdet.InsufficientInput)
+			So(d.GetClosestLanguage("   "), ShouldEqual, langdet.InsufficientInput)
+			name, confidence, reliable := d.GetClosestLanguageWithConfidence("what is")
+			So(name, ShouldEqual, langdet.InsufficientInput)
+			So(confidence, ShouldEqual, 0)
+			So(reliable, ShouldBeFalse)
+			So(d.DetectBatch([]string{"ok"}, 1)[0].Language, ShouldEqual, langdet.InsufficientInput)
+		})
+		Convey("White space should not count", func() {
+			So(d.GetClosestLanguage("w h a t  i s"), ShouldEqual, langdet.InsufficientInput)
+			So(d.GetClosestLanguage("what is your language"), ShouldEqual, "english")
+		})
+		Convey("Words of the WordSets and symbolic texts should still be detected", func() {
+			d.SetWordSet("french", langdet.NewWords("oui"))
+			So(d.GetClosestLanguage("oui"), ShouldEqual, "french")
+			So(d.GetClosestLanguage("👍👍"), ShouldEqual, langdet.SymbolicLanguage)
+		})
+		Convey("0 should detect texts of any length", func() {
+			d.MinInputLength = 0
+			So(d.GetClosestLanguage("ok"), ShouldNotEqual, langdet.InsufficientInput)
+		})
+	})
+}
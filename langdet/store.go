@@ -0,0 +1,103 @@
+package langdet
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProfileStore loads the profiles of a directory on first use and keeps them as CompiledProfiles,
+// for services with many profiles of which most requests only need a few, e.g. with candidate
+// lists. The directory may be in any fs.FS, like an os.DirFS or the *zip.ReadCloser of
+// zip.OpenReader. Profiles are named by their file names without extension, like "en" for
+// "en.json". It is safe for concurrent use.
+type ProfileStore struct {
+	fsys  fs.FS
+	files map[string]string
+
+	mu     sync.Mutex
+	loaded map[string]CompiledProfile
+}
+
+// OpenStore returns a ProfileStore of the json or binary profile files of the directory dir of
+// fsys. It only lists the directory, the profiles are loaded by the methods of the store.
+func OpenStore(fsys fs.FS, dir string) (*ProfileStore, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &ProfileStore{fsys: fsys, files: make(map[string]string), loaded: make(map[string]CompiledProfile)}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		s.files[strings.TrimSuffix(name, path.Ext(name))] = path.Join(dir, name)
+	}
+	return s, nil
+}
+
+// Names returns the sorted names of the profiles of the store, loaded or not
+func (s *ProfileStore) Names() []string {
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Loaded returns the number of profiles loaded so far
+func (s *ProfileStore) Loaded() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.loaded)
+}
+
+// Profile returns the profile of the given name, loading it on first use
+func (s *ProfileStore) Profile(name string) (CompiledProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if profile, ok := s.loaded[name]; ok {
+		return profile, nil
+	}
+	file, ok := s.files[name]
+	if !ok {
+		return CompiledProfile{}, fmt.Errorf("no profile %q in the store", name)
+	}
+	language, err := decodeLanguageFile(s.fsys, file)
+	if err != nil {
+		return CompiledProfile{}, err
+	}
+	profile := Compile(language)
+	s.loaded[name] = profile
+	return profile, nil
+}
+
+// Profiles returns the profiles of the given names, or of all names if there are none
+func (s *ProfileStore) Profiles(names ...string) ([]CompiledProfile, error) {
+	if len(names) == 0 {
+		names = s.Names()
+	}
+	profiles := make([]CompiledProfile, len(names))
+	for i, name := range names {
+		var err error
+		if profiles[i], err = s.Profile(name); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// ScoreText compares a text with the profiles of the given names, or of all names if there are
+// none, like the function ScoreText
+func (s *ProfileStore) ScoreText(text string, options ScoreOptions, names ...string) ([]DetectionResult, error) {
+	profiles, err := s.Profiles(names...)
+	if err != nil {
+		return nil, err
+	}
+	return ScoreText(text, profiles, options), nil
+}
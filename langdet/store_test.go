@@ -0,0 +1,56 @@
+package langdet_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProfileStore(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Lazily loaded profile stores", t, func() {
+		english, _ := json.Marshal(langdet.Analyze(en, "english"))
+		french, _ := json.Marshal(langdet.Analyze(fr, "french"))
+		fsys := fstest.MapFS{
+			"profiles/en.json": {Data: english},
+			"profiles/fr.json": {Data: french},
+		}
+		Convey("Profiles should be loaded on first use", func() {
+			store, err := langdet.OpenStore(fsys, "profiles")
+			So(err, ShouldBeNil)
+			So(store.Names(), ShouldResemble, []string{"en", "fr"})
+			So(store.Loaded(), ShouldEqual, 0)
+			results, err := store.ScoreText("what is your language", langdet.ScoreOptions{}, "en")
+			So(err, ShouldBeNil)
+			So(results, ShouldHaveLength, 1)
+			So(results[0].Name, ShouldEqual, "english")
+			So(store.Loaded(), ShouldEqual, 1)
+			results, err = store.ScoreText("ce que tu dis", langdet.ScoreOptions{})
+			So(err, ShouldBeNil)
+			So(results[0].Name, ShouldEqual, "french")
+			So(store.Loaded(), ShouldEqual, 2)
+			_, err = store.Profile("de")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Stores should read zip archives", func() {
+			var buf bytes.Buffer
+			w := zip.NewWriter(&buf)
+			f, _ := w.Create("en.json")
+			f.Write(english)
+			So(w.Close(), ShouldBeNil)
+			r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			So(err, ShouldBeNil)
+			store, err := langdet.OpenStore(r, ".")
+			So(err, ShouldBeNil)
+			profile, err := store.Profile("en")
+			So(err, ShouldBeNil)
+			So(profile.Language().Profile, ShouldResemble, langdet.Analyze(en, "english").Profile)
+		})
+	})
+}
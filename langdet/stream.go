@@ -0,0 +1,75 @@
+package langdet
+
+import (
+	"bytes"
+	"io"
+)
+
+// streamChunkSize is the number of bytes that are read from a stream at once
+const streamChunkSize = 32 * 1024
+
+// GetClosestLanguageFromReader returns the name of the language which is closest to the text of a
+// reader like GetClosestLanguage, without loading the whole text into memory. If limit is positive,
+// only the first limit bytes are read, as the profile of a long text is stable long before its end.
+func (d *Detector) GetClosestLanguageFromReader(reader io.Reader, limit int64) (string, error) {
+	occ, err := readOccurences(reader, limit, d.inputDepth(d.snapshot()), d.Tokenizer)
+	if err != nil {
+		return "undefined", err
+	}
+	name, _, reliable := d.closestFromOccurences("", occ, nil)
+	if !reliable {
+		return "undefined", nil
+	}
+	return name, nil
+}
+
+// GetLanguagesFromReader returns the DetectionResult of all languages of this detector for the text
+// of a reader like GetLanguages. If limit is positive, only the first limit bytes are read.
+func (d *Detector) GetLanguagesFromReader(reader io.Reader, limit int64) ([]DetectionResult, error) {
+	occ, err := readOccurences(reader, limit, d.inputDepth(d.snapshot()), d.Tokenizer)
+	if err != nil {
+		return nil, err
+	}
+	return d.closestFromTable(CreateRankLookupMap(occ)), nil
+}
+
+// readOccurences builds the occurrence map of a stream like updateFromReader
+func readOccurences(reader io.Reader, limit int64, gramDepth int, tokenizer Tokenizer) (map[string]int, error) {
+	occ := make(map[string]int)
+	if err := updateFromReader(occ, reader, limit, gramDepth, tokenizer); err != nil {
+		return nil, err
+	}
+	return occ, nil
+}
+
+// updateFromReader updates the occurrence map occ from a stream chunk by chunk. Chunks are split
+// after the last space or newline, so that words are never split. If limit is positive, at most
+// limit bytes are read and a word that may be cut by the limit is ignored. The chunks are split into
+// words by tokenizer, if it is not nil.
+func updateFromReader(occ map[string]int, reader io.Reader, limit int64, gramDepth int, tokenizer Tokenizer) error {
+	if limit > 0 {
+		reader = io.LimitReader(reader, limit)
+	}
+	buf := make([]byte, streamChunkSize)
+	pending := []byte{}
+	var total int64
+	for {
+		n, err := reader.Read(buf)
+		total += int64(n)
+		pending = append(pending, buf[:n]...)
+		if i := bytes.LastIndexAny(pending, " \n"); i >= 0 {
+			UpdateOccurenceMap(occ, tokenize(tokenizer, string(pending[:i+1])), gramDepth)
+			pending = append(pending[:0], pending[i+1:]...)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if limit <= 0 || total < limit {
+		UpdateOccurenceMap(occ, tokenize(tokenizer, string(pending)), gramDepth)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package langdet_test
+
+import (
+	"errors"
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDetectFromStream(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say?\n"
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis.\n"
+	Convey("Subject: Detect the language of streams", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+		long := strings.Repeat(fr, 2000)
+
+		Convey("Streamed texts should be scored like whole strings", func() {
+			d.MaxAnalyzedRunes = -1
+			res, err := d.GetLanguagesFromReader(iotest.OneByteReader(strings.NewReader(fr+en)), 0)
+			So(err, ShouldBeNil)
+			So(res, ShouldResemble, d.GetLanguages(fr+en))
+			res, err = d.GetLanguagesFromReader(strings.NewReader(long), 0)
+			So(err, ShouldBeNil)
+			So(res, ShouldResemble, d.GetLanguages(long))
+		})
+		Convey("Only the bytes up to the limit should be read", func() {
+			name, err := d.GetClosestLanguageFromReader(strings.NewReader(en+long), int64(len(en)))
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, "english")
+			name, err = d.GetClosestLanguageFromReader(strings.NewReader(en+long), 0)
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, "french")
+		})
+		Convey("Read errors should be returned", func() {
+			reader := io.MultiReader(strings.NewReader(en), iotest.ErrReader(errors.New("broken")))
+			_, err := d.GetClosestLanguageFromReader(reader, 0)
+			So(err, ShouldNotBeNil)
+			_, err = d.GetLanguagesFromReader(reader, 0)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,89 @@
+package langdet
+
+// LanguageSupport describes a language of a Detector for lists of supported languages, see
+// SupportMatrix
+type LanguageSupport struct {
+	// Name is the name of the language in the detector
+	Name string
+	// Code is the ISO 639-1 code of the language, its ISO 639-3 code if it has none, or its Name
+	// if neither is known
+	Code string
+	// DisplayName is the english name of the language, its Name if it is unknown
+	DisplayName string
+	// NativeName is the name of the language in the language itself, the DisplayName if it is unknown
+	NativeName string
+	// Scripts are the unicode scripts of the language, most frequent first
+	Scripts []string
+	// Direction is the text direction of the language, LeftToRight or RightToLeft
+	Direction string
+	// Quality is a score of the profile between 0 and 1: the share of DefaultProfileSize ranks the
+	// profile has, times the share of its Samples that are detected as the language, if it has any
+	Quality float64
+	// ShortTexts tells whether short texts like search queries are detected well: the language
+	// has a WordSet or is the only language of the detector written in its main script
+	ShortTexts bool
+}
+
+// SupportMatrix returns the support of the languages of this detector in their order, so that
+// lists of supported languages can be generated from the loaded profiles. Display names are
+// looked up in DefaultMeta. The Quality of languages with Samples detects them, so it is as
+// expensive as a SelfTest.
+func (d *Detector) SupportMatrix() []LanguageSupport {
+	languages := d.snapshot()
+	wordSets := d.currentWordSets()
+	byScript := make(map[string]int)
+	for _, language := range languages {
+		if scripts := language.TextScripts(); len(scripts) > 0 {
+			byScript[scripts[0]]++
+		}
+	}
+	matrix := make([]LanguageSupport, len(languages))
+	for i, language := range languages {
+		tag := language.LookupTag()
+		support := LanguageSupport{
+			Name:        language.Name,
+			Code:        language.Name,
+			DisplayName: language.Name,
+			Scripts:     language.TextScripts(),
+			Direction:   language.TextDirection(),
+			Quality:     d.profileQuality(language),
+			ShortTexts:  wordSets[language.Name] != nil,
+		}
+		switch {
+		case tag.ISO6391 != "":
+			support.Code = tag.ISO6391
+		case tag.ISO6393 != "":
+			support.Code = tag.ISO6393
+		}
+		if tag.DisplayName != "" {
+			support.DisplayName = tag.DisplayName
+		}
+		support.NativeName = support.DisplayName
+		if _, ok := DefaultMeta.Get(language.Name); ok {
+			support.NativeName = DefaultMeta.DisplayName(language.Name, support.Code)
+		}
+		if len(support.Scripts) > 0 && byScript[support.Scripts[0]] == 1 {
+			support.ShortTexts = true
+		}
+		matrix[i] = support
+	}
+	return matrix
+}
+
+// profileQuality returns the Quality of LanguageSupport of a language
+func (d *Detector) profileQuality(language Language) float64 {
+	quality := float64(len(language.Profile)) / float64(DefaultProfileSize)
+	if quality > 1 {
+		quality = 1
+	}
+	if len(language.Samples) == 0 {
+		return quality
+	}
+	detected := 0
+	for _, sample := range language.Samples {
+		if d.GetClosestLanguage(sample) == language.Name {
+			detected++
+		}
+	}
+	return quality * float64(detected) / float64(len(language.Samples))
+}
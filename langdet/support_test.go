@@ -0,0 +1,51 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSupportMatrix(t *testing.T) {
+	Convey("Subject: List the support of the languages of a detector", t, func() {
+		d := langdet.NewDefaultLanguages()
+		matrix := d.SupportMatrix()
+		byName := map[string]langdet.LanguageSupport{}
+		for _, support := range matrix {
+			byName[support.Name] = support
+		}
+
+		Convey("Every language should be listed with its codes and names", func() {
+			So(matrix, ShouldHaveLength, len(d.ListLanguages()))
+			de := byName["de"]
+			So(de.Code, ShouldEqual, "de")
+			So(de.DisplayName, ShouldEqual, "German")
+			So(de.NativeName, ShouldEqual, "Deutsch")
+			So(de.Scripts, ShouldResemble, []string{"Latin"})
+			So(byName["he"].Direction, ShouldEqual, langdet.RightToLeft)
+			So(de.Quality, ShouldAlmostEqual, 1)
+		})
+		Convey("Languages of their own script or with word sets should suit short texts", func() {
+			So(byName["ru"].ShortTexts, ShouldBeTrue)
+			So(byName["en"].ShortTexts, ShouldBeFalse)
+			d.SetWordSet("en", langdet.NewWords("the", "and"))
+			for _, support := range d.SupportMatrix() {
+				if support.Name == "en" {
+					So(support.ShortTexts, ShouldBeTrue)
+				}
+			}
+		})
+		Convey("Samples that are not detected should lower the quality", func() {
+			custom := langdet.NewDetector()
+			english := langdet.Analyze("Hello I am english text, what is your language?", "xx")
+			english.Samples = []string{"what is your language", "Je ne sais pas ce que tu dis"}
+			custom.AddLanguage(english, langdet.Analyze("Je parles français et toi? Je ne sais pas ce que tu dis.", "fr"))
+			quality := custom.SupportMatrix()[0]
+			So(quality.Code, ShouldEqual, "xx")
+			So(quality.DisplayName, ShouldEqual, "xx")
+			So(quality.Quality, ShouldBeLessThan, float64(len(english.Profile))/float64(langdet.DefaultProfileSize))
+			So(quality.Quality, ShouldBeGreaterThan, 0)
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package langdet
+
+import "unicode"
+
+// SymbolicLanguage is the pseudo language returned by GetClosestLanguage for texts that
+// consist predominantly of emoji and symbols, e.g. emoji-only or sticker messages
+const SymbolicLanguage = "symbolic"
+
+// InsufficientInput is the pseudo language returned by GetClosestLanguage for texts shorter than
+// the MinInputLength of the detector, so that callers can tell texts too short to detect from
+// texts of no known language, which are undefined
+const InsufficientInput = "insufficient-input"
+
+// DefaultSymbolicThreshold is the default minimum fraction of emoji and symbols a text must
+// consist of to be detected as SymbolicLanguage
+const DefaultSymbolicThreshold = 0.8
+
+// SymbolRatio returns the fraction of emoji and symbols of the characters of a text, not counting
+// spaces and punctuation. Joiners and variation selectors, which glue emoji sequences together,
+// count as symbols. Texts of only spaces and punctuation have a ratio of 0.
+func SymbolRatio(text string) float64 {
+	total, symbols := 0, 0
+	for _, r := range text {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			continue
+		}
+		total++
+		if unicode.IsSymbol(r) || unicode.Is(unicode.Variation_Selector, r) ||
+			r == '\u200d' || r == '\u20e3' {
+			symbols++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(symbols) / float64(total)
+}
+
+// isSymbolic tells whether text consists predominantly of emoji and symbols
+func (d *Detector) isSymbolic(text string) bool {
+	threshold := d.SymbolicThreshold
+	if threshold == 0 {
+		threshold = DefaultSymbolicThreshold
+	}
+	if threshold < 0 {
+		return false
+	}
+	return SymbolRatio(text) >= threshold
+}
@@ -0,0 +1,39 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSymbolic(t *testing.T) {
+	Convey("Subject: Detect symbolic texts", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "english")
+		Convey("Emoji-only messages should be symbolic", func() {
+			So(d.GetClosestLanguage("😂😂 👍🏽 ❤️"), ShouldEqual, langdet.SymbolicLanguage)
+			So(d.GetClosestLanguage("👨‍👩‍👧 !!!"), ShouldEqual, langdet.SymbolicLanguage)
+		})
+		Convey("Texts with some emoji should not be symbolic", func() {
+			So(d.GetClosestLanguage("what is your language 😂"), ShouldNotEqual, langdet.SymbolicLanguage)
+		})
+		Convey("The threshold should be configurable", func() {
+			So(langdet.SymbolRatio("ok 👍👍👍"), ShouldAlmostEqual, 0.6)
+			d.SymbolicThreshold = 0.5
+			So(d.GetClosestLanguage("ok 👍👍👍"), ShouldEqual, langdet.SymbolicLanguage)
+			d.SymbolicThreshold = -1
+			So(d.GetClosestLanguage("👍👍👍"), ShouldNotEqual, langdet.SymbolicLanguage)
+		})
+		Convey("Empty texts should not be symbolic", func() {
+			So(langdet.SymbolRatio(" "), ShouldEqual, 0)
+		})
+		Convey("Punctuation should not be symbolic", func() {
+			So(langdet.SymbolRatio("..."), ShouldEqual, 0)
+			So(langdet.SymbolRatio("ok!!! 👍"), ShouldAlmostEqual, 1.0/3)
+			name, confidence, reliable := d.GetClosestLanguageWithConfidence("... ?!")
+			So(name, ShouldNotEqual, langdet.SymbolicLanguage)
+			So(confidence, ShouldEqual, 0)
+			So(reliable, ShouldBeFalse)
+		})
+	})
+}
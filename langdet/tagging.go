@@ -0,0 +1,83 @@
+package langdet
+
+import "unicode"
+
+// tagContext is the number of words on each side of a word that are its context for TagTokens
+const tagContext = 2
+
+// tagWordWeight is the weight of the detection of a word itself, compared to its context
+const tagWordWeight = 0.6
+
+// TaggedToken is a word of a text labeled with its language
+type TaggedToken struct {
+	Token string
+	// Start and End are the byte offsets of the word in the text
+	Start, End int
+	// Language is the name of the language of the word, or undefined
+	Language string
+	// Confidence is the weighted share of the language in the confidences of the word and its
+	// context, between 0 and 1
+	Confidence float64
+}
+
+// TagTokens labels every word of a text with its language, e.g. to route single foreign words to
+// transliteration. Single words are too short for reliable detection, so the detection of every
+// word is combined with the detection of a window of the words around it. This is experimental:
+// the labels are noisier than the results of DetectCodeMixing for whole texts.
+func (d *Detector) TagTokens(text string) []TaggedToken {
+	tokens := splitWords(text)
+	for i := range tokens {
+		from, to := i-tagContext, i+tagContext+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(tokens) {
+			to = len(tokens)
+		}
+		combined := make(map[string]float64)
+		addShares(combined, d.GetLanguages(tokens[i].Token), tagWordWeight)
+		addShares(combined, d.GetLanguages(text[tokens[from].Start:tokens[to-1].End]), 1-tagWordWeight)
+		tokens[i].Language = "undefined"
+		for _, language := range d.snapshot() {
+			if confidence := combined[language.Name]; confidence > tokens[i].Confidence {
+				tokens[i].Language, tokens[i].Confidence = language.Name, confidence
+			}
+		}
+	}
+	return tokens
+}
+
+// addShares adds the shares of the languages in the sum of the confidences of results,
+// multiplied by weight, to shares
+func addShares(shares map[string]float64, results []DetectionResult, weight float64) {
+	total := 0.0
+	for _, result := range results {
+		total += result.Confidence
+	}
+	if total == 0 {
+		return
+	}
+	for _, result := range results {
+		shares[result.Name] += weight * result.Confidence / total
+	}
+}
+
+// splitWords returns the words of a text separated by white space, with their offsets
+func splitWords(text string) []TaggedToken {
+	words := []TaggedToken{}
+	start := -1
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				words = append(words, TaggedToken{Token: text[start:i], Start: start, End: i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, TaggedToken{Token: text[start:], Start: start, End: len(text)})
+	}
+	return words
+}
@@ -0,0 +1,33 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestTagTokens(t *testing.T) {
+	Convey("Subject: Tag the words of a text with their language", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say?", "english")
+		d.AddLanguageFromText("Привет, я русский текст. Какой у тебя язык? Я правда не знаю, что ты говоришь.", "russian")
+
+		Convey("Foreign words should be tagged with their own language", func() {
+			text := "I really dont know  язык  you say"
+			tokens := d.TagTokens(text)
+			So(len(tokens), ShouldEqual, 7)
+			for i, token := range tokens {
+				So(text[token.Start:token.End], ShouldEqual, token.Token)
+				if i == 4 {
+					So(token.Language, ShouldEqual, "russian")
+				} else {
+					So(token.Language, ShouldEqual, "english")
+				}
+				So(token.Confidence, ShouldBeBetweenOrEqual, 0, 1)
+			}
+		})
+		Convey("Texts without words should have no tokens", func() {
+			So(d.TagTokens(" \n "), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package langdet
+
+// TextInfo is the language of a text with its script and writing direction, which most consumers of
+// the language need as well, e.g. to render the text
+type TextInfo struct {
+	// Language is the closest language, or undefined if the detection is not reliable. It is empty
+	// for the TextInfo of DetectTextInfo.
+	Language string
+	// Confidence is the confidence of the closest language, between 0 and 1
+	Confidence float64
+	Reliable   bool
+	// Script is the unicode script of most letters of the text, e.g. "Latin" or "Arabic", see
+	// DetectScript. It is empty for texts without letters.
+	Script string
+	// Direction is the direction of most letters of the text, LeftToRight or RightToLeft. Texts
+	// without letters are LeftToRight.
+	Direction string
+	// Mixed tells whether the text has letters of both directions, see DetectBidi
+	Mixed bool
+}
+
+// DetectTextInfo returns the script and writing direction of a text by the unicode properties of
+// its letters, without its language
+func DetectTextInfo(text string) TextInfo {
+	bidi := DetectBidi(text)
+	info := TextInfo{Script: DetectScript(text), Direction: bidi.Dominant, Mixed: bidi.Mixed}
+	if info.Direction == "" {
+		info.Direction = LeftToRight
+	}
+	return info
+}
+
+// GetTextInfo returns the closest language of a text, like GetClosestLanguageWithConfidence, with
+// the script and writing direction of the text
+func (d *Detector) GetTextInfo(text string) TextInfo {
+	info := DetectTextInfo(text)
+	name, confidence, reliable := d.GetClosestLanguageWithConfidence(text)
+	info.Language, info.Confidence, info.Reliable = name, confidence, reliable
+	if !reliable {
+		info.Language = "undefined"
+	}
+	return info
+}
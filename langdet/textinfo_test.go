@@ -0,0 +1,33 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestTextInfo(t *testing.T) {
+	Convey("Subject: Script and direction of texts", t, func() {
+		Convey("The script and direction should follow the letters of the text", func() {
+			So(langdet.DetectTextInfo("Hello world"), ShouldResemble, langdet.TextInfo{Script: "Latin", Direction: langdet.LeftToRight})
+			So(langdet.DetectTextInfo("Привет, мир"), ShouldResemble, langdet.TextInfo{Script: "Cyrillic", Direction: langdet.LeftToRight})
+			So(langdet.DetectTextInfo("مرحبا بالعالم"), ShouldResemble, langdet.TextInfo{Script: "Arabic", Direction: langdet.RightToLeft})
+			info := langdet.DetectTextInfo("שלום עולם, hi")
+			So(info.Script, ShouldEqual, "Hebrew")
+			So(info.Direction, ShouldEqual, langdet.RightToLeft)
+			So(info.Mixed, ShouldBeTrue)
+			So(langdet.DetectTextInfo("123 !?"), ShouldResemble, langdet.TextInfo{Direction: langdet.LeftToRight})
+		})
+		Convey("Detected texts should have their language", func() {
+			d := langdet.NewDetector()
+			d.AddLanguageFromText("Hello I am english text, what is your language? I really dont know you say? ", "english")
+			d.AddLanguageFromText("שלום, אני טקסט בעברית, מה השפה שלך? אני באמת לא יודע מה אתה אומר", "hebrew")
+			info := d.GetTextInfo("מה השפה שלך")
+			So(info.Language, ShouldEqual, "hebrew")
+			So(info.Reliable, ShouldBeTrue)
+			So(info.Script, ShouldEqual, "Hebrew")
+			So(info.Direction, ShouldEqual, langdet.RightToLeft)
+			So(d.GetTextInfo("").Language, ShouldEqual, "undefined")
+		})
+	})
+}
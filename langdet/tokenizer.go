@@ -0,0 +1,134 @@
+package langdet
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenizer splits a text into the words whose n-grams are analyzed, so that parts of texts that
+// tell nothing about their language, like URLs, numbers or markup, don't pollute the n-grams.
+// Detectors and trainers should use the same Tokenizer, so that their n-grams are comparable.
+type Tokenizer interface {
+	// Tokenize returns the words of a text
+	Tokenize(text string) []string
+}
+
+// TokenizerFunc adapts a function to a Tokenizer
+type TokenizerFunc func(text string) []string
+
+// Tokenize implements Tokenizer
+func (f TokenizerFunc) Tokenize(text string) []string {
+	return f(text)
+}
+
+// WordTokenizer is the default Tokenizer. It drops URLs, email addresses, @mentions and hashtags,
+// and splits words at every rune that is not a letter or a mark, which removes numbers and
+// punctuation.
+type WordTokenizer struct {
+	// Lowercase lowercases the words, for profiles trained from lowercased text
+	Lowercase bool
+}
+
+// Tokenize implements Tokenizer
+func (t WordTokenizer) Tokenize(text string) []string {
+	words := []string{}
+	for _, field := range strings.Fields(text) {
+		if isNonWord(field) {
+			continue
+		}
+		if t.Lowercase {
+			field = strings.ToLower(field)
+		}
+		words = append(words, strings.FieldsFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsMark(r)
+		})...)
+	}
+	return words
+}
+
+// SocialTokenizer is a Tokenizer for tweets, chats and comments. Like WordTokenizer, it drops URLs,
+// email addresses, @mentions, numbers and punctuation, and also emoji with their variation
+// selectors and keycaps. It keeps the words of hashtags, split at their capitals, e.g. "#BonneJournée"
+// into "Bonne" and "Journée", and shortens letters repeated for emphasis to two, e.g. "soooo" to "soo".
+type SocialTokenizer struct {
+	// Lowercase lowercases the words, for profiles trained from lowercased text
+	Lowercase bool
+}
+
+// Tokenize implements Tokenizer
+func (t SocialTokenizer) Tokenize(text string) []string {
+	words := []string{}
+	for _, field := range strings.Fields(text) {
+		if strings.HasPrefix(field, "#") {
+			field = splitCamelCase(field)
+		} else if isNonWord(field) {
+			continue
+		}
+		field = shortenRepeats(field)
+		if t.Lowercase {
+			field = strings.ToLower(field)
+		}
+		words = append(words, strings.FieldsFunc(field, isSocialSeparator)...)
+	}
+	return words
+}
+
+// isSocialSeparator tells whether a rune separates the words of SocialTokenizer: runes that are not
+// letters or marks, and the marks of emoji, like variation selectors and enclosing keycaps
+func isSocialSeparator(r rune) bool {
+	return (!unicode.IsLetter(r) && !unicode.IsMark(r)) || unicode.Is(unicode.Variation_Selector, r) || unicode.Is(unicode.Me, r)
+}
+
+// splitCamelCase separates the words of a hashtag by a space before every upper case letter that
+// follows a lower case one
+func splitCamelCase(hashtag string) string {
+	var b strings.Builder
+	previous := ' '
+	for _, r := range hashtag {
+		if unicode.IsUpper(r) && unicode.IsLower(previous) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+		previous = r
+	}
+	return b.String()
+}
+
+// shortenRepeats shortens the runs of more than two of the same letter to two
+func shortenRepeats(word string) string {
+	var b strings.Builder
+	previous, run := rune(-1), 0
+	for _, r := range word {
+		if r == previous {
+			run++
+		} else {
+			previous, run = r, 1
+		}
+		if run <= 2 || !unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isNonWord tells whether a whitespace separated field is a URL, an email address, an @mention
+// or a hashtag
+func isNonWord(field string) bool {
+	lower := strings.ToLower(field)
+	switch {
+	case strings.Contains(lower, "://"), strings.HasPrefix(lower, "www."):
+		return true
+	case strings.HasPrefix(field, "@"), strings.HasPrefix(field, "#"):
+		return true
+	}
+	at := strings.Index(field, "@")
+	return at > 0 && strings.Contains(field[at:], ".")
+}
+
+// tokenize returns the words of a text by tokenizer joined by spaces, or the text if tokenizer is nil
+func tokenize(tokenizer Tokenizer, text string) string {
+	if tokenizer == nil {
+		return text
+	}
+	return strings.Join(tokenizer.Tokenize(text), " ")
+}
@@ -0,0 +1,60 @@
+package langdet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTokenizer(t *testing.T) {
+	Convey("Subject: Tokenizers", t, func() {
+		Convey("The word tokenizer should drop what is not words", func() {
+			text := "Mail me@example.com or see https://example.com/x?y=1, @bob #go 42 times: naïve Straße!"
+			So(langdet.WordTokenizer{}.Tokenize(text), ShouldResemble, []string{"Mail", "or", "see", "times", "naïve", "Straße"})
+			So(langdet.WordTokenizer{Lowercase: true}.Tokenize("Hello WORLD"), ShouldResemble, []string{"hello", "world"})
+			So(langdet.WordTokenizer{}.Tokenize("नमस्ते दुनिया"), ShouldResemble, []string{"नमस्ते", "दुनिया"})
+		})
+		Convey("Detectors and trainers should analyze the tokenized words", func() {
+			d := langdet.NewDetector()
+			d.AddLanguageFromText("Hello I am english text, what is your language?", "english")
+			url := "what is https://www.example.com/index.html"
+			d.Tokenizer = langdet.TokenizerFunc(strings.Fields)
+			withURL := d.GetLanguages(url)[0]
+			d.Tokenizer = langdet.WordTokenizer{}
+			So(d.GetLanguages(url)[0].Confidence, ShouldBeGreaterThan, withURL.Confidence)
+			So(d.GetLanguages(url), ShouldResemble, d.GetLanguages("what is"))
+			results, err := d.GetLanguagesFromReader(strings.NewReader(url), 0)
+			So(err, ShouldBeNil)
+			So(results, ShouldResemble, d.GetLanguages("what is"))
+
+			trainer := langdet.NewTrainer()
+			trainer.Tokenizer = langdet.WordTokenizer{}
+			trainer.Feed("see www.example.com")
+			So(trainer.Counts()["see"], ShouldEqual, 1)
+			So(trainer.Counts()["exa"], ShouldEqual, 0)
+		})
+		Convey("The social tokenizer should keep the words of hashtags and shorten elongations", func() {
+			text := "Soooo happy today 😍❤️ #BonneJournée #go_team @bob 1️⃣ https://t.co/x 🇫🇷!!!"
+			So(langdet.SocialTokenizer{}.Tokenize(text), ShouldResemble, []string{"Soo", "happy", "today", "Bonne", "Journée", "go", "team"})
+			So(langdet.SocialTokenizer{Lowercase: true}.Tokenize("#NoWayHome NOOOO"), ShouldResemble, []string{"no", "way", "home", "noo"})
+			So(langdet.SocialTokenizer{}.Tokenize("Schifffahrt"), ShouldResemble, []string{"Schiffahrt"})
+		})
+		Convey("Tweets should be detected by their words", func() {
+			d := langdet.NewDetector()
+			d.AddLanguageFromText("what a great day, I am so happy to see all of you here tonight, thank you so much for coming", "en")
+			d.AddLanguageFromText("quelle belle journée, je suis tellement heureux de vous voir tous ici ce soir, merci beaucoup", "fr")
+			d.AddLanguageFromText("qué día tan bonito, estoy muy feliz de veros a todos aquí esta noche, muchas gracias por venir", "es")
+			d.Tokenizer = langdet.SocialTokenizer{Lowercase: true}
+			tweets := map[string]string{
+				"sooooo happy to see you all tonight 😍😍 #ThankYou @venue https://t.co/abc": "en",
+				"merciiii beaucoup 🙏 #BonneJournée @amis":                                  "fr",
+				"muchaaas gracias por venir!!! 🎉🎉 #EstaNoche":                              "es",
+			}
+			for tweet, language := range tweets {
+				So(d.GetClosestLanguage(tweet), ShouldEqual, language)
+			}
+		})
+	})
+}
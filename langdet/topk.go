@@ -0,0 +1,47 @@
+package langdet
+
+// DefaultMinMargin is the minimum margin of TopK, when the detector's MinMargin is 0. On held-out
+// sentences, it rejects about half of the wrong detections and half a percent of the right ones.
+var DefaultMinMargin = 0.02
+
+// TopKResult is the result of Detector.TopK
+type TopKResult struct {
+	// Results are the DetectionResults of the k closest languages, most confident first
+	Results []DetectionResult
+	// Margin is the difference between the confidences of the closest and the second closest
+	// language, between 0 and 1, or the confidence of the closest language if it is the only one
+	Margin float64
+	// Ambiguous tells whether the margin is below the minimum margin of the detector
+	Ambiguous bool
+	// Reliable tells whether the closest language is confident enough, like the result of
+	// GetClosestLanguageWithConfidence, and not ambiguous
+	Reliable bool
+}
+
+// TopK returns the DetectionResults of the k closest languages to the text, and whether the
+// closest one clearly wins over the second closest, see MinMargin. k less than 1 returns the
+// results of all languages.
+func (d *Detector) TopK(text string, k int) TopKResult {
+	text = d.prepare(text)
+	occ := CreateOccurenceMap(text, d.inputDepth(d.snapshot()))
+	lookupMap := CreateRankLookupMap(occ)
+	results := d.closestFromInput(lookupMap, d.wordLookup(text))
+	top := TopKResult{Results: results}
+	if k > 0 && len(results) > k {
+		top.Results = results[:k]
+	}
+	if len(results) == 0 {
+		return top
+	}
+	top.Margin = margin(results)
+	minimum := d.MinMargin
+	if minimum == 0 {
+		minimum = DefaultMinMargin
+	}
+	top.Ambiguous = top.Margin < minimum
+	top.Reliable = !top.Ambiguous && d.reliable(lookupMap, results[0])
+	if top.Reliable {
+		d.recent.see(results[0].Name)
+	}
+	return top
+}
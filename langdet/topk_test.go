@@ -0,0 +1,35 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTopK(t *testing.T) {
+	Convey("Subject: Top k detection results with margins", t, func() {
+		d := langdet.NewDefaultLanguages()
+		Convey("Clear texts should have a reliable winner", func() {
+			top := d.TopK("Das ist ein kleiner Text in deutscher Sprache.", 3)
+			So(top.Results, ShouldHaveLength, 3)
+			So(top.Results[0].Name, ShouldEqual, "de")
+			So(top.Margin, ShouldAlmostEqual, (top.Results[0].Confidence-top.Results[1].Confidence)/100)
+			So(top.Ambiguous, ShouldBeFalse)
+			So(top.Reliable, ShouldBeTrue)
+		})
+		Convey("Texts of two languages should be ambiguous with a high minimum margin", func() {
+			d.MinMargin = 0.5
+			top := d.TopK("Das ist ein Text. This is a text.", 2)
+			So(top.Ambiguous, ShouldBeTrue)
+			So(top.Reliable, ShouldBeFalse)
+			d.MinMargin = -1
+			So(d.TopK("Das ist ein Text. This is a text.", 2).Ambiguous, ShouldBeFalse)
+		})
+		Convey("k less than 1 should return all languages", func() {
+			So(d.TopK("text", 0).Results, ShouldHaveLength, len(d.Snapshot()))
+			empty := langdet.NewDetector()
+			So(empty.TopK("text", 3).Reliable, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,337 @@
+package langdet
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Trainer counts the n-grams of texts to build a language profile, e.g. to train or extend profiles
+// in applications. Training can be split between several trainers, e.g. one per goroutine, that are
+// merged before the profile is built. A Trainer is not safe for concurrent use.
+type Trainer struct {
+	// Depth is the n-gram depth of the counts, like the gramDepth of CreateOccurenceMap. It must not
+	// be changed after the first text was fed. 0 selects the depth by the script of the texts with
+	// DepthForScript when the language is built.
+	Depth int
+	// KeepCounts stores the counts with the built languages, so that they can be updated later
+	KeepCounts bool
+	// MaxRanks is the number of ranked tokens of the built profiles, 0 means DefaultProfileSize.
+	// Bigger profiles need bigger corpora to rank their rare tokens reliably.
+	MaxRanks int
+	// Tokenizer splits the fed texts into the words that are counted, nil counts all words.
+	// Detectors of the built profiles should use the same Tokenizer.
+	Tokenizer Tokenizer
+	// Source describes the training corpus, it is the SourceDescription of the built profiles
+	Source string
+	// WordRanks is the number of ranked words of the WordProfile of the built profiles, 0 doesn't
+	// count words. It must not be changed after the first text was fed.
+	WordRanks int
+	// MaxCapitalized drops the sentences of the texts fed with Feed whose fraction of capitalized
+	// words, not counting the first word, is above it, e.g. lists of names or sports rosters, which
+	// add the n-grams of other languages to profiles trained on Wikipedia. Languages that capitalize
+	// nouns, like German, need a higher fraction. 0 keeps all sentences.
+	MaxCapitalized float64
+	// Transliterator transliterates the fed texts before they are counted, e.g. RussianLatin to
+	// train the profile of "ru-Latn" from a Russian corpus in Cyrillic. nil keeps the texts.
+	Transliterator Transliterator
+	// Lowercase lowercases the fed texts before they are counted, so that "The" and "the" count as
+	// the same n-grams, and builds CaseFolded profiles for detectors that lowercase their input
+	Lowercase bool
+	// Progress is called with the progress of the training every ProgressInterval fed documents,
+	// nil reports no progress
+	Progress func(TrainProgress)
+	// ProgressInterval is the number of fed documents between the calls of Progress, 0 means
+	// DefaultProgressInterval
+	ProgressInterval int
+	occurences       map[string]int
+	words            map[string]int
+	dropped          int
+	documents        int
+}
+
+// maxLineSize is the maximum size of the lines fed by FeedLines
+const maxLineSize = 16 * 1024 * 1024
+
+// DefaultProgressInterval is the number of fed documents between the progress reports of a Trainer
+const DefaultProgressInterval = 10000
+
+// TrainProgress is the progress of a Trainer reported to its Progress callback
+type TrainProgress struct {
+	// Documents is the number of documents fed so far, every text fed with Feed and every reader
+	// fed with FeedReader is a document, every line fed with FeedLines
+	Documents int
+	// NGrams is the number of unique n-grams counted so far
+	NGrams int
+}
+
+// DefaultProfileSize is the number of ranked tokens of the profiles created by Analyze and Trainer
+const DefaultProfileSize = maxSampleSize - 1
+
+// NewTrainer returns a Trainer with the n-gram depth of the detection
+func NewTrainer() *Trainer {
+	return &Trainer{Depth: nDepth, occurences: make(map[string]int)}
+}
+
+// Feed counts the n-grams of a text
+func (t *Trainer) Feed(text string) {
+	if t.Transliterator != nil {
+		text = t.Transliterator.Transliterate(text)
+	}
+	if t.MaxCapitalized > 0 {
+		text = t.dropCapitalized(text)
+	}
+	if t.Lowercase {
+		text = strings.ToLower(text)
+	}
+	UpdateOccurenceMap(t.counts(), tokenize(t.Tokenizer, text), t.feedDepth())
+	if t.WordRanks > 0 {
+		countWords(t.wordCounts(), text)
+	}
+	t.fed()
+}
+
+// FeedLines feeds every line of the text of a reader with Feed, as a document of its own, until the
+// reader ends or ctx is done. It returns the error of ctx if it is done, the lines fed so far are
+// counted, e.g. to save a Checkpoint and resume the training later.
+func (t *Trainer) FeedLines(ctx context.Context, reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			t.Feed(line)
+		}
+	}
+	return scanner.Err()
+}
+
+// fed counts a fed document and reports the progress every ProgressInterval documents
+func (t *Trainer) fed() {
+	t.documents++
+	if t.Progress == nil {
+		return
+	}
+	interval := t.ProgressInterval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	if t.documents%interval == 0 {
+		t.Progress(TrainProgress{Documents: t.documents, NGrams: len(t.occurences)})
+	}
+}
+
+// Documents returns the number of documents fed so far, see TrainProgress
+func (t *Trainer) Documents() int {
+	return t.documents
+}
+
+// FeedReader counts the n-grams of the text of a reader, without loading the whole text into memory.
+// With a Transliterator or Lowercase, the text is transliterated or lowercased line by line.
+func (t *Trainer) FeedReader(reader io.Reader) error {
+	if t.Transliterator != nil {
+		reader = newTransliteratingReader(reader, t.Transliterator)
+	}
+	if t.Lowercase {
+		reader = newTransliteratingReader(reader, lowercaser{})
+	}
+	if t.WordRanks <= 0 {
+		if err := updateFromReader(t.counts(), reader, 0, t.feedDepth(), t.Tokenizer); err != nil {
+			return err
+		}
+		t.fed()
+		return nil
+	}
+	words := &wordWriter{counts: t.wordCounts()}
+	if err := updateFromReader(t.counts(), io.TeeReader(reader, words), 0, t.feedDepth(), t.Tokenizer); err != nil {
+		return err
+	}
+	words.flush()
+	t.fed()
+	return nil
+}
+
+// Merge adds the counts of other, which should have the same Depth and WordRanks, to the counts of
+// this trainer
+func (t *Trainer) Merge(other *Trainer) {
+	counts := t.counts()
+	for token, count := range other.occurences {
+		counts[token] += count
+	}
+	if len(other.words) > 0 {
+		words := t.wordCounts()
+		for word, count := range other.words {
+			words[word] += count
+		}
+	}
+	t.dropped += other.dropped
+	t.documents += other.documents
+}
+
+// Dropped returns the number of sentences dropped by MaxCapitalized so far
+func (t *Trainer) Dropped() int {
+	return t.dropped
+}
+
+// dropCapitalized returns the sentences of a text whose fraction of capitalized words is at most
+// MaxCapitalized, one per line
+func (t *Trainer) dropCapitalized(text string) string {
+	kept := []string{}
+	for _, sentence := range splitSentences(text) {
+		if capitalizedRatio(sentence.Text) > t.MaxCapitalized {
+			t.dropped++
+			continue
+		}
+		kept = append(kept, sentence.Text)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// capitalizedRatio returns the fraction of the words of a sentence after the first one that start
+// with an upper case letter. Words without letters are not counted, sentences without counted
+// words have a fraction of 0.
+func capitalizedRatio(sentence string) float64 {
+	words, capitalized := 0, 0
+	for i, word := range strings.Fields(sentence) {
+		word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) })
+		if i == 0 || word == "" {
+			continue
+		}
+		words++
+		if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) || unicode.IsTitle(first) {
+			capitalized++
+		}
+	}
+	if words == 0 {
+		return 0
+	}
+	return float64(capitalized) / float64(words)
+}
+
+// Resume adds the Counts of a language that was built with KeepCounts to the counts of this
+// trainer, so that training of a deployed profile can continue with new texts
+func (t *Trainer) Resume(language Language) error {
+	if language.Counts == nil {
+		return fmt.Errorf("language %s has no counts to resume training", language.Name)
+	}
+	counts := t.counts()
+	for token, count := range language.Counts {
+		counts[token] += count
+	}
+	return nil
+}
+
+// Counts returns a copy of the number of occurrences of every n-gram fed so far
+func (t *Trainer) Counts() map[string]int {
+	counts := make(map[string]int, len(t.occurences))
+	for token, count := range t.occurences {
+		counts[token] = count
+	}
+	return counts
+}
+
+// Build returns the language with the given name and the profile of the n-grams fed so far, with
+// the metadata of the training.
+// The trainer can be fed further texts to build extended profiles afterwards.
+func (t *Trainer) Build(name string) Language {
+	depth, counts := t.Depth, t.occurences
+	if depth <= 0 {
+		depth = DepthForScript(countedScript(counts))
+		counts = withinDepth(counts, depth)
+	}
+	maxRanks := t.MaxRanks
+	if maxRanks <= 0 {
+		maxRanks = DefaultProfileSize
+	}
+	tokens := 0
+	for _, count := range counts {
+		tokens += count
+	}
+	language := Language{
+		Name: name, Depth: depth, Profile: rankLookupMap(counts, maxRanks), Size: maxRanks,
+		TokenCount: tokens, SourceDescription: t.Source, CreatedAt: time.Now().UTC(), FormatVersion: ProfileFormatVersion,
+	}
+	if t.KeepCounts {
+		language.Counts = withinDepth(counts, depth)
+	}
+	language.CaseFolded = t.Lowercase
+	if t.WordRanks > 0 && len(t.words) > 0 {
+		language.WordProfile = rankLookupMap(t.words, t.WordRanks)
+	}
+	language.infer()
+	return language
+}
+
+// lowercaser is the Transliterator of Lowercase
+type lowercaser struct{}
+
+func (lowercaser) Transliterate(text string) string {
+	return strings.ToLower(text)
+}
+
+// feedDepth returns the depth the texts are counted with, the deepest depth of DepthForScript
+// if the depth is selected when the language is built
+func (t *Trainer) feedDepth() int {
+	if t.Depth <= 0 {
+		return latinDepth
+	}
+	return t.Depth
+}
+
+// countedScript returns the script of most letters counted in the single letter tokens of counts
+func countedScript(counts map[string]int) string {
+	scripts := make(map[string]int)
+	for token, count := range counts {
+		if letters := []rune(token); len(letters) == 1 && unicode.IsLetter(letters[0]) {
+			scripts[ScriptOf(letters[0])] += count
+		}
+	}
+	return mostFrequentScript(scripts)
+}
+
+// withinDepth returns a copy of the counts of the tokens of up to depth+1 letters
+func withinDepth(counts map[string]int, depth int) map[string]int {
+	within := make(map[string]int, len(counts))
+	for token, count := range counts {
+		if utf8.RuneCountInString(token) <= depth+1 {
+			within[token] = count
+		}
+	}
+	return within
+}
+
+// counts returns the occurrence map of the trainer, creating it for trainers that are not created
+// by NewTrainer
+func (t *Trainer) counts() map[string]int {
+	if t.occurences == nil {
+		t.occurences = make(map[string]int)
+	}
+	return t.occurences
+}
+
+// Update adds the n-grams of a text to the Counts of the language and re-ranks its profile, e.g.
+// to improve a deployed profile as new labeled text arrives. It fails for languages without Counts,
+// whose ranks can't be updated exactly. The n-gram depth is the Depth of the language, or is
+// inferred from the counts. The texts of CaseFolded languages are lowercased.
+func (l *Language) Update(text string) error {
+	if l.Counts == nil {
+		return fmt.Errorf("language %s has no counts to update", l.Name)
+	}
+	depth := l.Depth
+	if depth == 0 {
+		depth, _, _ = inspectProfile(l.Counts)
+	}
+	trainer := &Trainer{Depth: depth, KeepCounts: true, Lowercase: l.CaseFolded}
+	trainer.Resume(*l)
+	trainer.Feed(text)
+	l.Counts = trainer.counts()
+	l.Rerank()
+	return nil
+}
@@ -0,0 +1,179 @@
+package langdet_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrainer(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	Convey("Subject: Train language profiles", t, func() {
+		Convey("Fed texts should build the profile of Analyze", func() {
+			trainer := langdet.NewTrainer()
+			trainer.Feed(en)
+			built := trainer.Build("english")
+			So(built.CreatedAt, ShouldHappenWithin, time.Minute, time.Now())
+			built.CreatedAt = time.Time{}
+			So(built, ShouldResemble, langdet.Analyze(en, "english"))
+		})
+		Convey("Built profiles should have the metadata of the training", func() {
+			trainer := &langdet.Trainer{Depth: 1, Source: "unit test"}
+			trainer.Feed("ab")
+			built := trainer.Build("test")
+			So(built.Depth, ShouldEqual, 1)
+			So(built.TokenCount, ShouldEqual, 5)
+			So(built.SourceDescription, ShouldEqual, "unit test")
+			So(built.FormatVersion, ShouldEqual, langdet.ProfileFormatVersion)
+		})
+		Convey("Readers should be counted like texts", func() {
+			trainer := langdet.NewTrainer()
+			So(trainer.FeedReader(strings.NewReader(en)), ShouldBeNil)
+			So(trainer.Counts(), ShouldResemble, langdet.CreateOccurenceMap(en, 4))
+		})
+		Convey("Merged trainers should count the texts of both", func() {
+			first, second, both := langdet.NewTrainer(), langdet.NewTrainer(), langdet.NewTrainer()
+			first.Feed(en)
+			second.Feed("Je ne sais pas ce que tu dis.")
+			both.Feed(en)
+			both.Feed("Je ne sais pas ce que tu dis.")
+			first.Merge(second)
+			So(first.Counts(), ShouldResemble, both.Counts())
+		})
+		Convey("Zero trainers should be usable", func() {
+			trainer := &langdet.Trainer{Depth: 1}
+			trainer.Feed("ab")
+			So(trainer.Counts(), ShouldResemble, map[string]int{"a": 1, "b": 1, "_a": 1, "ab": 1, "b_": 1})
+		})
+		Convey("Languages with counts should be updated exactly", func() {
+			fr := "Je ne sais pas ce que tu dis."
+			trainer := langdet.NewTrainer()
+			trainer.KeepCounts = true
+			trainer.Feed(en)
+			language := trainer.Build("english")
+			So(language.Counts, ShouldResemble, trainer.Counts())
+			So(language.Update(fr), ShouldBeNil)
+			trainer.Feed(fr)
+			So(language.Profile, ShouldResemble, trainer.Build("english").Profile)
+
+			resumed := langdet.NewTrainer()
+			So(resumed.Resume(language), ShouldBeNil)
+			So(resumed.Counts(), ShouldResemble, trainer.Counts())
+		})
+		Convey("Profiles should have the trained size", func() {
+			trainer := langdet.NewTrainer()
+			trainer.Feed(en)
+			So(trainer.Build("english").Size, ShouldEqual, langdet.DefaultProfileSize)
+			trainer.MaxRanks = 10
+			language := trainer.Build("english")
+			So(language.Size, ShouldEqual, 10)
+			So(language.Profile, ShouldHaveLength, 10)
+		})
+		Convey("Languages without counts should not be updated", func() {
+			language := langdet.Analyze(en, "english")
+			So(language.Update(en), ShouldNotBeNil)
+			So(langdet.NewTrainer().Resume(language), ShouldNotBeNil)
+		})
+		Convey("Sentences of mostly capitalized words should be dropped", func() {
+			trainer := langdet.NewTrainer()
+			trainer.MaxCapitalized = 0.5
+			trainer.Feed("The cat sleeps on the mat. Roster: Zlatan Ibrahimović, Kylian Mbappé, Łukasz Piszczek.\nNo names here at all")
+			So(trainer.Dropped(), ShouldEqual, 1)
+			kept := langdet.NewTrainer()
+			kept.Feed("The cat sleeps on the mat.\nNo names here at all")
+			So(trainer.Counts(), ShouldResemble, kept.Counts())
+		})
+		Convey("Lowercasing trainers should count the lowercased texts", func() {
+			trainer := langdet.NewTrainer()
+			trainer.Lowercase = true
+			trainer.Feed("The Cat")
+			So(trainer.FeedReader(strings.NewReader("THE CAT")), ShouldBeNil)
+			lowercased := langdet.NewTrainer()
+			lowercased.Feed("the cat")
+			lowercased.Feed("the cat")
+			So(trainer.Counts(), ShouldResemble, lowercased.Counts())
+			So(trainer.Build("english").CaseFolded, ShouldBeTrue)
+			So(lowercased.Build("english").CaseFolded, ShouldBeFalse)
+		})
+	})
+
+	Convey("Subject: Progress, cancellation and checkpoints of training", t, func() {
+		lines := "first line\nsecond line\n\nthird line\nfourth line\n"
+		Convey("Progress should be reported every interval", func() {
+			reports := []langdet.TrainProgress{}
+			trainer := langdet.NewTrainer()
+			trainer.ProgressInterval = 2
+			trainer.Progress = func(progress langdet.TrainProgress) { reports = append(reports, progress) }
+			So(trainer.FeedLines(context.Background(), strings.NewReader(lines)), ShouldBeNil)
+			So(trainer.Documents(), ShouldEqual, 4)
+			So(reports, ShouldHaveLength, 2)
+			So(reports[1].Documents, ShouldEqual, 4)
+			So(reports[1].NGrams, ShouldEqual, len(trainer.Counts()))
+		})
+		Convey("Cancelled training should stop", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			trainer := langdet.NewTrainer()
+			trainer.ProgressInterval = 1
+			trainer.Progress = func(langdet.TrainProgress) { cancel() }
+			So(trainer.FeedLines(ctx, strings.NewReader(lines)), ShouldEqual, context.Canceled)
+			So(trainer.Documents(), ShouldEqual, 1)
+		})
+		Convey("Resumed training should count like uninterrupted training", func() {
+			whole := langdet.NewTrainer()
+			So(whole.FeedLines(context.Background(), strings.NewReader(lines)), ShouldBeNil)
+
+			interrupted := langdet.NewTrainer()
+			interrupted.Feed("first line")
+			interrupted.Feed("second line")
+			checkpoint := &bytes.Buffer{}
+			So(interrupted.WriteCheckpoint(checkpoint, 3), ShouldBeNil)
+			resumed := langdet.NewTrainer()
+			position, err := resumed.ReadCheckpoint(checkpoint)
+			So(err, ShouldBeNil)
+			So(position, ShouldEqual, 3)
+			So(resumed.Documents(), ShouldEqual, 2)
+			resumed.Feed("third line")
+			resumed.Feed("fourth line")
+			So(resumed.Counts(), ShouldResemble, whole.Counts())
+			So(resumed.Documents(), ShouldEqual, whole.Documents())
+		})
+		Convey("Checkpoints of other depths should not be read", func() {
+			checkpoint := &bytes.Buffer{}
+			So((&langdet.Trainer{Depth: 2}).WriteCheckpoint(checkpoint, 0), ShouldBeNil)
+			_, err := langdet.NewTrainer().ReadCheckpoint(checkpoint)
+			So(err, ShouldNotBeNil)
+			_, err = langdet.NewTrainer().ReadCheckpoint(strings.NewReader("not json"))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDeterminism(t *testing.T) {
+	Convey("Subject: Deterministic training and detection", t, func() {
+		texts := map[string]string{
+			"english": "Hello I am english text, what is your language? I really dont know you say?",
+			"french":  "Je parles français et toi? Je ne sais pas ce que tu dis.",
+			"german":  "Das ist ein deutscher Satz, ich weiss nicht was du sagst",
+		}
+		build := func() langdet.Detector {
+			d := langdet.NewDetector()
+			for _, name := range []string{"english", "french", "german"} {
+				trainer := langdet.NewTrainer()
+				trainer.Feed(texts[name])
+				language := trainer.Build(name)
+				language.CreatedAt = time.Time{}
+				d.AddLanguage(language)
+			}
+			return d
+		}
+		first, second := build(), build()
+		So(second.Snapshot(), ShouldResemble, first.Snapshot())
+		for i := 0; i < 20; i++ {
+			So(second.GetLanguages("ce que tu dis"), ShouldResemble, first.GetLanguages("ce que tu dis"))
+		}
+	})
+}
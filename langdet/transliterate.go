@@ -0,0 +1,103 @@
+package langdet
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Transliterator converts texts of one script into another, e.g. Russian in Cyrillic into the Latin
+// spelling of chats. A Trainer with a Transliterator trains the profile of the transliterated
+// variant of a language, like "ru-Latn", from a corpus in its native script.
+type Transliterator interface {
+	// Transliterate returns the transliterated text
+	Transliterate(text string) string
+}
+
+// TransliterationTable is a Transliterator that replaces the runes of its keys by their values and
+// keeps all other runes. The entries are lowercase, upper case runes without an entry of their own
+// are replaced by the capitalized value of their lower case rune.
+type TransliterationTable map[rune]string
+
+// Transliterate implements Transliterator
+func (t TransliterationTable) Transliterate(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		if value, ok := t[r]; ok {
+			b.WriteString(value)
+			continue
+		}
+		if lower := unicode.ToLower(r); lower != r {
+			if value, ok := t[lower]; ok {
+				b.WriteString(capitalize(value))
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// capitalize returns a string with its first rune in upper case
+func capitalize(s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(first)) + s[size:]
+}
+
+// RussianLatin transliterates Russian in Cyrillic into the informal Latin spelling of chats and
+// forums, e.g. "привет, как дела" into "privet, kak dela"
+var RussianLatin = TransliterationTable{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// GreekLatin transliterates Greek into the Latin spelling known as Greeklish, e.g. "καλημέρα" into
+// "kalimera"
+var GreekLatin = TransliterationTable{
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i",
+	'ή': "i", 'θ': "th", 'ι': "i", 'ί': "i", 'ϊ': "i", 'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m",
+	'ν': "n", 'ξ': "ks", 'ο': "o", 'ό': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t",
+	'υ': "y", 'ύ': "y", 'ϋ': "y", 'ΰ': "y", 'φ': "f", 'χ': "x", 'ψ': "ps", 'ω': "o", 'ώ': "o",
+}
+
+// Transliterations are the built-in Transliterators by the name of the transliterated variant of
+// their language
+var Transliterations = map[string]Transliterator{
+	"ru-Latn": RussianLatin,
+	"el-Latn": GreekLatin,
+}
+
+// transliteratingReader transliterates the text of a reader line by line
+type transliteratingReader struct {
+	r              *bufio.Reader
+	transliterator Transliterator
+	buf            string
+	err            error
+}
+
+// newTransliteratingReader returns a reader of the transliterated text of r
+func newTransliteratingReader(r io.Reader, transliterator Transliterator) io.Reader {
+	return &transliteratingReader{r: bufio.NewReader(r), transliterator: transliterator}
+}
+
+func (t *transliteratingReader) Read(p []byte) (int, error) {
+	for t.buf == "" && t.err == nil {
+		var line string
+		line, t.err = t.r.ReadString('\n')
+		t.buf = t.transliterator.Transliterate(line)
+	}
+	if t.buf == "" {
+		return 0, t.err
+	}
+	n := copy(p, t.buf)
+	t.buf = t.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,34 @@
+package langdet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransliterate(t *testing.T) {
+	Convey("Subject: Transliterate texts into another script", t, func() {
+		Convey("Tables should replace their runes and keep all others", func() {
+			So(langdet.RussianLatin.Transliterate("привет, как дела?"), ShouldEqual, "privet, kak dela?")
+			So(langdet.RussianLatin.Transliterate("Жена съела щи 2 раза"), ShouldEqual, "Zhena sela schi 2 raza")
+			So(langdet.GreekLatin.Transliterate("Καλημέρα"), ShouldEqual, "Kalimera")
+			So(langdet.Transliterations["ru-Latn"], ShouldEqual, langdet.RussianLatin)
+		})
+		Convey("Trainers should train the transliterated texts", func() {
+			text := "Я не знаю, что сказать.\nМожно мне чашку кофе?\nСегодня хорошая погода."
+			expected := langdet.Analyze(langdet.RussianLatin.Transliterate(text), "ru-Latn")
+
+			trainer := langdet.NewTrainer()
+			trainer.Transliterator = langdet.RussianLatin
+			trainer.Feed(text)
+			So(trainer.Build("ru-Latn").Profile, ShouldResemble, expected.Profile)
+
+			trainer = langdet.NewTrainer()
+			trainer.Transliterator = langdet.RussianLatin
+			So(trainer.FeedReader(strings.NewReader(text)), ShouldBeNil)
+			So(trainer.Build("ru-Latn").Profile, ShouldResemble, expected.Profile)
+		})
+	})
+}
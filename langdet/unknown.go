@@ -0,0 +1,81 @@
+package langdet
+
+import (
+	"math"
+	"sort"
+)
+
+// UnknownLanguage is the name of the "none of the above" result of GetLanguages with
+// ReportUnknown, the BCP 47 tag of undetermined languages
+const UnknownLanguage = "und"
+
+// unknownQuantile is the quantile of the confidences of a language on texts of untrained
+// languages that FitUnknown stores as its UnknownBelow
+const unknownQuantile = 0.95
+
+// unknownResult returns the result of UnknownLanguage for the text of a lookupMap and its sorted
+// results. Its confidence is the highest of the confidence of the Background model and of the
+// UnknownBelow of the closest language, so that a language must beat it to be more likely than an
+// untrained language.
+func (d *Detector) unknownResult(lookupMap map[string]int, results []DetectionResult) DetectionResult {
+	unknown := DetectionResult{Name: UnknownLanguage}
+	if background, ok := d.backgroundResult(lookupMap); ok {
+		unknown.Confidence = background.Confidence
+		unknown.Distance = background.Distance
+		unknown.MatchedTokens = background.MatchedTokens
+		unknown.ComparedTokens = background.ComparedTokens
+		unknown.InputTokens = background.InputTokens
+	}
+	if len(results) > 0 {
+		if language := d.language(results[0].Name); language != nil {
+			unknown.Confidence = math.Max(unknown.Confidence, language.UnknownBelow*100)
+		}
+	}
+	return unknown
+}
+
+// withUnknown returns the sorted results with the unknown result before the first result that is
+// not more confident
+func withUnknown(results []DetectionResult, unknown DetectionResult) []DetectionResult {
+	i := sort.Search(len(results), func(i int) bool { return results[i].Confidence <= unknown.Confidence })
+	withUnknown := make([]DetectionResult, 0, len(results)+1)
+	withUnknown = append(withUnknown, results[:i]...)
+	withUnknown = append(withUnknown, unknown)
+	return append(withUnknown, results[i:]...)
+}
+
+// belowUnknown tells whether a result is not more confident than the UnknownBelow of its language
+func (d *Detector) belowUnknown(result DetectionResult) bool {
+	language := d.language(result.Name)
+	return language != nil && language.UnknownBelow > 0 && result.Confidence <= language.UnknownBelow*100
+}
+
+// FitUnknown sets the UnknownBelow of every language of this detector to the 95th percentile of
+// its confidences on texts of languages that are not trained, e.g. of a mix of other languages,
+// so that texts of untrained languages come back as UnknownLanguage instead of as the closest
+// trained language. The languages are updated like ReplaceLanguage, profiles written afterwards
+// store their cutoffs.
+func (d *Detector) FitUnknown(texts []string) {
+	languages := d.snapshot()
+	confidences := make(map[string][]float64, len(languages))
+	depth := d.inputDepth(languages)
+	for _, text := range texts {
+		occ := CreateOccurenceMap(d.prepare(text), depth)
+		for _, result := range d.closestFromTable(CreateRankLookupMap(occ)) {
+			confidences[result.Name] = append(confidences[result.Name], result.Confidence/100)
+		}
+	}
+	fitted := make([]Language, 0, len(languages))
+	for _, language := range languages {
+		values := confidences[language.Name]
+		if len(values) == 0 {
+			continue
+		}
+		sort.Float64s(values)
+		language.UnknownBelow = values[int(unknownQuantile*float64(len(values)-1))]
+		fitted = append(fitted, language)
+	}
+	d.update(func(l []Language) []Language {
+		return swapLanguages(l, fitted, nil)
+	})
+}
@@ -0,0 +1,38 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnknownLanguage(t *testing.T) {
+	Convey("Subject: Unknown language results", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText("The dog and the cat play in the garden. The children go to school every day.", "english")
+		d.AddLanguageFromText("Der Hund und die Katze spielen im Garten. Die Kinder gehen jeden Tag in die Schule.", "german")
+		untrained := "Le chien et le chat jouent dans le jardin."
+
+		Convey("GetLanguages should not report unknown languages by default", func() {
+			for _, result := range d.GetLanguages("The dog plays in the garden") {
+				So(result.Name, ShouldNotEqual, langdet.UnknownLanguage)
+			}
+		})
+		Convey("Fitted cutoffs should rank texts of untrained languages as unknown", func() {
+			d.FitUnknown([]string{untrained, untrained, untrained})
+			for _, language := range d.Snapshot() {
+				So(language.UnknownBelow, ShouldBeGreaterThan, 0)
+			}
+			d.ReportUnknown = true
+			results := d.GetLanguages(untrained)
+			So(results, ShouldHaveLength, 3)
+			So(results[0].Name, ShouldEqual, langdet.UnknownLanguage)
+			So(d.GetClosestLanguage(untrained), ShouldEqual, "undefined")
+
+			results = d.GetLanguages("The children play with the dog in the garden every day.")
+			So(results[0].Name, ShouldEqual, "english")
+			So(results[1].Name, ShouldEqual, langdet.UnknownLanguage)
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package langdet
+
+import "strings"
+
+// selectVariants returns the languages without the variants of languages that have a cased and a
+// case-folded variant that don't match folded. Languages with a single variant are kept.
+func selectVariants(languages []Language, folded bool) []Language {
+	variants := false
+	for _, language := range languages {
+		if language.CaseFolded {
+			variants = true
+			break
+		}
+	}
+	if !variants {
+		return languages
+	}
+	cased := make(map[string]bool)
+	caseFolded := make(map[string]bool)
+	for _, language := range languages {
+		if language.CaseFolded {
+			caseFolded[language.Name] = true
+		} else {
+			cased[language.Name] = true
+		}
+	}
+	selected := make([]Language, 0, len(languages))
+	for _, language := range languages {
+		if cased[language.Name] && caseFolded[language.Name] && language.CaseFolded != folded {
+			continue
+		}
+		selected = append(selected, language)
+	}
+	return selected
+}
+
+// variantKey identifies a language by its name and whether it is the case-folded variant
+func variantKey(language Language) string {
+	if language.CaseFolded {
+		return language.Name + "\x00folded"
+	}
+	return language.Name
+}
+
+// BuildFolded returns the case-folded variant of the language that Build returns, with the profile
+// of the lowercased n-grams fed so far, so that both variants are trained in one pass. The
+// n-grams of mixed case are summed up with their lowercase n-grams.
+func (t *Trainer) BuildFolded(name string) Language {
+	folded := make(map[string]int, len(t.occurences))
+	for token, count := range t.occurences {
+		folded[strings.ToLower(token)] += count
+	}
+	language := (&Trainer{Depth: t.Depth, KeepCounts: t.KeepCounts, MaxRanks: t.MaxRanks, Source: t.Source, WordRanks: t.WordRanks, occurences: folded, words: t.words}).Build(name)
+	language.CaseFolded = true
+	return language
+}
@@ -0,0 +1,56 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCaseVariants(t *testing.T) {
+	Convey("Subject: Cased and case-folded variants of profiles", t, func() {
+		trainer := &langdet.Trainer{Depth: 3}
+		trainer.Feed("Der Hund und die Katze spielen im Garten. Das Haus hat einen großen Garten.")
+		cased := trainer.Build("german")
+		folded := trainer.BuildFolded("german")
+
+		Convey("Both variants should be trained in one pass", func() {
+			So(cased.CaseFolded, ShouldBeFalse)
+			So(folded.CaseFolded, ShouldBeTrue)
+			So(cased.Profile, ShouldContainKey, "_H")
+			So(folded.Profile, ShouldNotContainKey, "_H")
+			So(folded.Profile, ShouldContainKey, "_h")
+			So(folded.TokenCount, ShouldEqual, cased.TokenCount)
+		})
+		Convey("Detectors should select the variant by FoldCase", func() {
+			d := langdet.NewDetector()
+			d.AddLanguage(cased, folded)
+			d.AddLanguageFromText("The dog and the cat play in the garden.", "english")
+			So(d.ListLanguages(), ShouldResemble, []string{"german", "english"})
+			So(d.Snapshot(), ShouldHaveLength, 3)
+			So(d.GetClosestLanguage("Der Hund spielt im Garten"), ShouldEqual, "german")
+			casedResults := d.GetLanguages("Der Hund spielt im Garten")
+
+			d.FoldCase = true
+			So(d.ListLanguages(), ShouldResemble, []string{"german", "english"})
+			So(d.GetClosestLanguage("DER HUND SPIELT IM GARTEN"), ShouldEqual, "german")
+			So(d.GetLanguages("Der Hund spielt im Garten"), ShouldNotResemble, casedResults)
+		})
+		Convey("Replacing a variant should keep the other one", func() {
+			d := langdet.NewDetector()
+			d.AddLanguage(cased, folded)
+			trainer.Feed("Die Kinder gehen in die Schule.")
+			d.ReplaceLanguage(trainer.BuildFolded("german"))
+			So(d.Snapshot(), ShouldHaveLength, 2)
+			So(d.RemoveLanguage("german"), ShouldBeTrue)
+			So(d.Snapshot(), ShouldBeEmpty)
+		})
+		Convey("Languages with a single variant should always be detected", func() {
+			d := langdet.NewDetector()
+			d.AddLanguage(folded)
+			So(d.ListLanguages(), ShouldResemble, []string{"german"})
+			d.FoldCase = true
+			So(d.ListLanguages(), ShouldResemble, []string{"german"})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package langdet
+
+import "fmt"
+
+// Verify tells whether a text is written in the named language, and the confidence of the language
+// between 0 and 1. Only that language is scored, which is much faster than a full detection with
+// many languages. The text is verified if the confidence reaches the minimum confidence of the
+// language, enough input tokens match and the text is closer to the language than to the Background
+// model, like the result of GetClosestLanguageWithConfidence.
+// Texts of another script than the language are rejected without scoring.
+func (d *Detector) Verify(text, lang string) (bool, float64) {
+	language := d.language(lang)
+	if language == nil {
+		return false, 0
+	}
+	result, lookupMap, ok := d.scoreSingle(text, *language)
+	if !ok {
+		return false, 0
+	}
+	verified := result.Confidence >= asPercent(d.minimumConfidence(lang)) && d.matchedEnough(result) && !d.closerToBackground(lookupMap, result)
+	return verified, result.Confidence / 100
+}
+
+// Score returns the score of a text for the named language between 0 and 1, the confidence of the
+// language in a full detection with the LanguageOptions of the language, without its thresholds.
+// It allows applications to make their own decisions, e.g. with the margin to the score of another
+// language. Texts of another script than the language score 0, unknown languages are errors.
+func (d *Detector) Score(text, lang string) (float64, error) {
+	language := d.language(lang)
+	if language == nil {
+		return 0, fmt.Errorf("unknown language %q", lang)
+	}
+	result, _, ok := d.scoreSingle(text, *language)
+	if !ok {
+		return 0, nil
+	}
+	return result.Confidence / 100, nil
+}
+
+// scoreSingle scores a text for a single language and returns the weighed result and the input
+// tokens of the depth of the language. It returns false for texts of another script than the
+// language, which are not scored.
+func (d *Detector) scoreSingle(text string, language Language) (DetectionResult, map[string]int, bool) {
+	lookupMap := d.singleLookupMap(text, language)
+	if script := d.inputScript(lookupMap); script != "" && !language.writtenIn(script) {
+		return DetectionResult{}, lookupMap, false
+	}
+	result := d.score(lookupMap, d.maxInputRanks(len(lookupMap)), language)
+	d.LanguageOptions(language.Name).weigh(&result)
+	return result, lookupMap, true
+}
+
+// singleLookupMap returns the rank lookup map of the input tokens of a text of the depth of a
+// language, which are compared with it by closestFromTable
+func (d *Detector) singleLookupMap(text string, language Language) map[string]int {
+	depth := language.Depth
+	if depth == 0 || (d.Depth > 0 && d.Depth < depth) {
+		depth = d.inputDepth(nil)
+	}
+	return CreateRankLookupMap(CreateOccurenceMap(d.prepare(text), depth))
+}
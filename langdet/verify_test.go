@@ -0,0 +1,56 @@
+package langdet_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	fr := "Je parles français et toi? Je ne sais pas ce que tu dis."
+	Convey("Subject: Verify the language of a text", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(en, "english")
+		d.AddLanguageFromText(fr, "french")
+
+		Convey("The confidence should equal the one of a full detection", func() {
+			text := "what is your language"
+			ok, confidence := d.Verify(text, "english")
+			name, detected, reliable := d.GetClosestLanguageWithConfidence(text)
+			So(name, ShouldEqual, "english")
+			So(confidence, ShouldEqual, detected)
+			So(ok, ShouldEqual, reliable)
+			So(ok, ShouldBeTrue)
+		})
+		Convey("Texts of other languages should not be verified", func() {
+			ok, _ := d.Verify("Je ne sais pas ce que tu dis", "english")
+			So(ok, ShouldBeFalse)
+			ok, confidence := d.Verify("Привет, как дела?", "english")
+			So(ok, ShouldBeFalse)
+			So(confidence, ShouldEqual, 0)
+		})
+		Convey("Unknown languages should not be verified", func() {
+			ok, confidence := d.Verify(en, "klingon")
+			So(ok, ShouldBeFalse)
+			So(confidence, ShouldEqual, 0)
+		})
+		Convey("Scores should equal the confidences of a full detection", func() {
+			text := "what is your language"
+			score, err := d.Score(text, "english")
+			So(err, ShouldBeNil)
+			_, detected, _ := d.GetClosestLanguageWithConfidence(text)
+			So(score, ShouldEqual, detected)
+
+			french, err := d.Score(text, "french")
+			So(err, ShouldBeNil)
+			So(french, ShouldBeLessThan, score)
+			french, err = d.Score("Привет, как дела?", "french")
+			So(err, ShouldBeNil)
+			So(french, ShouldEqual, 0)
+
+			_, err = d.Score(text, "klingon")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
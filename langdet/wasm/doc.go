@@ -0,0 +1,21 @@
+// Package wasm exposes a langdet.Detector to JavaScript when the detector is built for the browser
+// or Node.js with GOOS=js GOARCH=wasm. Programs register the detector and keep running:
+//
+//	//go:build js && wasm
+//
+//	package main
+//
+//	func main() {
+//		detector := langdet.NewDetectorWithLanguages(langdet.EmbeddedLanguages()...)
+//		wasm.Run("langdet", &detector)
+//	}
+//
+// JavaScript then detects texts with the functions of the global langdet object:
+//
+//	langdet.detect("do not care about quantity")   // {language: "en", confidence: 0.93, reliable: true}
+//	langdet.languages("ont permis d'identifier")    // [{name: "fr", confidence: 0.86}, ...]
+//	langdet.load(new Uint8Array(profiles))          // null, or the message of the error
+//
+// The embedded profiles make the binary bigger. Build with -tags langdet_nodefaults to leave them
+// out and load the profiles fetched by the page instead.
+package wasm
@@ -0,0 +1,59 @@
+//go:build js && wasm
+
+package wasm
+
+import (
+	"syscall/js"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+)
+
+// Register sets the global JavaScript object name with the functions detect, languages and load of
+// a detector, see the package documentation
+func Register(name string, d *langdet.Detector) {
+	js.Global().Set(name, Object(d))
+}
+
+// Run registers a detector like Register and blocks, so that the functions stay callable after
+// main would return
+func Run(name string, d *langdet.Detector) {
+	Register(name, d)
+	select {}
+}
+
+// Object returns the JavaScript object with the functions of a detector
+func Object(d *langdet.Detector) js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"detect": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			name, confidence, reliable := d.GetClosestLanguageWithConfidence(textArg(args))
+			return map[string]interface{}{"language": name, "confidence": confidence, "reliable": reliable}
+		}),
+		"languages": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			results := d.GetLanguages(textArg(args))
+			values := make([]interface{}, len(results))
+			for i, result := range results {
+				values[i] = map[string]interface{}{"name": result.Name, "confidence": result.Confidence / 100}
+			}
+			return values
+		}),
+		"load": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			if len(args) == 0 || args[0].Type() != js.TypeObject {
+				return "load expects a Uint8Array of the languages file"
+			}
+			data := make([]byte, args[0].Get("length").Int())
+			js.CopyBytesToGo(data, args[0])
+			if err := d.LoadLanguagesFromBytes(data); err != nil {
+				return err.Error()
+			}
+			return nil
+		}),
+	})
+}
+
+// textArg returns the first argument as a string, or an empty string for other types
+func textArg(args []js.Value) string {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return ""
+	}
+	return args[0].String()
+}
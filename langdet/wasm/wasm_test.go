@@ -0,0 +1,42 @@
+//go:build js && wasm
+
+package wasm_test
+
+import (
+	"syscall/js"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/wasm"
+)
+
+// goconvey does not run with GOOS=js, these tests use the testing package only. Run them with
+// GOOS=js GOARCH=wasm go test -exec "$(go env GOROOT)/lib/wasm/go_js_wasm_exec" ./langdet/wasm
+func TestObject(t *testing.T) {
+	en := "Hello I am english text, what is your language? I really dont know you say? "
+	d := langdet.NewDetector()
+	d.AddLanguageFromText(en, "en")
+	wasm.Register("langdet", &d)
+	object := js.Global().Get("langdet")
+
+	result := object.Call("detect", en)
+	if language := result.Get("language").String(); language != "en" || !result.Get("reliable").Bool() {
+		t.Errorf("detect returned %s, reliable %v, expected en", language, result.Get("reliable").Bool())
+	}
+	if confidence := object.Call("languages", en).Index(0).Get("confidence").Float(); confidence != 1 {
+		t.Errorf("languages returned the confidence %v, expected 1", confidence)
+	}
+
+	data := []byte(`[{"name": "fr", "profile": {"_je": 1}}]`)
+	array := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(array, data)
+	if err := object.Call("load", array); !err.IsNull() {
+		t.Errorf("load returned %s", err.String())
+	}
+	if languages := d.ListLanguages(); len(languages) != 1 || languages[0] != "fr" {
+		t.Errorf("load loaded %v, expected fr", languages)
+	}
+	if err := object.Call("load", "profiles"); err.Type() != js.TypeString {
+		t.Errorf("load of a string returned no error")
+	}
+}
@@ -0,0 +1,181 @@
+package langdet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is the interval at which WatchDir polls the profile directory for changes
+// if no interval is given
+const DefaultWatchInterval = 2 * time.Second
+
+// The operations of ReloadEvents
+const (
+	ReloadAdded   = "added"
+	ReloadChanged = "changed"
+	ReloadRemoved = "removed"
+)
+
+// ReloadEvent describes a change of the languages of a Detector by WatchDir
+type ReloadEvent struct {
+	// File is the name of the profile file in the watched directory
+	File string
+	// Language is the name of the added, changed or removed language, empty if Err is set
+	Language string
+	// Op is ReloadAdded, ReloadChanged or ReloadRemoved
+	Op string
+	// Err is the error of a profile file that could not be decoded, whose previous language is
+	// kept
+	Err error
+}
+
+// watchedFile is the state of a profile file of WatchDir
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	// language is the name of the language of the file, empty if it could not be decoded
+	language string
+	// key is the variantKey of the language
+	key string
+}
+
+// WatchDir keeps the languages of this Detector in sync with the json or binary profile files of a
+// directory, like LoadLanguagesFromDir, until ctx is done, for long-running services whose
+// profiles are retrained. It first replaces or adds the languages of all files, see
+// ReplaceLanguage, and fails if any of them cannot be read. Then it polls the directory every
+// interval, 0 for DefaultWatchInterval: the languages of new and modified files are swapped in and
+// the languages of deleted files are removed, all changes of a poll at once, so that concurrent
+// detections see either the old or the new languages. Files that cannot be decoded, e.g. while
+// they are written, keep their previous language. onReload, if not nil, is called for every change
+// and error. WatchDir returns the error of ctx when it is done.
+func (d *Detector) WatchDir(ctx context.Context, dirPath string, interval time.Duration, onReload func(ReloadEvent)) error {
+	files := make(map[string]watchedFile)
+	events, err := d.syncDir(dirPath, files)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if event.Err != nil {
+			return event.Err
+		}
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		events, err := d.syncDir(dirPath, files)
+		if err != nil {
+			events = []ReloadEvent{{File: dirPath, Err: err}}
+		}
+		if onReload != nil {
+			for _, event := range events {
+				onReload(event)
+			}
+		}
+	}
+}
+
+// syncDir updates the languages of this detector with the profile files of a directory that are
+// new or changed since their state in files, which it updates, and returns the changes
+func (d *Detector) syncDir(dirPath string, files map[string]watchedFile) ([]ReloadEvent, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	events := []ReloadEvent{}
+	replaced := []Language{}
+	removed := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		seen[name] = true
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		previous, known := files[name]
+		if known && info.ModTime().Equal(previous.modTime) && info.Size() == previous.size {
+			continue
+		}
+		current := watchedFile{modTime: info.ModTime(), size: info.Size(), language: previous.language, key: previous.key}
+		files[name] = current
+		language, err := decodeLanguageFile(os.DirFS(dirPath), name)
+		if err != nil {
+			events = append(events, ReloadEvent{File: filepath.Join(dirPath, name), Err: err})
+			continue
+		}
+		if previous.key != "" && previous.key != variantKey(language) {
+			removed[previous.key] = true
+		}
+		current.language, current.key = language.Name, variantKey(language)
+		files[name] = current
+		replaced = append(replaced, language)
+		op := ReloadChanged
+		if previous.language == "" {
+			op = ReloadAdded
+		}
+		events = append(events, ReloadEvent{File: filepath.Join(dirPath, name), Language: language.Name, Op: op})
+	}
+	for name, file := range files {
+		if seen[name] {
+			continue
+		}
+		delete(files, name)
+		if file.language != "" {
+			removed[file.key] = true
+			events = append(events, ReloadEvent{File: filepath.Join(dirPath, name), Language: file.language, Op: ReloadRemoved})
+		}
+	}
+	for _, language := range replaced {
+		delete(removed, variantKey(language))
+	}
+	if len(replaced) > 0 || len(removed) > 0 {
+		d.update(func(l []Language) []Language {
+			return swapLanguages(l, replaced, removed)
+		})
+	}
+	return events, nil
+}
+
+// swapLanguages returns languages without the removed ones, with the replaced languages in the
+// positions of the languages of their names and the others appended. Languages are identified by
+// their variantKey, so that replacing a variant keeps the other variant.
+func swapLanguages(languages, replaced []Language, removed map[string]bool) []Language {
+	replacements := make(map[string]int, len(replaced))
+	for i, language := range replaced {
+		replacements[variantKey(language)] = i
+	}
+	swapped := make([]Language, 0, len(languages)+len(replaced))
+	for _, language := range languages {
+		key := variantKey(language)
+		i, ok := replacements[key]
+		switch {
+		case removed[key]:
+		case !ok:
+			swapped = append(swapped, language)
+		case i >= 0:
+			swapped = append(swapped, replaced[i])
+			replacements[key] = -1
+		}
+	}
+	for _, language := range replaced {
+		if i := replacements[variantKey(language)]; i >= 0 {
+			swapped = append(swapped, replaced[i])
+			replacements[variantKey(language)] = -1
+		}
+	}
+	return swapped
+}
@@ -0,0 +1,101 @@
+package langdet_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWatchDir(t *testing.T) {
+	Convey("Subject: Reload the profiles of a directory on changes", t, func() {
+		dir := t.TempDir()
+		modified := time.Now().Add(-time.Hour)
+		write := func(file, text, name string) {
+			data, err := json.Marshal(langdet.Analyze(text, name))
+			So(err, ShouldBeNil)
+			So(os.WriteFile(filepath.Join(dir, file), data, 0644), ShouldBeNil)
+			// distinct modification times, also on file systems of coarse timestamps
+			modified = modified.Add(time.Second)
+			So(os.Chtimes(filepath.Join(dir, file), modified, modified), ShouldBeNil)
+		}
+		write("en.json", "Hello I am english text, what is your language?", "english")
+		write("fr.json", "Je parles français et toi?", "french")
+
+		d := langdet.NewDetector()
+		var mu sync.Mutex
+		events := []langdet.ReloadEvent{}
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- d.WatchDir(ctx, dir, 10*time.Millisecond, func(event langdet.ReloadEvent) {
+				mu.Lock()
+				events = append(events, event)
+				mu.Unlock()
+			})
+		}()
+		Reset(func() {
+			cancel()
+			<-done
+		})
+		waitFor := func(count int) []langdet.ReloadEvent {
+			for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+				mu.Lock()
+				n := len(events)
+				mu.Unlock()
+				if n >= count {
+					break
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]langdet.ReloadEvent{}, events...)
+		}
+		for deadline := time.Now().Add(5 * time.Second); len(d.ListLanguages()) < 2 && time.Now().Before(deadline); {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		Convey("The profiles of the directory should be loaded first", func() {
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french"})
+		})
+		Convey("Changed profiles should be swapped in", func() {
+			write("fr.json", "Je suis un texte français, quelle est ta langue?", "french")
+			received := waitFor(1)
+			So(received, ShouldHaveLength, 1)
+			So(received[0].Op, ShouldEqual, langdet.ReloadChanged)
+			So(received[0].Language, ShouldEqual, "french")
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french"})
+			So(d.GetClosestLanguage("quelle est ta langue?"), ShouldEqual, "french")
+		})
+		Convey("New profiles should be added and deleted ones removed", func() {
+			write("de.json", "Ich spreche Deutsch und du?", "german")
+			So(waitFor(1)[0].Op, ShouldEqual, langdet.ReloadAdded)
+			So(os.Remove(filepath.Join(dir, "en.json")), ShouldBeNil)
+			received := waitFor(2)
+			So(received[1].Op, ShouldEqual, langdet.ReloadRemoved)
+			So(received[1].Language, ShouldEqual, "english")
+			So(d.ListLanguages(), ShouldResemble, []string{"french", "german"})
+		})
+		Convey("Broken profiles should be reported and keep their language", func() {
+			So(os.WriteFile(filepath.Join(dir, "fr.json"), []byte("{broken"), 0644), ShouldBeNil)
+			received := waitFor(1)
+			So(received[0].Err, ShouldNotBeNil)
+			So(d.ListLanguages(), ShouldResemble, []string{"english", "french"})
+		})
+		Convey("Watching should stop when the context is done", func() {
+			cancel()
+			So(<-done, ShouldEqual, context.Canceled)
+			done <- nil
+		})
+	})
+	Convey("Subject: Watch a directory that cannot be read", t, func() {
+		d := langdet.NewDetector()
+		So(d.WatchDir(context.Background(), filepath.Join(t.TempDir(), "missing"), 0, nil), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,71 @@
+package langdet
+
+import (
+	"bytes"
+	"unicode"
+)
+
+// countWords adds the lowercase words of a text to counts
+func countWords(counts map[string]int, text string) {
+	for _, word := range (WordTokenizer{Lowercase: true}).Tokenize(text) {
+		counts[word]++
+	}
+}
+
+// wordCounts returns the word counts of the trainer, creating them on the first fed text
+func (t *Trainer) wordCounts() map[string]int {
+	if t.words == nil {
+		t.words = make(map[string]int)
+	}
+	return t.words
+}
+
+// wordWriter counts the words of the text written to it, a word split between two writes is
+// counted once it is complete
+type wordWriter struct {
+	counts  map[string]int
+	partial []byte
+}
+
+func (w *wordWriter) Write(p []byte) (int, error) {
+	data := append(w.partial, p...)
+	end := bytes.LastIndexFunc(data, unicode.IsSpace)
+	if end < 0 {
+		w.partial = data
+		return len(p), nil
+	}
+	countWords(w.counts, string(data[:end]))
+	w.partial = append([]byte(nil), data[end:]...)
+	return len(p), nil
+}
+
+// flush counts the last word written
+func (w *wordWriter) flush() {
+	countWords(w.counts, string(w.partial))
+	w.partial = nil
+}
+
+// wordLookup returns the rank lookup map of the lowercase words of a prepared text, or nil if the
+// detector doesn't score words
+func (d *Detector) wordLookup(text string) map[string]int {
+	if d.WordWeight <= 0 {
+		return nil
+	}
+	counts := map[string]int{}
+	countWords(counts, text)
+	return CreateRankLookupMap(counts)
+}
+
+// weighWords combines the confidence of the n-grams of a result with the confidence of the words
+// of the input, a rank lookup map, for languages with a WordProfile
+func (d *Detector) weighWords(result *DetectionResult, words map[string]int, language Language) {
+	if len(words) == 0 || len(language.WordProfile) == 0 {
+		return
+	}
+	weight := d.WordWeight
+	if weight > 1 {
+		weight = 1
+	}
+	scored := scoreRanks(words, len(words), 0, DetectionResult{}, rankMap(language.WordProfile))
+	result.Confidence = (1-weight)*result.Confidence + weight*scored.Confidence
+}
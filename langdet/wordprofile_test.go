@@ -0,0 +1,46 @@
+package langdet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWordProfiles(t *testing.T) {
+	Convey("Subject: Word profiles of closely related languages", t, func() {
+		danish := "Jeg kan ikke lide det. Hvad er det for noget? Vi skal hjem nu, for det er sent. Hun har ikke været her i dag."
+		norwegian := "Jeg liker det ikke. Hva er det for noe? Vi skal hjem nå, for det er sent. Hun har ikke vært her i dag."
+		train := func(text, name string) langdet.Language {
+			trainer := &langdet.Trainer{Depth: 3, WordRanks: 100}
+			trainer.Feed(text)
+			return trainer.Build(name)
+		}
+
+		Convey("Trainers should rank the words besides the n-grams", func() {
+			language := train(norwegian, "norwegian")
+			So(language.Profile, ShouldNotBeEmpty)
+			So(language.WordProfile, ShouldContainKey, "hva")
+			So(language.WordProfile["det"], ShouldEqual, 1)
+		})
+		Convey("Trainers should count the words of readers like the words of texts", func() {
+			trainer := &langdet.Trainer{Depth: 3, WordRanks: 100}
+			So(trainer.FeedReader(strings.NewReader(norwegian)), ShouldBeNil)
+			So(trainer.Build("norwegian").WordProfile, ShouldResemble, train(norwegian, "norwegian").WordProfile)
+		})
+		Convey("Words should only count with a WordWeight", func() {
+			d := langdet.NewDetector()
+			d.AddLanguage(train(danish, "danish"), train(norwegian, "norwegian"))
+			text := "Hva er det? Jeg liker det ikke."
+			ngrams := d.GetLanguages(text)
+
+			d.WordWeight = 0.5
+			combined := d.GetLanguages(text)
+			So(combined[0].Name, ShouldEqual, "norwegian")
+			So(combined, ShouldNotResemble, ngrams)
+			lead := func(results []langdet.DetectionResult) float64 { return results[0].Confidence - results[1].Confidence }
+			So(lead(combined), ShouldBeGreaterThan, lead(ngrams))
+		})
+	})
+}
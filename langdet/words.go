@@ -0,0 +1,108 @@
+package langdet
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// WordSet is a set of words of a language, e.g. a hash set of its most common words or a bloom
+// filter of its vocabulary. GetClosestLanguageWithConfidence and DetectShort look up single-word
+// texts in the word sets of the languages before they score n-grams, which can't tell the
+// language of a word like "danke" reliably. Contains is called with lowercase words without
+// surrounding punctuation, and must be safe for concurrent use.
+type WordSet interface {
+	Contains(word string) bool
+}
+
+// Words is a WordSet of lowercase words
+type Words map[string]struct{}
+
+// NewWords returns the Words of the lowercased words
+func NewWords(words ...string) Words {
+	set := make(Words, len(words))
+	for _, word := range words {
+		set[strings.ToLower(word)] = struct{}{}
+	}
+	return set
+}
+
+// Contains tells whether the word is in the set
+func (w Words) Contains(word string) bool {
+	_, ok := w[word]
+	return ok
+}
+
+// SetWordSet sets the WordSet of the named language, nil removes it. The language doesn't have
+// to be loaded, e.g. for words of languages whose profiles are not detectable. The Cache is purged.
+func (d *Detector) SetWordSet(name string, set WordSet) {
+	if d.mu != nil {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+	}
+	updated := make(map[string]WordSet, len(d.wordSets)+1)
+	for language, current := range d.wordSets {
+		updated[language] = current
+	}
+	if set == nil {
+		delete(updated, name)
+	} else {
+		updated[name] = set
+	}
+	d.wordSets = updated
+	d.Cache.Purge()
+}
+
+// currentWordSets returns the current word sets of all languages, which must not be modified
+func (d *Detector) currentWordSets() map[string]WordSet {
+	if d.mu != nil {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+	}
+	return d.wordSets
+}
+
+// closestWord returns the language of a single-word text from the word sets, with a confidence
+// of 1 if a single word set contains the word. If several do, the closest of their languages by
+// the n-gram scores wins with its confidence. It returns false for texts of several words and for
+// words of no word set.
+func (d *Detector) closestWord(text string) (string, float64, bool) {
+	sets := d.currentWordSets()
+	if len(sets) == 0 {
+		return "", 0, false
+	}
+	fields := strings.Fields(text)
+	if len(fields) != 1 {
+		return "", 0, false
+	}
+	word := strings.ToLower(strings.TrimFunc(fields[0], unicode.IsPunct))
+	if word == "" {
+		return "", 0, false
+	}
+	matches := make(map[string]bool)
+	for name, set := range sets {
+		if set.Contains(word) {
+			matches[name] = true
+		}
+	}
+	names := make([]string, 0, len(matches))
+	for name := range matches {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", 0, false
+	}
+	// the first name wins if none of the languages is loaded
+	sort.Strings(names)
+	name, confidence := names[0], 1.0
+	if len(names) > 1 {
+		for _, result := range d.GetLanguages(text) {
+			if matches[result.Name] {
+				name, confidence = result.Name, result.Confidence/100
+				break
+			}
+		}
+	}
+	d.recent.see(name)
+	return name, confidence, true
+}
@@ -0,0 +1,42 @@
+package langdet_test
+
+import (
+	"testing"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWordSets(t *testing.T) {
+	Convey("Subject: Word sets of single-word inputs", t, func() {
+		d := langdet.NewDefaultLanguages()
+		d.SetWordSet("fr", langdet.NewWords("bonjour", "merci"))
+		d.SetWordSet("de", langdet.NewWords("Danke", "die"))
+		d.SetWordSet("en", langdet.NewWords("the", "die"))
+
+		Convey("Words of a single word set should be exact matches", func() {
+			name, confidence, reliable := d.GetClosestLanguageWithConfidence("  Bonjour! ")
+			So(name, ShouldEqual, "fr")
+			So(confidence, ShouldEqual, 1)
+			So(reliable, ShouldBeTrue)
+			So(d.GetClosestLanguage("danke"), ShouldEqual, "de")
+			name, _, _ = d.DetectShort("DANKE")
+			So(name, ShouldEqual, "de")
+		})
+		Convey("Words of several word sets should go to the closest of their languages", func() {
+			name, confidence, reliable := d.GetClosestLanguageWithConfidence("die")
+			So(name, ShouldBeIn, "de", "en")
+			So(confidence, ShouldBeLessThan, 1)
+			So(reliable, ShouldBeTrue)
+		})
+		Convey("Texts of several words should be scored by their n-grams", func() {
+			_, confidence, _ := d.GetClosestLanguageWithConfidence("bonjour merci")
+			So(confidence, ShouldBeLessThan, 1)
+		})
+		Convey("Removed word sets should not be consulted", func() {
+			d.SetWordSet("fr", nil)
+			_, confidence, _ := d.GetClosestLanguageWithConfidence("bonjour")
+			So(confidence, ShouldBeLessThan, 1)
+		})
+	})
+}
@@ -0,0 +1,118 @@
+package langdet
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// detectEvery is the number of bytes that must be written before the DetectingWriter detects again
+const detectEvery = 256
+
+// DefaultMaxDetectBytes is the default number of bytes a DetectingWriter accumulates for the detection
+const DefaultMaxDetectBytes = 64 * 1024
+
+// DetectingWriter is an io.Writer that passes all writes through to an underlying writer,
+// accumulates the written bytes and calls a callback once the detection of the accumulated
+// text reaches a confidence threshold. It can be used to attach a language label to streams,
+// e.g. when tee-ing into logging pipelines. It is safe for concurrent use.
+type DetectingWriter struct {
+	// MaxBytes is the number of bytes that are accumulated for the detection,
+	// later writes are only passed through. 0 means DefaultMaxDetectBytes.
+	MaxBytes int
+
+	mu        sync.Mutex
+	w         io.Writer
+	detector  *Detector
+	threshold float64
+	callback  func(DetectionResult)
+	buf       bytes.Buffer
+	checked   int
+	result    *DetectionResult
+}
+
+// NewDetectingWriter returns a DetectingWriter writing to w, which may be nil to only detect.
+// callback is called once with the closest language, when its confidence (between 0 and 1)
+// reaches threshold.
+func NewDetectingWriter(w io.Writer, detector *Detector, threshold float64, callback func(DetectionResult)) *DetectingWriter {
+	return &DetectingWriter{w: w, detector: detector, threshold: threshold, callback: callback}
+}
+
+// Write writes p to the underlying writer and detects the language of the accumulated bytes
+func (dw *DetectingWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	var err error
+	if dw.w != nil {
+		n, err = dw.w.Write(p)
+	}
+
+	dw.mu.Lock()
+	maxBytes := dw.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxDetectBytes
+	}
+	if dw.result != nil || dw.buf.Len() >= maxBytes {
+		dw.mu.Unlock()
+		return n, err
+	}
+	if free := maxBytes - dw.buf.Len(); len(p) > free {
+		p = p[:free]
+	}
+	dw.buf.Write(p)
+	var result DetectionResult
+	var detected bool
+	if dw.buf.Len()-dw.checked >= detectEvery || dw.buf.Len() >= maxBytes {
+		result, detected = dw.detect()
+	}
+	dw.mu.Unlock()
+	if detected {
+		dw.notify(result)
+	}
+	return n, err
+}
+
+// Flush detects the language of all accumulated bytes, even if fewer than the usual
+// amount of bytes have been written since the last detection
+func (dw *DetectingWriter) Flush() {
+	dw.mu.Lock()
+	var result DetectionResult
+	var detected bool
+	if dw.result == nil && dw.buf.Len() > dw.checked {
+		result, detected = dw.detect()
+	}
+	dw.mu.Unlock()
+	if detected {
+		dw.notify(result)
+	}
+}
+
+// Result returns the detected language, or false if the threshold has not been reached yet
+func (dw *DetectingWriter) Result() (DetectionResult, bool) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	if dw.result == nil {
+		return DetectionResult{}, false
+	}
+	return *dw.result, true
+}
+
+// detect detects the accumulated text and returns the result if the threshold is reached.
+// It must be called with dw.mu held.
+func (dw *DetectingWriter) detect() (DetectionResult, bool) {
+	dw.checked = dw.buf.Len()
+	results := dw.detector.GetLanguages(StripPreamble(dw.buf.String()))
+	if len(results) == 0 || results[0].Confidence/100 < dw.threshold {
+		return DetectionResult{}, false
+	}
+	result := results[0]
+	dw.result = &result
+	return result, true
+}
+
+// notify calls the callback with a detected result. It is called without dw.mu held,
+// so that the callback may use the DetectingWriter.
+func (dw *DetectingWriter) notify(result DetectionResult) {
+	if dw.callback != nil {
+		dw.callback(result)
+	}
+}
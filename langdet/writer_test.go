@@ -0,0 +1,57 @@
+package langdet_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/imankulov/go-lang-detector/langdet"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDetectingWriter(t *testing.T) {
+	s := "Hello I am english text, what is your language? I really dont know you say? "
+	Convey("Subject: Detect the language of written bytes", t, func() {
+		d := langdet.NewDetector()
+		d.AddLanguageFromText(s, "english")
+		d.AddLanguageFromText("Je parles français et toi? Je ne sais pas ce que tu dis.", "french")
+		var out bytes.Buffer
+		calls := []string{}
+		w := langdet.NewDetectingWriter(&out, &d, 0.7, func(r langdet.DetectionResult) {
+			calls = append(calls, r.Name)
+		})
+		Convey("The callback should be called once when the threshold is reached", func() {
+			for i := 0; i < 10; i++ {
+				fmt.Fprint(w, s)
+			}
+			So(calls, ShouldResemble, []string{"english"})
+			So(out.String(), ShouldHaveLength, 10*len(s))
+			res, ok := w.Result()
+			So(ok, ShouldBeTrue)
+			So(res.Name, ShouldEqual, "english")
+		})
+		Convey("Short writes should only be detected when flushed", func() {
+			fmt.Fprint(w, s)
+			So(calls, ShouldBeEmpty)
+			w.Flush()
+			So(calls, ShouldResemble, []string{"english"})
+		})
+		Convey("The callback should be able to use the writer", func() {
+			var result langdet.DetectionResult
+			var w *langdet.DetectingWriter
+			w = langdet.NewDetectingWriter(nil, &d, 0.7, func(langdet.DetectionResult) {
+				result, _ = w.Result()
+				fmt.Fprint(w, s)
+			})
+			fmt.Fprint(w, s)
+			w.Flush()
+			So(result.Name, ShouldEqual, "english")
+		})
+		Convey("The threshold may never be reached", func() {
+			w := langdet.NewDetectingWriter(nil, &d, 1.1, nil)
+			fmt.Fprint(w, s)
+			w.Flush()
+			_, ok := w.Result()
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
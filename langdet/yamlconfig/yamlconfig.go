@@ -0,0 +1,33 @@
+// Package yamlconfig loads YAML config files of detectors, see langdet.Config. It is a separate
+// package, so that the langdet package does not depend on a YAML library.
+package yamlconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/imankulov/go-lang-detector/langdet"
+	"gopkg.in/yaml.v2"
+)
+
+// Load reads a YAML config file and returns the Detector it describes, like langdet.LoadConfig
+func Load(configPath string) (langdet.Detector, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return langdet.Detector{}, err
+	}
+	config, err := Parse(data)
+	if err != nil {
+		return langdet.Detector{}, fmt.Errorf("could not parse config %s: %w", configPath, err)
+	}
+	config.ResolvePaths(filepath.Dir(configPath))
+	return config.NewDetector()
+}
+
+// Parse decodes a YAML config. Unknown fields are errors, like those of langdet.ReadConfig.
+func Parse(data []byte) (langdet.Config, error) {
+	config := langdet.Config{}
+	err := yaml.UnmarshalStrict(data, &config)
+	return config, err
+}
@@ -0,0 +1,52 @@
+package yamlconfig_test
+
+import (
+	"github.com/imankulov/go-lang-detector/langdet"
+	"github.com/imankulov/go-lang-detector/langdet/yamlconfig"
+	. "github.com/smartystreets/goconvey/convey"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	Convey("Subject: Load a detector from a YAML config file", t, func() {
+		dir := t.TempDir()
+		os.Mkdir(filepath.Join(dir, "profiles"), 0755)
+		os.WriteFile(filepath.Join(dir, "profiles", "en.json"), []byte(`{"Profile":{"t":1},"Name":"en"}`), 0644)
+		os.WriteFile(filepath.Join(dir, "profiles", "fr.json"), []byte(`{"Profile":{"e":1},"Name":"fr"}`), 0644)
+		os.WriteFile(filepath.Join(dir, "more.json"), []byte(`[{"Profile":{"d":1},"Name":"de"},{"Profile":{"r":1},"Name":"ru"}]`), 0644)
+
+		Convey("A YAML config should configure sources, thresholds, candidates and preprocessing", func() {
+			configPath := filepath.Join(dir, "langdet.yaml")
+			os.WriteFile(configPath, []byte(`
+profiles: [profiles, more.json]
+languages: [en, de]
+maxInputRanks: 100
+minimumConfidence: 0.5
+minMatchedTokens: 3
+stripPreamble: true
+languageOptions:
+  en: {minConfidence: 0.75, prior: 1.5}
+`), 0644)
+			d, err := yamlconfig.Load(configPath)
+			So(err, ShouldBeNil)
+			So(len(*d.Languages), ShouldEqual, 2)
+			So((*d.Languages)[1].Name, ShouldEqual, "de")
+			So(d.MinimumConfidence, ShouldEqual, 0.5)
+			So(d.MinMatchedTokens, ShouldEqual, 3)
+			So(d.MaxInputRanks, ShouldEqual, 100)
+			So(d.StripPreamble, ShouldBeTrue)
+			So(d.LanguageOptions("en"), ShouldResemble, langdet.LanguageOptions{MinConfidence: 0.75, Prior: 1.5})
+		})
+		Convey("Unknown candidates and options should be errors", func() {
+			configPath := filepath.Join(dir, "bad.yaml")
+			os.WriteFile(configPath, []byte("profiles: [profiles]\nlanguages: [xx]\n"), 0644)
+			_, err := yamlconfig.Load(configPath)
+			So(err, ShouldNotBeNil)
+			os.WriteFile(configPath, []byte("threshold: 1\n"), 0644)
+			_, err = yamlconfig.Load(configPath)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}